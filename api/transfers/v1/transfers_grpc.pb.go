@@ -0,0 +1,259 @@
+// Protobuf definition of the internal transfers API, served over gRPC alongside the REST API.
+// Monetary amounts are decimal strings (e.g. "100.25") so no precision is lost in transit.
+// Regenerate the Go code with `make proto`.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: transfers/v1/transfers.proto
+
+package transfersv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	TransfersService_CreateAccount_FullMethodName      = "/transfers.v1.TransfersService/CreateAccount"
+	TransfersService_GetAccountDetails_FullMethodName  = "/transfers.v1.TransfersService/GetAccountDetails"
+	TransfersService_ProcessTransaction_FullMethodName = "/transfers.v1.TransfersService/ProcessTransaction"
+	TransfersService_ListTransactions_FullMethodName   = "/transfers.v1.TransfersService/ListTransactions"
+)
+
+// TransfersServiceClient is the client API for TransfersService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// TransfersService manages accounts and transfers between them.
+type TransfersServiceClient interface {
+	// CreateAccount creates an account with an initial balance and optional descriptive attributes.
+	// Returns ALREADY_EXISTS if the account exists.
+	CreateAccount(ctx context.Context, in *CreateAccountRequest, opts ...grpc.CallOption) (*CreateAccountResponse, error)
+	// GetAccountDetails returns an account's current balance and attributes.
+	// Returns NOT_FOUND if the account doesn't exist.
+	GetAccountDetails(ctx context.Context, in *GetAccountDetailsRequest, opts ...grpc.CallOption) (*GetAccountDetailsResponse, error)
+	// ProcessTransaction transfers funds between two accounts.
+	// Returns NOT_FOUND for missing accounts, FAILED_PRECONDITION for insufficient funds or closed accounts,
+	// and ABORTED if source_account_version is set and no longer matches.
+	ProcessTransaction(ctx context.Context, in *ProcessTransactionRequest, opts ...grpc.CallOption) (*ProcessTransactionResponse, error)
+	// ListTransactions returns the transfers into and out of an account, newest first.
+	ListTransactions(ctx context.Context, in *ListTransactionsRequest, opts ...grpc.CallOption) (*ListTransactionsResponse, error)
+}
+
+type transfersServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTransfersServiceClient(cc grpc.ClientConnInterface) TransfersServiceClient {
+	return &transfersServiceClient{cc}
+}
+
+func (c *transfersServiceClient) CreateAccount(ctx context.Context, in *CreateAccountRequest, opts ...grpc.CallOption) (*CreateAccountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateAccountResponse)
+	err := c.cc.Invoke(ctx, TransfersService_CreateAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *transfersServiceClient) GetAccountDetails(ctx context.Context, in *GetAccountDetailsRequest, opts ...grpc.CallOption) (*GetAccountDetailsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetAccountDetailsResponse)
+	err := c.cc.Invoke(ctx, TransfersService_GetAccountDetails_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *transfersServiceClient) ProcessTransaction(ctx context.Context, in *ProcessTransactionRequest, opts ...grpc.CallOption) (*ProcessTransactionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProcessTransactionResponse)
+	err := c.cc.Invoke(ctx, TransfersService_ProcessTransaction_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *transfersServiceClient) ListTransactions(ctx context.Context, in *ListTransactionsRequest, opts ...grpc.CallOption) (*ListTransactionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTransactionsResponse)
+	err := c.cc.Invoke(ctx, TransfersService_ListTransactions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TransfersServiceServer is the server API for TransfersService service.
+// All implementations must embed UnimplementedTransfersServiceServer
+// for forward compatibility.
+//
+// TransfersService manages accounts and transfers between them.
+type TransfersServiceServer interface {
+	// CreateAccount creates an account with an initial balance and optional descriptive attributes.
+	// Returns ALREADY_EXISTS if the account exists.
+	CreateAccount(context.Context, *CreateAccountRequest) (*CreateAccountResponse, error)
+	// GetAccountDetails returns an account's current balance and attributes.
+	// Returns NOT_FOUND if the account doesn't exist.
+	GetAccountDetails(context.Context, *GetAccountDetailsRequest) (*GetAccountDetailsResponse, error)
+	// ProcessTransaction transfers funds between two accounts.
+	// Returns NOT_FOUND for missing accounts, FAILED_PRECONDITION for insufficient funds or closed accounts,
+	// and ABORTED if source_account_version is set and no longer matches.
+	ProcessTransaction(context.Context, *ProcessTransactionRequest) (*ProcessTransactionResponse, error)
+	// ListTransactions returns the transfers into and out of an account, newest first.
+	ListTransactions(context.Context, *ListTransactionsRequest) (*ListTransactionsResponse, error)
+	mustEmbedUnimplementedTransfersServiceServer()
+}
+
+// UnimplementedTransfersServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedTransfersServiceServer struct{}
+
+func (UnimplementedTransfersServiceServer) CreateAccount(context.Context, *CreateAccountRequest) (*CreateAccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAccount not implemented")
+}
+func (UnimplementedTransfersServiceServer) GetAccountDetails(context.Context, *GetAccountDetailsRequest) (*GetAccountDetailsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccountDetails not implemented")
+}
+func (UnimplementedTransfersServiceServer) ProcessTransaction(context.Context, *ProcessTransactionRequest) (*ProcessTransactionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ProcessTransaction not implemented")
+}
+func (UnimplementedTransfersServiceServer) ListTransactions(context.Context, *ListTransactionsRequest) (*ListTransactionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTransactions not implemented")
+}
+func (UnimplementedTransfersServiceServer) mustEmbedUnimplementedTransfersServiceServer() {}
+func (UnimplementedTransfersServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeTransfersServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TransfersServiceServer will
+// result in compilation errors.
+type UnsafeTransfersServiceServer interface {
+	mustEmbedUnimplementedTransfersServiceServer()
+}
+
+func RegisterTransfersServiceServer(s grpc.ServiceRegistrar, srv TransfersServiceServer) {
+	// If the following call pancis, it indicates UnimplementedTransfersServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&TransfersService_ServiceDesc, srv)
+}
+
+func _TransfersService_CreateAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransfersServiceServer).CreateAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TransfersService_CreateAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransfersServiceServer).CreateAccount(ctx, req.(*CreateAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TransfersService_GetAccountDetails_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAccountDetailsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransfersServiceServer).GetAccountDetails(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TransfersService_GetAccountDetails_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransfersServiceServer).GetAccountDetails(ctx, req.(*GetAccountDetailsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TransfersService_ProcessTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProcessTransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransfersServiceServer).ProcessTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TransfersService_ProcessTransaction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransfersServiceServer).ProcessTransaction(ctx, req.(*ProcessTransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TransfersService_ListTransactions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTransactionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransfersServiceServer).ListTransactions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TransfersService_ListTransactions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransfersServiceServer).ListTransactions(ctx, req.(*ListTransactionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TransfersService_ServiceDesc is the grpc.ServiceDesc for TransfersService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TransfersService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "transfers.v1.TransfersService",
+	HandlerType: (*TransfersServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateAccount",
+			Handler:    _TransfersService_CreateAccount_Handler,
+		},
+		{
+			MethodName: "GetAccountDetails",
+			Handler:    _TransfersService_GetAccountDetails_Handler,
+		},
+		{
+			MethodName: "ProcessTransaction",
+			Handler:    _TransfersService_ProcessTransaction_Handler,
+		},
+		{
+			MethodName: "ListTransactions",
+			Handler:    _TransfersService_ListTransactions_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "transfers/v1/transfers.proto",
+}
@@ -2,19 +2,31 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
+	"github.com/cursed-ninja/internal-transfers-system/internal/certs"
 	"github.com/cursed-ninja/internal-transfers-system/internal/config"
+	"github.com/cursed-ninja/internal-transfers-system/internal/events"
 	"github.com/cursed-ninja/internal-transfers-system/internal/migrations"
+	"github.com/cursed-ninja/internal-transfers-system/internal/outbox"
+	"github.com/cursed-ninja/internal-transfers-system/internal/ratelimit"
 	"github.com/cursed-ninja/internal-transfers-system/internal/server"
+	"github.com/cursed-ninja/internal-transfers-system/internal/snapshots"
 	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
 	"github.com/cursed-ninja/internal-transfers-system/internal/utils"
+	"github.com/cursed-ninja/internal-transfers-system/internal/webhooks"
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 func main() {
@@ -22,15 +34,22 @@ func main() {
 
 	// Initialize configuration and logger
 	appEnv := config.GetEnv()
-	logger := utils.GetLogger(appEnv)
+	bootLogger := utils.GetLogger(appEnv)
 	err := config.InitViper(appEnv)
 	if err != nil {
-		logger.Fatal("failed to initialize viper", zap.Error(err))
+		bootLogger.Fatal("failed to initialize viper", zap.Error(err))
 	}
 	cfg := config.NewConfig(appEnv)
 
+	logger, logLevel, err := utils.NewLogger(cfg.Logger)
+	if err != nil {
+		bootLogger.Fatal("failed to initialize logger", zap.Error(err))
+	}
+	defer func() { _ = logger.Sync() }()
+	zap.ReplaceGlobals(logger)
+
 	// Initialize Postgres storage
-	pgClient, err := storage.NewPostgressManager(ctx, cfg.PostgresConfig)
+	pgClient, err := storage.NewPostgressManager(ctx, cfg.PostgresConfig, logger)
 	if err != nil {
 		logger.Fatal("failed to initialze postgres", zap.Error(err))
 	}
@@ -40,9 +59,107 @@ func main() {
 		logger.Fatal("failed to run migrations", zap.Error(err))
 	}
 
+	// Initialize JWT verification for bearer tokens
+	var jwtVerifier *server.JWTVerifier
+	if cfg.Auth.JWT.Enabled {
+		jwtVerifier, err = server.NewJWTVerifier(cfg.Auth.JWT)
+		if err != nil {
+			logger.Fatal("failed to initialize jwt verifier", zap.Error(err))
+		}
+	}
+
+	// Initialize HMAC signature verification for transfer requests
+	var requestVerifier *server.RequestVerifier
+	if cfg.Signing.Enabled {
+		requestVerifier, err = server.NewRequestVerifier(cfg.Signing)
+		if err != nil {
+			logger.Fatal("failed to initialize request verifier", zap.Error(err))
+		}
+	}
+
 	// Initialize and start the server
-	server := server.NewServer(cfg, pgClient)
-	httpSrv := startServer(cfg, server, logger)
+	server := server.NewServer(cfg, pgClient, logger, logLevel)
+	server.AddReadinessCheck("migrations", func(ctx context.Context) error {
+		pending, err := migrations.Pending(ctx, pgClient.DB())
+		if err != nil {
+			return err
+		}
+		if len(pending) > 0 {
+			return fmt.Errorf("%d pending migrations", len(pending))
+		}
+		return nil
+	})
+	if jwtVerifier != nil {
+		server.SetJWTVerifier(jwtVerifier)
+	}
+	if requestVerifier != nil {
+		server.SetRequestVerifier(requestVerifier)
+	}
+	if cfg.RateLimit.Enabled {
+		server.SetRateLimiter(ratelimit.NewMemoryStore())
+	}
+
+	// Start the background workers: the outbox relay publishing transfer events,
+	// the webhook worker delivering them to subscribers and the broker feeding event streams
+	workersCtx, stopWorkers := context.WithCancel(ctx)
+	var workers sync.WaitGroup
+	if cfg.Outbox.Enabled {
+		publisher, err := outbox.NewPublisher(cfg.Outbox, logger)
+		if err != nil {
+			logger.Fatal("failed to initialize outbox publisher", zap.Error(err))
+		}
+		if cfg.Webhooks.Enabled {
+			publisher = outbox.Fanout(publisher, webhooks.NewDispatcher(pgClient))
+		}
+		relay := outbox.NewRelay(pgClient, publisher, cfg.Outbox, logger)
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			relay.Run(workersCtx)
+		}()
+	} else if cfg.Webhooks.Enabled {
+		logger.Warn("webhooks are enabled but the outbox is disabled; no new deliveries will be queued")
+	}
+	if cfg.Webhooks.Enabled {
+		worker := webhooks.NewWorker(pgClient, cfg.Webhooks, logger)
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			worker.Run(workersCtx)
+		}()
+	}
+	if cfg.Events.Enabled {
+		var wake <-chan struct{}
+		listener, err := storage.NewOutboxListener(cfg.PostgresConfig.ConnStr, logger)
+		if err != nil {
+			logger.Warn("failed to listen for outbox notifications; falling back to polling", zap.Error(err))
+		} else {
+			defer func() { _ = listener.Close() }()
+			wake = listener.Wake()
+		}
+		broker := events.NewBroker(pgClient, wake, cfg.Events, logger)
+		server.SetEventBroker(broker)
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			broker.Run(workersCtx)
+		}()
+	}
+	if cfg.Snapshots.Enabled {
+		snapshotter := snapshots.NewSnapshotter(pgClient, cfg.Snapshots, logger)
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			snapshotter.Run(workersCtx)
+		}()
+	}
+
+	tlsConfig := loadTLSConfig(ctx, cfg, logger)
+	httpSrv := startServer(cfg, server, tlsConfig, logger)
+	var grpcSrv *grpc.Server
+	if cfg.GRPC.Enabled {
+		grpcSrv = startGRPCServer(cfg, server, tlsConfig, logger)
+	}
 
 	// Listen for OS shutdown signal
 	stop := make(chan os.Signal, 1)
@@ -51,34 +168,107 @@ func main() {
 	logger.Info("shutdown signal received")
 
 	// Stop the server gracefully
-	stopServer(ctx, httpSrv, logger)
+	stopServer(ctx, cfg, server, httpSrv, grpcSrv, logger)
+	stopWorkers()
+	workers.Wait()
 }
 
-// startServer starts the HTTP server and binds the routes.
-func startServer(cfg *config.Config, server *server.Server, log *zap.Logger) *http.Server {
+// loadTLSConfig returns the TLS configuration shared by the HTTP and gRPC servers, or nil when TLS is disabled.
+// Certificates are served from files that are reloaded on change.
+func loadTLSConfig(ctx context.Context, cfg *config.Config, log *zap.Logger) *tls.Config {
+	if !cfg.TLS.Enabled {
+		return nil
+	}
+	reloader, err := certs.NewReloader(cfg.TLS.CertFile, cfg.TLS.KeyFile, cfg.TLS.ClientCAFile, log)
+	if err != nil {
+		log.Fatal("failed to load tls certificates", zap.Error(err))
+	}
+	clientAuth, err := certs.ClientAuthType(cfg.TLS.ClientAuth)
+	if err != nil {
+		log.Fatal("invalid tls client auth mode", zap.Error(err))
+	}
+	if cfg.TLS.ReloadInterval > 0 {
+		go reloader.Watch(ctx, cfg.TLS.ReloadInterval)
+	}
+	return reloader.TLSConfig(clientAuth)
+}
+
+// startServer starts the HTTP server and binds the routes, serving TLS when tlsConfig is set.
+func startServer(cfg *config.Config, server *server.Server, tlsConfig *tls.Config, log *zap.Logger) *http.Server {
 	r := mux.NewRouter()
 	server.BindRoutes(r)
 
 	httpSrv := &http.Server{
-		Addr:    cfg.Port,
-		Handler: r,
+		Addr:      cfg.Port,
+		Handler:   r,
+		TLSConfig: tlsConfig,
 	}
 
 	go func() {
-		log.Info("HTTP server listening", zap.String("port", cfg.Port))
-		if err := httpSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Info("HTTP server listening", zap.String("port", cfg.Port), zap.Bool("tls", tlsConfig != nil))
+		var err error
+		if tlsConfig != nil {
+			err = httpSrv.ListenAndServeTLS("", "")
+		} else {
+			err = httpSrv.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			log.Fatal("failed to listen and serve", zap.Error(err))
 		}
 	}()
+
 	return httpSrv
 }
 
-// stopServer gracefully shuts down the HTTP server.
-func stopServer(ctx context.Context, httpSrv *http.Server, log *zap.Logger) {
+// startGRPCServer starts the gRPC API on its own port, serving TLS when tlsConfig is set.
+func startGRPCServer(cfg *config.Config, server *server.Server, tlsConfig *tls.Config, log *zap.Logger) *grpc.Server {
+	var opts []grpc.ServerOption
+	if tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	grpcSrv := server.NewGRPCServer(opts...)
+
+	lis, err := net.Listen("tcp", cfg.GRPC.Port)
+	if err != nil {
+		log.Fatal("failed to listen for gRPC", zap.Error(err))
+	}
+
+	go func() {
+		log.Info("gRPC server listening", zap.String("port", cfg.GRPC.Port), zap.Bool("tls", tlsConfig != nil))
+		if err := grpcSrv.Serve(lis); err != nil {
+			log.Fatal("failed to serve gRPC", zap.Error(err))
+		}
+	}()
+
+	return grpcSrv
+}
+
+// stopServer fails readiness, waits for load balancers to drain the instance,
+// then gracefully shuts down the HTTP and gRPC servers.
+func stopServer(ctx context.Context, cfg *config.Config, server *server.Server, httpSrv *http.Server, grpcSrv *grpc.Server, log *zap.Logger) {
+	server.StartDraining()
+	if drain := cfg.HealthConfig.ShutdownDrain; drain > 0 {
+		log.Info("draining before shutdown", zap.Duration("drain", drain))
+		time.Sleep(drain)
+	}
+
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 	if err := httpSrv.Shutdown(ctx); err != nil {
 		log.Error("failed to shutdown HTTP server", zap.Error(err))
 	}
+	if grpcSrv != nil {
+		stopped := make(chan struct{})
+		go func() {
+			grpcSrv.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+			log.Error("failed to gracefully stop gRPC server", zap.Error(ctx.Err()))
+			grpcSrv.Stop()
+		}
+	}
 	log.Info("server stopped")
 }
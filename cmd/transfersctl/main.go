@@ -0,0 +1,163 @@
+// Command transfersctl provides operational commands that run directly against the service database.
+//
+// Usage:
+//
+//	transfersctl audit verify
+//	transfersctl statement export <account-id> <from> <to> [<format>]
+//	transfersctl transactions import <file>
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/config"
+	"github.com/cursed-ninja/internal-transfers-system/internal/payments"
+	"github.com/cursed-ninja/internal-transfers-system/internal/statements"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+	"github.com/cursed-ninja/internal-transfers-system/internal/transfers"
+	"github.com/cursed-ninja/internal-transfers-system/internal/utils"
+	"go.uber.org/zap"
+)
+
+const usage = `usage: transfersctl <command> [arguments]
+
+commands:
+  audit verify                 verify the audit log hash chain; exits 1 if it is broken
+  statement export <account-id> <from> <to> [<format>]
+                               write an account statement to standard output; from and to are
+                               RFC 3339 timestamps or YYYY-MM-DD dates and format is camt053, csv,
+                               json (the default), mt940 or txt
+  transactions import <file>   make the transfers of a pain.001 file and print the pain.002 status
+                               report; exits 1 unless every transfer was settled
+`
+
+// command is a transfersctl subcommand. It returns the process exit code.
+type command func(ctx context.Context, store *storage.PostgressStorage, args []string) int
+
+var commands = map[string]command{
+	"audit verify":        auditVerify,
+	"statement export":    statementExport,
+	"transactions import": transactionsImport,
+}
+
+func main() {
+	if len(os.Args) < 3 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	cmd, ok := commands[os.Args[1]+" "+os.Args[2]]
+	if !ok {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	ctx := context.Background()
+
+	appEnv := config.GetEnv()
+	logger := utils.GetLogger(appEnv)
+	defer func() { _ = logger.Sync() }()
+	if err := config.InitViper(appEnv); err != nil {
+		logger.Fatal("failed to initialize viper", zap.Error(err))
+	}
+	cfg := config.NewConfig(appEnv)
+
+	store, err := storage.NewPostgressManager(ctx, cfg.PostgresConfig, logger)
+	if err != nil {
+		logger.Fatal("failed to initialze postgres", zap.Error(err))
+	}
+	defer func() { _ = store.DB().Close() }()
+
+	code := cmd(ctx, store, os.Args[3:])
+	_ = logger.Sync()
+	os.Exit(code)
+}
+
+// auditVerify walks the audit chain and prints the verification result as JSON.
+func auditVerify(ctx context.Context, store *storage.PostgressStorage, _ []string) int {
+	result, err := store.VerifyAuditChain(ctx)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(result); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if !result.Valid {
+		return 1
+	}
+	return 0
+}
+
+// statementExport streams an account's statement for a period to standard output, with the same period
+// and format rules as GET /accounts/{accountID}/statement.
+func statementExport(ctx context.Context, store *storage.PostgressStorage, args []string) int {
+	if len(args) != 3 && len(args) != 4 {
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+	query := url.Values{"from": {args[1]}, "to": {args[2]}}
+	if len(args) == 4 {
+		query.Set("format", args[3])
+	}
+
+	from, to, format, err := statements.ParseQuery(query, time.Now())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if !format.FitsAccountID(args[0]) {
+		fmt.Fprintln(os.Stderr, statements.ErrAccountID)
+		return 2
+	}
+
+	if err := store.StreamStatement(ctx, args[0], from, to, format.NewWriter(os.Stdout)); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// transactionsImport makes the credit transfers of a pain.001 file as POST /transactions/import does,
+// without account restrictions, and prints the pain.002 status report.
+func transactionsImport(ctx context.Context, store *storage.PostgressStorage, args []string) int {
+	if len(args) != 1 {
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer func() { _ = f.Close() }()
+
+	msg, err := payments.ParsePain001(f)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	report, err := transfers.ExecutePain001(ctx, store, msg, nil, time.Now())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if err := payments.WritePain002(os.Stdout, report); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if report.Status() != payments.StatusSettled {
+		return 1
+	}
+	return 0
+}
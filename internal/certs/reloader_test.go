@@ -0,0 +1,182 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// testCA is a throwaway certificate authority for issuing test certificates.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+// newTestCA creates a self-signed CA.
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tpl, tpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns PEM-encoded certificate and key signed by the CA.
+func (ca *testCA) issue(t *testing.T, cn string, serial int64, usage x509.ExtKeyUsage) ([]byte, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: cn},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tpl, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// writeFile writes data to path and bumps its modification time to mtime.
+func writeFile(t *testing.T, path string, data []byte, mtime time.Time) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	require.NoError(t, os.Chtimes(path, mtime, mtime))
+}
+
+// TestReloaderReloadIfChanged validates that rotated certificate files are picked up without a restart.
+func TestReloaderReloadIfChanged(t *testing.T) {
+	ca := newTestCA(t)
+	dir := t.TempDir()
+	certFile, keyFile, caFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key"), filepath.Join(dir, "ca.crt")
+
+	mtime := time.Now().Add(-time.Minute)
+	certPEM, keyPEM := ca.issue(t, "server-v1", 2, x509.ExtKeyUsageServerAuth)
+	writeFile(t, certFile, certPEM, mtime)
+	writeFile(t, keyFile, keyPEM, mtime)
+	writeFile(t, caFile, ca.pem, mtime)
+
+	r, err := NewReloader(certFile, keyFile, caFile, zap.NewNop())
+	require.NoError(t, err)
+
+	servedSerial := func() int64 {
+		cfg, err := r.TLSConfig(tls.NoClientCert).GetConfigForClient(nil)
+		require.NoError(t, err)
+		leaf, err := x509.ParseCertificate(cfg.Certificates[0].Certificate[0])
+		require.NoError(t, err)
+		return leaf.SerialNumber.Int64()
+	}
+	assert.Equal(t, int64(2), servedSerial())
+
+	reloaded, err := r.ReloadIfChanged()
+	require.NoError(t, err)
+	assert.False(t, reloaded)
+
+	mtime = mtime.Add(30 * time.Second)
+	certPEM, keyPEM = ca.issue(t, "server-v2", 3, x509.ExtKeyUsageServerAuth)
+	writeFile(t, certFile, certPEM, mtime)
+	writeFile(t, keyFile, keyPEM, mtime)
+
+	reloaded, err = r.ReloadIfChanged()
+	require.NoError(t, err)
+	assert.True(t, reloaded)
+	assert.Equal(t, int64(3), servedSerial())
+
+	mtime = mtime.Add(30 * time.Second)
+	writeFile(t, keyFile, []byte("not a key"), mtime)
+	_, err = r.ReloadIfChanged()
+	assert.Error(t, err)
+	assert.Equal(t, int64(3), servedSerial(), "failed reload must keep serving the previous certificate")
+}
+
+// TestReloaderMutualTLS validates that a server using the reloader's config verifies client certificates.
+func TestReloaderMutualTLS(t *testing.T) {
+	ca := newTestCA(t)
+	dir := t.TempDir()
+	certFile, keyFile, caFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key"), filepath.Join(dir, "ca.crt")
+
+	certPEM, keyPEM := ca.issue(t, "localhost", 2, x509.ExtKeyUsageServerAuth)
+	writeFile(t, certFile, certPEM, time.Now())
+	writeFile(t, keyFile, keyPEM, time.Now())
+	writeFile(t, caFile, ca.pem, time.Now())
+
+	r, err := NewReloader(certFile, keyFile, caFile, zap.NewNop())
+	require.NoError(t, err)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = io.WriteString(w, req.TLS.VerifiedChains[0][0].Subject.CommonName)
+	}))
+	srv.TLS = r.TLSConfig(tls.RequireAndVerifyClientCert)
+	srv.StartTLS()
+	defer srv.Close()
+
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM(ca.pem)
+
+	clientCertPEM, clientKeyPEM := ca.issue(t, "payroll", 4, x509.ExtKeyUsageClientAuth)
+	clientCert, err := tls.X509KeyPair(clientCertPEM, clientKeyPEM)
+	require.NoError(t, err)
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+		RootCAs:      roots,
+		Certificates: []tls.Certificate{clientCert},
+		ServerName:   "localhost",
+	}}}
+	resp, err := client.Get(srv.URL)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, "payroll", string(body))
+
+	anonymous := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+		RootCAs:    roots,
+		ServerName: "localhost",
+	}}}
+	_, err = anonymous.Get(srv.URL)
+	assert.Error(t, err)
+}
+
+// TestClientAuthType validates parsing of configured client authentication modes.
+func TestClientAuthType(t *testing.T) {
+	mode, err := ClientAuthType(ClientAuthRequire)
+	assert.NoError(t, err)
+	assert.Equal(t, tls.RequireAndVerifyClientCert, mode)
+
+	_, err = ClientAuthType("sometimes")
+	assert.Error(t, err)
+}
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -13,6 +15,18 @@ type Config struct {
 	Port           string
 	Env            AppEnv
 	PostgresConfig *PostgresConfig
+	HealthConfig   *HealthConfig
+	AccessLog      *AccessLogConfig
+	Logger         *LoggerConfig
+	Auth           *AuthConfig
+	Signing        *SigningConfig
+	TLS            *TLSConfig
+	RateLimit      *RateLimitConfig
+	Outbox         *OutboxConfig
+	Webhooks       *WebhooksConfig
+	Events         *EventsConfig
+	Snapshots      *SnapshotsConfig
+	GRPC           *GRPCConfig
 }
 
 // PostgresConfig holds the PostgreSQL database configuration.
@@ -20,6 +34,192 @@ type PostgresConfig struct {
 	ConnStr string
 }
 
+// HealthConfig holds the liveness and readiness probe configuration.
+type HealthConfig struct {
+	// ReadinessTimeout bounds how long each readiness check may take.
+	ReadinessTimeout time.Duration
+	// ShutdownDrain is how long readiness reports failing before the server shuts down,
+	// giving load balancers time to stop routing traffic to the instance.
+	ShutdownDrain time.Duration
+}
+
+// AccessLogConfig holds the per-request access log configuration.
+type AccessLogConfig struct {
+	Enabled bool
+	// SampleRate is the fraction of successful requests logged, between 0 and 1.
+	// Server errors are always logged.
+	SampleRate float64
+	// RedactFields lists header and query parameter names whose values are masked.
+	RedactFields []string
+}
+
+// LoggerConfig holds the root application logger configuration.
+type LoggerConfig struct {
+	// Level is the initial minimum level (debug, info, warn, error). It can be changed at runtime.
+	Level string
+	// Encoding is either "json" or "console".
+	Encoding string
+	// SamplingInitial and SamplingThereafter configure zap sampling per second; zero disables sampling.
+	SamplingInitial    int
+	SamplingThereafter int
+	// OutputPaths lists the log sinks, e.g. "stdout" or a file path.
+	OutputPaths []string
+}
+
+// AuthConfig holds the API authentication configuration.
+type AuthConfig struct {
+	// Enabled turns on authentication for every non-probe route.
+	Enabled bool
+	// BootstrapAdminKey is a static key granted the admin scope, used to issue the first API keys.
+	// It should be supplied through the AUTH_BOOTSTRAP_ADMIN_KEY environment variable.
+	BootstrapAdminKey string
+	JWT               *JWTConfig
+}
+
+// JWTConfig holds the bearer token validation configuration.
+type JWTConfig struct {
+	Enabled bool
+	// HMACSecret verifies HS256 tokens. It should be supplied through AUTH_JWT_HMAC_SECRET.
+	HMACSecret string
+	// JWKSFile is the path to a local JWKS document whose RSA keys verify RS256 tokens.
+	JWKSFile string
+	// Issuer and Audience, when set, must match the token's iss and aud claims.
+	Issuer   string
+	Audience string
+}
+
+// SigningConfig holds the HMAC request signing configuration for transfer requests.
+type SigningConfig struct {
+	Enabled bool
+	// Required rejects unsigned requests from every client; otherwise only clients with secrets must sign.
+	Required bool
+	// MaxSkew is the maximum age (or future offset) of a signature timestamp.
+	MaxSkew time.Duration
+	Clients []SigningClient
+}
+
+// SigningClient holds the shared secrets of one client. Multiple secrets with
+// overlapping validity windows allow rotation without downtime.
+type SigningClient struct {
+	ClientID string          `mapstructure:"client_id"`
+	Secrets  []SigningSecret `mapstructure:"secrets"`
+}
+
+// SigningSecret is a shared secret valid between NotBefore and NotAfter (RFC 3339, empty for unbounded).
+type SigningSecret struct {
+	Secret    string `mapstructure:"secret"`
+	NotBefore string `mapstructure:"not_before"`
+	NotAfter  string `mapstructure:"not_after"`
+}
+
+// TLSConfig holds the HTTPS and mutual TLS configuration.
+type TLSConfig struct {
+	Enabled  bool
+	CertFile string
+	KeyFile  string
+	// ClientCAFile is a PEM bundle used to verify client certificates.
+	ClientCAFile string
+	// ClientAuth is one of "none", "verify_if_given" or "require".
+	ClientAuth string
+	// ReloadInterval is how often the certificate files are checked for changes.
+	ReloadInterval time.Duration
+	// ClientIdentities maps verified client certificate subjects to caller identities.
+	ClientIdentities []ClientIdentity
+}
+
+// ClientIdentity maps a client certificate subject to a client ID and scopes.
+// Subject matches either the certificate's common name or its full distinguished name.
+type ClientIdentity struct {
+	Subject  string   `mapstructure:"subject"`
+	ClientID string   `mapstructure:"client_id"`
+	Scopes   []string `mapstructure:"scopes"`
+}
+
+// RateLimitConfig holds the token-bucket rate limits applied per route.
+type RateLimitConfig struct {
+	Enabled bool
+	// Default applies to routes without an entry in Routes.
+	Default RateLimitRule
+	Routes  []RateLimitRule
+}
+
+// RateLimitRule holds the limits for one route, keyed by API client, client IP and source account.
+type RateLimitRule struct {
+	// Route is "METHOD /path/template", e.g. "POST /transactions".
+	Route      string    `mapstructure:"route"`
+	PerClient  RateLimit `mapstructure:"per_client"`
+	PerIP      RateLimit `mapstructure:"per_ip"`
+	PerAccount RateLimit `mapstructure:"per_account"`
+}
+
+// RateLimit allows Rate requests per second with bursts of up to Burst. A zero Rate disables it.
+type RateLimit struct {
+	Rate  float64 `mapstructure:"rate"`
+	Burst int     `mapstructure:"burst"`
+}
+
+// OutboxConfig holds the outbox relay configuration.
+type OutboxConfig struct {
+	Enabled bool
+	// PollInterval is how often the relay checks for unpublished events.
+	PollInterval time.Duration
+	// BatchSize is the maximum number of events read per poll.
+	BatchSize int
+	// Publisher is one of "log", "file" or "webhook".
+	Publisher string
+	// FilePath is the file events are appended to by the "file" publisher.
+	FilePath string
+	// WebhookURL receives events as JSON POST requests from the "webhook" publisher.
+	WebhookURL     string
+	WebhookTimeout time.Duration
+}
+
+// WebhooksConfig holds the webhook delivery worker configuration.
+type WebhooksConfig struct {
+	Enabled bool
+	// PollInterval is how often the worker checks for due deliveries.
+	PollInterval time.Duration
+	// BatchSize is the maximum number of deliveries attempted per poll.
+	BatchSize int
+	// Timeout bounds each delivery request.
+	Timeout time.Duration
+	// MaxAttempts is the number of attempts before a delivery is dead-lettered.
+	MaxAttempts int
+	// BackoffBase is the delay before the first retry; it doubles with each attempt up to BackoffMax.
+	BackoffBase time.Duration
+	BackoffMax  time.Duration
+	// AllowPrivateNetworks permits deliveries to loopback, link-local and private addresses.
+	// Leave it off in production: subscribers choose the URL.
+	AllowPrivateNetworks bool
+}
+
+// EventsConfig holds the account event stream configuration.
+type EventsConfig struct {
+	Enabled bool
+	// PollInterval is how often the outbox is checked for new events when no notification arrives.
+	PollInterval time.Duration
+	// HeartbeatInterval is how often idle streams receive a keep-alive comment.
+	HeartbeatInterval time.Duration
+}
+
+// SnapshotsConfig holds the balance snapshot worker configuration.
+type SnapshotsConfig struct {
+	Enabled bool
+	// Interval is the spacing of snapshot times, aligned to UTC midnight when it divides a day.
+	Interval time.Duration
+	// SettleDelay is how long after a snapshot time the snapshot is taken, so in-flight transfers commit first.
+	SettleDelay time.Duration
+	// PollInterval is how often the worker checks whether a snapshot is due.
+	PollInterval time.Duration
+}
+
+// GRPCConfig holds the gRPC API configuration.
+type GRPCConfig struct {
+	Enabled bool
+	// Port is the listen address of the gRPC server, separate from the REST port.
+	Port string
+}
+
 // AppEnv represents the application environment.
 type AppEnv string
 
@@ -55,6 +255,87 @@ func NewConfig(env AppEnv) *Config {
 		PostgresConfig: &PostgresConfig{
 			ConnStr: viper.GetString("postgres.connstr"),
 		},
+		HealthConfig: &HealthConfig{
+			ReadinessTimeout: viper.GetDuration("health.readiness_timeout"),
+			ShutdownDrain:    viper.GetDuration("health.shutdown_drain"),
+		},
+		AccessLog: &AccessLogConfig{
+			Enabled:      viper.GetBool("access_log.enabled"),
+			SampleRate:   viper.GetFloat64("access_log.sample_rate"),
+			RedactFields: viper.GetStringSlice("access_log.redact_fields"),
+		},
+		Logger: &LoggerConfig{
+			Level:              viper.GetString("logger.level"),
+			Encoding:           viper.GetString("logger.encoding"),
+			SamplingInitial:    viper.GetInt("logger.sampling.initial"),
+			SamplingThereafter: viper.GetInt("logger.sampling.thereafter"),
+			OutputPaths:        viper.GetStringSlice("logger.output_paths"),
+		},
+		Auth: &AuthConfig{
+			Enabled:           viper.GetBool("auth.enabled"),
+			BootstrapAdminKey: viper.GetString("auth.bootstrap_admin_key"),
+			JWT: &JWTConfig{
+				Enabled:    viper.GetBool("auth.jwt.enabled"),
+				HMACSecret: viper.GetString("auth.jwt.hmac_secret"),
+				JWKSFile:   viper.GetString("auth.jwt.jwks_file"),
+				Issuer:     viper.GetString("auth.jwt.issuer"),
+				Audience:   viper.GetString("auth.jwt.audience"),
+			},
+		},
+		Signing: &SigningConfig{
+			Enabled:  viper.GetBool("signing.enabled"),
+			Required: viper.GetBool("signing.required"),
+			MaxSkew:  viper.GetDuration("signing.max_skew"),
+			Clients:  signingClients(),
+		},
+		TLS: &TLSConfig{
+			Enabled:          viper.GetBool("tls.enabled"),
+			CertFile:         viper.GetString("tls.cert_file"),
+			KeyFile:          viper.GetString("tls.key_file"),
+			ClientCAFile:     viper.GetString("tls.client_ca_file"),
+			ClientAuth:       viper.GetString("tls.client_auth"),
+			ReloadInterval:   viper.GetDuration("tls.reload_interval"),
+			ClientIdentities: clientIdentities(),
+		},
+		RateLimit: &RateLimitConfig{
+			Enabled: viper.GetBool("rate_limit.enabled"),
+			Default: rateLimitDefault(),
+			Routes:  rateLimitRoutes(),
+		},
+		Outbox: &OutboxConfig{
+			Enabled:        viper.GetBool("outbox.enabled"),
+			PollInterval:   viper.GetDuration("outbox.poll_interval"),
+			BatchSize:      viper.GetInt("outbox.batch_size"),
+			Publisher:      viper.GetString("outbox.publisher"),
+			FilePath:       viper.GetString("outbox.file_path"),
+			WebhookURL:     viper.GetString("outbox.webhook_url"),
+			WebhookTimeout: viper.GetDuration("outbox.webhook_timeout"),
+		},
+		Webhooks: &WebhooksConfig{
+			Enabled:              viper.GetBool("webhooks.enabled"),
+			PollInterval:         viper.GetDuration("webhooks.poll_interval"),
+			BatchSize:            viper.GetInt("webhooks.batch_size"),
+			Timeout:              viper.GetDuration("webhooks.timeout"),
+			MaxAttempts:          viper.GetInt("webhooks.max_attempts"),
+			BackoffBase:          viper.GetDuration("webhooks.backoff_base"),
+			BackoffMax:           viper.GetDuration("webhooks.backoff_max"),
+			AllowPrivateNetworks: viper.GetBool("webhooks.allow_private_networks"),
+		},
+		Events: &EventsConfig{
+			Enabled:           viper.GetBool("events.enabled"),
+			PollInterval:      viper.GetDuration("events.poll_interval"),
+			HeartbeatInterval: viper.GetDuration("events.heartbeat_interval"),
+		},
+		Snapshots: &SnapshotsConfig{
+			Enabled:      viper.GetBool("snapshots.enabled"),
+			Interval:     viper.GetDuration("snapshots.interval"),
+			SettleDelay:  viper.GetDuration("snapshots.settle_delay"),
+			PollInterval: viper.GetDuration("snapshots.poll_interval"),
+		},
+		GRPC: &GRPCConfig{
+			Enabled: viper.GetBool("grpc.enabled"),
+			Port:    viper.GetString("grpc.port"),
+		},
 	}
 }
 
@@ -63,11 +344,58 @@ func InitViper(env AppEnv) error {
 	path := fmt.Sprintf(envFilePath, env)
 
 	viper.SetConfigFile(path)
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	viper.AutomaticEnv()
 
 	if err := viper.ReadInConfig(); err != nil {
 		return errors.New("failed to read config")
 	}
 
+	var clients []SigningClient
+	if err := viper.UnmarshalKey("signing.clients", &clients); err != nil {
+		return fmt.Errorf("invalid signing.clients config: %w", err)
+	}
+
+	var identities []ClientIdentity
+	if err := viper.UnmarshalKey("tls.client_identities", &identities); err != nil {
+		return fmt.Errorf("invalid tls.client_identities config: %w", err)
+	}
+
+	var rule RateLimitRule
+	if err := viper.UnmarshalKey("rate_limit.default", &rule); err != nil {
+		return fmt.Errorf("invalid rate_limit.default config: %w", err)
+	}
+	var rules []RateLimitRule
+	if err := viper.UnmarshalKey("rate_limit.routes", &rules); err != nil {
+		return fmt.Errorf("invalid rate_limit.routes config: %w", err)
+	}
 	return nil
 }
+
+// signingClients decodes the signing clients list. InitViper has already validated it.
+func signingClients() []SigningClient {
+	var clients []SigningClient
+	_ = viper.UnmarshalKey("signing.clients", &clients)
+	return clients
+}
+
+// clientIdentities decodes the client certificate identity mapping. InitViper has already validated it.
+func clientIdentities() []ClientIdentity {
+	var identities []ClientIdentity
+	_ = viper.UnmarshalKey("tls.client_identities", &identities)
+	return identities
+}
+
+// rateLimitDefault decodes the default rate limit rule. InitViper has already validated it.
+func rateLimitDefault() RateLimitRule {
+	var rule RateLimitRule
+	_ = viper.UnmarshalKey("rate_limit.default", &rule)
+	return rule
+}
+
+// rateLimitRoutes decodes the per-route rate limit rules. InitViper has already validated them.
+func rateLimitRoutes() []RateLimitRule {
+	var rules []RateLimitRule
+	_ = viper.UnmarshalKey("rate_limit.routes", &rules)
+	return rules
+}
@@ -0,0 +1,26 @@
+// Package currency describes ISO 4217 currencies.
+package currency
+
+import "github.com/shopspring/decimal"
+
+// minorUnits lists the ISO 4217 currencies whose amounts do not have two decimal places.
+var minorUnits = map[string]int32{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0, "PYG": 0,
+	"RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+	"CLF": 4, "UYW": 4,
+}
+
+// Decimals returns the number of decimal places amounts in the currency are written with.
+func Decimals(code string) int32 {
+	if places, ok := minorUnits[code]; ok {
+		return places
+	}
+	return 2
+}
+
+// Fits reports whether amount is a whole number of the currency's minor unit, so it can be written
+// in the currency without rounding. Trailing zeros do not matter: 1.500 fits USD.
+func Fits(amount decimal.Decimal, code string) bool {
+	return amount.Equal(amount.Truncate(Decimals(code)))
+}
@@ -0,0 +1,32 @@
+package currency
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFits validates amounts are checked against each currency's minor unit.
+func TestFits(t *testing.T) {
+	tests := []struct {
+		amount   string
+		code     string
+		expected bool
+	}{
+		{amount: "12.34", code: "USD", expected: true},
+		{amount: "12.500", code: "EUR", expected: true},
+		{amount: "12.345", code: "EUR", expected: false},
+		{amount: "1500", code: "JPY", expected: true},
+		{amount: "1499.5", code: "JPY", expected: false},
+		{amount: "1.234", code: "KWD", expected: true},
+		{amount: "1.2345", code: "KWD", expected: false},
+		{amount: "0.0001", code: "CLF", expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code+" "+tt.amount, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Fits(decimal.RequireFromString(tt.amount), tt.code))
+		})
+	}
+}
@@ -0,0 +1,181 @@
+// Package events fans committed account events out to in-process subscribers such as SSE streams.
+package events
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/config"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultPollInterval = 5 * time.Second
+	// pollBatchSize is the number of outbox events read per query.
+	pollBatchSize = 500
+	// subscriptionBuffer is the number of events a subscriber may fall behind before it is dropped.
+	subscriptionBuffer = 64
+)
+
+// Store is the subset of storage.Storage the broker reads events from.
+type Store interface {
+	ListEventsAfter(ctx context.Context, afterID int64, accountID string, limit int) ([]storage.OutboxEvent, error)
+	LatestEventID(ctx context.Context) (int64, error)
+}
+
+// Subscription receives the events of one account.
+type Subscription struct {
+	accountID string
+	ch        chan storage.OutboxEvent
+}
+
+// Events returns the subscription's event channel. It is closed when the subscriber falls too far
+// behind or the broker stops; the subscriber should then resume from the last event ID it saw.
+func (s *Subscription) Events() <-chan storage.OutboxEvent {
+	return s.ch
+}
+
+// Broker tails the outbox for newly committed events and delivers them to subscribers of the accounts
+// they touch. It reads the outbox when woken by a notification and on a fallback poll interval.
+type Broker struct {
+	store    Store
+	wake     <-chan struct{}
+	interval time.Duration
+	logger   *zap.Logger
+
+	mu     sync.Mutex
+	subs   map[string]map[*Subscription]struct{}
+	lastID int64
+}
+
+// NewBroker creates a Broker reading from store. wake may be nil, in which case the broker only polls.
+func NewBroker(store Store, wake <-chan struct{}, cfg *config.EventsConfig, logger *zap.Logger) *Broker {
+	b := &Broker{
+		store:    store,
+		wake:     wake,
+		interval: cfg.PollInterval,
+		logger:   logger,
+		subs:     make(map[string]map[*Subscription]struct{}),
+	}
+	if b.interval <= 0 {
+		b.interval = defaultPollInterval
+	}
+	return b
+}
+
+// Subscribe registers a subscriber for accountID's events.
+func (b *Broker) Subscribe(accountID string) *Subscription {
+	sub := &Subscription{accountID: accountID, ch: make(chan storage.OutboxEvent, subscriptionBuffer)}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.subs[accountID] == nil {
+		b.subs[accountID] = make(map[*Subscription]struct{})
+	}
+	b.subs[accountID][sub] = struct{}{}
+	return sub
+}
+
+// Unsubscribe removes a subscriber and closes its channel. It is safe to call more than once.
+func (b *Broker) Unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.remove(sub)
+}
+
+// Publish delivers event to the subscribers of every account it touches. Subscribers whose buffer is
+// full are dropped rather than blocking delivery to others.
+func (b *Broker) Publish(event storage.OutboxEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, accountID := range event.AccountIDs {
+		for sub := range b.subs[accountID] {
+			select {
+			case sub.ch <- event:
+			default:
+				b.logger.Warn("dropping slow event subscriber", zap.String("account_id", accountID), zap.Int64("event_id", event.ID))
+				b.remove(sub)
+			}
+		}
+	}
+}
+
+// Run tails the outbox until ctx is cancelled, then closes every subscription.
+// Only events committed after Run starts are delivered; earlier events are replayed by subscribers
+// themselves from storage.
+func (b *Broker) Run(ctx context.Context) {
+	b.logger.Info("event broker started", zap.Duration("poll_interval", b.interval))
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+	defer b.closeAll()
+
+	started := false
+	for {
+		if !started {
+			lastID, err := b.store.LatestEventID(ctx)
+			if err != nil {
+				b.logger.Error("failed to read latest event id", zap.Error(err))
+			} else {
+				b.lastID = lastID
+				started = true
+			}
+		} else if err := b.poll(ctx); err != nil {
+			b.logger.Error("event broker poll failed", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			b.logger.Info("event broker stopped")
+			return
+		case <-b.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll publishes every event committed since the last poll.
+func (b *Broker) poll(ctx context.Context) error {
+	for {
+		events, err := b.store.ListEventsAfter(ctx, b.lastID, "", pollBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, event := range events {
+			b.Publish(event)
+			b.lastID = event.ID
+		}
+		if len(events) < pollBatchSize {
+			return nil
+		}
+	}
+}
+
+// remove drops a subscriber. The caller must hold b.mu.
+func (b *Broker) remove(sub *Subscription) {
+	subs, ok := b.subs[sub.accountID]
+	if !ok {
+		return
+	}
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+	delete(subs, sub)
+	close(sub.ch)
+	if len(subs) == 0 {
+		delete(b.subs, sub.accountID)
+	}
+}
+
+// closeAll drops every subscriber.
+func (b *Broker) closeAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, subs := range b.subs {
+		for sub := range subs {
+			b.remove(sub)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/config"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+// TestBrokerPublish validates routing by account, unsubscribing and dropping slow subscribers.
+func TestBrokerPublish(t *testing.T) {
+	b := NewBroker(nil, nil, &config.EventsConfig{}, zap.NewNop())
+
+	a := b.Subscribe("a")
+	c := b.Subscribe("c")
+	slow := b.Subscribe("a")
+
+	b.Publish(storage.OutboxEvent{ID: 1, AccountIDs: []string{"a", "b"}})
+	assert.Equal(t, int64(1), (<-a.Events()).ID)
+	assert.Empty(t, c.Events())
+
+	for i := int64(2); i <= subscriptionBuffer+1; i++ {
+		b.Publish(storage.OutboxEvent{ID: i, AccountIDs: []string{"a"}})
+		<-a.Events()
+	}
+	// slow never read, so its buffer filled and it was dropped.
+	for range slow.Events() {
+	}
+	b.Publish(storage.OutboxEvent{ID: 100, AccountIDs: []string{"a"}})
+	assert.Equal(t, int64(100), (<-a.Events()).ID)
+
+	b.Unsubscribe(a)
+	b.Unsubscribe(a)
+	_, ok := <-a.Events()
+	assert.False(t, ok)
+}
+
+// TestBrokerRun validates that the broker starts at the latest event, publishes new events when woken
+// and closes subscriptions when stopped.
+func TestBrokerRun(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockStorage := mocks.NewMockStorage(mockCtrl)
+	mockStorage.EXPECT().LatestEventID(gomock.Any()).Return(int64(10), nil)
+	mockStorage.EXPECT().ListEventsAfter(gomock.Any(), int64(10), "", pollBatchSize).
+		Return([]storage.OutboxEvent{{ID: 11, AccountIDs: []string{"a"}}, {ID: 12, AccountIDs: []string{"b"}}}, nil)
+	mockStorage.EXPECT().ListEventsAfter(gomock.Any(), int64(12), "", pollBatchSize).Return(nil, nil).AnyTimes()
+
+	wake := make(chan struct{}, 1)
+	b := NewBroker(mockStorage, wake, &config.EventsConfig{PollInterval: time.Hour}, zap.NewNop())
+	sub := b.Subscribe("a")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		b.Run(ctx)
+		close(done)
+	}()
+
+	wake <- struct{}{}
+	select {
+	case event := <-sub.Events():
+		assert.Equal(t, int64(11), event.ID)
+	case <-time.After(time.Second):
+		require.Fail(t, "event not delivered")
+	}
+
+	cancel()
+	<-done
+	_, ok := <-sub.Events()
+	assert.False(t, ok)
+}
+
+// lockedOutbox is an in-memory outbox whose writers draw IDs under a lock held until they commit, as
+// storage does with the audit chain lock. Readers only see committed events, like other transactions.
+type lockedOutbox struct {
+	commitLock sync.Mutex
+
+	mu        sync.Mutex
+	nextID    int64
+	committed []storage.OutboxEvent
+}
+
+// commit records an event touching accountID, yielding between drawing its ID and committing it so
+// concurrent writers and readers interleave.
+func (o *lockedOutbox) commit(accountID string) {
+	o.commitLock.Lock()
+	defer o.commitLock.Unlock()
+
+	o.mu.Lock()
+	o.nextID++
+	event := storage.OutboxEvent{ID: o.nextID, AccountIDs: []string{accountID}}
+	o.mu.Unlock()
+
+	time.Sleep(time.Duration(event.ID%3) * time.Millisecond)
+
+	o.mu.Lock()
+	o.committed = append(o.committed, event)
+	o.mu.Unlock()
+}
+
+func (o *lockedOutbox) ListEventsAfter(_ context.Context, afterID int64, _ string, limit int) ([]storage.OutboxEvent, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	var events []storage.OutboxEvent
+	for _, event := range o.committed {
+		if event.ID > afterID && len(events) < limit {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (o *lockedOutbox) LatestEventID(context.Context) (int64, error) {
+	return 0, nil
+}
+
+// TestBrokerConcurrentCommits validates that events committed concurrently while the broker polls are
+// each delivered once, in ID order.
+func TestBrokerConcurrentCommits(t *testing.T) {
+	const writers, eventsPerWriter = 8, 25
+
+	outbox := &lockedOutbox{}
+	wake := make(chan struct{}, 1)
+	b := NewBroker(outbox, wake, &config.EventsConfig{PollInterval: time.Millisecond}, zap.NewNop())
+	sub := b.Subscribe("a")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go b.Run(ctx)
+
+	var wg sync.WaitGroup
+	for range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range eventsPerWriter {
+				outbox.commit("a")
+				select {
+				case wake <- struct{}{}:
+				default:
+				}
+			}
+		}()
+	}
+
+	var ids []int64
+	timeout := time.After(10 * time.Second)
+	for len(ids) < writers*eventsPerWriter {
+		select {
+		case event, ok := <-sub.Events():
+			require.True(t, ok, "subscription closed after %d events", len(ids))
+			ids = append(ids, event.ID)
+		case <-timeout:
+			require.Fail(t, "events not delivered", "got %d of %d", len(ids), writers*eventsPerWriter)
+		}
+	}
+	wg.Wait()
+
+	for i, id := range ids {
+		assert.Equal(t, int64(i+1), id)
+	}
+}
@@ -0,0 +1,358 @@
+// Package jsonschema validates JSON documents against the subset of JSON Schema used by the API's
+// OpenAPI document: type, properties, required, additionalProperties, items, enum, string and array
+// length bounds, numeric bounds, pattern, the uri and date-time formats, and local $refs.
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidJSON is returned by Validate when the document is not well-formed JSON.
+var ErrInvalidJSON = errors.New("invalid JSON format")
+
+// refPrefix is the only $ref form supported: a schema in the OpenAPI document's components.
+const refPrefix = "#/components/schemas/"
+
+// Schema is a compiled JSON Schema.
+type Schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 Types              `json:"type"`
+	Properties           map[string]*Schema `json:"properties"`
+	Required             []string           `json:"required"`
+	AdditionalProperties *Additional        `json:"additionalProperties"`
+	Items                *Schema            `json:"items"`
+	Enum                 []any              `json:"enum"`
+	MinLength            *int               `json:"minLength"`
+	MaxLength            *int               `json:"maxLength"`
+	MinItems             *int               `json:"minItems"`
+	MaxItems             *int               `json:"maxItems"`
+	Minimum              *float64           `json:"minimum"`
+	Maximum              *float64           `json:"maximum"`
+	Pattern              string             `json:"pattern"`
+	Format               string             `json:"format"`
+
+	pattern *regexp.Regexp
+	ref     *Schema
+}
+
+// Types is the value of type: a single type name or a list of allowed type names.
+type Types []string
+
+// UnmarshalJSON accepts either a type name or an array of type names.
+func (t *Types) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*t = Types{name}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(t))
+}
+
+// Additional is the value of additionalProperties: either a boolean or a schema for extra properties.
+type Additional struct {
+	Allowed bool
+	Schema  *Schema
+}
+
+// UnmarshalJSON accepts either a boolean or a schema object.
+func (a *Additional) UnmarshalJSON(data []byte) error {
+	if b, err := strconv.ParseBool(string(bytes.TrimSpace(data))); err == nil {
+		a.Allowed = b
+		return nil
+	}
+	a.Allowed = true
+	return json.Unmarshal(data, &a.Schema)
+}
+
+// FieldError describes one place where a document does not match its schema.
+type FieldError struct {
+	// Field is the path to the offending value, e.g. "scopes[1]"; empty for the document itself.
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Compile parses the named schemas, resolves $refs between them and compiles patterns.
+func Compile(defs map[string]json.RawMessage) (map[string]*Schema, error) {
+	schemas := make(map[string]*Schema, len(defs))
+	for name, raw := range defs {
+		var s Schema
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil, fmt.Errorf("schema %s: %w", name, err)
+		}
+		schemas[name] = &s
+	}
+	for name, s := range schemas {
+		if err := s.compile(schemas); err != nil {
+			return nil, fmt.Errorf("schema %s: %w", name, err)
+		}
+	}
+	return schemas, nil
+}
+
+// compile resolves references and compiles patterns throughout the schema.
+func (s *Schema) compile(schemas map[string]*Schema) error {
+	if s.Ref != "" {
+		name, ok := strings.CutPrefix(s.Ref, refPrefix)
+		if !ok || schemas[name] == nil {
+			return fmt.Errorf("unresolved $ref %q", s.Ref)
+		}
+		s.ref = schemas[name]
+		return nil
+	}
+	if s.Pattern != "" {
+		re, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", s.Pattern, err)
+		}
+		s.pattern = re
+	}
+	children := make([]*Schema, 0, len(s.Properties)+2)
+	for _, child := range s.Properties {
+		children = append(children, child)
+	}
+	children = append(children, s.Items)
+	if s.AdditionalProperties != nil {
+		children = append(children, s.AdditionalProperties.Schema)
+	}
+	for _, child := range children {
+		if child == nil {
+			continue
+		}
+		if err := child.compile(schemas); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate decodes data and returns every place it does not match the schema.
+// It returns ErrInvalidJSON if data is not a single well-formed JSON value.
+func (s *Schema) Validate(data []byte) ([]FieldError, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, ErrInvalidJSON
+	}
+	if _, err := dec.Token(); err == nil {
+		return nil, ErrInvalidJSON
+	}
+
+	var errs []FieldError
+	s.validate("", v, &errs)
+	return errs, nil
+}
+
+// validate appends the errors for value v at path to errs.
+func (s *Schema) validate(path string, v any, errs *[]FieldError) {
+	if s.ref != nil {
+		s.ref.validate(path, v, errs)
+		return
+	}
+	add := func(format string, args ...any) {
+		*errs = append(*errs, FieldError{Field: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if len(s.Type) > 0 && !s.Type.match(v) {
+		add("must be %s", s.Type)
+		return
+	}
+	if len(s.Enum) > 0 && !inEnum(v, s.Enum) {
+		add("must be one of %s", enumList(s.Enum))
+		return
+	}
+
+	switch v := v.(type) {
+	case string:
+		s.validateString(v, add)
+	case json.Number:
+		f, _ := v.Float64()
+		if s.Minimum != nil && f < *s.Minimum {
+			add("must be at least %v", *s.Minimum)
+		}
+		if s.Maximum != nil && f > *s.Maximum {
+			add("must be at most %v", *s.Maximum)
+		}
+	case []any:
+		if s.MinItems != nil && len(v) < *s.MinItems {
+			add("must have at least %d items", *s.MinItems)
+		}
+		if s.MaxItems != nil && len(v) > *s.MaxItems {
+			add("must have at most %d items", *s.MaxItems)
+		}
+		if s.Items != nil {
+			for i, item := range v {
+				s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, errs)
+			}
+		}
+	case map[string]any:
+		s.validateObject(path, v, errs)
+	}
+}
+
+// validateString checks string length, pattern and format.
+func (s *Schema) validateString(v string, add func(string, ...any)) {
+	n := len([]rune(v))
+	if s.MinLength != nil && n < *s.MinLength {
+		if *s.MinLength == 1 {
+			add("must not be empty")
+		} else {
+			add("must be at least %d characters", *s.MinLength)
+		}
+	}
+	if s.MaxLength != nil && n > *s.MaxLength {
+		add("must be at most %d characters", *s.MaxLength)
+	}
+	if s.pattern != nil && !s.pattern.MatchString(v) {
+		add("must match pattern %s", s.Pattern)
+	}
+	switch s.Format {
+	case "uri":
+		if u, err := url.Parse(v); err != nil || !u.IsAbs() {
+			add("must be an absolute URI")
+		}
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, v); err != nil {
+			add("must be an RFC 3339 date-time")
+		}
+	}
+}
+
+// validateObject checks required, declared and additional properties, in a stable order.
+func (s *Schema) validateObject(path string, v map[string]any, errs *[]FieldError) {
+	for _, name := range s.Required {
+		if _, ok := v[name]; !ok {
+			*errs = append(*errs, FieldError{Field: joinPath(path, name), Message: "is required"})
+		}
+	}
+
+	names := make([]string, 0, len(v))
+	for name := range v {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if prop, ok := s.Properties[name]; ok {
+			prop.validate(joinPath(path, name), v[name], errs)
+			continue
+		}
+		switch {
+		case s.AdditionalProperties == nil || (s.AdditionalProperties.Allowed && s.AdditionalProperties.Schema == nil):
+		case !s.AdditionalProperties.Allowed:
+			*errs = append(*errs, FieldError{Field: joinPath(path, name), Message: "is not a known field"})
+		default:
+			s.AdditionalProperties.Schema.validate(joinPath(path, name), v[name], errs)
+		}
+	}
+}
+
+// match reports whether v is of any of the types.
+func (t Types) match(v any) bool {
+	for _, name := range t {
+		if hasType(v, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// String describes the types for an error message, e.g. "a string or null".
+func (t Types) String() string {
+	parts := make([]string, len(t))
+	for i, name := range t {
+		parts[i] = article(name)
+	}
+	return strings.Join(parts, " or ")
+}
+
+// hasType reports whether v is of the JSON Schema type t.
+func hasType(v any, t string) bool {
+	switch t {
+	case "object":
+		_, ok := v.(map[string]any)
+		return ok
+	case "array":
+		_, ok := v.([]any)
+		return ok
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "number":
+		_, ok := v.(json.Number)
+		return ok
+	case "integer":
+		n, ok := v.(json.Number)
+		if !ok {
+			return false
+		}
+		_, err := n.Int64()
+		return err == nil
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "null":
+		return v == nil
+	}
+	return true
+}
+
+// inEnum reports whether v equals one of the allowed values.
+func inEnum(v any, values []any) bool {
+	for _, allowed := range values {
+		switch a := allowed.(type) {
+		case float64:
+			if n, ok := v.(json.Number); ok {
+				if f, err := n.Float64(); err == nil && f == a {
+					return true
+				}
+			}
+		default:
+			if v == allowed {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// enumList formats the allowed values for an error message.
+func enumList(values []any) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		if v == nil {
+			parts[i] = "null"
+			continue
+		}
+		parts[i] = fmt.Sprintf("%q", fmt.Sprint(v))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// article prefixes a type name with "a" or "an"; null is left bare.
+func article(t string) string {
+	switch t {
+	case "null":
+		return t
+	case "object", "array", "integer":
+		return "an " + t
+	}
+	return "a " + t
+}
+
+// joinPath appends a property name to a field path.
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
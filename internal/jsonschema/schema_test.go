@@ -0,0 +1,125 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSchemas exercises every supported keyword, including a $ref and a free-form object.
+var testSchemas = map[string]json.RawMessage{
+	"Transfer": json.RawMessage(`{
+		"type": "object",
+		"additionalProperties": false,
+		"required": ["id", "amount"],
+		"properties": {
+			"id": {"type": "string", "minLength": 1, "maxLength": 8, "pattern": "^[a-z0-9-]+$"},
+			"amount": {"type": "string"},
+			"count": {"type": "integer", "minimum": 1, "maximum": 10},
+			"kind": {"type": "string", "enum": ["internal", "external"]},
+			"tags": {"type": "array", "minItems": 1, "maxItems": 2, "items": {"type": "string"}},
+			"callback": {"type": "string", "format": "uri"},
+			"note": {"type": ["string", "null"], "maxLength": 4},
+			"at": {"type": "string", "format": "date-time"},
+			"party": {"$ref": "#/components/schemas/Party"},
+			"metadata": {"type": "object", "additionalProperties": {"type": "string"}}
+		}
+	}`),
+	"Party": json.RawMessage(`{"type": "object", "required": ["name"], "properties": {"name": {"type": "string"}}}`),
+}
+
+// TestValidate validates documents against the compiled schemas, checking every reported field error.
+func TestValidate(t *testing.T) {
+	schemas, err := Compile(testSchemas)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		body        string
+		expected    []FieldError
+		expectedErr error
+	}{
+		{
+			name: "valid",
+			body: `{"id":"t-1","amount":"10.5","count":3,"kind":"internal","tags":["a"],"callback":"https://example.com/cb",
+				"at":"2025-12-01T12:00:00Z","note":null,"party":{"name":"acme","extra":true},"metadata":{"k":"v"}}`,
+		},
+		{
+			name:     "missing required fields",
+			body:     `{}`,
+			expected: []FieldError{{Field: "id", Message: "is required"}, {Field: "amount", Message: "is required"}},
+		},
+		{
+			name:     "unknown field",
+			body:     `{"id":"t-1","amount":"1","amout":"1"}`,
+			expected: []FieldError{{Field: "amout", Message: "is not a known field"}},
+		},
+		{
+			name: "wrong types",
+			body: `{"id":7,"amount":null,"count":1.5,"note":1,"tags":"a"}`,
+			expected: []FieldError{
+				{Field: "amount", Message: "must be a string"},
+				{Field: "count", Message: "must be an integer"},
+				{Field: "id", Message: "must be a string"},
+				{Field: "note", Message: "must be a string or null"},
+				{Field: "tags", Message: "must be an array"},
+			},
+		},
+		{
+			name: "bounds, enum, pattern and formats",
+			body: `{"id":"","amount":"1","count":11,"kind":"other","tags":["a","b","c"],"callback":"/relative","at":"yesterday"}`,
+			expected: []FieldError{
+				{Field: "at", Message: "must be an RFC 3339 date-time"},
+				{Field: "callback", Message: "must be an absolute URI"},
+				{Field: "count", Message: "must be at most 10"},
+				{Field: "id", Message: "must not be empty"},
+				{Field: "id", Message: "must match pattern ^[a-z0-9-]+$"},
+				{Field: "kind", Message: `must be one of "internal", "external"`},
+				{Field: "tags", Message: "must have at most 2 items"},
+			},
+		},
+		{
+			name: "nested paths",
+			body: `{"id":"t-1","amount":"1","tags":[1],"party":{},"metadata":{"k":1}}`,
+			expected: []FieldError{
+				{Field: "metadata.k", Message: "must be a string"},
+				{Field: "party.name", Message: "is required"},
+				{Field: "tags[0]", Message: "must be a string"},
+			},
+		},
+		{
+			name:     "not an object",
+			body:     `[]`,
+			expected: []FieldError{{Field: "", Message: "must be an object"}},
+		},
+		{
+			name:        "malformed json",
+			body:        `{"id":`,
+			expectedErr: ErrInvalidJSON,
+		},
+		{
+			name:        "trailing data",
+			body:        `{"id":"t-1","amount":"1"} {}`,
+			expectedErr: ErrInvalidJSON,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			errs, err := schemas["Transfer"].Validate([]byte(tc.body))
+			assert.Equal(t, tc.expectedErr, err)
+			assert.Equal(t, tc.expected, errs)
+		})
+	}
+}
+
+// TestCompileErrors verifies unresolved references and invalid patterns are rejected.
+func TestCompileErrors(t *testing.T) {
+	_, err := Compile(map[string]json.RawMessage{"A": json.RawMessage(`{"properties":{"b":{"$ref":"#/components/schemas/B"}}}`)})
+	assert.ErrorContains(t, err, "unresolved $ref")
+
+	_, err = Compile(map[string]json.RawMessage{"A": json.RawMessage(`{"type":"string","pattern":"("}`)})
+	assert.ErrorContains(t, err, "invalid pattern")
+}
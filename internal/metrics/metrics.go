@@ -0,0 +1,126 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// latencyBuckets are the upper bounds, in seconds, of the request latency histogram.
+var latencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5}
+
+// Recorder receives observations about completed HTTP requests.
+type Recorder interface {
+	ObserveHTTPRequest(method, route string, status int, size int64, latency time.Duration)
+}
+
+// requestKey identifies a series of HTTP request observations.
+type requestKey struct {
+	method string
+	route  string
+	status int
+}
+
+// requestStats accumulates observations for a single requestKey.
+type requestStats struct {
+	count        uint64
+	bytes        int64
+	latencySum   float64
+	bucketCounts []uint64
+}
+
+// Registry is an in-process Recorder that exposes its data in the Prometheus text format.
+type Registry struct {
+	mu       sync.Mutex
+	requests map[requestKey]*requestStats
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		requests: make(map[requestKey]*requestStats),
+	}
+}
+
+// ObserveHTTPRequest records a completed HTTP request.
+func (r *Registry) ObserveHTTPRequest(method, route string, status int, size int64, latency time.Duration) {
+	key := requestKey{method: method, route: route, status: status}
+	seconds := latency.Seconds()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats, ok := r.requests[key]
+	if !ok {
+		stats = &requestStats{bucketCounts: make([]uint64, len(latencyBuckets))}
+		r.requests[key] = stats
+	}
+	stats.count++
+	stats.bytes += size
+	stats.latencySum += seconds
+	for i, bound := range latencyBuckets {
+		if seconds <= bound {
+			stats.bucketCounts[i]++
+		}
+	}
+}
+
+// WritePrometheus writes all recorded series to w in the Prometheus text exposition format.
+func (r *Registry) WritePrometheus(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	keys := make([]requestKey, 0, len(r.requests))
+	for k := range r.requests {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].route != keys[j].route {
+			return keys[i].route < keys[j].route
+		}
+		if keys[i].method != keys[j].method {
+			return keys[i].method < keys[j].method
+		}
+		return keys[i].status < keys[j].status
+	})
+
+	var b []byte
+	b = append(b, "# TYPE http_requests_total counter\n"...)
+	for _, k := range keys {
+		b = fmt.Appendf(b, "http_requests_total{%s} %d\n", labels(k), r.requests[k].count)
+	}
+	b = append(b, "# TYPE http_response_size_bytes_total counter\n"...)
+	for _, k := range keys {
+		b = fmt.Appendf(b, "http_response_size_bytes_total{%s} %d\n", labels(k), r.requests[k].bytes)
+	}
+	b = append(b, "# TYPE http_request_duration_seconds histogram\n"...)
+	for _, k := range keys {
+		stats := r.requests[k]
+		for i, bound := range latencyBuckets {
+			b = fmt.Appendf(b, "http_request_duration_seconds_bucket{%s,le=\"%s\"} %d\n", labels(k), strconv.FormatFloat(bound, 'g', -1, 64), stats.bucketCounts[i])
+		}
+		b = fmt.Appendf(b, "http_request_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", labels(k), stats.count)
+		b = fmt.Appendf(b, "http_request_duration_seconds_sum{%s} %s\n", labels(k), strconv.FormatFloat(stats.latencySum, 'g', -1, 64))
+		b = fmt.Appendf(b, "http_request_duration_seconds_count{%s} %d\n", labels(k), stats.count)
+	}
+
+	_, err := w.Write(b)
+	return err
+}
+
+// Handler returns an http.Handler that serves the registry in the Prometheus text format.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		_ = r.WritePrometheus(w)
+	})
+}
+
+// labels renders the label set for a requestKey.
+func labels(k requestKey) string {
+	return fmt.Sprintf("method=%q,route=%q,status=\"%d\"", k.method, k.route, k.status)
+}
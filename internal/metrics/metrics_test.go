@@ -0,0 +1,28 @@
+package metrics
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRegistryWritePrometheus validates counters and histogram buckets in the exposition output.
+func TestRegistryWritePrometheus(t *testing.T) {
+	r := NewRegistry()
+	r.ObserveHTTPRequest("GET", "/accounts/{accountID}", 200, 40, 20*time.Millisecond)
+	r.ObserveHTTPRequest("GET", "/accounts/{accountID}", 200, 60, 200*time.Millisecond)
+	r.ObserveHTTPRequest("POST", "/transactions", 500, 10, time.Millisecond)
+
+	var buf bytes.Buffer
+	assert.NoError(t, r.WritePrometheus(&buf))
+	out := buf.String()
+
+	assert.Contains(t, out, `http_requests_total{method="GET",route="/accounts/{accountID}",status="200"} 2`)
+	assert.Contains(t, out, `http_requests_total{method="POST",route="/transactions",status="500"} 1`)
+	assert.Contains(t, out, `http_response_size_bytes_total{method="GET",route="/accounts/{accountID}",status="200"} 100`)
+	assert.Contains(t, out, `http_request_duration_seconds_bucket{method="GET",route="/accounts/{accountID}",status="200",le="0.025"} 1`)
+	assert.Contains(t, out, `http_request_duration_seconds_bucket{method="GET",route="/accounts/{accountID}",status="200",le="0.25"} 2`)
+	assert.Contains(t, out, `http_request_duration_seconds_count{method="POST",route="/transactions",status="500"} 1`)
+}
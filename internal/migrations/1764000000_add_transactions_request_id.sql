@@ -0,0 +1,7 @@
+-- Adds the request_id column to transactions so a ledger entry can be traced
+-- back to the HTTP request (X-Request-ID) that created it.
+-- Run this against the local Postgres instance (see docker-compose.local.yml).
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS request_id TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_transactions_request_id ON transactions (request_id);
@@ -0,0 +1,17 @@
+-- Creates the api_keys table used to authenticate service-to-service callers.
+-- Only the SHA-256 hash of each key is stored; the plaintext is shown once at creation.
+-- scopes lists the permissions granted to the key, e.g. accounts:read
+-- revoked_at is set when a key is revoked; revoked keys are rejected
+-- Adds client_id to transactions so each transfer records the authenticated caller.
+-- Run this against the local Postgres instance (see docker-compose.local.yml).
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id TEXT PRIMARY KEY,
+    client_id TEXT NOT NULL,
+    key_hash TEXT NOT NULL UNIQUE,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at TIMESTAMP
+);
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS client_id TEXT;
@@ -0,0 +1,29 @@
+-- Creates the append-only audit_events table recording every state change.
+-- Each row stores the SHA-256 hash of its content chained with the previous row's hash,
+-- so altering or deleting any row breaks the chain from that point on.
+-- payload is stored as TEXT so the hashed bytes are preserved exactly.
+-- A trigger rejects UPDATE and DELETE to keep the table append-only.
+-- Run this against the local Postgres instance (see docker-compose.local.yml).
+
+CREATE TABLE IF NOT EXISTS audit_events (
+    id BIGSERIAL PRIMARY KEY,
+    event_type TEXT NOT NULL,
+    entity_id TEXT NOT NULL,
+    actor TEXT NOT NULL DEFAULT '',
+    request_id TEXT NOT NULL DEFAULT '',
+    payload TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    prev_hash TEXT NOT NULL,
+    hash TEXT NOT NULL UNIQUE
+);
+
+CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_events_append_only ON audit_events;
+CREATE TRIGGER audit_events_append_only
+    BEFORE UPDATE OR DELETE ON audit_events
+    FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
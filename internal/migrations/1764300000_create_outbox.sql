@@ -0,0 +1,17 @@
+-- Creates the outbox table holding domain events written in the same transaction as the change they describe.
+-- account_ids lists the accounts an event affects; the relay publishes events in id order per account.
+-- published_at is set once the relay has delivered the event; attempts and last_error track failed deliveries.
+-- Run this against the local Postgres instance (see docker-compose.local.yml).
+
+CREATE TABLE IF NOT EXISTS outbox (
+    id BIGSERIAL PRIMARY KEY,
+    event_type TEXT NOT NULL,
+    account_ids TEXT[] NOT NULL DEFAULT '{}',
+    payload TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    published_at TIMESTAMPTZ,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_unpublished ON outbox (id) WHERE published_at IS NULL;
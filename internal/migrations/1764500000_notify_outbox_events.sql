@@ -0,0 +1,18 @@
+-- Notifies listeners on the outbox_events channel when an outbox row is inserted.
+-- Notifications are delivered on commit, so listeners only see committed events.
+-- The payload is the outbox id; listeners read the event itself from the outbox table.
+-- Run this against the local Postgres instance (see docker-compose.local.yml).
+
+CREATE OR REPLACE FUNCTION outbox_notify() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('outbox_events', NEW.id::text);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS outbox_notify ON outbox;
+CREATE TRIGGER outbox_notify
+    AFTER INSERT ON outbox
+    FOR EACH ROW EXECUTE FUNCTION outbox_notify();
+
+CREATE INDEX IF NOT EXISTS idx_outbox_account_ids ON outbox USING GIN (account_ids);
@@ -0,0 +1,6 @@
+-- Indexes transactions by account so an account's history can be listed newest first
+-- without scanning the whole table. Listing unions both sides of the transfer.
+-- Run this against the local Postgres instance (see docker-compose.local.yml).
+
+CREATE INDEX IF NOT EXISTS idx_transactions_source_account ON transactions (source_account_id, id DESC);
+CREATE INDEX IF NOT EXISTS idx_transactions_destination_account ON transactions (destination_account_id, id DESC);
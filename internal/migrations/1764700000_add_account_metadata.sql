@@ -0,0 +1,20 @@
+-- Adds descriptive attributes to accounts: a display name, an owner reference, an account type,
+-- free-form labels and a JSON metadata object. version is bumped on every PATCH so clients can
+-- update with optimistic concurrency; existing accounts start at version 1.
+-- Run this against the local Postgres instance (see docker-compose.local.yml).
+
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS display_name TEXT NOT NULL DEFAULT '';
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS owner_ref TEXT NOT NULL DEFAULT '';
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS account_type TEXT NOT NULL DEFAULT '';
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS labels TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS metadata JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT now();
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+ALTER TABLE accounts DROP CONSTRAINT IF EXISTS accounts_account_type_check;
+ALTER TABLE accounts ADD CONSTRAINT accounts_account_type_check
+    CHECK (account_type IN ('', 'operating', 'settlement', 'fee', 'customer'));
+ALTER TABLE accounts DROP CONSTRAINT IF EXISTS accounts_metadata_object_check;
+ALTER TABLE accounts ADD CONSTRAINT accounts_metadata_object_check
+    CHECK (jsonb_typeof(metadata) = 'object');
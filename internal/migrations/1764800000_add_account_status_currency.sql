@@ -0,0 +1,19 @@
+-- Adds an account status and currency, and indexes the columns accounts are listed by.
+-- Existing accounts are active and in USD, the currency the service assumed until now.
+-- Listing pages by (sort key, id), so the sort indexes include id.
+-- Run this against the local Postgres instance (see docker-compose.local.yml).
+
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'active';
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'USD';
+
+ALTER TABLE accounts DROP CONSTRAINT IF EXISTS accounts_status_check;
+ALTER TABLE accounts ADD CONSTRAINT accounts_status_check CHECK (status IN ('active', 'closed'));
+ALTER TABLE accounts DROP CONSTRAINT IF EXISTS accounts_currency_check;
+ALTER TABLE accounts ADD CONSTRAINT accounts_currency_check CHECK (currency ~ '^[A-Z]{3}$');
+
+CREATE INDEX IF NOT EXISTS idx_accounts_created_at ON accounts (created_at, id);
+CREATE INDEX IF NOT EXISTS idx_accounts_balance ON accounts (balance, id);
+CREATE INDEX IF NOT EXISTS idx_accounts_account_type ON accounts (account_type);
+CREATE INDEX IF NOT EXISTS idx_accounts_status ON accounts (status);
+CREATE INDEX IF NOT EXISTS idx_accounts_currency ON accounts (currency);
+CREATE INDEX IF NOT EXISTS idx_accounts_labels ON accounts USING GIN (labels);
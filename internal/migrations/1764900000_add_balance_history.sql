@@ -0,0 +1,50 @@
+-- Supports point-in-time balance queries: an account's balance at any time is its opening balance,
+-- or its latest snapshot before that time, plus the transfers since.
+-- Transfer times become TIMESTAMPTZ; existing values are taken to be UTC, the server's time zone.
+-- Accounts that predate created_at tracking get the time of their first transfer if it is earlier.
+-- Run this against the local Postgres instance (see docker-compose.local.yml).
+
+DO $$
+BEGIN
+    IF (SELECT data_type FROM information_schema.columns
+        WHERE table_name = 'transactions' AND column_name = 'created_at') = 'timestamp without time zone' THEN
+        ALTER TABLE transactions ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'UTC';
+    END IF;
+END;
+$$;
+ALTER TABLE transactions ALTER COLUMN created_at SET DEFAULT now();
+
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS opening_balance NUMERIC(23, 5);
+
+-- The opening balance is the current balance with every transfer undone.
+UPDATE accounts a
+SET opening_balance = a.balance
+    - COALESCE((SELECT SUM(amount) FROM transactions WHERE destination_account_id = a.id), 0)
+    + COALESCE((SELECT SUM(amount) FROM transactions WHERE source_account_id = a.id), 0)
+WHERE a.opening_balance IS NULL;
+
+ALTER TABLE accounts ALTER COLUMN opening_balance SET NOT NULL;
+
+UPDATE accounts a
+SET created_at = t.first_at
+FROM (
+    SELECT account_id, MIN(created_at) AS first_at
+    FROM (
+        SELECT source_account_id AS account_id, created_at FROM transactions
+        UNION ALL
+        SELECT destination_account_id, created_at FROM transactions
+    ) moves
+    GROUP BY account_id
+) t
+WHERE t.account_id = a.id AND t.first_at < a.created_at;
+
+CREATE INDEX IF NOT EXISTS idx_transactions_source_account_created_at ON transactions (source_account_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_transactions_destination_account_created_at ON transactions (destination_account_id, created_at);
+
+CREATE TABLE IF NOT EXISTS account_balance_snapshots (
+    account_id TEXT NOT NULL REFERENCES accounts(id) ON DELETE RESTRICT,
+    as_of TIMESTAMPTZ NOT NULL,
+    balance NUMERIC(23, 5) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (account_id, as_of)
+);
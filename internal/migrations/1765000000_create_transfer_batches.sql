@@ -0,0 +1,13 @@
+-- Creates the transfer_batches table recording the transfer batches, such as pain.001 payment files,
+-- that have been executed, so a batch uploaded twice is not paid twice.
+-- batch_id is the client's identifier of the batch, unique per client; client_id is empty for batches
+-- imported with transfersctl.
+-- Run this against the local Postgres instance (see docker-compose.local.yml).
+
+CREATE TABLE IF NOT EXISTS transfer_batches (
+    client_id TEXT NOT NULL DEFAULT '',
+    batch_id TEXT NOT NULL,
+    transfer_count INT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (client_id, batch_id)
+);
@@ -0,0 +1,8 @@
+-- Rejects TRUNCATE on audit_events. Row triggers do not fire for TRUNCATE, so without this
+-- statement trigger the whole chain could be wiped, leaving an empty chain that still verifies.
+-- Run this against the local Postgres instance (see docker-compose.local.yml).
+
+DROP TRIGGER IF EXISTS audit_events_no_truncate ON audit_events;
+CREATE TRIGGER audit_events_no_truncate
+    BEFORE TRUNCATE ON audit_events
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_events_append_only();
@@ -13,14 +13,35 @@ import (
 //go:embed *.sql
 var migrationFiles embed.FS
 
+const (
+	// Query to create the table tracking applied migrations
+	createMigrationsTableQuery = `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			name TEXT PRIMARY KEY,
+			applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`
+	// Query to list applied migrations
+	appliedMigrationsQuery = `
+		SELECT name
+		FROM schema_migrations
+	`
+	// Query to record an applied migration
+	recordMigrationQuery = `
+		INSERT INTO schema_migrations (name)
+		VALUES ($1)
+	`
+)
+
 // migration represents a single DB migration.
 type migration struct {
 	name string
 	sql  string
 }
 
-// Run applies all migrations to the database.
-// It reads migration files from the embedded filesystem and executes them in order.
+// Run applies all pending migrations to the database.
+// It reads migration files from the embedded filesystem and executes them in order,
+// recording each applied migration in the schema_migrations table.
 // If any migration fails, it returns an error.
 func Run(ctx context.Context, db *sql.DB, logger *zap.Logger) error {
 	migs, err := loadMigrations()
@@ -28,9 +49,21 @@ func Run(ctx context.Context, db *sql.DB, logger *zap.Logger) error {
 		return err
 	}
 
+	if _, err := db.ExecContext(ctx, createMigrationsTableQuery); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	applied, err := appliedMigrations(ctx, db)
+	if err != nil {
+		return err
+	}
+
 	for _, m := range migs {
+		if applied[m.name] {
+			continue
+		}
 		logger.Info("applying migration", zap.String("migration", m.name))
-		if _, err := db.ExecContext(ctx, m.sql); err != nil {
+		if err := apply(ctx, db, m); err != nil {
 			logger.Error("migration failed", zap.String("migration", m.name), zap.Error(err))
 			return fmt.Errorf("failed to apply migration %s: %w", m.name, err)
 		}
@@ -39,6 +72,69 @@ func Run(ctx context.Context, db *sql.DB, logger *zap.Logger) error {
 	return nil
 }
 
+// Pending returns the names of embedded migrations that have not been applied to the database.
+// An empty slice means the schema is current.
+func Pending(ctx context.Context, db *sql.DB) ([]string, error) {
+	migs, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := appliedMigrations(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := make([]string, 0)
+	for _, m := range migs {
+		if !applied[m.name] {
+			pending = append(pending, m.name)
+		}
+	}
+	return pending, nil
+}
+
+// apply executes a single migration and records it within one DB transaction.
+func apply(ctx context.Context, db *sql.DB, m migration) (err error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	if _, err = tx.ExecContext(ctx, m.sql); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, recordMigrationQuery, m.name)
+	return err
+}
+
+// appliedMigrations returns the set of migration names recorded in schema_migrations.
+func appliedMigrations(ctx context.Context, db *sql.DB) (map[string]bool, error) {
+	rows, err := db.QueryContext(ctx, appliedMigrationsQuery)
+	if err != nil {
+		return nil, fmt.Errorf("read applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("read applied migrations: %w", err)
+		}
+		applied[name] = true
+	}
+	return applied, rows.Err()
+}
+
 // loadMigrations reads migration files from the embedded filesystem
 // and returns a slice of migration structs.
 // If reading fails, it returns an error.
@@ -0,0 +1,167 @@
+// Package outbox relays events written to the transactional outbox to downstream consumers.
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/config"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+	"go.uber.org/zap"
+)
+
+// Publisher delivers a single outbox event. Publish must return an error unless the event was accepted;
+// failed events are retried, so consumers should deduplicate on the event ID.
+type Publisher interface {
+	Publish(ctx context.Context, event storage.OutboxEvent) error
+}
+
+// NewPublisher builds the publisher selected in the outbox configuration.
+func NewPublisher(cfg *config.OutboxConfig, logger *zap.Logger) (Publisher, error) {
+	switch cfg.Publisher {
+	case "", "log":
+		return NewLogPublisher(logger), nil
+	case "file":
+		return NewFilePublisher(cfg.FilePath)
+	case "webhook":
+		return NewWebhookPublisher(cfg.WebhookURL, cfg.WebhookTimeout)
+	default:
+		return nil, fmt.Errorf("unknown outbox publisher %q", cfg.Publisher)
+	}
+}
+
+// fanout publishes each event through several publishers.
+type fanout []Publisher
+
+// Fanout returns a Publisher that hands each event to every publisher in turn. The event fails if any
+// publisher fails, and is then retried on all of them.
+func Fanout(publishers ...Publisher) Publisher {
+	return fanout(publishers)
+}
+
+// Publish publishes the event through every publisher and joins their errors.
+func (f fanout) Publish(ctx context.Context, event storage.OutboxEvent) error {
+	var errs []error
+	for _, p := range f {
+		if err := p.Publish(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// LogPublisher writes each event to the logger. It is meant for local development.
+type LogPublisher struct {
+	logger *zap.Logger
+}
+
+// NewLogPublisher creates a LogPublisher writing to logger.
+func NewLogPublisher(logger *zap.Logger) *LogPublisher {
+	return &LogPublisher{logger: logger}
+}
+
+// Publish logs the event.
+func (p *LogPublisher) Publish(_ context.Context, event storage.OutboxEvent) error {
+	p.logger.Info("outbox event",
+		zap.Int64("event_id", event.ID),
+		zap.String("event_type", event.EventType),
+		zap.Strings("account_ids", event.AccountIDs),
+		zap.ByteString("payload", event.Payload),
+	)
+	return nil
+}
+
+// FilePublisher appends each event to a file as one JSON document per line.
+type FilePublisher struct {
+	mu sync.Mutex
+	w  io.WriteCloser
+}
+
+// NewFilePublisher opens path for appending, creating it if needed.
+func NewFilePublisher(path string) (*FilePublisher, error) {
+	if path == "" {
+		return nil, fmt.Errorf("outbox file publisher requires a file path")
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open outbox file: %w", err)
+	}
+	return &FilePublisher{w: f}, nil
+}
+
+// Publish appends the event as a JSON line.
+func (p *FilePublisher) Publish(_ context.Context, event storage.OutboxEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshal event: %w", err)
+	}
+	line = append(line, '\n')
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, err := p.w.Write(line); err != nil {
+		return fmt.Errorf("write event: %w", err)
+	}
+	return nil
+}
+
+// Close closes the underlying file.
+func (p *FilePublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.w.Close()
+}
+
+// WebhookPublisher POSTs each event as JSON to a fixed URL. Any non-2xx response is a failed delivery.
+type WebhookPublisher struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookPublisher creates a WebhookPublisher posting to url with the given request timeout.
+func NewWebhookPublisher(url string, timeout time.Duration) (*WebhookPublisher, error) {
+	if url == "" {
+		return nil, fmt.Errorf("outbox webhook publisher requires a url")
+	}
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	return &WebhookPublisher{url: url, client: &http.Client{Timeout: timeout}}, nil
+}
+
+// Publish posts the event. The X-Event-ID header lets receivers deduplicate redeliveries.
+func (p *WebhookPublisher) Publish(ctx context.Context, event storage.OutboxEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshal event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-ID", strconv.FormatInt(event.ID, 10))
+	req.Header.Set("X-Event-Type", event.EventType)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("deliver webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
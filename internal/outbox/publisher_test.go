@@ -0,0 +1,126 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/config"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var testEvent = storage.OutboxEvent{
+	ID:         42,
+	EventType:  storage.EventTransferCompleted,
+	AccountIDs: []string{"a", "b"},
+	Payload:    json.RawMessage(`{"amount":"10"}`),
+	CreatedAt:  time.Date(2025, 12, 1, 12, 0, 0, 0, time.UTC),
+}
+
+// TestNewPublisher validates publisher selection from config.
+func TestNewPublisher(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         config.OutboxConfig
+		expectedErr bool
+	}{
+		{name: "default log", cfg: config.OutboxConfig{}},
+		{name: "file", cfg: config.OutboxConfig{Publisher: "file", FilePath: filepath.Join(t.TempDir(), "events.jsonl")}},
+		{name: "file without path", cfg: config.OutboxConfig{Publisher: "file"}, expectedErr: true},
+		{name: "webhook", cfg: config.OutboxConfig{Publisher: "webhook", WebhookURL: "http://localhost:9000/events"}},
+		{name: "webhook without url", cfg: config.OutboxConfig{Publisher: "webhook"}, expectedErr: true},
+		{name: "unknown", cfg: config.OutboxConfig{Publisher: "kafka"}, expectedErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			publisher, err := NewPublisher(&tc.cfg, zap.NewNop())
+			if tc.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotNil(t, publisher)
+			if fp, ok := publisher.(*FilePublisher); ok {
+				assert.NoError(t, fp.Close())
+			}
+		})
+	}
+}
+
+// TestFilePublisher validates that events are appended as JSON lines.
+func TestFilePublisher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	publisher, err := NewFilePublisher(path)
+	require.NoError(t, err)
+
+	assert.NoError(t, publisher.Publish(context.Background(), testEvent))
+	assert.NoError(t, publisher.Publish(context.Background(), testEvent))
+	assert.NoError(t, publisher.Close())
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	assert.Len(t, lines, 2)
+	assert.JSONEq(t, `{"id":42,"event_type":"transfer.completed","account_ids":["a","b"],"payload":{"amount":"10"},"created_at":"2025-12-01T12:00:00Z"}`, lines[0])
+}
+
+// TestWebhookPublisher validates delivery headers, body and non-2xx failures.
+func TestWebhookPublisher(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		expectedErr bool
+	}{
+		{name: "accepted", status: http.StatusAccepted},
+		{name: "server error", status: http.StatusServiceUnavailable, expectedErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var gotHeaders http.Header
+			var gotBody []byte
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotHeaders = r.Header.Clone()
+				gotBody, _ = io.ReadAll(r.Body)
+				w.WriteHeader(tc.status)
+			}))
+			defer srv.Close()
+
+			publisher, err := NewWebhookPublisher(srv.URL, time.Second)
+			require.NoError(t, err)
+
+			err = publisher.Publish(context.Background(), testEvent)
+
+			if tc.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, "42", gotHeaders.Get("X-Event-ID"))
+			assert.Equal(t, storage.EventTransferCompleted, gotHeaders.Get("X-Event-Type"))
+			assert.Equal(t, "application/json", gotHeaders.Get("Content-Type"))
+			assert.JSONEq(t, `{"id":42,"event_type":"transfer.completed","account_ids":["a","b"],"payload":{"amount":"10"},"created_at":"2025-12-01T12:00:00Z"}`, string(gotBody))
+		})
+	}
+}
+
+// TestFanout validates that every publisher receives the event and failures are reported.
+func TestFanout(t *testing.T) {
+	ok := &fakePublisher{}
+	failing := &fakePublisher{failures: map[int64]bool{testEvent.ID: true}}
+
+	assert.NoError(t, Fanout(ok).Publish(context.Background(), testEvent))
+	assert.Error(t, Fanout(failing, ok).Publish(context.Background(), testEvent))
+	assert.Equal(t, []int64{testEvent.ID, testEvent.ID}, ok.published)
+}
@@ -0,0 +1,129 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/config"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultPollInterval = time.Second
+	defaultBatchSize    = 100
+)
+
+// Store is the subset of storage.Storage the relay needs.
+type Store interface {
+	ListUnpublishedEvents(ctx context.Context, limit int) ([]storage.OutboxEvent, error)
+	MarkEventPublished(ctx context.Context, eventID int64) error
+	MarkEventFailed(ctx context.Context, eventID int64, reason string) error
+}
+
+// Relay polls the outbox and hands unpublished events to a Publisher.
+//
+// Delivery is at-least-once: an event is marked published only after Publish succeeds, so a crash in
+// between redelivers it. Events are published in outbox order per account; when an event fails, later
+// events touching any of its accounts are held back until it succeeds, while other accounts proceed.
+// Only one relay should run against a database at a time.
+type Relay struct {
+	store     Store
+	publisher Publisher
+	interval  time.Duration
+	batchSize int
+	logger    *zap.Logger
+}
+
+// NewRelay creates a Relay reading from store and publishing through publisher.
+func NewRelay(store Store, publisher Publisher, cfg *config.OutboxConfig, logger *zap.Logger) *Relay {
+	r := &Relay{
+		store:     store,
+		publisher: publisher,
+		interval:  cfg.PollInterval,
+		batchSize: cfg.BatchSize,
+		logger:    logger,
+	}
+	if r.interval <= 0 {
+		r.interval = defaultPollInterval
+	}
+	if r.batchSize <= 0 {
+		r.batchSize = defaultBatchSize
+	}
+	return r
+}
+
+// Run publishes pending events every poll interval until ctx is cancelled.
+func (r *Relay) Run(ctx context.Context) {
+	r.logger.Info("outbox relay started", zap.Duration("poll_interval", r.interval), zap.Int("batch_size", r.batchSize))
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.RunOnce(ctx); err != nil {
+			r.logger.Error("outbox relay poll failed", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			r.logger.Info("outbox relay stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce publishes one batch of pending events and returns how many were published.
+func (r *Relay) RunOnce(ctx context.Context) (int, error) {
+	events, err := r.store.ListUnpublishedEvents(ctx, r.batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	blocked := make(map[string]bool)
+	for _, event := range events {
+		if ctx.Err() != nil {
+			return published, ctx.Err()
+		}
+		if touchesAny(event.AccountIDs, blocked) {
+			// Held back events block all of their accounts, so nothing behind them overtakes them.
+			block(event.AccountIDs, blocked)
+			continue
+		}
+
+		logger := r.logger.With(zap.Int64("event_id", event.ID), zap.String("event_type", event.EventType))
+		if err := r.publisher.Publish(ctx, event); err != nil {
+			logger.Warn("failed to publish outbox event", zap.Int("attempts", event.Attempts+1), zap.Error(err))
+			block(event.AccountIDs, blocked)
+			if err := r.store.MarkEventFailed(ctx, event.ID, err.Error()); err != nil {
+				logger.Error("failed to record outbox delivery failure", zap.Error(err))
+			}
+			continue
+		}
+		if err := r.store.MarkEventPublished(ctx, event.ID); err != nil {
+			// The event will be redelivered; hold back its accounts so later events don't overtake it.
+			logger.Error("failed to mark outbox event published", zap.Error(err))
+			block(event.AccountIDs, blocked)
+			continue
+		}
+		published++
+	}
+	return published, nil
+}
+
+// touchesAny reports whether any of accountIDs is in blocked.
+func touchesAny(accountIDs []string, blocked map[string]bool) bool {
+	for _, id := range accountIDs {
+		if blocked[id] {
+			return true
+		}
+	}
+	return false
+}
+
+// block adds accountIDs to blocked.
+func block(accountIDs []string, blocked map[string]bool) {
+	for _, id := range accountIDs {
+		blocked[id] = true
+	}
+}
@@ -0,0 +1,110 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/config"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+// fakePublisher records published event IDs and fails the IDs in failures.
+type fakePublisher struct {
+	published []int64
+	failures  map[int64]bool
+}
+
+func (f *fakePublisher) Publish(_ context.Context, event storage.OutboxEvent) error {
+	if f.failures[event.ID] {
+		return errors.New("downstream unavailable")
+	}
+	f.published = append(f.published, event.ID)
+	return nil
+}
+
+// TestRelayRunOnce validates publishing order, per-account blocking after failures and store errors.
+func TestRelayRunOnce(t *testing.T) {
+	events := []storage.OutboxEvent{
+		{ID: 1, EventType: storage.EventAccountCreated, AccountIDs: []string{"a"}},
+		{ID: 2, EventType: storage.EventAccountCreated, AccountIDs: []string{"b"}},
+		{ID: 3, EventType: storage.EventTransferCompleted, AccountIDs: []string{"a", "c"}},
+		{ID: 4, EventType: storage.EventTransferCompleted, AccountIDs: []string{"c", "b"}},
+		{ID: 5, EventType: storage.EventAccountCreated, AccountIDs: []string{"d"}},
+	}
+
+	tests := []struct {
+		name              string
+		failures          map[int64]bool
+		listErr           error
+		prepare           func(m *mocks.MockStorage)
+		expectedPublished []int64
+		expectedErr       string
+	}{
+		{
+			name: "all published in order",
+			prepare: func(m *mocks.MockStorage) {
+				for _, e := range events {
+					m.EXPECT().MarkEventPublished(gomock.Any(), e.ID).Return(nil)
+				}
+			},
+			expectedPublished: []int64{1, 2, 3, 4, 5},
+		},
+		{
+			name:     "failure holds back later events for the same accounts",
+			failures: map[int64]bool{1: true},
+			prepare: func(m *mocks.MockStorage) {
+				m.EXPECT().MarkEventFailed(gomock.Any(), int64(1), "downstream unavailable").Return(nil)
+				m.EXPECT().MarkEventPublished(gomock.Any(), int64(2)).Return(nil)
+				m.EXPECT().MarkEventPublished(gomock.Any(), int64(5)).Return(nil)
+			},
+			expectedPublished: []int64{2, 5},
+		},
+		{
+			name: "mark published error holds back its accounts",
+			prepare: func(m *mocks.MockStorage) {
+				m.EXPECT().MarkEventPublished(gomock.Any(), int64(1)).Return(nil)
+				m.EXPECT().MarkEventPublished(gomock.Any(), int64(2)).Return(errors.New(storage.ErrUpdateOutboxEventMsg))
+				m.EXPECT().MarkEventPublished(gomock.Any(), int64(3)).Return(nil)
+				m.EXPECT().MarkEventPublished(gomock.Any(), int64(5)).Return(nil)
+			},
+			expectedPublished: []int64{1, 2, 3, 5},
+		},
+		{
+			name:        "list error",
+			listErr:     errors.New(storage.ErrListOutboxEventsMsg),
+			expectedErr: storage.ErrListOutboxEventsMsg,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockStorage := mocks.NewMockStorage(mockCtrl)
+			if tc.listErr != nil {
+				mockStorage.EXPECT().ListUnpublishedEvents(gomock.Any(), 10).Return(nil, tc.listErr)
+			} else {
+				mockStorage.EXPECT().ListUnpublishedEvents(gomock.Any(), 10).Return(events, nil)
+			}
+			if tc.prepare != nil {
+				tc.prepare(mockStorage)
+			}
+
+			publisher := &fakePublisher{failures: tc.failures}
+			relay := NewRelay(mockStorage, publisher, &config.OutboxConfig{BatchSize: 10}, zap.NewNop())
+
+			_, err := relay.RunOnce(context.Background())
+
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedPublished, publisher.published)
+		})
+	}
+}
@@ -0,0 +1,230 @@
+// Package payments reads ISO 20022 pain.001 customer credit transfer initiations and writes the
+// pain.002 payment status reports that answer them.
+package payments
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// pain001Namespace is the XML namespace of ISO 20022 CustomerCreditTransferInitiationV03 documents.
+const pain001Namespace = "urn:iso:std:iso:20022:tech:xsd:pain.001.001.03"
+
+// pain001MessageName is the message name identification of pain.001.001.03, as reported back in pain.002.
+const pain001MessageName = "pain.001.001.03"
+
+// maxAmountPlaces is the number of decimal places amounts in ISO 20022 messages may have.
+const maxAmountPlaces = 5
+
+// MaxTransfers is the largest number of credit transfers one message may carry; they are executed in
+// a single database transaction.
+const MaxTransfers = 1000
+
+// paymentMethodTransfer is the only payment method of credit transfer initiations.
+const paymentMethodTransfer = "TRF"
+
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// Errors describing messages that cannot be processed at all.
+var (
+	ErrMalformedMessage   = errors.New("message is not well-formed XML")
+	ErrUnsupportedMessage = errors.New("message must be a pain.001.001.03 customer credit transfer initiation")
+	ErrMissingMessageID   = errors.New("GrpHdr/MsgId is required")
+	ErrMissingPaymentID   = errors.New("PmtInfId is required")
+	ErrNoPayments         = errors.New("message must contain at least one PmtInf")
+	ErrNoTransfers        = errors.New("PmtInf must contain at least one credit transfer")
+	ErrTooManyTransfers   = fmt.Errorf("message must contain at most %d credit transfers", MaxTransfers)
+	ErrNumberOfTransfers  = errors.New("NbOfTxs must be the number of credit transfers")
+	ErrControlSum         = errors.New("CtrlSum does not match the sum of the instructed amounts")
+	ErrPaymentMethod      = errors.New("PmtMtd must be TRF")
+	ErrExecutionDate      = errors.New("ReqdExctnDt must be a YYYY-MM-DD date")
+	ErrInstructedAmount   = errors.New("InstdAmt must be a non-negative amount with at most 5 decimal places and a three-letter Ccy")
+)
+
+// CreditTransferInitiation is a pain.001 message: payment information blocks, each debiting one
+// account with one or more credit transfers.
+type CreditTransferInitiation struct {
+	MessageID            string
+	NumberOfTransactions int
+	// ControlSum is the declared sum of every instructed amount, nil if the message has none.
+	ControlSum *decimal.Decimal
+	Payments   []PaymentInformation
+}
+
+// PaymentInformation is a group of credit transfers debited from one account.
+type PaymentInformation struct {
+	ID                     string
+	RequestedExecutionDate time.Time
+	// DebtorAccountID is the account debited: its IBAN, or its other identification.
+	DebtorAccountID string
+	ControlSum      *decimal.Decimal
+	Transfers       []CreditTransfer
+}
+
+// CreditTransfer is one instruction to credit an account.
+type CreditTransfer struct {
+	InstructionID string
+	EndToEndID    string
+	Amount        decimal.Decimal
+	Currency      string
+	// CreditorAccountID is the account credited: its IBAN, or its other identification.
+	CreditorAccountID string
+}
+
+type pain001Document struct {
+	XMLName    xml.Name
+	Initiation *struct {
+		GroupHeader struct {
+			MessageID            string `xml:"MsgId"`
+			NumberOfTransactions string `xml:"NbOfTxs"`
+			ControlSum           string `xml:"CtrlSum"`
+		} `xml:"GrpHdr"`
+		Payments []struct {
+			ID                     string         `xml:"PmtInfId"`
+			Method                 string         `xml:"PmtMtd"`
+			NumberOfTransactions   string         `xml:"NbOfTxs"`
+			ControlSum             string         `xml:"CtrlSum"`
+			RequestedExecutionDate string         `xml:"ReqdExctnDt"`
+			DebtorAccount          pain001Account `xml:"DbtrAcct"`
+			Transfers              []struct {
+				InstructionID string `xml:"PmtId>InstrId"`
+				EndToEndID    string `xml:"PmtId>EndToEndId"`
+				Amount        struct {
+					Currency string `xml:"Ccy,attr"`
+					Value    string `xml:",chardata"`
+				} `xml:"Amt>InstdAmt"`
+				CreditorAccount pain001Account `xml:"CdtrAcct"`
+			} `xml:"CdtTrfTxInf"`
+		} `xml:"PmtInf"`
+	} `xml:"CstmrCdtTrfInitn"`
+}
+
+type pain001Account struct {
+	IBAN  string `xml:"Id>IBAN"`
+	Other string `xml:"Id>Othr>Id"`
+}
+
+// id returns the account's IBAN, or its other identification if it has none.
+func (a pain001Account) id() string {
+	if iban := strings.TrimSpace(a.IBAN); iban != "" {
+		return iban
+	}
+	return strings.TrimSpace(a.Other)
+}
+
+// ParsePain001 reads a pain.001.001.03 message and checks it is consistent: the declared numbers of
+// transactions and control sums must match its credit transfers, and every amount must be a
+// non-negative decimal with at most five decimal places in a three-letter currency. Whether each
+// transfer can be made is left to the caller.
+func ParsePain001(r io.Reader) (*CreditTransferInitiation, error) {
+	var doc pain001Document
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedMessage, err)
+	}
+	if doc.XMLName.Space != pain001Namespace || doc.XMLName.Local != "Document" || doc.Initiation == nil {
+		return nil, ErrUnsupportedMessage
+	}
+
+	hdr := doc.Initiation.GroupHeader
+	msg := &CreditTransferInitiation{
+		MessageID: strings.TrimSpace(hdr.MessageID),
+	}
+	if msg.MessageID == "" {
+		return nil, ErrMissingMessageID
+	}
+
+	total := decimal.Zero
+	for _, pmt := range doc.Initiation.Payments {
+		info := PaymentInformation{
+			ID:              strings.TrimSpace(pmt.ID),
+			DebtorAccountID: pmt.DebtorAccount.id(),
+		}
+		if info.ID == "" {
+			return nil, ErrMissingPaymentID
+		}
+		if strings.TrimSpace(pmt.Method) != paymentMethodTransfer {
+			return nil, fmt.Errorf("PmtInf %s: %w", info.ID, ErrPaymentMethod)
+		}
+		date, err := time.Parse(time.DateOnly, strings.TrimSpace(pmt.RequestedExecutionDate))
+		if err != nil {
+			return nil, fmt.Errorf("PmtInf %s: %w", info.ID, ErrExecutionDate)
+		}
+		info.RequestedExecutionDate = date
+
+		sum := decimal.Zero
+		for _, tx := range pmt.Transfers {
+			amount, err := decimal.NewFromString(strings.TrimSpace(tx.Amount.Value))
+			currency := strings.TrimSpace(tx.Amount.Currency)
+			if err != nil || amount.IsNegative() || amount.Exponent() < -maxAmountPlaces || !currencyPattern.MatchString(currency) {
+				return nil, fmt.Errorf("PmtInf %s: %w", info.ID, ErrInstructedAmount)
+			}
+			info.Transfers = append(info.Transfers, CreditTransfer{
+				InstructionID:     strings.TrimSpace(tx.InstructionID),
+				EndToEndID:        strings.TrimSpace(tx.EndToEndID),
+				Amount:            amount,
+				Currency:          currency,
+				CreditorAccountID: tx.CreditorAccount.id(),
+			})
+			sum = sum.Add(amount)
+		}
+		if len(info.Transfers) == 0 {
+			return nil, fmt.Errorf("PmtInf %s: %w", info.ID, ErrNoTransfers)
+		}
+		if err := checkTotals(pmt.NumberOfTransactions, pmt.ControlSum, len(info.Transfers), sum); err != nil {
+			return nil, fmt.Errorf("PmtInf %s: %w", info.ID, err)
+		}
+		info.ControlSum = parseControlSum(pmt.ControlSum)
+
+		msg.NumberOfTransactions += len(info.Transfers)
+		if msg.NumberOfTransactions > MaxTransfers {
+			return nil, ErrTooManyTransfers
+		}
+		total = total.Add(sum)
+		msg.Payments = append(msg.Payments, info)
+	}
+	if len(msg.Payments) == 0 {
+		return nil, ErrNoPayments
+	}
+	if strings.TrimSpace(hdr.NumberOfTransactions) == "" {
+		return nil, fmt.Errorf("GrpHdr: %w", ErrNumberOfTransfers)
+	}
+	if err := checkTotals(hdr.NumberOfTransactions, hdr.ControlSum, msg.NumberOfTransactions, total); err != nil {
+		return nil, fmt.Errorf("GrpHdr: %w", err)
+	}
+	msg.ControlSum = parseControlSum(hdr.ControlSum)
+
+	return msg, nil
+}
+
+// checkTotals compares a declared number of transactions and optional control sum with the credit
+// transfers read. The number is mandatory in the group header and optional in payment information.
+func checkTotals(declaredCount, declaredSum string, count int, sum decimal.Decimal) error {
+	if declaredCount = strings.TrimSpace(declaredCount); declaredCount != "" {
+		if n, err := strconv.Atoi(declaredCount); err != nil || n != count {
+			return ErrNumberOfTransfers
+		}
+	}
+	if strings.TrimSpace(declaredSum) != "" {
+		if cs := parseControlSum(declaredSum); cs == nil || !cs.Equal(sum) {
+			return ErrControlSum
+		}
+	}
+	return nil
+}
+
+// parseControlSum returns the control sum written in a message, or nil if there is none.
+func parseControlSum(s string) *decimal.Decimal {
+	sum, err := decimal.NewFromString(strings.TrimSpace(s))
+	if err != nil {
+		return nil
+	}
+	return &sum
+}
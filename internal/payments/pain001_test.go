@@ -0,0 +1,132 @@
+package payments
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readPayroll returns the sample payroll message.
+func readPayroll(t *testing.T) string {
+	t.Helper()
+	data, err := os.ReadFile("testdata/payroll.pain001.xml")
+	require.NoError(t, err)
+	return string(data)
+}
+
+// TestParsePain001 validates a payroll message is read into its payment information blocks and
+// credit transfers, with IBAN and other account identifications.
+func TestParsePain001(t *testing.T) {
+	msg, err := ParsePain001(strings.NewReader(readPayroll(t)))
+	require.NoError(t, err)
+
+	total := decimal.RequireFromString("4250.50")
+	salaries := decimal.RequireFromString("4000.50")
+	assert.Equal(t, &CreditTransferInitiation{
+		MessageID:            "PAYROLL-2025-06",
+		NumberOfTransactions: 3,
+		ControlSum:           &total,
+		Payments: []PaymentInformation{
+			{
+				ID:                     "SALARIES",
+				RequestedExecutionDate: time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC),
+				DebtorAccountID:        "payroll",
+				ControlSum:             &salaries,
+				Transfers: []CreditTransfer{
+					{InstructionID: "SAL-1", EndToEndID: "E2E-SAL-1", Amount: decimal.RequireFromString("2500.00"), Currency: "EUR", CreditorAccountID: "emp-1"},
+					{EndToEndID: "E2E-SAL-2", Amount: decimal.RequireFromString("1500.50"), Currency: "EUR", CreditorAccountID: "DE89370400440532013000"},
+				},
+			},
+			{
+				ID:                     "EXPENSES",
+				RequestedExecutionDate: time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC),
+				DebtorAccountID:        "expenses",
+				Transfers: []CreditTransfer{
+					{InstructionID: "EXP-1", EndToEndID: "E2E-EXP-1", Amount: decimal.RequireFromString("250"), Currency: "EUR", CreditorAccountID: "emp-1"},
+				},
+			},
+		},
+	}, msg)
+}
+
+// TestParsePain001Rejects validates messages that cannot be processed are refused as a whole.
+func TestParsePain001Rejects(t *testing.T) {
+	payroll := readPayroll(t)
+
+	tests := []struct {
+		name        string
+		doc         string
+		expectedErr error
+	}{
+		{name: "not XML", doc: "MsgId,amount\n", expectedErr: ErrMalformedMessage},
+		{name: "truncated", doc: payroll[:200], expectedErr: ErrMalformedMessage},
+		{
+			name:        "other message",
+			doc:         strings.ReplaceAll(payroll, "pain.001.001.03", "pain.001.001.09"),
+			expectedErr: ErrUnsupportedMessage,
+		},
+		{
+			name:        "no namespace",
+			doc:         strings.Replace(payroll, ` xmlns="urn:iso:std:iso:20022:tech:xsd:pain.001.001.03"`, "", 1),
+			expectedErr: ErrUnsupportedMessage,
+		},
+		{name: "missing message ID", doc: strings.Replace(payroll, "<MsgId>PAYROLL-2025-06</MsgId>", "", 1), expectedErr: ErrMissingMessageID},
+		{name: "missing payment ID", doc: strings.Replace(payroll, "<PmtInfId>EXPENSES</PmtInfId>", "", 1), expectedErr: ErrMissingPaymentID},
+		{name: "direct debit", doc: strings.Replace(payroll, "<PmtMtd>TRF</PmtMtd>", "<PmtMtd>CHK</PmtMtd>", 1), expectedErr: ErrPaymentMethod},
+		{name: "invalid execution date", doc: strings.Replace(payroll, "2025-06-30", "30/06/2025", 1), expectedErr: ErrExecutionDate},
+		{name: "invalid amount", doc: strings.Replace(payroll, ">2500.00<", ">2,500.00<", 1), expectedErr: ErrInstructedAmount},
+		{name: "negative amount", doc: strings.Replace(payroll, ">250<", ">-250<", 1), expectedErr: ErrInstructedAmount},
+		{name: "too many decimal places", doc: strings.Replace(payroll, ">250<", ">250.000001<", 1), expectedErr: ErrInstructedAmount},
+		{name: "missing currency", doc: strings.Replace(payroll, `<InstdAmt Ccy="EUR">250`, "<InstdAmt>250", 1), expectedErr: ErrInstructedAmount},
+		{name: "group count mismatch", doc: strings.Replace(payroll, "<NbOfTxs>3</NbOfTxs>", "<NbOfTxs>4</NbOfTxs>", 1), expectedErr: ErrNumberOfTransfers},
+		{name: "group count missing", doc: strings.Replace(payroll, "<NbOfTxs>3</NbOfTxs>", "", 1), expectedErr: ErrNumberOfTransfers},
+		{name: "group control sum mismatch", doc: strings.Replace(payroll, "<CtrlSum>4250.50</CtrlSum>", "<CtrlSum>4250.51</CtrlSum>", 1), expectedErr: ErrControlSum},
+		{name: "payment count mismatch", doc: strings.Replace(payroll, "<NbOfTxs>2</NbOfTxs>", "<NbOfTxs>1</NbOfTxs>", 1), expectedErr: ErrNumberOfTransfers},
+		{name: "payment control sum mismatch", doc: strings.Replace(payroll, "<CtrlSum>4000.50</CtrlSum>", "<CtrlSum>4000</CtrlSum>", 1), expectedErr: ErrControlSum},
+		{
+			name:        "no payment information",
+			doc:         payroll[:strings.Index(payroll, "<PmtInf>")] + "</CstmrCdtTrfInitn></Document>",
+			expectedErr: ErrNoPayments,
+		},
+		{
+			name:        "payment information without transfers",
+			doc:         strings.Replace(payroll, "</PmtInf>", "</PmtInf><PmtInf><PmtInfId>EMPTY</PmtInfId><PmtMtd>TRF</PmtMtd><ReqdExctnDt>2025-06-30</ReqdExctnDt></PmtInf>", 1),
+			expectedErr: ErrNoTransfers,
+		},
+		{name: "too many transfers", doc: manyTransfers(MaxTransfers + 1), expectedErr: ErrTooManyTransfers},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := ParsePain001(strings.NewReader(tt.doc))
+			assert.Nil(t, msg)
+			assert.True(t, errors.Is(err, tt.expectedErr), "got %v", err)
+		})
+	}
+}
+
+// TestParsePain001MaxTransfers validates a message with the largest allowed number of transfers is read.
+func TestParsePain001MaxTransfers(t *testing.T) {
+	msg, err := ParsePain001(strings.NewReader(manyTransfers(MaxTransfers)))
+	require.NoError(t, err)
+	assert.Equal(t, MaxTransfers, msg.NumberOfTransactions)
+}
+
+// manyTransfers returns a message with n one-unit transfers.
+func manyTransfers(n int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `<Document xmlns="%s"><CstmrCdtTrfInitn><GrpHdr><MsgId>BULK</MsgId><NbOfTxs>%d</NbOfTxs></GrpHdr>`, pain001Namespace, n)
+	b.WriteString(`<PmtInf><PmtInfId>BULK</PmtInfId><PmtMtd>TRF</PmtMtd><ReqdExctnDt>2025-06-30</ReqdExctnDt><DbtrAcct><Id><Othr><Id>src</Id></Othr></Id></DbtrAcct>`)
+	for i := range n {
+		fmt.Fprintf(&b, `<CdtTrfTxInf><PmtId><EndToEndId>E2E-%d</EndToEndId></PmtId><Amt><InstdAmt Ccy="EUR">1</InstdAmt></Amt><CdtrAcct><Id><Othr><Id>dst-%d</Id></Othr></Id></CdtrAcct></CdtTrfTxInf>`, i, i)
+	}
+	b.WriteString(`</PmtInf></CstmrCdtTrfInitn></Document>`)
+	return b.String()
+}
@@ -0,0 +1,255 @@
+package payments
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// pain002Namespace is the XML namespace of ISO 20022 CustomerPaymentStatusReportV03 documents.
+const pain002Namespace = "urn:iso:std:iso:20022:tech:xsd:pain.002.001.03"
+
+// maxReasonInfo is the length limit of a status reason's additional information.
+const maxReasonInfo = 105
+
+// Statuses of transactions, payment information blocks and messages.
+const (
+	// StatusSettled means the transfer was made.
+	StatusSettled = "ACSC"
+	// StatusRejected means the transfer was refused.
+	StatusRejected = "RJCT"
+	// StatusPartial means some transfers of a block or message were made and some refused.
+	StatusPartial = "PART"
+)
+
+// ISO 20022 external status reason codes given for refused transfers.
+const (
+	ReasonIncorrectAccount       = "AC01"
+	ReasonInvalidCreditorAccount = "AC03"
+	ReasonClosedAccount          = "AC04"
+	ReasonTransactionForbidden   = "AG01"
+	ReasonCurrencyNotAllowed     = "AM03"
+	ReasonInsufficientFunds      = "AM04"
+	ReasonInvalidAmount          = "AM12"
+	ReasonInvalidDate            = "DT01"
+	ReasonNarrative              = "NARR"
+)
+
+// TransferStatus is the outcome of one credit transfer.
+type TransferStatus struct {
+	Transfer *CreditTransfer
+	// Status is StatusSettled or StatusRejected.
+	Status string
+	// TransactionID identifies the transaction a settled transfer created.
+	TransactionID int64
+	// Reason is the status reason code of a rejected transfer, and Info explains it.
+	Reason string
+	Info   string
+}
+
+// PaymentStatus is the outcome of the credit transfers of one payment information block, in order.
+type PaymentStatus struct {
+	Payment   *PaymentInformation
+	Transfers []TransferStatus
+}
+
+// StatusReport answers a pain.001 message with the outcome of each of its credit transfers.
+type StatusReport struct {
+	Original  *CreditTransferInitiation
+	CreatedAt time.Time
+	Payments  []PaymentStatus
+}
+
+// Status returns the status of the whole message.
+func (r *StatusReport) Status() string {
+	var settled, rejected int
+	for _, p := range r.Payments {
+		s, rj := p.counts()
+		settled, rejected = settled+s, rejected+rj
+	}
+	return aggregateStatus(settled, rejected)
+}
+
+// Status returns the status of the payment information block.
+func (p *PaymentStatus) Status() string {
+	return aggregateStatus(p.counts())
+}
+
+func (p *PaymentStatus) counts() (settled, rejected int) {
+	for _, t := range p.Transfers {
+		if t.Status == StatusSettled {
+			settled++
+		} else {
+			rejected++
+		}
+	}
+	return settled, rejected
+}
+
+func aggregateStatus(settled, rejected int) string {
+	switch {
+	case rejected == 0:
+		return StatusSettled
+	case settled == 0:
+		return StatusRejected
+	default:
+		return StatusPartial
+	}
+}
+
+type pain002Document struct {
+	XMLName xml.Name `xml:"Document"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	Report  struct {
+		GroupHeader struct {
+			MessageID string `xml:"MsgId"`
+			CreatedAt string `xml:"CreDtTm"`
+		} `xml:"GrpHdr"`
+		Group struct {
+			MessageID            string             `xml:"OrgnlMsgId"`
+			MessageName          string             `xml:"OrgnlMsgNmId"`
+			NumberOfTransactions int                `xml:"OrgnlNbOfTxs"`
+			ControlSum           string             `xml:"OrgnlCtrlSum,omitempty"`
+			Status               string             `xml:"GrpSts"`
+			PerStatus            []pain002PerStatus `xml:"NbOfTxsPerSts"`
+		} `xml:"OrgnlGrpInfAndSts"`
+		Payments []pain002Payment `xml:"OrgnlPmtInfAndSts"`
+	} `xml:"CstmrPmtStsRpt"`
+}
+
+type pain002PerStatus struct {
+	Count      int    `xml:"DtldNbOfTxs"`
+	Status     string `xml:"DtldSts"`
+	ControlSum string `xml:"DtldCtrlSum"`
+}
+
+type pain002Payment struct {
+	ID                   string             `xml:"OrgnlPmtInfId"`
+	NumberOfTransactions int                `xml:"OrgnlNbOfTxs"`
+	ControlSum           string             `xml:"OrgnlCtrlSum,omitempty"`
+	Status               string             `xml:"PmtInfSts"`
+	PerStatus            []pain002PerStatus `xml:"NbOfTxsPerSts"`
+	Transfers            []pain002Transfer  `xml:"TxInfAndSts"`
+}
+
+type pain002Transfer struct {
+	InstructionID string         `xml:"OrgnlInstrId,omitempty"`
+	EndToEndID    string         `xml:"OrgnlEndToEndId,omitempty"`
+	Status        string         `xml:"TxSts"`
+	Reason        *pain002Reason `xml:"StsRsnInf"`
+	ServicerRef   string         `xml:"AcctSvcrRef,omitempty"`
+	Amount        pain002Amount  `xml:"OrgnlTxRef>Amt>InstdAmt"`
+}
+
+type pain002Reason struct {
+	Code string `xml:"Rsn>Cd"`
+	Info string `xml:"AddtlInf,omitempty"`
+}
+
+type pain002Amount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+// WritePain002 writes the report as a pain.002.001.03 customer payment status report. Each transfer is
+// reported with its original instruction and end-to-end IDs; a settled transfer carries the ID of the
+// transaction it created as the account servicer reference, and a rejected one its reason.
+func WritePain002(w io.Writer, r *StatusReport) error {
+	var doc pain002Document
+	doc.Xmlns = pain002Namespace
+
+	hdr := &doc.Report.GroupHeader
+	hdr.MessageID = reportMessageID(r)
+	hdr.CreatedAt = r.CreatedAt.UTC().Format(time.RFC3339)
+
+	group := &doc.Report.Group
+	group.MessageID = r.Original.MessageID
+	group.MessageName = pain001MessageName
+	group.NumberOfTransactions = r.Original.NumberOfTransactions
+	group.ControlSum = controlSum(r.Original.ControlSum)
+	group.Status = r.Status()
+
+	var all []TransferStatus
+	for _, p := range r.Payments {
+		all = append(all, p.Transfers...)
+		payment := pain002Payment{
+			ID:                   p.Payment.ID,
+			NumberOfTransactions: len(p.Payment.Transfers),
+			ControlSum:           controlSum(p.Payment.ControlSum),
+			Status:               p.Status(),
+			PerStatus:            perStatus(p.Transfers),
+		}
+		for _, t := range p.Transfers {
+			tx := pain002Transfer{
+				InstructionID: t.Transfer.InstructionID,
+				EndToEndID:    t.Transfer.EndToEndID,
+				Status:        t.Status,
+				Amount:        pain002Amount{Currency: t.Transfer.Currency, Value: t.Transfer.Amount.String()},
+			}
+			if t.Status == StatusSettled {
+				tx.ServicerRef = strconv.FormatInt(t.TransactionID, 10)
+			} else {
+				tx.Reason = &pain002Reason{Code: t.Reason, Info: truncate(t.Info, maxReasonInfo)}
+			}
+			payment.Transfers = append(payment.Transfers, tx)
+		}
+		doc.Report.Payments = append(doc.Report.Payments, payment)
+	}
+	group.PerStatus = perStatus(all)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// perStatus counts and sums transfers by status, settled first.
+func perStatus(transfers []TransferStatus) []pain002PerStatus {
+	var out []pain002PerStatus
+	for _, status := range []string{StatusSettled, StatusRejected} {
+		count, sum := 0, decimal.Zero
+		for _, t := range transfers {
+			if t.Status == status {
+				count++
+				sum = sum.Add(t.Transfer.Amount)
+			}
+		}
+		if count > 0 {
+			out = append(out, pain002PerStatus{Count: count, Status: status, ControlSum: sum.String()})
+		}
+	}
+	return out
+}
+
+// reportMessageID identifies the report by its creation time and the message it answers.
+func reportMessageID(r *StatusReport) string {
+	sum := sha256.Sum256([]byte(r.Original.MessageID))
+	return r.CreatedAt.UTC().Format("20060102150405") + "-" + hex.EncodeToString(sum[:10])
+}
+
+func controlSum(sum *decimal.Decimal) string {
+	if sum == nil {
+		return ""
+	}
+	return sum.String()
+}
+
+// truncate shortens s to at most limit runes.
+func truncate(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit])
+}
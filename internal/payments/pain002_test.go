@@ -0,0 +1,149 @@
+package payments
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/xmltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pain002Schema is the pain.002.001.03 schema generated reports are validated against.
+const pain002Schema = "testdata/pain.002.001.03.xsd"
+
+// sampleReport answers the payroll message: the first salary is paid, the second refused for lack of
+// funds and the expense paid.
+func sampleReport(t *testing.T) *StatusReport {
+	t.Helper()
+	msg, err := ParsePain001(strings.NewReader(readPayroll(t)))
+	require.NoError(t, err)
+
+	salaries, expenses := &msg.Payments[0], &msg.Payments[1]
+	return &StatusReport{
+		Original:  msg,
+		CreatedAt: time.Date(2025, 6, 30, 8, 0, 0, 0, time.UTC),
+		Payments: []PaymentStatus{
+			{Payment: salaries, Transfers: []TransferStatus{
+				{Transfer: &salaries.Transfers[0], Status: StatusSettled, TransactionID: 41},
+				{Transfer: &salaries.Transfers[1], Status: StatusRejected, Reason: ReasonInsufficientFunds, Info: "insufficient funds in source account"},
+			}},
+			{Payment: expenses, Transfers: []TransferStatus{
+				{Transfer: &expenses.Transfers[0], Status: StatusSettled, TransactionID: 42},
+			}},
+		},
+	}
+}
+
+// TestStatusReportStatus validates how transfer statuses add up to block and message statuses.
+func TestStatusReportStatus(t *testing.T) {
+	report := sampleReport(t)
+	assert.Equal(t, StatusPartial, report.Status())
+	assert.Equal(t, StatusPartial, report.Payments[0].Status())
+	assert.Equal(t, StatusSettled, report.Payments[1].Status())
+
+	report.Payments[1].Transfers[0] = TransferStatus{Transfer: report.Payments[1].Transfers[0].Transfer, Status: StatusRejected, Reason: ReasonClosedAccount}
+	assert.Equal(t, StatusRejected, report.Payments[1].Status())
+
+	report.Payments[0].Transfers[0].Status = StatusRejected
+	assert.Equal(t, StatusRejected, report.Status())
+}
+
+// TestWritePain002 validates the report's statuses, counts, references and reasons, and that it is
+// valid against the pain.002 schema.
+func TestWritePain002(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WritePain002(&buf, sampleReport(t)))
+	out := buf.String()
+	assert.True(t, strings.HasPrefix(out, xml.Header))
+
+	type perStatus struct {
+		Count      int    `xml:"DtldNbOfTxs"`
+		Status     string `xml:"DtldSts"`
+		ControlSum string `xml:"DtldCtrlSum"`
+	}
+	var doc struct {
+		XMLName   xml.Name `xml:"urn:iso:std:iso:20022:tech:xsd:pain.002.001.03 Document"`
+		MessageID string   `xml:"CstmrPmtStsRpt>GrpHdr>MsgId"`
+		CreatedAt string   `xml:"CstmrPmtStsRpt>GrpHdr>CreDtTm"`
+		Group     struct {
+			MessageID   string      `xml:"OrgnlMsgId"`
+			MessageName string      `xml:"OrgnlMsgNmId"`
+			Count       int         `xml:"OrgnlNbOfTxs"`
+			ControlSum  string      `xml:"OrgnlCtrlSum"`
+			Status      string      `xml:"GrpSts"`
+			PerStatus   []perStatus `xml:"NbOfTxsPerSts"`
+		} `xml:"CstmrPmtStsRpt>OrgnlGrpInfAndSts"`
+		Payments []struct {
+			ID         string      `xml:"OrgnlPmtInfId"`
+			Count      int         `xml:"OrgnlNbOfTxs"`
+			ControlSum string      `xml:"OrgnlCtrlSum"`
+			Status     string      `xml:"PmtInfSts"`
+			PerStatus  []perStatus `xml:"NbOfTxsPerSts"`
+			Transfers  []struct {
+				InstructionID string `xml:"OrgnlInstrId"`
+				EndToEndID    string `xml:"OrgnlEndToEndId"`
+				Status        string `xml:"TxSts"`
+				Reason        string `xml:"StsRsnInf>Rsn>Cd"`
+				Info          string `xml:"StsRsnInf>AddtlInf"`
+				ServicerRef   string `xml:"AcctSvcrRef"`
+				Amount        string `xml:"OrgnlTxRef>Amt>InstdAmt"`
+			} `xml:"TxInfAndSts"`
+		} `xml:"CstmrPmtStsRpt>OrgnlPmtInfAndSts"`
+	}
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+
+	assert.Len(t, doc.MessageID, 35)
+	assert.True(t, strings.HasPrefix(doc.MessageID, "20250630080000-"))
+	assert.Equal(t, "2025-06-30T08:00:00Z", doc.CreatedAt)
+
+	assert.Equal(t, "PAYROLL-2025-06", doc.Group.MessageID)
+	assert.Equal(t, "pain.001.001.03", doc.Group.MessageName)
+	assert.Equal(t, 3, doc.Group.Count)
+	assert.Equal(t, "4250.5", doc.Group.ControlSum)
+	assert.Equal(t, StatusPartial, doc.Group.Status)
+	assert.Equal(t, []perStatus{{Count: 2, Status: StatusSettled, ControlSum: "2750"}, {Count: 1, Status: StatusRejected, ControlSum: "1500.5"}}, doc.Group.PerStatus)
+
+	require.Len(t, doc.Payments, 2)
+	salaries := doc.Payments[0]
+	assert.Equal(t, "SALARIES", salaries.ID)
+	assert.Equal(t, 2, salaries.Count)
+	assert.Equal(t, StatusPartial, salaries.Status)
+	require.Len(t, salaries.Transfers, 2)
+	assert.Equal(t, "SAL-1", salaries.Transfers[0].InstructionID)
+	assert.Equal(t, "E2E-SAL-1", salaries.Transfers[0].EndToEndID)
+	assert.Equal(t, StatusSettled, salaries.Transfers[0].Status)
+	assert.Equal(t, "41", salaries.Transfers[0].ServicerRef)
+	assert.Empty(t, salaries.Transfers[0].Reason)
+	assert.Equal(t, "2500", salaries.Transfers[0].Amount)
+	assert.Empty(t, salaries.Transfers[1].InstructionID)
+	assert.Equal(t, StatusRejected, salaries.Transfers[1].Status)
+	assert.Equal(t, ReasonInsufficientFunds, salaries.Transfers[1].Reason)
+	assert.Equal(t, "insufficient funds in source account", salaries.Transfers[1].Info)
+	assert.Empty(t, salaries.Transfers[1].ServicerRef)
+
+	expenses := doc.Payments[1]
+	assert.Equal(t, "EXPENSES", expenses.ID)
+	assert.Empty(t, expenses.ControlSum)
+	assert.Equal(t, StatusSettled, expenses.Status)
+	assert.Equal(t, []perStatus{{Count: 1, Status: StatusSettled, ControlSum: "250"}}, expenses.PerStatus)
+
+	report, err := xmltest.Validate(t, pain002Schema, out)
+	assert.NoError(t, err, report)
+}
+
+// TestWritePain002LongReason validates additional information is cut to the schema's limit.
+func TestWritePain002LongReason(t *testing.T) {
+	report := sampleReport(t)
+	report.Payments[0].Transfers[1].Info = strings.Repeat("x", 200)
+
+	var buf bytes.Buffer
+	require.NoError(t, WritePain002(&buf, report))
+	assert.Contains(t, buf.String(), "<AddtlInf>"+strings.Repeat("x", maxReasonInfo)+"</AddtlInf>")
+
+	out, err := xmltest.Validate(t, pain002Schema, buf.String())
+	assert.NoError(t, err, out)
+}
@@ -0,0 +1,60 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMemoryStoreAllow validates token consumption, refill over time and independent keys.
+func TestMemoryStoreAllow(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 12, 1, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	limit := Limit{Rate: 1, Burst: 2}
+
+	res, err := store.Allow(ctx, "client:a", limit)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 2, res.Limit)
+	assert.Equal(t, 1, res.Remaining)
+
+	res, _ = store.Allow(ctx, "client:a", limit)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+	assert.Equal(t, 2*time.Second, res.ResetAfter)
+
+	res, _ = store.Allow(ctx, "client:a", limit)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, time.Second, res.RetryAfter)
+
+	res, _ = store.Allow(ctx, "client:b", limit)
+	assert.True(t, res.Allowed, "other keys have their own bucket")
+
+	now = now.Add(1500 * time.Millisecond)
+	res, _ = store.Allow(ctx, "client:a", limit)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+}
+
+// TestMemoryStoreSweep validates that idle, refilled buckets are evicted.
+func TestMemoryStoreSweep(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 12, 1, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+
+	_, _ = store.Allow(ctx, "ip:1", Limit{Rate: 10, Burst: 5})
+	_, _ = store.Allow(ctx, "ip:2", Limit{Rate: 0.001, Burst: 5})
+
+	now = now.Add(2 * sweepInterval)
+	_, _ = store.Allow(ctx, "ip:3", Limit{Rate: 10, Burst: 5})
+
+	assert.NotContains(t, store.buckets, "ip:1")
+	assert.Contains(t, store.buckets, "ip:2")
+	assert.Contains(t, store.buckets, "ip:3")
+}
@@ -0,0 +1,157 @@
+package server
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/utils"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// redactedValue replaces the value of sensitive headers and query parameters in access logs.
+const redactedValue = "[REDACTED]"
+
+// defaultRedactFields are always masked, regardless of configuration.
+var defaultRedactFields = []string{"authorization", "cookie", "x-api-key"}
+
+// responseRecorder wraps an http.ResponseWriter to capture the status code and bytes written.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+// newResponseRecorder wraps w, defaulting the status to 200 as net/http does.
+func newResponseRecorder(w http.ResponseWriter) *responseRecorder {
+	return &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+}
+
+// WriteHeader records the status code before delegating.
+func (rr *responseRecorder) WriteHeader(status int) {
+	rr.status = status
+	rr.ResponseWriter.WriteHeader(status)
+}
+
+// Write records the number of bytes written before delegating.
+func (rr *responseRecorder) Write(b []byte) (int, error) {
+	n, err := rr.ResponseWriter.Write(b)
+	rr.bytes += int64(n)
+	return n, err
+}
+
+// Flush delegates to the wrapped writer when it supports streaming.
+func (rr *responseRecorder) Flush() {
+	if f, ok := rr.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap exposes the wrapped writer to http.ResponseController.
+func (rr *responseRecorder) Unwrap() http.ResponseWriter {
+	return rr.ResponseWriter
+}
+
+// accessLogMiddleware records the status, size and latency of every request.
+// It feeds the metrics recorder and emits one sampled, redacted access-log line per request.
+func (s *Server) accessLogMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := newResponseRecorder(w)
+
+		next.ServeHTTP(rec, r)
+
+		latency := time.Since(start)
+		route := routeTemplate(r)
+		if s.metrics != nil {
+			s.metrics.ObserveHTTPRequest(r.Method, route, rec.status, rec.bytes, latency)
+		}
+
+		cfg := s.cfg.AccessLog
+		if cfg == nil || !cfg.Enabled || !sampled(cfg.SampleRate, rec.status) {
+			return
+		}
+
+		redact := redactSet(cfg.RedactFields)
+		utils.ContextLogger(r.Context()).Info("access",
+			zap.String("method", r.Method),
+			zap.String("route", route),
+			zap.String("path", r.URL.Path),
+			zap.Any("query", redactQuery(r.URL.Query(), redact)),
+			zap.Any("headers", redactHeaders(r.Header, redact)),
+			zap.Int("status", rec.status),
+			zap.Int64("bytes", rec.bytes),
+			zap.Duration("latency", latency),
+			zap.String("remote_addr", r.RemoteAddr),
+		)
+	})
+}
+
+// routeTemplate returns the matched mux route template, falling back to the raw path.
+func routeTemplate(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if tpl, err := route.GetPathTemplate(); err == nil {
+			return tpl
+		}
+	}
+	return r.URL.Path
+}
+
+// sampled reports whether a request with the given status should be logged.
+// Server errors are always logged.
+func sampled(rate float64, status int) bool {
+	if status >= http.StatusInternalServerError || rate >= 1 {
+		return true
+	}
+	return rand.Float64() < rate
+}
+
+// redactSet builds a lookup of lower-cased field names to redact, including the defaults.
+func redactSet(fields []string) map[string]bool {
+	set := make(map[string]bool, len(fields)+len(defaultRedactFields))
+	for _, f := range defaultRedactFields {
+		set[f] = true
+	}
+	for _, f := range fields {
+		set[strings.ToLower(strings.TrimSpace(f))] = true
+	}
+	return set
+}
+
+// redactHeaders flattens headers into a map, masking sensitive values.
+func redactHeaders(h http.Header, redact map[string]bool) map[string]string {
+	out := make(map[string]string, len(h))
+	for name, values := range h {
+		if redact[strings.ToLower(name)] {
+			out[name] = redactedValue
+			continue
+		}
+		out[name] = strings.Join(values, ",")
+	}
+	return out
+}
+
+// redactQuery flattens query parameters into a map, masking sensitive values.
+func redactQuery(q url.Values, redact map[string]bool) map[string]string {
+	out := make(map[string]string, len(q))
+	for name, values := range q {
+		if redact[strings.ToLower(name)] {
+			out[name] = redactedValue
+			continue
+		}
+		out[name] = strings.Join(values, ",")
+	}
+	return out
+}
+
+// MetricsHandler serves the request metrics in the Prometheus text format.
+func (s *Server) MetricsHandler(w http.ResponseWriter, r *http.Request) {
+	if s.metrics == nil {
+		http.Error(w, "metrics are not enabled", http.StatusNotFound)
+		return
+	}
+	s.metrics.Handler().ServeHTTP(w, r)
+}
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/config"
+	"github.com/cursed-ninja/internal-transfers-system/internal/metrics"
+	"github.com/cursed-ninja/internal-transfers-system/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// TestAccessLogMiddleware tests that a single redacted access-log line is emitted with status and size,
+// and that the same observation reaches the metrics registry.
+func TestAccessLogMiddleware(t *testing.T) {
+	tests := []struct {
+		name         string
+		sampleRate   float64
+		status       int
+		expectedLogs int
+	}{
+		{
+			name:         "logged",
+			sampleRate:   1,
+			status:       http.StatusCreated,
+			expectedLogs: 1,
+		},
+		{
+			name:         "sampled out",
+			sampleRate:   0,
+			status:       http.StatusOK,
+			expectedLogs: 0,
+		},
+		{
+			name:         "server errors always logged",
+			sampleRate:   0,
+			status:       http.StatusInternalServerError,
+			expectedLogs: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.InfoLevel)
+			registry := metrics.NewRegistry()
+			s := Server{
+				cfg: &config.Config{
+					AccessLog: &config.AccessLogConfig{
+						Enabled:      true,
+						SampleRate:   tc.sampleRate,
+						RedactFields: []string{"token"},
+					},
+				},
+				metrics: registry,
+			}
+
+			handler := s.accessLogMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte("hello"))
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/transactions?token=secret&page=2", nil)
+			req.Header.Set("Authorization", "Bearer secret")
+			req = req.WithContext(context.WithValue(req.Context(), utils.LoggerContextKey, zap.New(core)))
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedLogs, logs.Len())
+			if tc.expectedLogs > 0 {
+				fields := logs.All()[0].ContextMap()
+				assert.Equal(t, int64(tc.status), fields["status"])
+				assert.Equal(t, int64(5), fields["bytes"])
+				assert.Equal(t, redactedValue, fields["query"].(map[string]string)["token"])
+				assert.Equal(t, "2", fields["query"].(map[string]string)["page"])
+				assert.Equal(t, redactedValue, fields["headers"].(map[string]string)["Authorization"])
+			}
+
+			var buf strings.Builder
+			assert.NoError(t, registry.WritePrometheus(&buf))
+			assert.Contains(t, buf.String(), `http_requests_total{method="POST",route="/transactions",status="`)
+		})
+	}
+}
@@ -0,0 +1,408 @@
+package server
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+	"github.com/cursed-ninja/internal-transfers-system/internal/utils"
+	"go.uber.org/zap"
+)
+
+// Account import modes: an atomic import creates every account or none, a partial import creates the
+// accounts that are not refused.
+const (
+	importModeAtomic  = "atomic"
+	importModePartial = "partial"
+)
+
+// maxImportAccounts caps the number of accounts a POST /accounts/import request may create.
+const maxImportAccounts = 10000
+
+// maxImportBodyBytes bounds POST /accounts/import uploads, leaving room for maxImportAccounts rows
+// with names, labels and metadata. Uploads are read as they arrive rather than buffered.
+const maxImportBodyBytes = 32 << 20
+
+// metadataColumnPrefix starts the import columns that each set one metadata key, e.g. metadata.branch.
+const metadataColumnPrefix = "metadata."
+
+// importColumns are the columns an import CSV may have besides metadata.<key> columns.
+var importColumns = map[string]bool{
+	"id":              true,
+	"initial_balance": true,
+	"currency":        true,
+	"name":            true,
+	"owner_ref":       true,
+	"type":            true,
+	"labels":          true,
+	"metadata":        true,
+}
+
+// readOnlyImportColumns are export columns an import skips, so an export can be imported again.
+var readOnlyImportColumns = map[string]bool{
+	"balance":    true,
+	"status":     true,
+	"version":    true,
+	"created_at": true,
+	"updated_at": true,
+}
+
+// exportColumns is the header of an account export. The current balance is written as initial_balance,
+// so an export can be imported to recreate its accounts.
+var exportColumns = []string{"id", "initial_balance", "currency", "status", "name", "owner_ref", "type", "labels", "metadata", "version", "created_at", "updated_at"}
+
+// formulaPrefixes start cells that spreadsheets evaluate as formulas. Exported cells starting with one
+// of them, or with the quote that escapes them, are prefixed with a quote, which imports remove.
+const formulaPrefixes = "=+-@\t\r'"
+
+// importRow is an account read from an import CSV, with the line it starts on.
+type importRow struct {
+	line int
+	req  createAccountRequest
+	// err is set when the row's labels cell cannot be read.
+	err error
+}
+
+type importAccountsResponse struct {
+	Mode    string               `json:"mode"`
+	Rows    int                  `json:"rows"`
+	Created int                  `json:"created"`
+	Failed  int                  `json:"failed"`
+	Errors  []importAccountError `json:"errors"`
+}
+
+// importAccountError is the reason a row of an import was refused.
+type importAccountError struct {
+	Row       int    `json:"row"`
+	AccountID string `json:"account_id"`
+	Error     string `json:"error"`
+}
+
+// ImportAccounts handles POST /accounts/import, creating the accounts of an uploaded CSV. Rows are
+// validated like POST /accounts requests as they are read. In atomic mode, the default, one refused row
+// means no account is created; the accounts are created in one DB transaction, which delays every other
+// account change and transfer until it commits. In partial mode the other rows are still created, in
+// chunks committed one after the other. The response reports every refused row with 201 when every
+// account was created, 200 when some were and 422 when none were. A CSV that cannot be read returns 400
+// and one over maxImportBodyBytes returns 413.
+func (s *Server) ImportAccounts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := utils.ContextLogger(ctx)
+
+	logger.Info("received ImportAccounts request")
+
+	atomic, err := ValidateImportMode(r.URL.Query())
+	if err != nil {
+		logger.Error("failed to validate request", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp := importAccountsResponse{Mode: importModePartial, Errors: []importAccountError{}}
+	if atomic {
+		resp.Mode = importModeAtomic
+	}
+	refuse := func(row *importRow, err error) {
+		resp.Errors = append(resp.Errors, importAccountError{Row: row.line, AccountID: row.req.AccountID, Error: err.Error()})
+	}
+
+	var (
+		accounts []storage.NewAccount
+		// pending are the rows of accounts, in the order they are sent to storage.
+		pending []*importRow
+	)
+	rows, err := newImportReader(http.MaxBytesReader(w, r.Body, maxImportBodyBytes))
+	for err == nil {
+		var row *importRow
+		if row, err = rows.next(); err != nil {
+			break
+		}
+		resp.Rows++
+		if row.err != nil {
+			row.req.AccountID = strings.TrimSpace(row.req.AccountID)
+			refuse(row, row.err)
+			continue
+		}
+		balance, err := ValidateCreateAccount(&row.req)
+		if err != nil {
+			refuse(row, err)
+			continue
+		}
+		accounts = append(accounts, storage.NewAccount{ID: row.req.AccountID, Balance: balance, AccountAttributes: row.req.attributes()})
+		pending = append(pending, row)
+	}
+	if errors.Is(err, io.EOF) && resp.Rows == 0 {
+		err = ErrNoImportAccounts
+	}
+	if !errors.Is(err, io.EOF) {
+		logger.Error("failed to read accounts CSV", zap.Error(err))
+		if maxBytesErr := (*http.MaxBytesError)(nil); errors.As(err, &maxBytesErr) {
+			http.Error(w, ErrImportTooLarge.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// An atomic import refused before storage would be rolled back anyway.
+	if len(accounts) > 0 && (!atomic || len(resp.Errors) == 0) {
+		results, err := s.store.CreateAccounts(ctx, accounts, atomic)
+		if err != nil {
+			logger.Error("failed to import accounts", zap.Error(err))
+			if len(results) == 0 {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			// The leading chunks of a partial import were committed; the other rows can be sent again.
+			for _, row := range pending[len(results):] {
+				refuse(row, err)
+			}
+		}
+		created := 0
+		for k, err := range results {
+			if err != nil {
+				refuse(pending[k], err)
+				continue
+			}
+			created++
+		}
+		if !atomic || created == len(accounts) {
+			resp.Created = created
+		}
+		slices.SortFunc(resp.Errors, func(a, b importAccountError) int { return a.Row - b.Row })
+	}
+	resp.Failed = len(resp.Errors)
+
+	statusCode := http.StatusOK
+	switch resp.Created {
+	case resp.Rows:
+		statusCode = http.StatusCreated
+	case 0:
+		statusCode = http.StatusUnprocessableEntity
+	}
+
+	logger.Info("accounts imported", zap.String("mode", resp.Mode), zap.Int("rows", resp.Rows), zap.Int("created", resp.Created))
+	writeJSON(w, logger, statusCode, resp)
+}
+
+// ExportAccounts handles GET /accounts/export, streaming every account matching the filters of
+// GET /accounts as CSV, ordered by creation time. Callers restricted to specific accounts only export
+// those accounts.
+func (s *Server) ExportAccounts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := utils.ContextLogger(ctx)
+
+	logger.Info("received ExportAccounts request")
+
+	query, _, err := ValidateListAccounts(r.URL.Query())
+	if err != nil {
+		logger.Error("failed to validate request", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	query.Limit = maxAccountsPageSize
+	// Exports page through the accounts in order; balances can change between pages, so ordering by
+	// them could skip or repeat accounts.
+	if query.Sort == storage.AccountSortBalance {
+		query.Sort, query.Descending = storage.AccountSortCreatedAt, false
+	}
+	if p := principalFromContext(ctx); p != nil {
+		query.IDs = p.readableAccounts()
+	}
+
+	resp := &attachmentResponse{w: w, contentType: "text/csv; charset=utf-8", filename: "accounts.csv"}
+	cw := csv.NewWriter(resp)
+	exported := 0
+	for {
+		accounts, err := s.store.ListAccounts(ctx, query)
+		if err != nil {
+			logger.Error("failed to list accounts", zap.Error(err))
+			if resp.written {
+				// The status has been sent; the client sees a truncated export.
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if exported == 0 {
+			_ = cw.Write(exportColumns)
+		}
+		for i := range accounts {
+			_ = cw.Write(exportRecord(&accounts[i]))
+		}
+		exported += len(accounts)
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			logger.Error("failed to write accounts", zap.Error(err))
+			return
+		}
+
+		if len(accounts) < query.Limit {
+			break
+		}
+		query.After = &accounts[len(accounts)-1]
+	}
+
+	logger.Info("accounts exported", zap.Int("count", exported))
+}
+
+// importReader reads the accounts of an import CSV one row at a time. The header names the columns,
+// in any order: id and initial_balance are required; currency, name, owner_ref and type are optional
+// text; labels is a JSON array and metadata a JSON object. The read-only columns of an export are
+// skipped. Instead of metadata, metadata.<key> columns
+// each set one string key of the metadata, empty cells being left out. A labels or metadata cell that
+// cannot be read refuses its row only; any other problem fails the whole CSV.
+type importReader struct {
+	cr           *csv.Reader
+	columns      map[string]int
+	metadataKeys []string
+	rows         int
+}
+
+// newImportReader reads and checks the header of an import CSV.
+func newImportReader(r io.Reader) (*importReader, error) {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, ErrNoImportAccounts
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	// Spreadsheets often start UTF-8 CSV files with a byte order mark.
+	header[0] = strings.TrimPrefix(header[0], "\ufeff")
+
+	ir := &importReader{cr: cr, columns: make(map[string]int, len(header))}
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		if _, ok := ir.columns[name]; ok {
+			return nil, fmt.Errorf("%w: %q", ErrDuplicateImportColumn, name)
+		}
+		if readOnlyImportColumns[name] {
+			continue
+		}
+		key, isMetadataKey := strings.CutPrefix(name, metadataColumnPrefix)
+		if !importColumns[name] && (!isMetadataKey || key == "") {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownImportColumn, name)
+		}
+		ir.columns[name] = i
+		if isMetadataKey {
+			ir.metadataKeys = append(ir.metadataKeys, key)
+		}
+	}
+	if _, ok := ir.columns["id"]; !ok {
+		return nil, ErrMissingImportColumns
+	}
+	if _, ok := ir.columns["initial_balance"]; !ok {
+		return nil, ErrMissingImportColumns
+	}
+	if _, ok := ir.columns["metadata"]; ok && len(ir.metadataKeys) > 0 {
+		return nil, ErrImportMetadataColumns
+	}
+	return ir, nil
+}
+
+// next reads the next row of the CSV, returning io.EOF after the last one.
+func (ir *importReader) next() (*importRow, error) {
+	record, err := ir.cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	if ir.rows == maxImportAccounts {
+		return nil, ErrTooManyImportAccounts
+	}
+	ir.rows++
+	line, _ := ir.cr.FieldPos(0)
+	row := newImportRow(line, record, ir.columns, ir.metadataKeys)
+	return &row, nil
+}
+
+// newImportRow builds the account creation request of a CSV record.
+func newImportRow(line int, record []string, columns map[string]int, metadataKeys []string) importRow {
+	cell := func(name string) string {
+		if i, ok := columns[name]; ok {
+			return unescapeCell(record[i])
+		}
+		return ""
+	}
+
+	row := importRow{line: line, req: createAccountRequest{
+		AccountID:      cell("id"),
+		InitialBalance: cell("initial_balance"),
+		Currency:       cell("currency"),
+		Name:           cell("name"),
+		OwnerRef:       cell("owner_ref"),
+		Type:           cell("type"),
+	}}
+
+	if raw := strings.TrimSpace(cell("labels")); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &row.req.Labels); err != nil {
+			row.err = ErrInvalidLabels
+		}
+	}
+	if raw := strings.TrimSpace(cell("metadata")); raw != "" {
+		row.req.Metadata = json.RawMessage(raw)
+	}
+	if len(metadataKeys) > 0 {
+		metadata := make(map[string]string, len(metadataKeys))
+		for _, key := range metadataKeys {
+			if value := cell(metadataColumnPrefix + key); value != "" {
+				metadata[key] = value
+			}
+		}
+		row.req.Metadata, _ = json.Marshal(metadata)
+	}
+	return row
+}
+
+// exportRecord renders an account as a row of an account export.
+func exportRecord(acc *storage.Account) []string {
+	resp := newAccountResponse(acc)
+	labels, _ := json.Marshal(resp.Labels)
+	record := []string{
+		resp.ID,
+		resp.Balance,
+		resp.Currency,
+		resp.Status,
+		resp.Name,
+		resp.OwnerRef,
+		resp.Type,
+		string(labels),
+		string(resp.Metadata),
+		strconv.FormatInt(resp.Version, 10),
+		resp.CreatedAt.Format(time.RFC3339Nano),
+		resp.UpdatedAt.Format(time.RFC3339Nano),
+	}
+	for i := range record {
+		record[i] = escapeCell(record[i])
+	}
+	return record
+}
+
+// escapeCell prefixes a cell that a spreadsheet would read as a formula with a quote.
+func escapeCell(value string) string {
+	if value != "" && strings.ContainsRune(formulaPrefixes, rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// unescapeCell removes the quote escapeCell adds.
+func unescapeCell(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsRune(formulaPrefixes, rune(value[1])) {
+		return value[1:]
+	}
+	return value
+}
@@ -0,0 +1,413 @@
+package server
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/config"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage/mocks"
+	"github.com/gorilla/mux"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// TestImportAccounts validates atomic and partial imports, the report of refused rows, and CSVs that
+// cannot be read.
+func TestImportAccounts(t *testing.T) {
+	const accountsCSV = "id,initial_balance,currency,name,labels,metadata.branch\n" +
+		"acc-1,100,eur,Payroll,\"[\"\"vip\"\"]\",7\n" +
+		"acc-2,0,,,,\n"
+	payroll := storage.NewAccount{
+		ID:      "acc-1",
+		Balance: decimal.RequireFromString("100"),
+		AccountAttributes: storage.AccountAttributes{
+			Currency: "EUR", Name: "Payroll", Labels: []string{"vip"}, Metadata: json.RawMessage(`{"branch":"7"}`),
+		},
+	}
+	savings := storage.NewAccount{
+		ID:                "acc-2",
+		Balance:           decimal.RequireFromString("0"),
+		AccountAttributes: storage.AccountAttributes{Currency: storage.DefaultCurrency, Labels: []string{}, Metadata: json.RawMessage(`{}`)},
+	}
+	const invalidRowCSV = "id,initial_balance\nacc-1,100\nacc-2,-5\nacc-3,7\n"
+
+	tests := []struct {
+		name           string
+		query          string
+		body           string
+		mockSetup      func(m *mocks.MockStorage)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "every account created",
+			body: accountsCSV,
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().CreateAccounts(gomock.Any(), []storage.NewAccount{payroll, savings}, true).Return([]error{nil, nil}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"mode":"atomic","rows":2,"created":2,"failed":0,"errors":[]}`,
+		},
+		{
+			name:  "partial import with refused rows",
+			query: "?mode=partial",
+			body:  invalidRowCSV,
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().CreateAccounts(gomock.Any(), gomock.Len(2), false).Return([]error{errors.New(storage.ErrAccountExists), nil}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"mode":"partial","rows":3,"created":1,"failed":2,"errors":[
+				{"row":2,"account_id":"acc-1","error":"` + storage.ErrAccountExists + `"},
+				{"row":3,"account_id":"acc-2","error":"` + ErrNegativeBalance.Error() + `"}]}`,
+		},
+		{
+			name:           "atomic import with an invalid row",
+			body:           invalidRowCSV,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody: `{"mode":"atomic","rows":3,"created":0,"failed":1,"errors":[
+				{"row":3,"account_id":"acc-2","error":"` + ErrNegativeBalance.Error() + `"}]}`,
+		},
+		{
+			name:  "atomic import with an existing account",
+			query: "?mode=atomic",
+			body:  accountsCSV,
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().CreateAccounts(gomock.Any(), gomock.Len(2), true).Return([]error{nil, errors.New(storage.ErrAccountExists)}, nil)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody: `{"mode":"atomic","rows":2,"created":0,"failed":1,"errors":[
+				{"row":3,"account_id":"acc-2","error":"` + storage.ErrAccountExists + `"}]}`,
+		},
+		{
+			name:           "every row refused",
+			query:          "?mode=partial",
+			body:           "id,initial_balance,labels,metadata\n,5,,\nacc-2,5,vip,\nacc-3,5,,[]\n",
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody: `{"mode":"partial","rows":3,"created":0,"failed":3,"errors":[
+				{"row":2,"account_id":"","error":"` + ErrMissingAccountID.Error() + `"},
+				{"row":3,"account_id":"acc-2","error":"` + ErrInvalidLabels.Error() + `"},
+				{"row":4,"account_id":"acc-3","error":"` + ErrInvalidMetadata.Error() + `"}]}`,
+		},
+		{
+			name: "quoted cells spanning lines and a byte order mark",
+			body: "\ufeffid,name,initial_balance\r\nacc-1,\"Pay\nroll\",1\r\nacc-2,,2\r\n",
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().CreateAccounts(gomock.Any(), gomock.Len(2), true).Return([]error{nil, errors.New(storage.ErrAccountExists)}, nil)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody: `{"mode":"atomic","rows":2,"created":0,"failed":1,"errors":[
+				{"row":4,"account_id":"acc-2","error":"` + storage.ErrAccountExists + `"}]}`,
+		},
+		{
+			name: "export imported again",
+			body: strings.Join(exportColumns, ",") + "\n" +
+				"acc-1,99.50,EUR,active,\"'=HYPERLINK(\"\"x\"\")\",'-ops,customer,[],{},2,2025-12-01T12:00:00Z,2025-12-01T13:00:00Z\n",
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().CreateAccounts(gomock.Any(), []storage.NewAccount{{
+					ID:      "acc-1",
+					Balance: decimal.RequireFromString("99.50"),
+					AccountAttributes: storage.AccountAttributes{
+						Currency: "EUR", Name: `=HYPERLINK("x")`, OwnerRef: "-ops", Type: storage.AccountTypeCustomer, Labels: []string{}, Metadata: json.RawMessage(`{}`),
+					},
+				}}, true).Return([]error{nil}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"mode":"atomic","rows":1,"created":1,"failed":0,"errors":[]}`,
+		},
+		{
+			name:           "unknown mode",
+			query:          "?mode=all",
+			body:           accountsCSV,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   ErrInvalidImportMode.Error(),
+		},
+		{
+			name:           "missing required column",
+			body:           "id,balance\nacc-1,100\n",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   ErrMissingImportColumns.Error(),
+		},
+		{
+			name:           "unknown column",
+			body:           "id,initial_balance,colour\nacc-1,100,red\n",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   ErrUnknownImportColumn.Error() + `: "colour"`,
+		},
+		{
+			name:           "no balance column",
+			body:           "id,currency\nacc-1,EUR\n",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   ErrMissingImportColumns.Error(),
+		},
+		{
+			name:           "duplicate column",
+			body:           "id,initial_balance,id\nacc-1,100,acc-2\n",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   ErrDuplicateImportColumn.Error() + `: "id"`,
+		},
+		{
+			name:           "metadata and metadata key columns",
+			body:           "id,initial_balance,metadata,metadata.branch\nacc-1,100,{},7\n",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   ErrImportMetadataColumns.Error(),
+		},
+		{
+			name:           "no accounts",
+			body:           "id,initial_balance\n",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   ErrNoImportAccounts.Error(),
+		},
+		{
+			name:           "wrong number of fields",
+			body:           "id,initial_balance\nacc-1,100,EUR\n",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid CSV: record on line 2: wrong number of fields",
+		},
+		{
+			name:           "too many accounts",
+			body:           "id,initial_balance\n" + strings.Repeat("acc,1\n", maxImportAccounts+1),
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   ErrTooManyImportAccounts.Error(),
+		},
+		{
+			name:           "upload too large",
+			body:           "id,initial_balance,name\nacc-1,100," + strings.Repeat("a", maxImportBodyBytes) + "\n",
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedBody:   ErrImportTooLarge.Error(),
+		},
+		{
+			name:  "partial import interrupted by an internal error",
+			query: "?mode=partial",
+			body:  accountsCSV,
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().CreateAccounts(gomock.Any(), gomock.Len(2), false).Return([]error{nil}, errors.New(storage.ErrImportAccountsMsg))
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"mode":"partial","rows":2,"created":1,"failed":1,"errors":[
+				{"row":3,"account_id":"acc-2","error":"` + storage.ErrImportAccountsMsg + `"}]}`,
+		},
+		{
+			name: "internal error",
+			body: accountsCSV,
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().CreateAccounts(gomock.Any(), gomock.Any(), true).Return(nil, errors.New(storage.ErrImportAccountsMsg))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   storage.ErrImportAccountsMsg,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockStorage := mocks.NewMockStorage(mockCtrl)
+			if tc.mockSetup != nil {
+				tc.mockSetup(mockStorage)
+			}
+
+			s := Server{cfg: &config.Config{}, store: mockStorage}
+			r := mux.NewRouter()
+			s.BindRoutes(r)
+
+			req := httptest.NewRequest(http.MethodPost, "/accounts/import"+tc.query, strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "text/csv")
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if w.Header().Get("Content-Type") == "application/json" {
+				assert.JSONEq(t, tc.expectedBody, w.Body.String())
+			} else {
+				assert.Equal(t, tc.expectedBody+"\n", w.Body.String())
+			}
+		})
+	}
+}
+
+// TestImportAccountsLarge validates an import of nearly maxImportAccounts rows with every column set,
+// well over the size of the bodies middleware buffers.
+func TestImportAccountsLarge(t *testing.T) {
+	const rows = maxImportAccounts - 1
+	var body strings.Builder
+	body.WriteString("id,initial_balance,currency,name,owner_ref,type,labels,metadata\n")
+	for i := range rows {
+		fmt.Fprintf(&body, "employee-%05d,1500.25,EUR,Employee %05d payroll account,hr-system/employee/%05d,customer,"+
+			"\"[\"\"payroll\"\",\"\"eu\"\"]\",\"{\"\"cost_center\"\":\"\"CC-%05d\"\",\"\"onboarding\"\":\"\"2025-Q3\"\"}\"\n", i, i, i, i)
+	}
+	require.Greater(t, body.Len(), maxBufferedBodyBytes)
+
+	mockCtrl := gomock.NewController(t)
+	mockStorage := mocks.NewMockStorage(mockCtrl)
+	mockStorage.EXPECT().CreateAccounts(gomock.Any(), gomock.Len(rows), true).Return(make([]error, rows), nil)
+
+	s := Server{cfg: &config.Config{}, store: mockStorage}
+	r := mux.NewRouter()
+	s.BindRoutes(r)
+
+	req := httptest.NewRequest(http.MethodPost, "/accounts/import", strings.NewReader(body.String()))
+	req.Header.Set("Content-Type", "text/csv")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.JSONEq(t, fmt.Sprintf(`{"mode":"atomic","rows":%d,"created":%d,"failed":0,"errors":[]}`, rows, rows), w.Body.String())
+}
+
+// TestExportAccounts validates the CSV export, its paging through every account and errors before and
+// after the export has started.
+func TestExportAccounts(t *testing.T) {
+	createdAt := time.Date(2025, 12, 1, 12, 0, 0, 0, time.UTC)
+	account := func(id string) storage.Account {
+		return storage.Account{
+			ID:                id,
+			Balance:           decimal.RequireFromString("99.5"),
+			Status:            storage.AccountStatusActive,
+			AccountAttributes: storage.AccountAttributes{Currency: "EUR", Name: "Payroll, EU", Type: storage.AccountTypeCustomer, Labels: []string{"vip"}, Metadata: json.RawMessage(`{"branch":"7"}`)},
+			Version:           2,
+			CreatedAt:         createdAt,
+			UpdatedAt:         createdAt.Add(time.Hour),
+		}
+	}
+	fullPage := make([]storage.Account, maxAccountsPageSize)
+	for i := range fullPage {
+		fullPage[i] = account(fmt.Sprintf("acc-%03d", i))
+	}
+	query := storage.AccountQuery{Sort: storage.AccountSortCreatedAt, Limit: maxAccountsPageSize}
+
+	tests := []struct {
+		name            string
+		query           string
+		principal       *principal
+		mockSetup       func(m *mocks.MockStorage)
+		expectedStatus  int
+		expectedRecords [][]string
+		expectedRows    int
+		expectedBody    string
+	}{
+		{
+			name:  "filtered, ordered by creation instead of balance",
+			query: "?type=customer&sort=-balance&limit=1",
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().ListAccounts(gomock.Any(), storage.AccountQuery{Type: storage.AccountTypeCustomer, Sort: storage.AccountSortCreatedAt, Limit: maxAccountsPageSize}).
+					Return([]storage.Account{account("acc-1")}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedRecords: [][]string{
+				exportColumns,
+				{"acc-1", "99.5", "EUR", "active", "Payroll, EU", "", "customer", `["vip"]`, `{"branch":"7"}`, "2", "2025-12-01T12:00:00Z", "2025-12-01T13:00:00Z"},
+			},
+		},
+		{
+			name:  "cells read as formulas are escaped",
+			query: "?sort=-created_at",
+			mockSetup: func(m *mocks.MockStorage) {
+				acc := account("acc-1")
+				acc.Name, acc.OwnerRef = `=HYPERLINK("x")`, "@ops"
+				m.EXPECT().ListAccounts(gomock.Any(), storage.AccountQuery{Sort: storage.AccountSortCreatedAt, Descending: true, Limit: maxAccountsPageSize}).
+					Return([]storage.Account{acc}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedRecords: [][]string{
+				exportColumns,
+				{"acc-1", "99.5", "EUR", "active", `'=HYPERLINK("x")`, "'@ops", "customer", `["vip"]`, `{"branch":"7"}`, "2", "2025-12-01T12:00:00Z", "2025-12-01T13:00:00Z"},
+			},
+		},
+		{
+			name:      "restricted caller without accounts",
+			principal: &principal{ClientID: "reporting", AccountRestricted: true},
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().ListAccounts(gomock.Any(), storage.AccountQuery{IDs: []string{}, Sort: storage.AccountSortCreatedAt, Limit: maxAccountsPageSize}).Return(nil, nil)
+			},
+			expectedStatus:  http.StatusOK,
+			expectedRecords: [][]string{exportColumns},
+		},
+		{
+			name: "several pages",
+			mockSetup: func(m *mocks.MockStorage) {
+				next := query
+				next.After = &fullPage[maxAccountsPageSize-1]
+				gomock.InOrder(
+					m.EXPECT().ListAccounts(gomock.Any(), query).Return(fullPage, nil),
+					m.EXPECT().ListAccounts(gomock.Any(), next).Return([]storage.Account{account("acc-500")}, nil),
+				)
+			},
+			expectedStatus: http.StatusOK,
+			expectedRows:   maxAccountsPageSize + 1,
+		},
+		{
+			name:           "invalid filter",
+			query:          "?status=open",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   ErrUnknownAccountStatus.Error() + "\n",
+		},
+		{
+			name: "internal error",
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().ListAccounts(gomock.Any(), query).Return(nil, errors.New(storage.ErrListAccountsMsg))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   storage.ErrListAccountsMsg + "\n",
+		},
+		{
+			name: "internal error after the first page",
+			mockSetup: func(m *mocks.MockStorage) {
+				gomock.InOrder(
+					m.EXPECT().ListAccounts(gomock.Any(), query).Return(fullPage, nil),
+					m.EXPECT().ListAccounts(gomock.Any(), gomock.Any()).Return(nil, errors.New(storage.ErrListAccountsMsg)),
+				)
+			},
+			expectedStatus: http.StatusOK,
+			expectedRows:   maxAccountsPageSize,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockStorage := mocks.NewMockStorage(mockCtrl)
+			if tc.mockSetup != nil {
+				tc.mockSetup(mockStorage)
+			}
+
+			s := Server{cfg: &config.Config{}, store: mockStorage}
+			r := mux.NewRouter()
+			s.BindRoutes(r)
+
+			req := httptest.NewRequest(http.MethodGet, "/accounts/export"+tc.query, nil)
+			if tc.principal != nil {
+				req = req.WithContext(withPrincipal(req.Context(), tc.principal))
+			}
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedStatus != http.StatusOK {
+				assert.Equal(t, tc.expectedBody, w.Body.String())
+				return
+			}
+
+			assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+			assert.Equal(t, `attachment; filename=accounts.csv`, w.Header().Get("Content-Disposition"))
+			records, err := csv.NewReader(w.Body).ReadAll()
+			require.NoError(t, err)
+			if tc.expectedRecords != nil {
+				assert.Equal(t, tc.expectedRecords, records)
+			} else {
+				assert.Len(t, records, tc.expectedRows+1)
+			}
+		})
+	}
+}
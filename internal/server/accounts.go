@@ -0,0 +1,198 @@
+package server
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+	"github.com/cursed-ninja/internal-transfers-system/internal/utils"
+	"github.com/gorilla/mux"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultAccountsPageSize is used when a GET /accounts request does not set a limit.
+	defaultAccountsPageSize = 50
+	// maxAccountsPageSize caps the limit of a GET /accounts request.
+	maxAccountsPageSize = 500
+)
+
+// ErrInvalidCursor is returned for a cursor that was not issued for the requested sort order.
+var ErrInvalidCursor = errors.New("cursor is invalid or does not match sort")
+
+type listAccountsResponse struct {
+	Accounts []accountResponse `json:"accounts"`
+	// NextCursor fetches the next page; empty on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+	// Total is the number of accounts matching the filters, when include_total is set.
+	Total *int64 `json:"total,omitempty"`
+}
+
+// accountCursor is the position after the last account of a page. It is encoded as base64url JSON.
+type accountCursor struct {
+	// Sort is the sort order the cursor was issued for, e.g. "-balance".
+	Sort string `json:"s"`
+	// Key is the sort key of the last account: its balance or RFC 3339 creation time.
+	Key string `json:"k"`
+	ID  string `json:"id"`
+}
+
+// ListAccounts handles GET /accounts requests, returning a page of accounts matching the filters in
+// the query string. Callers restricted to specific accounts only see those accounts.
+func (s *Server) ListAccounts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := utils.ContextLogger(ctx)
+
+	logger.Info("received ListAccounts request")
+
+	values := r.URL.Query()
+	query, includeTotal, err := ValidateListAccounts(values)
+	if err != nil {
+		logger.Error("failed to validate request", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if raw := values.Get("cursor"); raw != "" {
+		if query.After, err = decodeAccountCursor(raw, query); err != nil {
+			logger.Error("failed to decode cursor", zap.Error(err))
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if p := principalFromContext(ctx); p != nil {
+		query.IDs = p.readableAccounts()
+	}
+
+	// Fetch one extra row to tell whether another page follows.
+	pageSize := query.Limit
+	query.Limit++
+	accounts, err := s.store.ListAccounts(ctx, query)
+	if err != nil {
+		logger.Error("failed to list accounts", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	resp := listAccountsResponse{Accounts: make([]accountResponse, 0, min(len(accounts), pageSize))}
+	if len(accounts) > pageSize {
+		accounts = accounts[:pageSize]
+		resp.NextCursor = encodeAccountCursor(&accounts[pageSize-1], query)
+	}
+	for i := range accounts {
+		resp.Accounts = append(resp.Accounts, newAccountResponse(&accounts[i]))
+	}
+
+	if includeTotal {
+		total, err := s.store.CountAccounts(ctx, query)
+		if err != nil {
+			logger.Error("failed to count accounts", zap.Error(err))
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		resp.Total = &total
+	}
+
+	logger.Info("accounts listed successfully", zap.Int("count", len(resp.Accounts)))
+	writeJSON(w, logger, http.StatusOK, resp)
+}
+
+// CloseAccount handles POST /accounts/{accountID}/close requests. Only active accounts with a zero
+// balance can be closed; closed accounts can no longer send or receive transfers. An If-Match header
+// makes the request conditional on the account's ETag, failing with 412 if it has changed since.
+func (s *Server) CloseAccount(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := utils.ContextLogger(ctx)
+
+	logger.Info("received CloseAccount request")
+
+	accountID := strings.TrimSpace(mux.Vars(r)["accountID"])
+	if accountID == "" {
+		logger.Error("missing account_id in URL path")
+		http.Error(w, "account_id is required in URL path", http.StatusBadRequest)
+		return
+	}
+
+	ctx, logger = utils.LoggerWithKey(ctx, zap.String("account_id", accountID))
+
+	version, _, err := ifMatchVersion(r)
+	if err != nil {
+		logger.Error("failed to parse If-Match header", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if p := principalFromContext(ctx); p != nil && !p.canDebit(accountID) {
+		logger.Warn("caller is not allowed to close account")
+		http.Error(w, ErrAccountForbidden.Error(), http.StatusForbidden)
+		return
+	}
+
+	acc, err := s.store.CloseAccount(ctx, accountID, version)
+	if err != nil {
+		logger.Error("failed to close account", zap.Error(err))
+		errorMsg := err.Error()
+		statusCode := http.StatusInternalServerError
+		switch errorMsg {
+		case storage.ErrAccountNotFound:
+			statusCode = http.StatusNotFound
+		case storage.ErrAccountVersionMsg:
+			statusCode = http.StatusPreconditionFailed
+		case storage.ErrAccountClosedMsg, storage.ErrAccountNotEmptyMsg:
+			statusCode = http.StatusConflict
+		}
+		http.Error(w, errorMsg, statusCode)
+		return
+	}
+
+	logger.Info("account closed successfully", zap.Int64("version", acc.Version))
+	w.Header().Set("ETag", accountETag(acc.Version))
+	writeJSON(w, logger, http.StatusOK, newAccountResponse(acc))
+}
+
+// sortName returns the query's sort order as given in the sort parameter, e.g. "-balance".
+func sortName(query storage.AccountQuery) string {
+	if query.Descending {
+		return "-" + query.Sort
+	}
+	return query.Sort
+}
+
+// encodeAccountCursor returns the cursor for the page following acc.
+func encodeAccountCursor(acc *storage.Account, query storage.AccountQuery) string {
+	cursor := accountCursor{Sort: sortName(query), ID: acc.ID}
+	if query.Sort == storage.AccountSortBalance {
+		cursor.Key = acc.Balance.String()
+	} else {
+		cursor.Key = acc.CreatedAt.Format(time.RFC3339Nano)
+	}
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeAccountCursor parses a cursor issued by encodeAccountCursor for the same sort order into the
+// position listing resumes after.
+func decodeAccountCursor(raw string, query storage.AccountQuery) (*storage.Account, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor accountCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.Sort != sortName(query) || cursor.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+
+	after := &storage.Account{ID: cursor.ID}
+	if query.Sort == storage.AccountSortBalance {
+		if after.Balance, err = decimal.NewFromString(cursor.Key); err != nil {
+			return nil, ErrInvalidCursor
+		}
+	} else if after.CreatedAt, err = time.Parse(time.RFC3339Nano, cursor.Key); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return after, nil
+}
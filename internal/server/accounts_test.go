@@ -0,0 +1,372 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/config"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage/mocks"
+	"github.com/gorilla/mux"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestListAccounts tests the ListAccounts endpoint: filters, sorting, pagination, totals, callers
+// restricted to specific accounts and storage failures.
+func TestListAccounts(t *testing.T) {
+	createdAt := time.Date(2025, 12, 1, 12, 0, 0, 0, time.UTC)
+	account := func(id, balance string) storage.Account {
+		return storage.Account{
+			ID:                id,
+			Balance:           decimal.RequireFromString(balance),
+			Status:            storage.AccountStatusActive,
+			AccountAttributes: storage.AccountAttributes{Currency: "EUR", Type: storage.AccountTypeCustomer, Labels: []string{"vip"}},
+			Version:           1,
+			CreatedAt:         createdAt,
+			UpdatedAt:         createdAt,
+		}
+	}
+	minBalance := decimal.RequireFromString("10")
+	maxBalance := decimal.RequireFromString("1000")
+	balanceDesc := storage.AccountQuery{Sort: storage.AccountSortBalance, Descending: true, Limit: 1}
+	balanceCursor := encodeAccountCursor(&storage.Account{ID: "acc-2", Balance: decimal.RequireFromString("99.5")}, balanceDesc)
+	createdCursor := encodeAccountCursor(&storage.Account{ID: "acc-1", CreatedAt: createdAt}, storage.AccountQuery{Sort: storage.AccountSortCreatedAt})
+
+	tests := []struct {
+		name           string
+		query          string
+		principal      *principal
+		mockSetup      func(m *mocks.MockStorage)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "defaults",
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().ListAccounts(gomock.Any(), storage.AccountQuery{Sort: storage.AccountSortCreatedAt, Limit: defaultAccountsPageSize + 1}).
+					Return([]storage.Account{account("acc-1", "100")}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"accounts":[{"account_id":"acc-1","balance":"100","currency":"EUR","status":"active","name":"","owner_ref":"",
+				"type":"customer","labels":["vip"],"metadata":{},"version":1,"created_at":"2025-12-01T12:00:00Z","updated_at":"2025-12-01T12:00:00Z"}]}`,
+		},
+		{
+			name:  "filters with next page",
+			query: "type=customer&status=active&currency=eur&label=vip&label=eu&label=vip&min_balance=10&max_balance=1000&sort=-balance&limit=1",
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().ListAccounts(gomock.Any(), storage.AccountQuery{
+					Type:       storage.AccountTypeCustomer,
+					Status:     storage.AccountStatusActive,
+					Currency:   "EUR",
+					Labels:     []string{"vip", "eu"},
+					MinBalance: &minBalance,
+					MaxBalance: &maxBalance,
+					Sort:       storage.AccountSortBalance,
+					Descending: true,
+					Limit:      2,
+				}).Return([]storage.Account{account("acc-2", "99.5"), account("acc-1", "50")}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"accounts":[{"account_id":"acc-2","balance":"99.5","currency":"EUR","status":"active","name":"","owner_ref":"",
+				"type":"customer","labels":["vip"],"metadata":{},"version":1,"created_at":"2025-12-01T12:00:00Z","updated_at":"2025-12-01T12:00:00Z"}],
+				"next_cursor":"` + balanceCursor + `"}`,
+		},
+		{
+			name:  "balance cursor",
+			query: "sort=-balance&limit=1&cursor=" + balanceCursor,
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().ListAccounts(gomock.Any(), storage.AccountQuery{
+					Sort:       storage.AccountSortBalance,
+					Descending: true,
+					After:      &storage.Account{ID: "acc-2", Balance: decimal.RequireFromString("99.5")},
+					Limit:      2,
+				}).Return([]storage.Account{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"accounts":[]}`,
+		},
+		{
+			name:  "creation time cursor",
+			query: "cursor=" + createdCursor,
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().ListAccounts(gomock.Any(), storage.AccountQuery{
+					Sort:  storage.AccountSortCreatedAt,
+					After: &storage.Account{ID: "acc-1", CreatedAt: createdAt},
+					Limit: defaultAccountsPageSize + 1,
+				}).Return([]storage.Account{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:  "include total",
+			query: "status=closed&include_total=true",
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().ListAccounts(gomock.Any(), gomock.Any()).Return([]storage.Account{}, nil)
+				m.EXPECT().CountAccounts(gomock.Any(), gomock.Any()).DoAndReturn(func(_ any, q storage.AccountQuery) (int64, error) {
+					assert.Equal(t, storage.AccountStatusClosed, q.Status)
+					return 7, nil
+				})
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"accounts":[],"total":7}`,
+		},
+		{
+			name:      "restricted caller",
+			principal: &principal{ClientID: "user", AccountRestricted: true, ReadAccounts: map[string]bool{"acc-2": true}, DebitAccounts: map[string]bool{"acc-1": true}},
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().ListAccounts(gomock.Any(), storage.AccountQuery{
+					IDs:   []string{"acc-1", "acc-2"},
+					Sort:  storage.AccountSortCreatedAt,
+					Limit: defaultAccountsPageSize + 1,
+				}).Return([]storage.Account{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:      "caller with wildcard access",
+			principal: &principal{ClientID: "user", AccountRestricted: true, ReadAccounts: map[string]bool{wildcardAccount: true}},
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().ListAccounts(gomock.Any(), storage.AccountQuery{Sort: storage.AccountSortCreatedAt, Limit: defaultAccountsPageSize + 1}).
+					Return([]storage.Account{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "cursor for another sort",
+			query:          "sort=balance&cursor=" + balanceCursor,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "malformed cursor",
+			query:          "cursor=not-a-cursor",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown sort",
+			query:          "sort=name",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown status",
+			query:          "status=frozen",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid currency",
+			query:          "currency=EURO",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "inverted balance range",
+			query:          "min_balance=10&max_balance=1",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid limit",
+			query:          "limit=0",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid include_total",
+			query:          "include_total=maybe",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "list error",
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().ListAccounts(gomock.Any(), gomock.Any()).Return(nil, errors.New(storage.ErrListAccountsMsg))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:  "count error",
+			query: "include_total=1",
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().ListAccounts(gomock.Any(), gomock.Any()).Return([]storage.Account{}, nil)
+				m.EXPECT().CountAccounts(gomock.Any(), gomock.Any()).Return(int64(0), errors.New(storage.ErrCountAccountsMsg))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockStorage := mocks.NewMockStorage(mockCtrl)
+			if tc.mockSetup != nil {
+				tc.mockSetup(mockStorage)
+			}
+
+			s := Server{cfg: &config.Config{}, store: mockStorage}
+			r := mux.NewRouter()
+			s.BindRoutes(r)
+
+			req := httptest.NewRequest(http.MethodGet, "/accounts?"+tc.query, nil)
+			if tc.principal != nil {
+				req = req.WithContext(withPrincipal(req.Context(), tc.principal))
+			}
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedBody != "" {
+				assert.JSONEq(t, tc.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+// TestCloseAccount tests the CloseAccount endpoint: conditional and unconditional closes, accounts
+// that cannot be closed, and callers without write access to the account.
+func TestCloseAccount(t *testing.T) {
+	closed := &storage.Account{
+		ID:                "acc-1",
+		Balance:           decimal.Zero,
+		Status:            storage.AccountStatusClosed,
+		AccountAttributes: storage.AccountAttributes{Currency: storage.DefaultCurrency},
+		Version:           5,
+	}
+
+	tests := []struct {
+		name           string
+		ifMatch        string
+		principal      *principal
+		mockSetup      func(m *mocks.MockStorage)
+		expectedStatus int
+		expectedETag   string
+	}{
+		{
+			name:    "conditional close",
+			ifMatch: `"4"`,
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().CloseAccount(gomock.Any(), "acc-1", int64(4)).Return(closed, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedETag:   `"5"`,
+		},
+		{
+			name: "unconditional close",
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().CloseAccount(gomock.Any(), "acc-1", storage.AnyVersion).Return(closed, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedETag:   `"5"`,
+		},
+		{
+			name:    "stale If-Match",
+			ifMatch: `"3"`,
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().CloseAccount(gomock.Any(), "acc-1", int64(3)).Return(nil, errors.New(storage.ErrAccountVersionMsg))
+			},
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:           "invalid If-Match",
+			ifMatch:        "4",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "already closed",
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().CloseAccount(gomock.Any(), "acc-1", storage.AnyVersion).Return(nil, errors.New(storage.ErrAccountClosedMsg))
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "non-zero balance",
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().CloseAccount(gomock.Any(), "acc-1", storage.AnyVersion).Return(nil, errors.New(storage.ErrAccountNotEmptyMsg))
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "account not found",
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().CloseAccount(gomock.Any(), "acc-1", storage.AnyVersion).Return(nil, errors.New(storage.ErrAccountNotFound))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "read-only caller",
+			principal:      &principal{ClientID: "user", AccountRestricted: true, ReadAccounts: map[string]bool{"acc-1": true}},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name: "internal error",
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().CloseAccount(gomock.Any(), "acc-1", storage.AnyVersion).Return(nil, errors.New(storage.ErrCloseAccountMsg))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockStorage := mocks.NewMockStorage(mockCtrl)
+			if tc.mockSetup != nil {
+				tc.mockSetup(mockStorage)
+			}
+
+			s := Server{cfg: &config.Config{}, store: mockStorage}
+			r := mux.NewRouter()
+			s.BindRoutes(r)
+
+			req := httptest.NewRequest(http.MethodPost, "/accounts/acc-1/close", nil)
+			if tc.ifMatch != "" {
+				req.Header.Set("If-Match", tc.ifMatch)
+			}
+			if tc.principal != nil {
+				req = req.WithContext(withPrincipal(req.Context(), tc.principal))
+			}
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Equal(t, tc.expectedETag, w.Header().Get("ETag"))
+		})
+	}
+}
+
+// TestAccountCursor verifies cursors round-trip and are rejected for a different sort order.
+func TestAccountCursor(t *testing.T) {
+	createdAt := time.Date(2025, 12, 1, 12, 0, 0, 123456789, time.UTC)
+	acc := &storage.Account{ID: "acc-1", Balance: decimal.RequireFromString("12.34567"), CreatedAt: createdAt}
+
+	for _, query := range []storage.AccountQuery{
+		{Sort: storage.AccountSortCreatedAt},
+		{Sort: storage.AccountSortCreatedAt, Descending: true},
+		{Sort: storage.AccountSortBalance},
+		{Sort: storage.AccountSortBalance, Descending: true},
+	} {
+		t.Run(sortName(query), func(t *testing.T) {
+			cursor := encodeAccountCursor(acc, query)
+
+			after, err := decodeAccountCursor(cursor, query)
+			assert.NoError(t, err)
+			assert.Equal(t, acc.ID, after.ID)
+			if query.Sort == storage.AccountSortBalance {
+				assert.True(t, acc.Balance.Equal(after.Balance))
+			} else {
+				assert.True(t, acc.CreatedAt.Equal(after.CreatedAt))
+			}
+
+			other := query
+			other.Descending = !query.Descending
+			_, err = decodeAccountCursor(cursor, other)
+			assert.ErrorIs(t, err, ErrInvalidCursor)
+		})
+	}
+
+	data, _ := json.Marshal(accountCursor{Sort: storage.AccountSortBalance, Key: "not-a-number", ID: "acc-1"})
+	_, err := decodeAccountCursor(string(data), storage.AccountQuery{Sort: storage.AccountSortBalance})
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
@@ -0,0 +1,187 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+	"github.com/cursed-ninja/internal-transfers-system/internal/utils"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+type createAPIKeyRequest struct {
+	ClientID string   `json:"client_id"`
+	Scopes   []string `json:"scopes"`
+}
+
+type apiKeyResponse struct {
+	ID        string     `json:"id"`
+	ClientID  string     `json:"client_id"`
+	Scopes    []string   `json:"scopes"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	// Key is the plaintext API key. It is only returned once, when the key is created.
+	Key string `json:"key,omitempty"`
+}
+
+// LogLevelHandler handles GET and PUT /admin/log-level requests.
+// GET returns the current level as {"level":"info"}; PUT with the same body changes it at runtime.
+func (s *Server) LogLevelHandler(w http.ResponseWriter, r *http.Request) {
+	logger := utils.ContextLogger(r.Context())
+	if r.Method == http.MethodPut {
+		logger.Info("received log level change request", zap.String("current_level", s.logLevel.String()))
+	}
+	s.logLevel.ServeHTTP(w, r)
+}
+
+// CreateAPIKey handles POST /admin/api-keys requests to issue a new API key.
+// The plaintext key is returned once in the response; only its hash is stored.
+func (s *Server) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := utils.ContextLogger(ctx)
+	logger.Info("received CreateAPIKey request")
+
+	var req createAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("failed to parse request body", zap.Error(err))
+		http.Error(w, "invalid JSON format", http.StatusBadRequest)
+		return
+	}
+
+	if err := ValidateCreateAPIKey(&req); err != nil {
+		logger.Error("failed to validate request", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	plaintext, err := generateAPIKey()
+	if err != nil {
+		logger.Error("failed to generate api key", zap.Error(err))
+		http.Error(w, storage.ErrCreateAPIKeyMsg, http.StatusInternalServerError)
+		return
+	}
+
+	key := &storage.APIKey{
+		ID:       uuid.NewString(),
+		ClientID: req.ClientID,
+		KeyHash:  hashAPIKey(plaintext),
+		Scopes:   req.Scopes,
+	}
+	ctx, logger = utils.LoggerWithKey(ctx, zap.String("api_key_id", key.ID))
+
+	if err := s.store.CreateAPIKey(ctx, key); err != nil {
+		logger.Error("failed to create api key", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := newAPIKeyResponse(key)
+	response.Key = plaintext
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Error("failed to encode response", zap.Error(err))
+		return
+	}
+	logger.Info("api key created successfully", zap.String("api_key_client_id", key.ClientID))
+}
+
+// ListAPIKeys handles GET /admin/api-keys requests.
+func (s *Server) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := utils.ContextLogger(ctx)
+	logger.Info("received ListAPIKeys request")
+
+	keys, err := s.store.ListAPIKeys(ctx)
+	if err != nil {
+		logger.Error("failed to list api keys", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := make([]apiKeyResponse, 0, len(keys))
+	for i := range keys {
+		response = append(response, newAPIKeyResponse(&keys[i]))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Error("failed to encode response", zap.Error(err))
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// RevokeAPIKey handles DELETE /admin/api-keys/{keyID} requests.
+func (s *Server) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := utils.ContextLogger(ctx)
+	logger.Info("received RevokeAPIKey request")
+
+	keyID := strings.TrimSpace(mux.Vars(r)["keyID"])
+	if keyID == "" {
+		logger.Error("missing key_id in URL path")
+		http.Error(w, "key_id is required in URL path", http.StatusBadRequest)
+		return
+	}
+	ctx, logger = utils.LoggerWithKey(ctx, zap.String("api_key_id", keyID))
+
+	if err := s.store.RevokeAPIKey(ctx, keyID); err != nil {
+		logger.Error("failed to revoke api key", zap.Error(err))
+		errorMsg := err.Error()
+		statusCode := http.StatusInternalServerError
+		if errorMsg == storage.ErrAPIKeyNotFound {
+			statusCode = http.StatusNotFound
+		}
+		http.Error(w, errorMsg, statusCode)
+		return
+	}
+
+	logger.Info("api key revoked successfully")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// newAPIKeyResponse converts a stored key to its API representation, without the plaintext key.
+func newAPIKeyResponse(key *storage.APIKey) apiKeyResponse {
+	return apiKeyResponse{
+		ID:        key.ID,
+		ClientID:  key.ClientID,
+		Scopes:    key.Scopes,
+		CreatedAt: key.CreatedAt,
+		RevokedAt: key.RevokedAt,
+	}
+}
+
+// VerifyAuditChain handles GET /admin/audit/verify requests.
+// It walks the audit log from the genesis event and reports the first broken link, if any.
+// Responds 200 when the chain is intact and 409 when it has been tampered with.
+func (s *Server) VerifyAuditChain(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := utils.ContextLogger(ctx)
+	logger.Info("received VerifyAuditChain request")
+
+	result, err := s.store.VerifyAuditChain(ctx)
+	if err != nil {
+		logger.Error("failed to verify audit chain", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	statusCode := http.StatusOK
+	if !result.Valid {
+		logger.Warn("audit chain verification failed", zap.Int64("broken_at_id", result.BrokenAtID), zap.String("reason", result.Reason))
+		statusCode = http.StatusConflict
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		logger.Error("failed to encode response", zap.Error(err))
+		return
+	}
+}
@@ -0,0 +1,216 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/config"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage/mocks"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestLogLevelHandler tests reading and changing the root logger level at runtime.
+func TestLogLevelHandler(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	level := zap.NewAtomicLevelAt(zapcore.InfoLevel)
+	s := NewServer(&config.Config{}, mocks.NewMockStorage(mockCtrl), zap.NewNop(), level)
+
+	r := mux.NewRouter()
+	s.BindRoutes(r)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/log-level", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"level":"info"}`, w.Body.String())
+
+	req = httptest.NewRequest(http.MethodPut, "/admin/log-level", bytes.NewBufferString(`{"level":"debug"}`))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, zapcore.DebugLevel, level.Level())
+
+	req = httptest.NewRequest(http.MethodPut, "/admin/log-level", bytes.NewBufferString(`{"level":"loud"}`))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, zapcore.DebugLevel, level.Level())
+}
+
+// TestCreateAPIKey tests the CreateAPIKey endpoint.
+// Scenarios include successful creation, invalid inputs, and internal errors.
+func TestCreateAPIKey(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		mockSetup      func(m *mocks.MockStorage)
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			body: `{"client_id":"payroll","scopes":["transactions:write","transactions:write"]}`,
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().CreateAPIKey(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, key *storage.APIKey) error {
+					assert.Equal(t, "payroll", key.ClientID)
+					assert.Equal(t, []string{ScopeTransactionsWrite}, key.Scopes)
+					assert.Len(t, key.KeyHash, 64)
+					return nil
+				})
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "invalid json",
+			body:           `{bad json`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing client id",
+			body:           `{"scopes":["accounts:read"]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing scopes",
+			body:           `{"client_id":"payroll","scopes":[]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown scope",
+			body:           `{"client_id":"payroll","scopes":["accounts:delete"]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "internal server error",
+			body: `{"client_id":"payroll","scopes":["accounts:read"]}`,
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().CreateAPIKey(gomock.Any(), gomock.Any()).Return(errors.New(storage.ErrCreateAPIKeyMsg))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockStorage := mocks.NewMockStorage(mockCtrl)
+			if tc.mockSetup != nil {
+				tc.mockSetup(mockStorage)
+			}
+			s := Server{cfg: &config.Config{}, store: mockStorage}
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/api-keys", bytes.NewBufferString(tc.body))
+			w := httptest.NewRecorder()
+			s.CreateAPIKey(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedStatus == http.StatusCreated {
+				var resp apiKeyResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				assert.True(t, strings.HasPrefix(resp.Key, apiKeyPrefix))
+			}
+		})
+	}
+}
+
+// TestRevokeAPIKey tests the RevokeAPIKey endpoint.
+// Scenarios include successful revocation, unknown keys, and internal errors.
+func TestRevokeAPIKey(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockErr        error
+		expectedStatus int
+	}{
+		{
+			name:           "success",
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "not found",
+			mockErr:        errors.New(storage.ErrAPIKeyNotFound),
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "internal error",
+			mockErr:        errors.New(storage.ErrRevokeAPIKeyMsg),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockStorage := mocks.NewMockStorage(mockCtrl)
+			mockStorage.EXPECT().RevokeAPIKey(gomock.Any(), "key-1").Return(tc.mockErr)
+			s := Server{cfg: &config.Config{}, store: mockStorage}
+
+			r := mux.NewRouter()
+			s.BindRoutes(r)
+
+			req := httptest.NewRequest(http.MethodDelete, "/admin/api-keys/key-1", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+		})
+	}
+}
+
+// TestVerifyAuditChain validates the audit verification endpoint for intact, broken and failing chains.
+func TestVerifyAuditChain(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockResult     *storage.AuditVerification
+		mockErr        error
+		expectedStatus int
+	}{
+		{
+			name:           "intact chain",
+			mockResult:     &storage.AuditVerification{Valid: true, EventsChecked: 3, HeadHash: "abc"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "broken chain",
+			mockResult:     &storage.AuditVerification{EventsChecked: 1, HeadHash: "abc", BrokenAtID: 2, Reason: "hash does not match the event content"},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "storage error",
+			mockErr:        errors.New(storage.ErrVerifyAuditChainMsg),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockStorage := mocks.NewMockStorage(mockCtrl)
+			mockStorage.EXPECT().VerifyAuditChain(gomock.Any()).Return(tc.mockResult, tc.mockErr)
+			s := Server{cfg: &config.Config{}, store: mockStorage}
+
+			r := mux.NewRouter()
+			s.BindRoutes(r)
+
+			req := httptest.NewRequest(http.MethodGet, "/admin/audit/verify", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.mockResult != nil {
+				var body storage.AuditVerification
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+				assert.Equal(t, *tc.mockResult, body)
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+	"github.com/cursed-ninja/internal-transfers-system/internal/transfers"
+	"github.com/cursed-ninja/internal-transfers-system/internal/utils"
+	"go.uber.org/zap"
+)
+
+// Scopes that can be granted to API keys.
+const (
+	ScopeAccountsRead      = "accounts:read"
+	ScopeAccountsWrite     = "accounts:write"
+	ScopeTransactionsWrite = "transactions:write"
+	ScopeWebhooksManage    = "webhooks:manage"
+	ScopeAdmin             = "admin"
+)
+
+const (
+	// apiKeyHeader carries the plaintext API key.
+	apiKeyHeader = "X-API-Key"
+	// apiKeyPrefix is prepended to generated keys so they are recognisable in secret scanners.
+	apiKeyPrefix = "itk_"
+	// webhookSecretPrefix is prepended to generated webhook signing secrets.
+	webhookSecretPrefix = "whsec_"
+	// bootstrapClientID identifies callers using the configured bootstrap admin key.
+	bootstrapClientID = "bootstrap-admin"
+)
+
+// Authentication and authorization errors.
+var (
+	ErrMissingCredentials = errors.New("missing credentials")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrInsufficientScope  = errors.New("insufficient scope")
+	ErrAccountForbidden   = transfers.ErrAccountForbidden
+)
+
+// knownScopes are the scopes accepted when issuing API keys.
+var knownScopes = map[string]bool{
+	ScopeAccountsRead:      true,
+	ScopeAccountsWrite:     true,
+	ScopeTransactionsWrite: true,
+	ScopeWebhooksManage:    true,
+	ScopeAdmin:             true,
+}
+
+type principalContextKey struct{}
+
+// principal is the authenticated caller of a request.
+type principal struct {
+	ClientID string
+	Scopes   map[string]bool
+	// AccountRestricted limits the caller to the accounts in ReadAccounts and DebitAccounts.
+	// Service-to-service API keys are unrestricted.
+	AccountRestricted bool
+	ReadAccounts      map[string]bool
+	DebitAccounts     map[string]bool
+}
+
+// hasScopes reports whether the principal holds every given scope. The admin scope implies all others.
+func (p *principal) hasScopes(scopes ...string) bool {
+	if p.Scopes[ScopeAdmin] {
+		return true
+	}
+	for _, scope := range scopes {
+		if !p.Scopes[scope] {
+			return false
+		}
+	}
+	return true
+}
+
+// canRead reports whether the principal may read the given account. Debit access implies read access.
+func (p *principal) canRead(accountID string) bool {
+	if !p.AccountRestricted {
+		return true
+	}
+	return p.ReadAccounts[wildcardAccount] || p.ReadAccounts[accountID] || p.canDebit(accountID)
+}
+
+// readableAccounts returns the accounts the principal may read, or nil if it may read every account.
+func (p *principal) readableAccounts() []string {
+	if !p.AccountRestricted || p.ReadAccounts[wildcardAccount] || p.DebitAccounts[wildcardAccount] {
+		return nil
+	}
+	ids := make([]string, 0, len(p.ReadAccounts)+len(p.DebitAccounts))
+	for id := range p.ReadAccounts {
+		ids = append(ids, id)
+	}
+	for id := range p.DebitAccounts {
+		if !p.ReadAccounts[id] {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// canDebit reports whether the principal may move funds out of the given account.
+func (p *principal) canDebit(accountID string) bool {
+	if !p.AccountRestricted {
+		return true
+	}
+	return p.DebitAccounts[wildcardAccount] || p.DebitAccounts[accountID]
+}
+
+// middleware wraps an http.Handler.
+type middleware func(http.Handler) http.Handler
+
+// requireScopes authenticates the caller and rejects requests lacking any of the given scopes.
+// Missing or invalid credentials return 401; insufficient scopes return 403.
+// When authentication is disabled, requests pass through unchanged.
+func (s *Server) requireScopes(scopes ...string) middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !s.authEnabled() {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := r.Context()
+			logger := utils.ContextLogger(ctx)
+
+			p, err := s.authenticate(r)
+			if err != nil {
+				logger.Warn("authentication failed", zap.Error(err))
+				if !errors.Is(err, ErrMissingCredentials) && !errors.Is(err, ErrInvalidCredentials) {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+				w.Header().Set("WWW-Authenticate", `Bearer, ApiKey header="`+apiKeyHeader+`"`)
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+
+			ctx = withPrincipal(ctx, p)
+			ctx, logger = utils.LoggerWithKey(ctx, zap.String("client_id", p.ClientID))
+
+			if !p.hasScopes(scopes...) {
+				logger.Warn("caller lacks required scopes", zap.Strings("required_scopes", scopes))
+				http.Error(w, ErrInsufficientScope.Error(), http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// authEnabled reports whether authentication is configured on.
+func (s *Server) authEnabled() bool {
+	return s.cfg != nil && s.cfg.Auth != nil && s.cfg.Auth.Enabled
+}
+
+// authenticate resolves the caller from the request credentials.
+// Bearer tokens are verified as JWTs, then API keys are checked, then verified TLS client certificates.
+func (s *Server) authenticate(r *http.Request) (*principal, error) {
+	if token := bearerToken(r); token != "" {
+		if s.jwt == nil {
+			return nil, fmt.Errorf("%w: bearer tokens are not accepted", ErrInvalidCredentials)
+		}
+		return s.jwt.Verify(token)
+	}
+
+	if key := apiKeyFromRequest(r); key != "" {
+		return s.authenticateAPIKey(r.Context(), key)
+	}
+
+	if p, ok, err := s.clientCertPrincipal(r); ok {
+		return p, err
+	}
+	return nil, ErrMissingCredentials
+}
+
+// authenticateAPIKey resolves a plaintext API key to a principal.
+func (s *Server) authenticateAPIKey(ctx context.Context, key string) (*principal, error) {
+	if bootstrap := s.cfg.Auth.BootstrapAdminKey; bootstrap != "" &&
+		subtle.ConstantTimeCompare([]byte(key), []byte(bootstrap)) == 1 {
+		return &principal{ClientID: bootstrapClientID, Scopes: map[string]bool{ScopeAdmin: true}}, nil
+	}
+
+	apiKey, err := s.store.GetAPIKeyByHash(ctx, hashAPIKey(key))
+	if err != nil {
+		if err.Error() == storage.ErrAPIKeyNotFound {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+	if apiKey.Revoked() {
+		return nil, ErrInvalidCredentials
+	}
+
+	p := &principal{ClientID: apiKey.ClientID, Scopes: make(map[string]bool, len(apiKey.Scopes))}
+	for _, scope := range apiKey.Scopes {
+		p.Scopes[scope] = true
+	}
+	return p, nil
+}
+
+// apiKeyFromRequest extracts the API key from the X-API-Key header or an "ApiKey" Authorization header.
+func apiKeyFromRequest(r *http.Request) string {
+	if key := strings.TrimSpace(r.Header.Get(apiKeyHeader)); key != "" {
+		return key
+	}
+	scheme, value, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if ok && strings.EqualFold(scheme, "ApiKey") {
+		return strings.TrimSpace(value)
+	}
+	return ""
+}
+
+// bearerToken extracts the token from a "Bearer" Authorization header.
+func bearerToken(r *http.Request) string {
+	scheme, value, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if ok && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(value)
+	}
+	return ""
+}
+
+// withPrincipal stores the authenticated principal and its client ID in the context.
+func withPrincipal(ctx context.Context, p *principal) context.Context {
+	ctx = context.WithValue(ctx, principalContextKey{}, p)
+	return utils.WithClientID(ctx, p.ClientID)
+}
+
+// principalFromContext returns the authenticated principal, or nil if the request is unauthenticated.
+func principalFromContext(ctx context.Context) *principal {
+	p, _ := ctx.Value(principalContextKey{}).(*principal)
+	return p
+}
+
+// generateAPIKey returns a new random plaintext API key.
+func generateAPIKey() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return apiKeyPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// generateWebhookSecret returns a new random secret for signing webhook deliveries.
+func generateWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return webhookSecretPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashAPIKey returns the hex-encoded SHA-256 hash under which a key is stored.
+// Keys carry 256 bits of entropy, so a fast hash is sufficient.
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,153 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/config"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage/mocks"
+	"github.com/cursed-ninja/internal-transfers-system/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestRequireScopes tests API key authentication and scope enforcement.
+// Scenarios include missing, unknown and revoked keys, insufficient scopes, admin keys and the bootstrap key.
+func TestRequireScopes(t *testing.T) {
+	revokedAt := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name           string
+		authDisabled   bool
+		header         string
+		value          string
+		mockSetup      func(m *mocks.MockStorage)
+		expectedStatus int
+		expectedClient string
+	}{
+		{
+			name:           "auth disabled",
+			authDisabled:   true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing key",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "unknown key",
+			header: apiKeyHeader,
+			value:  "itk_unknown",
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().GetAPIKeyByHash(gomock.Any(), hashAPIKey("itk_unknown")).Return(nil, errors.New(storage.ErrAPIKeyNotFound))
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "revoked key",
+			header: apiKeyHeader,
+			value:  "itk_revoked",
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().GetAPIKeyByHash(gomock.Any(), hashAPIKey("itk_revoked")).Return(&storage.APIKey{
+					ClientID:  "payroll",
+					Scopes:    []string{ScopeTransactionsWrite},
+					RevokedAt: &revokedAt,
+				}, nil)
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "insufficient scope",
+			header: apiKeyHeader,
+			value:  "itk_reader",
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().GetAPIKeyByHash(gomock.Any(), hashAPIKey("itk_reader")).Return(&storage.APIKey{
+					ClientID: "dashboard",
+					Scopes:   []string{ScopeAccountsRead},
+				}, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "authorized via authorization header",
+			header: "Authorization",
+			value:  "ApiKey itk_writer",
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().GetAPIKeyByHash(gomock.Any(), hashAPIKey("itk_writer")).Return(&storage.APIKey{
+					ClientID: "payroll",
+					Scopes:   []string{ScopeTransactionsWrite},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedClient: "payroll",
+		},
+		{
+			name:   "admin implies all scopes",
+			header: apiKeyHeader,
+			value:  "itk_admin",
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().GetAPIKeyByHash(gomock.Any(), hashAPIKey("itk_admin")).Return(&storage.APIKey{
+					ClientID: "ops",
+					Scopes:   []string{ScopeAdmin},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedClient: "ops",
+		},
+		{
+			name:           "bootstrap key",
+			header:         apiKeyHeader,
+			value:          "bootstrap-secret",
+			expectedStatus: http.StatusOK,
+			expectedClient: bootstrapClientID,
+		},
+		{
+			name:   "storage error",
+			header: apiKeyHeader,
+			value:  "itk_writer",
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().GetAPIKeyByHash(gomock.Any(), hashAPIKey("itk_writer")).Return(nil, errors.New(storage.ErrGetAPIKeyMsg))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockStorage := mocks.NewMockStorage(mockCtrl)
+			if tc.mockSetup != nil {
+				tc.mockSetup(mockStorage)
+			}
+
+			s := Server{
+				cfg: &config.Config{
+					Auth: &config.AuthConfig{
+						Enabled:           !tc.authDisabled,
+						BootstrapAdminKey: "bootstrap-secret",
+					},
+				},
+				store: mockStorage,
+			}
+
+			var clientID string
+			handler := s.requireScopes(ScopeTransactionsWrite)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				clientID = utils.ClientID(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/transactions", nil)
+			if tc.header != "" {
+				req.Header.Set(tc.header, tc.value)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Equal(t, tc.expectedClient, clientID)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+	"github.com/cursed-ninja/internal-transfers-system/internal/utils"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+type balanceResponse struct {
+	ID       string    `json:"account_id"`
+	Balance  string    `json:"balance"`
+	Currency string    `json:"currency"`
+	AsOf     time.Time `json:"as_of"`
+}
+
+// GetAccountBalance handles GET /accounts/{accountID}/balance, returning the account's balance at the
+// time given by the as_of query parameter, or now if it is absent.
+func (s *Server) GetAccountBalance(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := utils.ContextLogger(ctx)
+
+	logger.Info("received GetAccountBalance request")
+
+	accountID := strings.TrimSpace(mux.Vars(r)["accountID"])
+	if accountID == "" {
+		logger.Error("missing account_id in URL path")
+		http.Error(w, "account_id is required in URL path", http.StatusBadRequest)
+		return
+	}
+
+	ctx, logger = utils.LoggerWithKey(ctx, zap.String("account_id", accountID))
+
+	asOf, err := ValidateBalanceQuery(r.URL.Query(), time.Now())
+	if err != nil {
+		logger.Error("invalid balance query", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if p := principalFromContext(ctx); p != nil && !p.canRead(accountID) {
+		logger.Warn("caller is not allowed to read account")
+		http.Error(w, ErrAccountForbidden.Error(), http.StatusForbidden)
+		return
+	}
+
+	balance, err := s.store.GetBalanceAt(ctx, accountID, asOf)
+	if err != nil {
+		logger.Error("failed to get balance", zap.Error(err))
+		errorMsg := err.Error()
+		statusCode := http.StatusInternalServerError
+		switch errorMsg {
+		case storage.ErrAccountNotFound, storage.ErrAccountNotOpenMsg:
+			statusCode = http.StatusNotFound
+		}
+		http.Error(w, errorMsg, statusCode)
+		return
+	}
+
+	writeJSON(w, logger, http.StatusOK, balanceResponse{
+		ID:       balance.AccountID,
+		Balance:  balance.Balance.String(),
+		Currency: balance.Currency,
+		AsOf:     balance.AsOf.UTC(),
+	})
+}
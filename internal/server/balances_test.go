@@ -0,0 +1,119 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/config"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage/mocks"
+	"github.com/gorilla/mux"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestGetAccountBalance tests the GetAccountBalance endpoint: explicit and default as_of times,
+// invalid and future times, access restrictions and storage failures.
+func TestGetAccountBalance(t *testing.T) {
+	asOf := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	balance := &storage.AccountBalance{AccountID: "acc-1", Balance: decimal.RequireFromString("42.50"), Currency: "EUR", AsOf: asOf}
+
+	tests := []struct {
+		name           string
+		query          string
+		principal      *principal
+		mockSetup      func(m *mocks.MockStorage)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "balance at time",
+			query: "?as_of=2025-06-01T14:00:00%2B02:00",
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().GetBalanceAt(gomock.Any(), "acc-1", gomock.Cond(func(t time.Time) bool { return t.Equal(asOf) })).Return(balance, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"account_id":"acc-1","balance":"42.5","currency":"EUR","as_of":"2025-06-01T12:00:00Z"}` + "\n",
+		},
+		{
+			name: "defaults to now",
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().GetBalanceAt(gomock.Any(), "acc-1", gomock.Cond(func(t time.Time) bool { return time.Since(t) < time.Minute })).Return(balance, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid as_of",
+			query:          "?as_of=yesterday",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   ErrInvalidAsOf.Error() + "\n",
+		},
+		{
+			name:           "future as_of",
+			query:          "?as_of=2999-01-01T00:00:00Z",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   ErrFutureAsOf.Error() + "\n",
+		},
+		{
+			name:           "caller restricted to other accounts",
+			query:          "?as_of=2025-06-01T12:00:00Z",
+			principal:      &principal{ClientID: "user", AccountRestricted: true, ReadAccounts: map[string]bool{"acc-2": true}},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:  "account opened later",
+			query: "?as_of=2025-06-01T12:00:00Z",
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().GetBalanceAt(gomock.Any(), "acc-1", gomock.Any()).Return(nil, errors.New(storage.ErrAccountNotOpenMsg))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:  "account not found",
+			query: "?as_of=2025-06-01T12:00:00Z",
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().GetBalanceAt(gomock.Any(), "acc-1", gomock.Any()).Return(nil, errors.New(storage.ErrAccountNotFound))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:  "internal error",
+			query: "?as_of=2025-06-01T12:00:00Z",
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().GetBalanceAt(gomock.Any(), "acc-1", gomock.Any()).Return(nil, errors.New(storage.ErrGetBalanceMsg))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockStorage := mocks.NewMockStorage(mockCtrl)
+			if tc.mockSetup != nil {
+				tc.mockSetup(mockStorage)
+			}
+
+			s := Server{cfg: &config.Config{}, store: mockStorage}
+			r := mux.NewRouter()
+			s.BindRoutes(r)
+
+			req := httptest.NewRequest(http.MethodGet, "/accounts/acc-1/balance"+tc.query, nil)
+			if tc.principal != nil {
+				req = req.WithContext(withPrincipal(req.Context(), tc.principal))
+			}
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedBody != "" {
+				assert.Equal(t, tc.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+)
+
+// maxBufferedBodyBytes bounds request bodies read by middleware before the handler, on routes
+// without a larger limit of their own.
+const maxBufferedBodyBytes = 1 << 20
+
+// ErrBodyTooLarge is returned when a buffered request body exceeds the limit it is read under.
+var ErrBodyTooLarge = errors.New("request body too large")
+
+// bufferBody reads the request body, up to maxBytes, and replaces it with an in-memory copy,
+// so middleware can inspect the body and the handler can still decode it.
+// A body over maxBytes is left whole on the request, so a caller that ignores
+// ErrBodyTooLarge does not hand the rest of the body to the next reader.
+func bufferBody(r *http.Request, maxBytes int) ([]byte, error) {
+	if r.Body == nil {
+		return nil, nil
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, int64(maxBytes)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxBytes {
+		r.Body = readCloser{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+		return nil, ErrBodyTooLarge
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// readCloser reads from a reader while closing the original request body.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
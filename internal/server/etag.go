@@ -0,0 +1,44 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+)
+
+// ErrInvalidIfMatch is returned for an If-Match header that is neither "*" nor a single account ETag.
+var ErrInvalidIfMatch = errors.New(`If-Match must be "*" or a single account ETag`)
+
+// ErrIfMatchVersion is returned when a request body's version disagrees with its If-Match header.
+var ErrIfMatchVersion = errors.New("version does not match If-Match")
+
+// accountETag returns the strong entity tag of an account at the given version.
+func accountETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// ifMatchVersion parses the request's If-Match header into the account version it requires.
+// ok is false when the header is absent; "*" matches any version and yields storage.AnyVersion.
+// Weak tags are rejected, since If-Match uses strong comparison and they can never match.
+func ifMatchVersion(r *http.Request) (version int64, ok bool, err error) {
+	raw := strings.TrimSpace(r.Header.Get("If-Match"))
+	if raw == "" {
+		return 0, false, nil
+	}
+	if raw == "*" {
+		return storage.AnyVersion, true, nil
+	}
+	tag, quoted := strings.CutPrefix(raw, `"`)
+	tag, closed := strings.CutSuffix(tag, `"`)
+	if !quoted || !closed {
+		return 0, false, ErrInvalidIfMatch
+	}
+	version, err = strconv.ParseInt(tag, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, false, ErrInvalidIfMatch
+	}
+	return version, true, nil
+}
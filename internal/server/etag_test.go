@@ -0,0 +1,46 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestIfMatchVersion verifies If-Match headers are parsed into the account version they require.
+func TestIfMatchVersion(t *testing.T) {
+	tests := []struct {
+		name            string
+		header          string
+		expectedVersion int64
+		expectedOK      bool
+		expectedErr     error
+	}{
+		{name: "absent"},
+		{name: "strong tag", header: `"3"`, expectedVersion: 3, expectedOK: true},
+		{name: "padded", header: ` "12" `, expectedVersion: 12, expectedOK: true},
+		{name: "wildcard", header: "*", expectedVersion: storage.AnyVersion, expectedOK: true},
+		{name: "round trip", header: accountETag(42), expectedVersion: 42, expectedOK: true},
+		{name: "weak tag", header: `W/"3"`, expectedErr: ErrInvalidIfMatch},
+		{name: "unquoted", header: "3", expectedErr: ErrInvalidIfMatch},
+		{name: "several tags", header: `"3", "4"`, expectedErr: ErrInvalidIfMatch},
+		{name: "not a version", header: `"abc"`, expectedErr: ErrInvalidIfMatch},
+		{name: "zero", header: `"0"`, expectedErr: ErrInvalidIfMatch},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPatch, "/accounts/acc-1", nil)
+			if tc.header != "" {
+				req.Header.Set("If-Match", tc.header)
+			}
+
+			version, ok, err := ifMatchVersion(req)
+			assert.Equal(t, tc.expectedErr, err)
+			assert.Equal(t, tc.expectedOK, ok)
+			assert.Equal(t, tc.expectedVersion, version)
+		})
+	}
+}
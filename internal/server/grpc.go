@@ -0,0 +1,314 @@
+package server
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	transfersv1 "github.com/cursed-ninja/internal-transfers-system/api/transfers/v1"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+	"github.com/cursed-ninja/internal-transfers-system/internal/utils"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// defaultTransactionsPageSize is used when a ListTransactions request does not set a page size.
+	defaultTransactionsPageSize = 50
+	// maxTransactionsPageSize caps the page size of a ListTransactions request.
+	maxTransactionsPageSize = 500
+)
+
+// grpcMethodScopes lists the scopes each gRPC method requires, mirroring the REST routes.
+var grpcMethodScopes = map[string][]string{
+	transfersv1.TransfersService_CreateAccount_FullMethodName:      {ScopeAccountsWrite},
+	transfersv1.TransfersService_GetAccountDetails_FullMethodName:  {ScopeAccountsRead},
+	transfersv1.TransfersService_ProcessTransaction_FullMethodName: {ScopeTransactionsWrite},
+	transfersv1.TransfersService_ListTransactions_FullMethodName:   {ScopeAccountsRead},
+}
+
+// grpcMethodRoutes maps gRPC methods to the REST route whose rate limits they share. Methods without a
+// REST counterpart are limited under their full method name.
+var grpcMethodRoutes = map[string]string{
+	transfersv1.TransfersService_CreateAccount_FullMethodName:      "POST /accounts",
+	transfersv1.TransfersService_GetAccountDetails_FullMethodName:  "GET /accounts/{accountID}",
+	transfersv1.TransfersService_ProcessTransaction_FullMethodName: "POST /transactions",
+}
+
+// grpcSignedMethods lists the gRPC methods whose REST routes verify request signatures.
+var grpcSignedMethods = map[string]bool{
+	transfersv1.TransfersService_ProcessTransaction_FullMethodName: true,
+}
+
+// grpcService implements the TransfersService gRPC API on top of the server's storage.
+type grpcService struct {
+	transfersv1.UnimplementedTransfersServiceServer
+	s *Server
+}
+
+// NewGRPCServer returns a gRPC server exposing the transfers API from the same storage as the REST handlers.
+// Every call passes through the recovery, logging, authentication, rate limiting and signing interceptors,
+// in that order.
+func (s *Server) NewGRPCServer(opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts, grpc.ChainUnaryInterceptor(
+		s.recoveryInterceptor,
+		s.loggingInterceptor,
+		s.authInterceptor,
+		s.rateLimitInterceptor,
+		s.signingInterceptor,
+	))
+	srv := grpc.NewServer(opts...)
+	transfersv1.RegisterTransfersServiceServer(srv, &grpcService{s: s})
+	return srv
+}
+
+// CreateAccount creates an account with an initial balance and optional attributes.
+func (g *grpcService) CreateAccount(ctx context.Context, in *transfersv1.CreateAccountRequest) (*transfersv1.CreateAccountResponse, error) {
+	logger := utils.ContextLogger(ctx)
+
+	logger.Info("received CreateAccount request")
+
+	req := createAccountRequest{
+		AccountID:      in.GetAccountId(),
+		InitialBalance: in.GetInitialBalance(),
+		Currency:       in.GetCurrency(),
+		Name:           in.GetName(),
+		OwnerRef:       in.GetOwnerRef(),
+		Type:           in.GetType(),
+		Labels:         in.GetLabels(),
+	}
+	if in.GetMetadata() != nil {
+		metadata, err := protojson.Marshal(in.GetMetadata())
+		if err != nil {
+			logger.Error("failed to encode metadata", zap.Error(err))
+			return nil, status.Error(codes.InvalidArgument, ErrInvalidMetadata.Error())
+		}
+		req.Metadata = metadata
+	}
+	balance, err := ValidateCreateAccount(&req)
+	if err != nil {
+		logger.Error("failed to validate request", zap.Error(err))
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	ctx, _ = utils.LoggerWithKey(ctx, zap.String("account_id", req.AccountID))
+	ctx, logger = utils.LoggerWithKey(ctx, zap.String("balance", balance.String()))
+
+	if err := g.s.store.CreateAccount(ctx, req.AccountID, balance, req.attributes()); err != nil {
+		logger.Error("failed to create account", zap.Error(err))
+		return nil, grpcStorageError(err)
+	}
+
+	account, err := grpcAccount(&storage.Account{
+		ID:                req.AccountID,
+		Balance:           balance,
+		Status:            storage.AccountStatusActive,
+		AccountAttributes: req.attributes(),
+		Version:           1,
+	})
+	if err != nil {
+		logger.Error("failed to convert account", zap.Error(err))
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	logger.Info("account created successfully")
+	return &transfersv1.CreateAccountResponse{Account: account}, nil
+}
+
+// GetAccountDetails returns an account's current balance and attributes.
+func (g *grpcService) GetAccountDetails(ctx context.Context, in *transfersv1.GetAccountDetailsRequest) (*transfersv1.GetAccountDetailsResponse, error) {
+	logger := utils.ContextLogger(ctx)
+
+	logger.Info("received GetAccountDetails request")
+
+	accountID := strings.TrimSpace(in.GetAccountId())
+	if accountID == "" {
+		logger.Error("missing account_id")
+		return nil, status.Error(codes.InvalidArgument, ErrMissingAccountID.Error())
+	}
+
+	ctx, logger = utils.LoggerWithKey(ctx, zap.String("account_id", accountID))
+
+	if p := principalFromContext(ctx); p != nil && !p.canRead(accountID) {
+		logger.Warn("caller is not allowed to read account")
+		return nil, status.Error(codes.PermissionDenied, ErrAccountForbidden.Error())
+	}
+
+	acc, err := g.s.store.GetAccountDetails(ctx, accountID)
+	if err != nil {
+		logger.Error("failed to get account details", zap.Error(err))
+		return nil, grpcStorageError(err)
+	}
+
+	account, err := grpcAccount(acc)
+	if err != nil {
+		logger.Error("failed to convert account", zap.Error(err))
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	logger.Info("account details retrieved successfully")
+	return &transfersv1.GetAccountDetailsResponse{Account: account}, nil
+}
+
+// ProcessTransaction transfers funds between two accounts.
+func (g *grpcService) ProcessTransaction(ctx context.Context, in *transfersv1.ProcessTransactionRequest) (*transfersv1.ProcessTransactionResponse, error) {
+	logger := utils.ContextLogger(ctx)
+
+	logger.Info("received ProcessTransaction request")
+
+	req := processTransactionRequest{
+		SourceAccID: in.GetSourceAccountId(),
+		DestAccID:   in.GetDestinationAccountId(),
+		Amount:      in.GetAmount(),
+	}
+	amt, err := ValidateProcessTransaction(&req)
+	if err != nil {
+		logger.Error("failed to validate request", zap.Error(err))
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	ctx, _ = utils.LoggerWithKey(ctx, zap.String("source_account_id", req.SourceAccID))
+	ctx, _ = utils.LoggerWithKey(ctx, zap.String("destination_account_id", req.DestAccID))
+	ctx, logger = utils.LoggerWithKey(ctx, zap.String("amount", amt.String()))
+
+	if p := principalFromContext(ctx); p != nil && !p.canDebit(req.SourceAccID) {
+		logger.Warn("caller is not allowed to debit source account")
+		return nil, status.Error(codes.PermissionDenied, ErrAccountForbidden.Error())
+	}
+
+	if in.GetSourceAccountVersion() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "source_account_version must be non-negative")
+	}
+
+	if err := g.s.store.ProcessTransaction(ctx, req.SourceAccID, req.DestAccID, amt, in.GetSourceAccountVersion()); err != nil {
+		logger.Error("failed to process transaction", zap.Error(err))
+		return nil, grpcStorageError(err)
+	}
+
+	logger.Info("transaction processed successfully")
+	return &transfersv1.ProcessTransactionResponse{}, nil
+}
+
+// ListTransactions returns one page of the transfers into and out of an account, newest first.
+// The page token is the ID of the last transaction on the previous page.
+func (g *grpcService) ListTransactions(ctx context.Context, in *transfersv1.ListTransactionsRequest) (*transfersv1.ListTransactionsResponse, error) {
+	logger := utils.ContextLogger(ctx)
+
+	logger.Info("received ListTransactions request")
+
+	accountID := strings.TrimSpace(in.GetAccountId())
+	if accountID == "" {
+		logger.Error("missing account_id")
+		return nil, status.Error(codes.InvalidArgument, ErrMissingAccountID.Error())
+	}
+
+	pageSize := int(in.GetPageSize())
+	switch {
+	case pageSize < 0:
+		return nil, status.Error(codes.InvalidArgument, "page_size must be non-negative")
+	case pageSize == 0:
+		pageSize = defaultTransactionsPageSize
+	case pageSize > maxTransactionsPageSize:
+		pageSize = maxTransactionsPageSize
+	}
+
+	var beforeID int64
+	if token := in.GetPageToken(); token != "" {
+		id, err := strconv.ParseInt(token, 10, 64)
+		if err != nil || id <= 0 {
+			logger.Error("invalid page_token", zap.String("page_token", token))
+			return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+		}
+		beforeID = id
+	}
+
+	ctx, logger = utils.LoggerWithKey(ctx, zap.String("account_id", accountID))
+
+	if p := principalFromContext(ctx); p != nil && !p.canRead(accountID) {
+		logger.Warn("caller is not allowed to read account")
+		return nil, status.Error(codes.PermissionDenied, ErrAccountForbidden.Error())
+	}
+
+	if _, err := g.s.store.GetAccountDetails(ctx, accountID); err != nil {
+		logger.Error("failed to get account details", zap.Error(err))
+		return nil, grpcStorageError(err)
+	}
+
+	// Fetch one extra row to tell whether another page follows.
+	transactions, err := g.s.store.ListTransactions(ctx, accountID, beforeID, pageSize+1)
+	if err != nil {
+		logger.Error("failed to list transactions", zap.Error(err))
+		return nil, grpcStorageError(err)
+	}
+
+	resp := &transfersv1.ListTransactionsResponse{}
+	if len(transactions) > pageSize {
+		transactions = transactions[:pageSize]
+		resp.NextPageToken = strconv.FormatInt(transactions[pageSize-1].ID, 10)
+	}
+	resp.Transactions = make([]*transfersv1.Transaction, 0, len(transactions))
+	for _, t := range transactions {
+		resp.Transactions = append(resp.Transactions, &transfersv1.Transaction{
+			Id:                   t.ID,
+			SourceAccountId:      t.SourceAccountID,
+			DestinationAccountId: t.DestinationAccountID,
+			Amount:               t.Amount.String(),
+			RequestId:            t.RequestID,
+			CreatedAt:            timestamppb.New(t.CreatedAt),
+		})
+	}
+
+	logger.Info("transactions listed successfully", zap.Int("count", len(resp.Transactions)))
+	return resp, nil
+}
+
+// grpcAccount converts a stored account to its protobuf representation.
+// Timestamps are left unset when unknown, as for a just-created account.
+func grpcAccount(acc *storage.Account) (*transfersv1.Account, error) {
+	account := &transfersv1.Account{
+		AccountId: acc.ID,
+		Balance:   acc.Balance.String(),
+		Currency:  acc.Currency,
+		Status:    acc.Status,
+		Name:      acc.Name,
+		OwnerRef:  acc.OwnerRef,
+		Type:      acc.Type,
+		Labels:    acc.Labels,
+		Metadata:  &structpb.Struct{},
+		Version:   acc.Version,
+	}
+	if len(acc.Metadata) > 0 {
+		if err := protojson.Unmarshal(acc.Metadata, account.Metadata); err != nil {
+			return nil, err
+		}
+	}
+	if !acc.CreatedAt.IsZero() {
+		account.CreatedAt = timestamppb.New(acc.CreatedAt)
+	}
+	if !acc.UpdatedAt.IsZero() {
+		account.UpdatedAt = timestamppb.New(acc.UpdatedAt)
+	}
+	return account, nil
+}
+
+// grpcStorageError maps a storage error to the gRPC status matching the REST status code.
+func grpcStorageError(err error) error {
+	errorMsg := err.Error()
+	switch errorMsg {
+	case storage.ErrAccountExists:
+		return status.Error(codes.AlreadyExists, errorMsg)
+	case storage.ErrAccountNotFound, storage.ErrSourceAccountMsg, storage.ErrDestinationAccountMsg:
+		return status.Error(codes.NotFound, errorMsg)
+	case storage.ErrInsufficientFundsMsg, storage.ErrCurrencyMismatchMsg, storage.ErrAmountPrecisionMsg, storage.ErrSourceClosedMsg, storage.ErrDestinationClosedMsg:
+		return status.Error(codes.FailedPrecondition, errorMsg)
+	case storage.ErrAccountVersionMsg:
+		return status.Error(codes.Aborted, errorMsg)
+	default:
+		return status.Error(codes.Internal, errorMsg)
+	}
+}
@@ -0,0 +1,193 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"runtime/debug"
+	"strconv"
+	"strings"
+
+	transfersv1 "github.com/cursed-ninja/internal-transfers-system/api/transfers/v1"
+	"github.com/cursed-ninja/internal-transfers-system/internal/utils"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// loggingInterceptor is the gRPC counterpart of loggingMiddleware. It attaches a request ID and logger
+// to each call's context, honoring a valid inbound x-request-id and traceparent, and echoes the ID
+// back in the response header metadata.
+func (s *Server) loggingInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	reqID := requestIDFromHeader(firstMetadataValue(md, requestIDHeader))
+	if reqID == "" {
+		reqID = newRequestID()
+	}
+	_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDHeader, reqID))
+
+	ctx = context.WithValue(ctx, utils.LoggerContextKey, s.rootLogger())
+	ctx = utils.WithRequestID(ctx, reqID)
+	ctx, _ = utils.LoggerWithKey(ctx, zap.String("request_id", reqID))
+	ctx, _ = utils.LoggerWithKey(ctx, zap.String("grpc_method", info.FullMethod))
+	if traceID, spanID, ok := parseTraceparent(firstMetadataValue(md, traceparentHeader)); ok {
+		ctx, _ = utils.LoggerWithKey(ctx, zap.String("trace_id", traceID))
+		ctx, _ = utils.LoggerWithKey(ctx, zap.String("parent_span_id", spanID))
+	}
+	return handler(ctx, req)
+}
+
+// authInterceptor is the gRPC counterpart of requireScopes. It authenticates the caller from the
+// authorization, x-api-key or TLS client certificate credentials and rejects calls lacking the
+// method's scopes. Methods without configured scopes are denied.
+// When authentication is disabled, calls pass through unchanged.
+func (s *Server) authInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if !s.authEnabled() {
+		return handler(ctx, req)
+	}
+
+	logger := utils.ContextLogger(ctx)
+
+	p, err := s.authenticate(grpcAuthRequest(ctx))
+	if err != nil {
+		logger.Warn("authentication failed", zap.Error(err))
+		if !errors.Is(err, ErrMissingCredentials) && !errors.Is(err, ErrInvalidCredentials) {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	ctx = withPrincipal(ctx, p)
+	ctx, logger = utils.LoggerWithKey(ctx, zap.String("client_id", p.ClientID))
+
+	scopes, ok := grpcMethodScopes[info.FullMethod]
+	if !ok || !p.hasScopes(scopes...) {
+		logger.Warn("caller lacks required scopes", zap.Strings("required_scopes", scopes))
+		return nil, status.Error(codes.PermissionDenied, ErrInsufficientScope.Error())
+	}
+	return handler(ctx, req)
+}
+
+// rateLimitInterceptor is the gRPC counterpart of rateLimit. Calls are limited under the rule of the
+// method's REST route, keyed by API client, peer IP and the account the call acts on, and rejected with
+// ResourceExhausted and a retry-after header when a bucket is empty.
+func (s *Server) rateLimitInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if s.limiter == nil || s.cfg.RateLimit == nil || !s.cfg.RateLimit.Enabled {
+		return handler(ctx, req)
+	}
+
+	route, ok := grpcMethodRoutes[info.FullMethod]
+	if !ok {
+		route = info.FullMethod
+	}
+	checks := s.rateLimitChecks(ctx, route, grpcPeerIP(ctx), func() string { return grpcRateLimitAccount(req) })
+	tightest, denied, checked := s.checkRateLimits(ctx, checks)
+	if checked {
+		_ = grpc.SetHeader(ctx, metadata.Pairs(
+			"ratelimit-limit", strconv.Itoa(tightest.Limit),
+			"ratelimit-remaining", strconv.Itoa(tightest.Remaining),
+			"ratelimit-reset", strconv.Itoa(ceilSeconds(tightest.ResetAfter)),
+		))
+	}
+	if denied {
+		utils.ContextLogger(ctx).Warn("rate limit exceeded", zap.String("route", route))
+		_ = grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(ceilSeconds(tightest.RetryAfter))))
+		return nil, status.Error(codes.ResourceExhausted, "rate limit exceeded")
+	}
+	return handler(ctx, req)
+}
+
+// signingInterceptor stands in for verifySignature. Request signatures cover the REST method, path and
+// JSON body, which gRPC calls do not have, so callers that must sign their REST requests are refused on
+// the methods whose routes verify signatures and have to use the REST API instead.
+func (s *Server) signingInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if s.signer == nil || !grpcSignedMethods[info.FullMethod] {
+		return handler(ctx, req)
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	clientID := strings.TrimSpace(firstMetadataValue(md, clientIDHeader))
+	if p := principalFromContext(ctx); p != nil {
+		clientID = p.ClientID
+	}
+
+	if s.signer.mustSign(clientID) {
+		utils.ContextLogger(ctx).Warn("caller must sign requests, which gRPC does not support", zap.String("signing_client_id", clientID))
+		return nil, status.Error(codes.Unauthenticated, ErrSigningUnsupported.Error())
+	}
+	return handler(ctx, req)
+}
+
+// recoveryInterceptor turns a panicking call into an Internal error instead of crashing the process.
+func (s *Server) recoveryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.rootLogger().Error("panic in gRPC handler",
+				zap.String("grpc_method", info.FullMethod),
+				zap.Any("panic", r),
+				zap.ByteString("stack", debug.Stack()),
+			)
+			resp, err = nil, status.Error(codes.Internal, "internal error")
+		}
+	}()
+	return handler(ctx, req)
+}
+
+// grpcAuthRequest adapts a call's credentials to an HTTP request so the REST authenticator can resolve them:
+// the authorization and x-api-key metadata become headers and a verified TLS peer becomes the connection state.
+func grpcAuthRequest(ctx context.Context) *http.Request {
+	r := (&http.Request{Header: make(http.Header)}).WithContext(ctx)
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for _, name := range []string{"Authorization", apiKeyHeader} {
+			for _, v := range md.Get(name) {
+				r.Header.Add(name, v)
+			}
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			r.TLS = &tlsInfo.State
+		}
+	}
+	return r
+}
+
+// grpcPeerIP returns the IP address of the calling peer, or an empty string.
+func grpcPeerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// grpcRateLimitAccount returns the account a call acts on, like rateLimitAccount does for REST requests.
+func grpcRateLimitAccount(req any) string {
+	switch in := req.(type) {
+	case *transfersv1.ProcessTransactionRequest:
+		return strings.TrimSpace(in.GetSourceAccountId())
+	case *transfersv1.GetAccountDetailsRequest:
+		return strings.TrimSpace(in.GetAccountId())
+	case *transfersv1.ListTransactionsRequest:
+		return strings.TrimSpace(in.GetAccountId())
+	}
+	return ""
+}
+
+// firstMetadataValue returns the first value of the metadata key, or an empty string.
+func firstMetadataValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
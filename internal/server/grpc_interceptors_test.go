@@ -0,0 +1,230 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	transfersv1 "github.com/cursed-ninja/internal-transfers-system/api/transfers/v1"
+	"github.com/cursed-ninja/internal-transfers-system/internal/config"
+	"github.com/cursed-ninja/internal-transfers-system/internal/ratelimit"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage/mocks"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// metadataContext returns an outgoing context carrying the given metadata key-value pairs.
+func metadataContext(kv ...string) context.Context {
+	return metadata.NewOutgoingContext(context.Background(), metadata.Pairs(kv...))
+}
+
+// TestGRPCLoggingInterceptorRequestID verifies inbound request IDs are echoed back and invalid ones are replaced.
+func TestGRPCLoggingInterceptorRequestID(t *testing.T) {
+	tests := []struct {
+		name       string
+		inbound    string
+		expectEcho bool
+	}{
+		{name: "valid inbound id is echoed", inbound: "req-abc", expectEcho: true},
+		{name: "invalid inbound id is replaced", inbound: "bad id!", expectEcho: false},
+		{name: "missing id is generated", inbound: "", expectEcho: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockStorage := mocks.NewMockStorage(ctrl)
+			mockStorage.EXPECT().GetAccountDetails(gomock.Any(), "acc-1").Return(&storage.Account{ID: "acc-1", Balance: decimal.Zero}, nil)
+			client := newGRPCClient(t, &Server{cfg: &config.Config{}, store: mockStorage})
+
+			ctx := context.Background()
+			if tc.inbound != "" {
+				ctx = metadataContext(requestIDHeader, tc.inbound)
+			}
+			var header metadata.MD
+			_, err := client.GetAccountDetails(ctx, &transfersv1.GetAccountDetailsRequest{AccountId: "acc-1"}, grpc.Header(&header))
+			assert.NoError(t, err)
+
+			got := firstMetadataValue(header, requestIDHeader)
+			assert.NotEmpty(t, got)
+			if tc.expectEcho {
+				assert.Equal(t, tc.inbound, got)
+			} else {
+				assert.NotEqual(t, tc.inbound, got)
+			}
+		})
+	}
+}
+
+// TestGRPCAuthInterceptor tests API key authentication and scope enforcement on gRPC calls.
+func TestGRPCAuthInterceptor(t *testing.T) {
+	tests := []struct {
+		name         string
+		md           []string
+		mockSetup    func(m *mocks.MockStorage)
+		expectedCode codes.Code
+	}{
+		{
+			name:         "missing credentials",
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name: "insufficient scope",
+			md:   []string{apiKeyHeader, "itk_writer"},
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().GetAPIKeyByHash(gomock.Any(), hashAPIKey("itk_writer")).Return(&storage.APIKey{
+					ClientID: "payroll",
+					Scopes:   []string{ScopeTransactionsWrite},
+				}, nil)
+			},
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name: "authorized via authorization metadata",
+			md:   []string{"authorization", "ApiKey itk_reader"},
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().GetAPIKeyByHash(gomock.Any(), hashAPIKey("itk_reader")).Return(&storage.APIKey{
+					ClientID: "dashboard",
+					Scopes:   []string{ScopeAccountsRead},
+				}, nil)
+				m.EXPECT().GetAccountDetails(gomock.Any(), "acc-1").Return(&storage.Account{ID: "acc-1", Balance: decimal.Zero}, nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name: "bootstrap key",
+			md:   []string{apiKeyHeader, "bootstrap-secret"},
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().GetAccountDetails(gomock.Any(), "acc-1").Return(&storage.Account{ID: "acc-1"}, nil)
+			},
+			expectedCode: codes.OK,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockStorage := mocks.NewMockStorage(ctrl)
+			if tc.mockSetup != nil {
+				tc.mockSetup(mockStorage)
+			}
+			cfg := &config.Config{Auth: &config.AuthConfig{Enabled: true, BootstrapAdminKey: "bootstrap-secret"}}
+			client := newGRPCClient(t, &Server{cfg: cfg, store: mockStorage})
+
+			ctx := context.Background()
+			if len(tc.md) > 0 {
+				ctx = metadataContext(tc.md...)
+			}
+			_, err := client.GetAccountDetails(ctx, &transfersv1.GetAccountDetailsRequest{AccountId: "acc-1"})
+			assert.Equal(t, tc.expectedCode, status.Code(err))
+		})
+	}
+}
+
+// TestGRPCRateLimitInterceptor verifies calls share the rate limits of their REST route.
+func TestGRPCRateLimitInterceptor(t *testing.T) {
+	cfg := &config.Config{RateLimit: &config.RateLimitConfig{
+		Enabled: true,
+		Routes: []config.RateLimitRule{{
+			Route:      "POST /transactions",
+			PerAccount: config.RateLimit{Rate: 1, Burst: 1},
+		}},
+	}}
+	s := &Server{cfg: cfg, limiter: ratelimit.NewMemoryStore()}
+	info := &grpc.UnaryServerInfo{FullMethod: transfersv1.TransfersService_ProcessTransaction_FullMethodName}
+	handler := func(context.Context, any) (any, error) { return &transfersv1.ProcessTransactionResponse{}, nil }
+
+	tests := []struct {
+		name         string
+		source       string
+		expectedCode codes.Code
+	}{
+		{name: "first call", source: "acc-1", expectedCode: codes.OK},
+		{name: "same source account", source: "acc-1", expectedCode: codes.ResourceExhausted},
+		{name: "other source account", source: "acc-2", expectedCode: codes.OK},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := &transfersv1.ProcessTransactionRequest{SourceAccountId: tc.source, DestinationAccountId: "acc-9", Amount: "1"}
+			_, err := s.rateLimitInterceptor(context.Background(), req, info, handler)
+			assert.Equal(t, tc.expectedCode, status.Code(err))
+		})
+	}
+}
+
+// TestGRPCSigningInterceptor verifies callers that must sign their transfers are refused over gRPC.
+func TestGRPCSigningInterceptor(t *testing.T) {
+	tests := []struct {
+		name         string
+		required     bool
+		clientID     string
+		method       string
+		expectedCode codes.Code
+	}{
+		{
+			name:         "client with signing secrets",
+			clientID:     "payroll",
+			method:       transfersv1.TransfersService_ProcessTransaction_FullMethodName,
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name:         "client without signing secrets",
+			clientID:     "dashboard",
+			method:       transfersv1.TransfersService_ProcessTransaction_FullMethodName,
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "signing required",
+			required:     true,
+			clientID:     "dashboard",
+			method:       transfersv1.TransfersService_ProcessTransaction_FullMethodName,
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name:         "method without signatures",
+			required:     true,
+			clientID:     "payroll",
+			method:       transfersv1.TransfersService_GetAccountDetails_FullMethodName,
+			expectedCode: codes.OK,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			v, err := NewRequestVerifier(&config.SigningConfig{
+				Required: tc.required,
+				Clients: []config.SigningClient{{
+					ClientID: "payroll",
+					Secrets:  []config.SigningSecret{{Secret: "payroll-secret"}},
+				}},
+			})
+			require.NoError(t, err)
+			s := &Server{cfg: &config.Config{}, signer: v}
+
+			ctx := withPrincipal(context.Background(), &principal{ClientID: tc.clientID})
+			_, err = s.signingInterceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tc.method}, func(context.Context, any) (any, error) {
+				return nil, nil
+			})
+			assert.Equal(t, tc.expectedCode, status.Code(err))
+		})
+	}
+}
+
+// TestGRPCRecoveryInterceptor verifies a panicking handler returns Internal instead of crashing.
+func TestGRPCRecoveryInterceptor(t *testing.T) {
+	s := &Server{cfg: &config.Config{}}
+	info := &grpc.UnaryServerInfo{FullMethod: transfersv1.TransfersService_GetAccountDetails_FullMethodName}
+
+	resp, err := s.recoveryInterceptor(context.Background(), nil, info, func(context.Context, any) (any, error) {
+		panic("boom")
+	})
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
@@ -0,0 +1,379 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	transfersv1 "github.com/cursed-ninja/internal-transfers-system/api/transfers/v1"
+	"github.com/cursed-ninja/internal-transfers-system/internal/config"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage/mocks"
+	"github.com/cursed-ninja/internal-transfers-system/internal/utils"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// newGRPCClient serves s over an in-memory gRPC connection and returns a client for it.
+func newGRPCClient(t *testing.T, s *Server) transfersv1.TransfersServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := s.NewGRPCServer()
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return transfersv1.NewTransfersServiceClient(conn)
+}
+
+// TestGRPCCreateAccount tests the CreateAccount RPC.
+// Scenarios include successful creation, invalid input, duplicate accounts and internal errors.
+func TestGRPCCreateAccount(t *testing.T) {
+	tests := []struct {
+		name         string
+		req          *transfersv1.CreateAccountRequest
+		mockSetup    func(m *mocks.MockStorage)
+		expectedCode codes.Code
+	}{
+		{
+			name: "success",
+			req: &transfersv1.CreateAccountRequest{
+				AccountId:      " acc-1 ",
+				InitialBalance: "100.50",
+				Name:           " Payroll ",
+				Type:           storage.AccountTypeOperating,
+				Metadata:       &structpb.Struct{Fields: map[string]*structpb.Value{"cost_center": structpb.NewStringValue("42")}},
+			},
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().CreateAccount(gomock.Any(), "acc-1", decimal.RequireFromString("100.50"), storage.AccountAttributes{
+					Currency: storage.DefaultCurrency,
+					Name:     "Payroll",
+					Type:     storage.AccountTypeOperating,
+					Labels:   []string{},
+					Metadata: json.RawMessage(`{"cost_center":"42"}`),
+				}).Return(nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "invalid balance",
+			req:          &transfersv1.CreateAccountRequest{AccountId: "acc-1", InitialBalance: "-1"},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "unknown account type",
+			req:          &transfersv1.CreateAccountRequest{AccountId: "acc-1", InitialBalance: "1", Type: "savings"},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "duplicate account",
+			req:  &transfersv1.CreateAccountRequest{AccountId: "acc-1", InitialBalance: "100"},
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().CreateAccount(gomock.Any(), "acc-1", gomock.Any(), gomock.Any()).Return(errors.New(storage.ErrAccountExists))
+			},
+			expectedCode: codes.AlreadyExists,
+		},
+		{
+			name: "internal error",
+			req:  &transfersv1.CreateAccountRequest{AccountId: "acc-1", InitialBalance: "100"},
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().CreateAccount(gomock.Any(), "acc-1", gomock.Any(), gomock.Any()).Return(errors.New(storage.ErrCreateAccountMsg))
+			},
+			expectedCode: codes.Internal,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockStorage := mocks.NewMockStorage(ctrl)
+			if tc.mockSetup != nil {
+				tc.mockSetup(mockStorage)
+			}
+			client := newGRPCClient(t, &Server{cfg: &config.Config{}, store: mockStorage})
+
+			resp, err := client.CreateAccount(context.Background(), tc.req)
+			assert.Equal(t, tc.expectedCode, status.Code(err))
+			if tc.expectedCode == codes.OK {
+				assert.Equal(t, "acc-1", resp.GetAccount().GetAccountId())
+				assert.Equal(t, "100.5", resp.GetAccount().GetBalance())
+				assert.Equal(t, "Payroll", resp.GetAccount().GetName())
+				assert.Equal(t, "42", resp.GetAccount().GetMetadata().GetFields()["cost_center"].GetStringValue())
+				assert.Equal(t, int64(1), resp.GetAccount().GetVersion())
+				assert.Equal(t, storage.DefaultCurrency, resp.GetAccount().GetCurrency())
+				assert.Equal(t, storage.AccountStatusActive, resp.GetAccount().GetStatus())
+			}
+		})
+	}
+}
+
+// TestGRPCGetAccountDetails tests the GetAccountDetails RPC, including missing IDs, unknown accounts
+// and callers restricted to other accounts.
+func TestGRPCGetAccountDetails(t *testing.T) {
+	tests := []struct {
+		name         string
+		accountID    string
+		principal    *principal
+		mockSetup    func(m *mocks.MockStorage)
+		expectedCode codes.Code
+	}{
+		{
+			name:      "success",
+			accountID: "acc-1",
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().GetAccountDetails(gomock.Any(), "acc-1").Return(&storage.Account{
+					ID:                "acc-1",
+					Balance:           decimal.RequireFromString("42.00"),
+					AccountAttributes: storage.AccountAttributes{Labels: []string{"eu"}, Metadata: json.RawMessage(`{"tier":2}`)},
+					Version:           4,
+				}, nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "missing account id",
+			accountID:    " ",
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:      "not found",
+			accountID: "acc-1",
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().GetAccountDetails(gomock.Any(), "acc-1").Return(nil, errors.New(storage.ErrAccountNotFound))
+			},
+			expectedCode: codes.NotFound,
+		},
+		{
+			name:         "forbidden account",
+			accountID:    "acc-1",
+			principal:    &principal{ClientID: "user", AccountRestricted: true, ReadAccounts: map[string]bool{"acc-2": true}},
+			expectedCode: codes.PermissionDenied,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockStorage := mocks.NewMockStorage(ctrl)
+			if tc.mockSetup != nil {
+				tc.mockSetup(mockStorage)
+			}
+			g := &grpcService{s: &Server{cfg: &config.Config{}, store: mockStorage}}
+
+			ctx := context.Background()
+			if tc.principal != nil {
+				ctx = withPrincipal(ctx, tc.principal)
+			}
+			resp, err := g.GetAccountDetails(ctx, &transfersv1.GetAccountDetailsRequest{AccountId: tc.accountID})
+			assert.Equal(t, tc.expectedCode, status.Code(err))
+			if tc.expectedCode == codes.OK {
+				assert.Equal(t, "42", resp.GetAccount().GetBalance())
+				assert.Equal(t, []string{"eu"}, resp.GetAccount().GetLabels())
+				assert.Equal(t, float64(2), resp.GetAccount().GetMetadata().GetFields()["tier"].GetNumberValue())
+				assert.Equal(t, int64(4), resp.GetAccount().GetVersion())
+			}
+		})
+	}
+}
+
+// TestGRPCProcessTransaction tests the ProcessTransaction RPC and the mapping of storage errors to status codes.
+func TestGRPCProcessTransaction(t *testing.T) {
+	tests := []struct {
+		name         string
+		req          *transfersv1.ProcessTransactionRequest
+		mockSetup    func(m *mocks.MockStorage)
+		expectedCode codes.Code
+	}{
+		{
+			name: "success",
+			req:  &transfersv1.ProcessTransactionRequest{SourceAccountId: "acc-1", DestinationAccountId: "acc-2", Amount: "10"},
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().ProcessTransaction(gomock.Any(), "acc-1", "acc-2", decimal.RequireFromString("10"), storage.AnyVersion).Return(nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "same account",
+			req:          &transfersv1.ProcessTransactionRequest{SourceAccountId: "acc-1", DestinationAccountId: "acc-1", Amount: "10"},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "source missing",
+			req:  &transfersv1.ProcessTransactionRequest{SourceAccountId: "acc-1", DestinationAccountId: "acc-2", Amount: "10"},
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().ProcessTransaction(gomock.Any(), "acc-1", "acc-2", gomock.Any(), storage.AnyVersion).Return(errors.New(storage.ErrSourceAccountMsg))
+			},
+			expectedCode: codes.NotFound,
+		},
+		{
+			name: "insufficient funds",
+			req:  &transfersv1.ProcessTransactionRequest{SourceAccountId: "acc-1", DestinationAccountId: "acc-2", Amount: "10"},
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().ProcessTransaction(gomock.Any(), "acc-1", "acc-2", gomock.Any(), storage.AnyVersion).Return(errors.New(storage.ErrInsufficientFundsMsg))
+			},
+			expectedCode: codes.FailedPrecondition,
+		},
+		{
+			name: "source version mismatch",
+			req:  &transfersv1.ProcessTransactionRequest{SourceAccountId: "acc-1", DestinationAccountId: "acc-2", Amount: "10", SourceAccountVersion: 3},
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().ProcessTransaction(gomock.Any(), "acc-1", "acc-2", gomock.Any(), int64(3)).Return(errors.New(storage.ErrAccountVersionMsg))
+			},
+			expectedCode: codes.Aborted,
+		},
+		{
+			name:         "negative source version",
+			req:          &transfersv1.ProcessTransactionRequest{SourceAccountId: "acc-1", DestinationAccountId: "acc-2", Amount: "10", SourceAccountVersion: -1},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "source account closed",
+			req:  &transfersv1.ProcessTransactionRequest{SourceAccountId: "acc-1", DestinationAccountId: "acc-2", Amount: "10"},
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().ProcessTransaction(gomock.Any(), "acc-1", "acc-2", gomock.Any(), storage.AnyVersion).Return(errors.New(storage.ErrSourceClosedMsg))
+			},
+			expectedCode: codes.FailedPrecondition,
+		},
+		{
+			name: "currency mismatch",
+			req:  &transfersv1.ProcessTransactionRequest{SourceAccountId: "acc-1", DestinationAccountId: "acc-2", Amount: "10"},
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().ProcessTransaction(gomock.Any(), "acc-1", "acc-2", gomock.Any(), storage.AnyVersion).Return(errors.New(storage.ErrCurrencyMismatchMsg))
+			},
+			expectedCode: codes.FailedPrecondition,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockStorage := mocks.NewMockStorage(ctrl)
+			if tc.mockSetup != nil {
+				tc.mockSetup(mockStorage)
+			}
+			client := newGRPCClient(t, &Server{cfg: &config.Config{}, store: mockStorage})
+
+			_, err := client.ProcessTransaction(context.Background(), tc.req)
+			assert.Equal(t, tc.expectedCode, status.Code(err))
+		})
+	}
+}
+
+// TestGRPCProcessTransactionRequestID verifies transfers are recorded under the call's request ID.
+func TestGRPCProcessTransactionRequestID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockStorage := mocks.NewMockStorage(ctrl)
+	mockStorage.EXPECT().ProcessTransaction(gomock.Any(), "acc-1", "acc-2", gomock.Any(), storage.AnyVersion).
+		DoAndReturn(func(ctx context.Context, _, _ string, _ decimal.Decimal, _ int64) error {
+			assert.Equal(t, "req-123", utils.RequestID(ctx))
+			return nil
+		})
+	client := newGRPCClient(t, &Server{cfg: &config.Config{}, store: mockStorage})
+
+	ctx := metadataContext(requestIDHeader, "req-123")
+	_, err := client.ProcessTransaction(ctx, &transfersv1.ProcessTransactionRequest{SourceAccountId: "acc-1", DestinationAccountId: "acc-2", Amount: "1"})
+	assert.NoError(t, err)
+}
+
+// TestGRPCListTransactions tests transaction listing with paging, defaults and invalid page tokens.
+func TestGRPCListTransactions(t *testing.T) {
+	createdAt := time.Date(2025, 12, 1, 12, 0, 0, 0, time.UTC)
+	txn := func(id int64) storage.Transaction {
+		return storage.Transaction{ID: id, SourceAccountID: "acc-1", DestinationAccountID: "acc-2", Amount: decimal.RequireFromString("5"), CreatedAt: createdAt}
+	}
+	account := &storage.Account{ID: "acc-1", Balance: decimal.Zero}
+
+	tests := []struct {
+		name          string
+		req           *transfersv1.ListTransactionsRequest
+		mockSetup     func(m *mocks.MockStorage)
+		expectedCode  codes.Code
+		expectedIDs   []int64
+		expectedToken string
+	}{
+		{
+			name: "first page with more",
+			req:  &transfersv1.ListTransactionsRequest{AccountId: "acc-1", PageSize: 2},
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().GetAccountDetails(gomock.Any(), "acc-1").Return(account, nil)
+				m.EXPECT().ListTransactions(gomock.Any(), "acc-1", int64(0), 3).Return([]storage.Transaction{txn(9), txn(7), txn(3)}, nil)
+			},
+			expectedCode:  codes.OK,
+			expectedIDs:   []int64{9, 7},
+			expectedToken: "7",
+		},
+		{
+			name: "last page with default size",
+			req:  &transfersv1.ListTransactionsRequest{AccountId: "acc-1", PageToken: "7"},
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().GetAccountDetails(gomock.Any(), "acc-1").Return(account, nil)
+				m.EXPECT().ListTransactions(gomock.Any(), "acc-1", int64(7), defaultTransactionsPageSize+1).Return([]storage.Transaction{txn(3)}, nil)
+			},
+			expectedCode: codes.OK,
+			expectedIDs:  []int64{3},
+		},
+		{
+			name:         "invalid page token",
+			req:          &transfersv1.ListTransactionsRequest{AccountId: "acc-1", PageToken: "abc"},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "account not found",
+			req:  &transfersv1.ListTransactionsRequest{AccountId: "acc-1"},
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().GetAccountDetails(gomock.Any(), "acc-1").Return(nil, errors.New(storage.ErrAccountNotFound))
+			},
+			expectedCode: codes.NotFound,
+		},
+		{
+			name: "list error",
+			req:  &transfersv1.ListTransactionsRequest{AccountId: "acc-1"},
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().GetAccountDetails(gomock.Any(), "acc-1").Return(account, nil)
+				m.EXPECT().ListTransactions(gomock.Any(), "acc-1", int64(0), gomock.Any()).Return(nil, errors.New(storage.ErrListTransactionsMsg))
+			},
+			expectedCode: codes.Internal,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockStorage := mocks.NewMockStorage(ctrl)
+			if tc.mockSetup != nil {
+				tc.mockSetup(mockStorage)
+			}
+			client := newGRPCClient(t, &Server{cfg: &config.Config{}, store: mockStorage})
+
+			resp, err := client.ListTransactions(context.Background(), tc.req)
+			assert.Equal(t, tc.expectedCode, status.Code(err))
+			if tc.expectedCode != codes.OK {
+				return
+			}
+			ids := make([]int64, 0, len(resp.GetTransactions()))
+			for _, txn := range resp.GetTransactions() {
+				ids = append(ids, txn.GetId())
+				assert.Equal(t, "5", txn.GetAmount())
+				assert.Equal(t, createdAt, txn.GetCreatedAt().AsTime())
+			}
+			assert.Equal(t, tc.expectedIDs, ids)
+			assert.Equal(t, tc.expectedToken, resp.GetNextPageToken())
+		})
+	}
+}
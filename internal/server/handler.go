@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
 	"github.com/cursed-ninja/internal-transfers-system/internal/utils"
@@ -14,13 +15,41 @@ import (
 // Request and Response Structs
 
 type createAccountRequest struct {
-	AccountID      string `json:"account_id"`
-	InitialBalance string `json:"initial_balance"`
+	AccountID      string          `json:"account_id"`
+	InitialBalance string          `json:"initial_balance"`
+	Currency       string          `json:"currency"`
+	Name           string          `json:"name"`
+	OwnerRef       string          `json:"owner_ref"`
+	Type           string          `json:"type"`
+	Labels         []string        `json:"labels"`
+	Metadata       json.RawMessage `json:"metadata"`
+}
+
+// updateAccountRequest is a JSON merge patch of an account's attributes: absent fields are left
+// unchanged and null clears a field. Version must match the account's current version; it may be
+// omitted in favour of an If-Match header.
+type updateAccountRequest struct {
+	Version  int64                     `json:"version"`
+	Name     nullable[string]          `json:"name"`
+	OwnerRef nullable[string]          `json:"owner_ref"`
+	Type     nullable[string]          `json:"type"`
+	Labels   nullable[[]string]        `json:"labels"`
+	Metadata nullable[json.RawMessage] `json:"metadata"`
 }
 
 type accountResponse struct {
-	ID      string `json:"account_id"`
-	Balance string `json:"balance"`
+	ID        string          `json:"account_id"`
+	Balance   string          `json:"balance"`
+	Currency  string          `json:"currency"`
+	Status    string          `json:"status"`
+	Name      string          `json:"name"`
+	OwnerRef  string          `json:"owner_ref"`
+	Type      string          `json:"type"`
+	Labels    []string        `json:"labels"`
+	Metadata  json.RawMessage `json:"metadata"`
+	Version   int64           `json:"version"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
 }
 
 type processTransactionRequest struct {
@@ -60,7 +89,7 @@ func (s *Server) CreateAccount(w http.ResponseWriter, r *http.Request) {
 	ctx, _ = utils.LoggerWithKey(ctx, zap.String("account_id", req.AccountID))
 	ctx, logger = utils.LoggerWithKey(ctx, zap.String("balance", balance.String()))
 
-	if err := s.store.CreateAccount(ctx, req.AccountID, balance); err != nil {
+	if err := s.store.CreateAccount(ctx, req.AccountID, balance, req.attributes()); err != nil {
 		logger.Error("failed to create account", zap.Error(err))
 		errorMsg := err.Error()
 		statusCode := http.StatusInternalServerError
@@ -72,6 +101,7 @@ func (s *Server) CreateAccount(w http.ResponseWriter, r *http.Request) {
 	}
 
 	logger.Info("account created successfully")
+	w.Header().Set("ETag", accountETag(1))
 	w.WriteHeader(http.StatusCreated)
 }
 
@@ -94,6 +124,12 @@ func (s *Server) GetAccountDetails(w http.ResponseWriter, r *http.Request) {
 
 	ctx, logger = utils.LoggerWithKey(ctx, zap.String("account_id", accountID))
 
+	if p := principalFromContext(ctx); p != nil && !p.canRead(accountID) {
+		logger.Warn("caller is not allowed to read account")
+		http.Error(w, ErrAccountForbidden.Error(), http.StatusForbidden)
+		return
+	}
+
 	acc, err := s.store.GetAccountDetails(ctx, accountID)
 	if err != nil {
 		logger.Error("failed to get account details", zap.Error(err))
@@ -106,12 +142,8 @@ func (s *Server) GetAccountDetails(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response := accountResponse{
-		ID:      acc.ID,
-		Balance: acc.Balance.String(),
-	}
-
-	if err := json.NewEncoder(w).Encode(response); err != nil {
+	w.Header().Set("ETag", accountETag(acc.Version))
+	if err := json.NewEncoder(w).Encode(newAccountResponse(acc)); err != nil {
 		logger.Error("failed to encode response", zap.Error(err))
 		http.Error(w, "failed to encode response", http.StatusInternalServerError)
 		return
@@ -120,13 +152,91 @@ func (s *Server) GetAccountDetails(w http.ResponseWriter, r *http.Request) {
 	logger.Info("account details retrieved successfully")
 }
 
+// UpdateAccount handles PATCH /accounts/{accountID} requests to change an account's name, owner,
+// type, labels or metadata. The request must carry the version it was based on, in the body or as an
+// If-Match ETag; if the account has changed since, the update is rejected with 409, or 412 for If-Match,
+// and the client should refetch and retry.
+func (s *Server) UpdateAccount(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := utils.ContextLogger(ctx)
+
+	logger.Info("received UpdateAccount request")
+
+	accountID := strings.TrimSpace(mux.Vars(r)["accountID"])
+	if accountID == "" {
+		logger.Error("missing account_id in URL path")
+		http.Error(w, "account_id is required in URL path", http.StatusBadRequest)
+		return
+	}
+
+	ctx, logger = utils.LoggerWithKey(ctx, zap.String("account_id", accountID))
+
+	ifMatch, hasIfMatch, err := ifMatchVersion(r)
+	if err != nil {
+		logger.Error("failed to parse If-Match header", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req updateAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("failed to parse request body", zap.Error(err))
+		http.Error(w, "invalid JSON format", http.StatusBadRequest)
+		return
+	}
+
+	patch, err := ValidateUpdateAccount(&req, ifMatch, hasIfMatch)
+	if err != nil {
+		logger.Error("failed to validate request", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if p := principalFromContext(ctx); p != nil && !p.canDebit(accountID) {
+		logger.Warn("caller is not allowed to update account")
+		http.Error(w, ErrAccountForbidden.Error(), http.StatusForbidden)
+		return
+	}
+
+	acc, err := s.store.UpdateAccount(ctx, accountID, req.Version, patch)
+	if err != nil {
+		logger.Error("failed to update account", zap.Error(err))
+		errorMsg := err.Error()
+		statusCode := http.StatusInternalServerError
+		switch errorMsg {
+		case storage.ErrAccountNotFound:
+			statusCode = http.StatusNotFound
+		case storage.ErrAccountVersionMsg:
+			statusCode = http.StatusConflict
+			if hasIfMatch && ifMatch != storage.AnyVersion {
+				statusCode = http.StatusPreconditionFailed
+			}
+		}
+		http.Error(w, errorMsg, statusCode)
+		return
+	}
+
+	logger.Info("account updated successfully", zap.Int64("version", acc.Version))
+	w.Header().Set("ETag", accountETag(acc.Version))
+	writeJSON(w, logger, http.StatusOK, newAccountResponse(acc))
+}
+
 // ProcessTransaction handles POST /transactions requests to transfer funds between accounts.
+// An If-Match header makes the transfer conditional on the source account's ETag, failing with 412
+// if the source account has changed since.
 func (s *Server) ProcessTransaction(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	logger := utils.ContextLogger(ctx)
 
 	logger.Info("received ProcessTransaction request")
 
+	sourceVersion, _, err := ifMatchVersion(r)
+	if err != nil {
+		logger.Error("failed to parse If-Match header", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	var req processTransactionRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -146,15 +256,23 @@ func (s *Server) ProcessTransaction(w http.ResponseWriter, r *http.Request) {
 	ctx, _ = utils.LoggerWithKey(ctx, zap.String("destination_account_id", req.DestAccID))
 	ctx, logger = utils.LoggerWithKey(ctx, zap.String("amount", amt.String()))
 
-	if err := s.store.ProcessTransaction(ctx, req.SourceAccID, req.DestAccID, amt); err != nil {
+	if p := principalFromContext(ctx); p != nil && !p.canDebit(req.SourceAccID) {
+		logger.Warn("caller is not allowed to debit source account")
+		http.Error(w, ErrAccountForbidden.Error(), http.StatusForbidden)
+		return
+	}
+
+	if err := s.store.ProcessTransaction(ctx, req.SourceAccID, req.DestAccID, amt, sourceVersion); err != nil {
 		logger.Error("failed to process transaction", zap.Error(err))
 		errorMsg := err.Error()
 		statusCode := http.StatusInternalServerError
 		switch errorMsg {
 		case storage.ErrSourceAccountMsg, storage.ErrDestinationAccountMsg:
 			statusCode = http.StatusNotFound
-		case storage.ErrInsufficientFundsMsg:
+		case storage.ErrInsufficientFundsMsg, storage.ErrCurrencyMismatchMsg, storage.ErrAmountPrecisionMsg, storage.ErrSourceClosedMsg, storage.ErrDestinationClosedMsg:
 			statusCode = http.StatusBadRequest
+		case storage.ErrAccountVersionMsg:
+			statusCode = http.StatusPreconditionFailed
 		}
 		http.Error(w, errorMsg, statusCode)
 		return
@@ -163,3 +281,68 @@ func (s *Server) ProcessTransaction(w http.ResponseWriter, r *http.Request) {
 	logger.Info("transaction processed successfully")
 	w.WriteHeader(http.StatusCreated)
 }
+
+// writeJSON writes v as a JSON response with the given status code.
+func writeJSON(w http.ResponseWriter, logger *zap.Logger, statusCode int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logger.Error("failed to encode response", zap.Error(err))
+	}
+}
+
+// attributes returns the validated descriptive fields of the request.
+func (req *createAccountRequest) attributes() storage.AccountAttributes {
+	return storage.AccountAttributes{
+		Currency: req.Currency,
+		Name:     req.Name,
+		OwnerRef: req.OwnerRef,
+		Type:     req.Type,
+		Labels:   req.Labels,
+		Metadata: req.Metadata,
+	}
+}
+
+// newAccountResponse converts a stored account to its API representation.
+func newAccountResponse(acc *storage.Account) accountResponse {
+	labels := acc.Labels
+	if labels == nil {
+		labels = []string{}
+	}
+	metadata := acc.Metadata
+	if len(metadata) == 0 {
+		metadata = json.RawMessage(`{}`)
+	}
+	return accountResponse{
+		ID:        acc.ID,
+		Balance:   acc.Balance.String(),
+		Currency:  acc.Currency,
+		Status:    acc.Status,
+		Name:      acc.Name,
+		OwnerRef:  acc.OwnerRef,
+		Type:      acc.Type,
+		Labels:    labels,
+		Metadata:  metadata,
+		Version:   acc.Version,
+		CreatedAt: acc.CreatedAt,
+		UpdatedAt: acc.UpdatedAt,
+	}
+}
+
+// nullable is a field of a JSON merge patch. Set reports whether the field was present in the
+// document and Null whether its value was null.
+type nullable[T any] struct {
+	Set   bool
+	Null  bool
+	Value T
+}
+
+// UnmarshalJSON records that the field was present, and whether it was null.
+func (n *nullable[T]) UnmarshalJSON(data []byte) error {
+	n.Set = true
+	if string(data) == "null" {
+		n.Null = true
+		return nil
+	}
+	return json.Unmarshal(data, &n.Value)
+}
@@ -2,10 +2,12 @@ package server
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/cursed-ninja/internal-transfers-system/internal/config"
 	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
@@ -45,10 +47,50 @@ func TestCreateAccount(t *testing.T) {
 			name: "success",
 			body: `{"account_id":"acc-1","initial_balance":"100.00"}`,
 			mockSetup: func(m *mocks.MockStorage) {
-				m.EXPECT().CreateAccount(gomock.Any(), "acc-1", decimal.RequireFromString("100.00")).Return(nil)
+				m.EXPECT().CreateAccount(gomock.Any(), "acc-1", decimal.RequireFromString("100.00"), gomock.Any()).Return(nil)
 			},
 			expectedStatus: http.StatusCreated,
 		},
+		{
+			name: "success with attributes",
+			body: `{"account_id":"acc-1","initial_balance":"1","name":" Payroll ","type":"operating","labels":["eu"," eu ","payroll"],"metadata":{"cost_center":"42"}}`,
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().CreateAccount(gomock.Any(), "acc-1", decimal.RequireFromString("1"), storage.AccountAttributes{
+					Currency: storage.DefaultCurrency,
+					Name:     "Payroll",
+					Type:     storage.AccountTypeOperating,
+					Labels:   []string{"eu", "payroll"},
+					Metadata: json.RawMessage(`{"cost_center":"42"}`),
+				}).Return(nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "success with currency",
+			body: `{"account_id":"acc-1","initial_balance":"1","currency":"eur"}`,
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().CreateAccount(gomock.Any(), "acc-1", decimal.RequireFromString("1"), storage.AccountAttributes{
+					Currency: "EUR",
+					Labels:   []string{},
+				}).Return(nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "invalid currency",
+			body:           `{"account_id":"acc-1","initial_balance":"1","currency":"EURO"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown account type",
+			body:           `{"account_id":"acc-1","initial_balance":"1","type":"savings"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "metadata not an object",
+			body:           `{"account_id":"acc-1","initial_balance":"1","metadata":[1]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
 		{
 			name:           "invalid json",
 			body:           `{not json`,
@@ -79,11 +121,17 @@ func TestCreateAccount(t *testing.T) {
 			mockSetup:      nil,
 			expectedStatus: http.StatusBadRequest,
 		},
+		{
+			name:           "balance finer than the currency",
+			body:           `{"account_id":"acc-1","initial_balance":"10.5","currency":"JPY"}`,
+			mockSetup:      nil,
+			expectedStatus: http.StatusBadRequest,
+		},
 		{
 			name: "duplicate account",
 			body: `{"account_id":"acc-1","initial_balance":"100"}`,
 			mockSetup: func(m *mocks.MockStorage) {
-				m.EXPECT().CreateAccount(gomock.Any(), "acc-1", decimal.RequireFromString("100")).Return(errors.New(storage.ErrAccountExists))
+				m.EXPECT().CreateAccount(gomock.Any(), "acc-1", decimal.RequireFromString("100"), gomock.Any()).Return(errors.New(storage.ErrAccountExists))
 			},
 			expectedStatus: http.StatusConflict,
 		},
@@ -91,7 +139,7 @@ func TestCreateAccount(t *testing.T) {
 			name: "internal server error",
 			body: `{"account_id":"acc-1","initial_balance":"100"}`,
 			mockSetup: func(m *mocks.MockStorage) {
-				m.EXPECT().CreateAccount(gomock.Any(), "acc-1", decimal.RequireFromString("100")).Return(assert.AnError)
+				m.EXPECT().CreateAccount(gomock.Any(), "acc-1", decimal.RequireFromString("100"), gomock.Any()).Return(assert.AnError)
 			},
 			expectedStatus: http.StatusInternalServerError,
 		},
@@ -123,12 +171,15 @@ func TestCreateAccount(t *testing.T) {
 // TestGetAccountDetails tests the GetAccountDetails endpoint.
 // Scenarios include successful retrieval, account not found, and internal errors.
 func TestGetAccountDetails(t *testing.T) {
+	createdAt := time.Date(2025, 12, 1, 12, 0, 0, 0, time.UTC)
+
 	tests := []struct {
 		name           string
 		accountID      string
 		mockSetup      func(m *mocks.MockStorage)
 		expectedStatus int
 		expectedBody   string
+		expectedETag   string
 	}{
 		{
 			name:      "success",
@@ -137,10 +188,43 @@ func TestGetAccountDetails(t *testing.T) {
 				m.EXPECT().GetAccountDetails(gomock.Any(), "acc-1").Return(&storage.Account{
 					ID:      "acc-1",
 					Balance: decimal.RequireFromString("150.50"),
+					Status:  storage.AccountStatusActive,
+					AccountAttributes: storage.AccountAttributes{
+						Currency: "EUR",
+						Name:     "Payroll",
+						OwnerRef: "team-payments",
+						Type:     storage.AccountTypeOperating,
+						Labels:   []string{"eu"},
+						Metadata: json.RawMessage(`{"cost_center":"42"}`),
+					},
+					Version:   2,
+					CreatedAt: createdAt,
+					UpdatedAt: createdAt,
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedETag:   `"2"`,
+			expectedBody: `{"account_id":"acc-1","balance":"150.5","currency":"EUR","status":"active","name":"Payroll","owner_ref":"team-payments","type":"operating",
+				"labels":["eu"],"metadata":{"cost_center":"42"},"version":2,"created_at":"2025-12-01T12:00:00Z","updated_at":"2025-12-01T12:00:00Z"}`,
+		},
+		{
+			name:      "account without attributes",
+			accountID: "acc-1",
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().GetAccountDetails(gomock.Any(), "acc-1").Return(&storage.Account{
+					ID:                "acc-1",
+					Balance:           decimal.RequireFromString("1"),
+					Status:            storage.AccountStatusClosed,
+					AccountAttributes: storage.AccountAttributes{Currency: storage.DefaultCurrency},
+					Version:           1,
+					CreatedAt:         createdAt,
+					UpdatedAt:         createdAt,
 				}, nil)
 			},
 			expectedStatus: http.StatusOK,
-			expectedBody:   `{"account_id":"acc-1","balance":"150.5"}`,
+			expectedETag:   `"1"`,
+			expectedBody: `{"account_id":"acc-1","balance":"1","currency":"USD","status":"closed","name":"","owner_ref":"","type":"","labels":[],"metadata":{},
+				"version":1,"created_at":"2025-12-01T12:00:00Z","updated_at":"2025-12-01T12:00:00Z"}`,
 		},
 		{
 			name:      "account not found",
@@ -188,6 +272,199 @@ func TestGetAccountDetails(t *testing.T) {
 			if tc.expectedBody != "" {
 				assert.JSONEq(t, tc.expectedBody, w.Body.String())
 			}
+			assert.Equal(t, tc.expectedETag, w.Header().Get("ETag"))
+		})
+	}
+}
+
+// TestUpdateAccount tests the UpdateAccount endpoint through the router, so request bodies are
+// also checked against the OpenAPI schema.
+// Scenarios include partial updates, clearing fields with null, invalid patches, versions given in
+// the body or If-Match, version conflicts, missing accounts and callers without write access to the account.
+func TestUpdateAccount(t *testing.T) {
+	createdAt := time.Date(2025, 12, 1, 12, 0, 0, 0, time.UTC)
+	name := "Payroll"
+	empty := ""
+	labels := []string{"eu", "payroll"}
+	noLabels := []string{}
+	updated := &storage.Account{
+		ID:                "acc-1",
+		Balance:           decimal.RequireFromString("10"),
+		Status:            storage.AccountStatusActive,
+		AccountAttributes: storage.AccountAttributes{Currency: storage.DefaultCurrency, Name: "Payroll", Labels: []string{"eu", "payroll"}, Metadata: json.RawMessage(`{}`)},
+		Version:           3,
+		CreatedAt:         createdAt,
+		UpdatedAt:         createdAt,
+	}
+
+	tests := []struct {
+		name           string
+		body           string
+		ifMatch        string
+		principal      *principal
+		mockSetup      func(m *mocks.MockStorage)
+		expectedStatus int
+		expectedBody   string
+		expectedETag   string
+	}{
+		{
+			name: "partial update",
+			body: `{"version":2,"name":" Payroll ","labels":["eu","payroll","eu"]}`,
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().UpdateAccount(gomock.Any(), "acc-1", int64(2), storage.AccountPatch{Name: &name, Labels: &labels}).Return(updated, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedETag:   `"3"`,
+			expectedBody: `{"account_id":"acc-1","balance":"10","currency":"USD","status":"active","name":"Payroll","owner_ref":"","type":"","labels":["eu","payroll"],
+				"metadata":{},"version":3,"created_at":"2025-12-01T12:00:00Z","updated_at":"2025-12-01T12:00:00Z"}`,
+		},
+		{
+			name: "null clears fields",
+			body: `{"version":2,"type":null,"labels":null,"metadata":null}`,
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().UpdateAccount(gomock.Any(), "acc-1", int64(2), storage.AccountPatch{
+					Type:     &empty,
+					Labels:   &noLabels,
+					Metadata: json.RawMessage(`{}`),
+				}).Return(updated, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing version",
+			body:           `{"name":"Payroll"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "version from If-Match",
+			body:    `{"name":"Payroll"}`,
+			ifMatch: `"2"`,
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().UpdateAccount(gomock.Any(), "acc-1", int64(2), storage.AccountPatch{Name: &name}).Return(updated, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedETag:   `"3"`,
+		},
+		{
+			name:    "If-Match agrees with version",
+			body:    `{"version":2,"name":"Payroll"}`,
+			ifMatch: `"2"`,
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().UpdateAccount(gomock.Any(), "acc-1", int64(2), gomock.Any()).Return(updated, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "If-Match disagrees with version",
+			body:           `{"version":1,"name":"Payroll"}`,
+			ifMatch:        `"2"`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "If-Match any version",
+			body:    `{"name":"Payroll"}`,
+			ifMatch: "*",
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().UpdateAccount(gomock.Any(), "acc-1", storage.AnyVersion, gomock.Any()).Return(updated, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:    "stale If-Match",
+			body:    `{"name":"Payroll"}`,
+			ifMatch: `"1"`,
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().UpdateAccount(gomock.Any(), "acc-1", int64(1), gomock.Any()).Return(nil, errors.New(storage.ErrAccountVersionMsg))
+			},
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:           "weak If-Match",
+			body:           `{"name":"Payroll"}`,
+			ifMatch:        `W/"2"`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "nothing to update",
+			body:           `{"version":2}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown account type",
+			body:           `{"version":2,"type":"savings"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{"error":"request body does not match schema","fields":[{"field":"type",
+				"message":"must be one of \"operating\", \"settlement\", \"fee\", \"customer\", null"}]}`,
+		},
+		{
+			name:           "unknown field",
+			body:           `{"version":2,"balance":"100"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"request body does not match schema","fields":[{"field":"balance","message":"is not a known field"}]}`,
+		},
+		{
+			name: "version conflict",
+			body: `{"version":1,"name":"Payroll"}`,
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().UpdateAccount(gomock.Any(), "acc-1", int64(1), gomock.Any()).Return(nil, errors.New(storage.ErrAccountVersionMsg))
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "account not found",
+			body: `{"version":1,"name":"Payroll"}`,
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().UpdateAccount(gomock.Any(), "acc-1", int64(1), gomock.Any()).Return(nil, errors.New(storage.ErrAccountNotFound))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "read-only caller",
+			body:           `{"version":1,"name":"Payroll"}`,
+			principal:      &principal{ClientID: "user", AccountRestricted: true, ReadAccounts: map[string]bool{"acc-1": true}},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name: "internal error",
+			body: `{"version":1,"name":"Payroll"}`,
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().UpdateAccount(gomock.Any(), "acc-1", int64(1), gomock.Any()).Return(nil, errors.New(storage.ErrUpdateAccountMsg))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockStorage := mocks.NewMockStorage(mockCtrl)
+			if tc.mockSetup != nil {
+				tc.mockSetup(mockStorage)
+			}
+
+			s := Server{cfg: &config.Config{}, store: mockStorage}
+			r := mux.NewRouter()
+			s.BindRoutes(r)
+
+			req := httptest.NewRequest(http.MethodPatch, "/accounts/acc-1", bytes.NewBufferString(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tc.ifMatch != "" {
+				req.Header.Set("If-Match", tc.ifMatch)
+			}
+			if tc.principal != nil {
+				req = req.WithContext(withPrincipal(req.Context(), tc.principal))
+			}
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedBody != "" {
+				assert.JSONEq(t, tc.expectedBody, w.Body.String())
+			}
+			if tc.expectedETag != "" {
+				assert.Equal(t, tc.expectedETag, w.Header().Get("ETag"))
+			}
 		})
 	}
 }
@@ -198,6 +475,7 @@ func TestProcessTransaction(t *testing.T) {
 	tests := []struct {
 		name           string
 		body           string
+		ifMatch        string
 		mockSetup      func(m *mocks.MockStorage)
 		expectedStatus int
 	}{
@@ -205,7 +483,7 @@ func TestProcessTransaction(t *testing.T) {
 			name: "success",
 			body: `{"source_account_id":"acc-1","destination_account_id":"acc-2","amount":"50.00"}`,
 			mockSetup: func(m *mocks.MockStorage) {
-				m.EXPECT().ProcessTransaction(gomock.Any(), "acc-1", "acc-2", decimal.RequireFromString("50.00")).
+				m.EXPECT().ProcessTransaction(gomock.Any(), "acc-1", "acc-2", decimal.RequireFromString("50.00"), storage.AnyVersion).
 					Return(nil)
 			},
 			expectedStatus: http.StatusCreated,
@@ -256,16 +534,25 @@ func TestProcessTransaction(t *testing.T) {
 			name: "insufficient funds",
 			body: `{"source_account_id":"acc-1","destination_account_id":"acc-2","amount":"50"}`,
 			mockSetup: func(m *mocks.MockStorage) {
-				m.EXPECT().ProcessTransaction(gomock.Any(), "acc-1", "acc-2", decimal.RequireFromString("50")).
+				m.EXPECT().ProcessTransaction(gomock.Any(), "acc-1", "acc-2", decimal.RequireFromString("50"), storage.AnyVersion).
 					Return(errors.New(storage.ErrInsufficientFundsMsg))
 			},
 			expectedStatus: http.StatusBadRequest,
 		},
+		{
+			name: "currency mismatch",
+			body: `{"source_account_id":"acc-1","destination_account_id":"acc-2","amount":"50"}`,
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().ProcessTransaction(gomock.Any(), "acc-1", "acc-2", decimal.RequireFromString("50"), storage.AnyVersion).
+					Return(errors.New(storage.ErrCurrencyMismatchMsg))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
 		{
 			name: "source_account_id not found",
 			body: `{"source_account_id":"acc-1","destination_account_id":"acc-2","amount":"10"}`,
 			mockSetup: func(m *mocks.MockStorage) {
-				m.EXPECT().ProcessTransaction(gomock.Any(), "acc-1", "acc-2", decimal.RequireFromString("10")).
+				m.EXPECT().ProcessTransaction(gomock.Any(), "acc-1", "acc-2", decimal.RequireFromString("10"), storage.AnyVersion).
 					Return(errors.New(storage.ErrSourceAccountMsg))
 			},
 			expectedStatus: http.StatusNotFound,
@@ -274,16 +561,50 @@ func TestProcessTransaction(t *testing.T) {
 			name: "destination_account_id not found",
 			body: `{"source_account_id":"acc-1","destination_account_id":"acc-2","amount":"10"}`,
 			mockSetup: func(m *mocks.MockStorage) {
-				m.EXPECT().ProcessTransaction(gomock.Any(), "acc-1", "acc-2", decimal.RequireFromString("10")).
+				m.EXPECT().ProcessTransaction(gomock.Any(), "acc-1", "acc-2", decimal.RequireFromString("10"), storage.AnyVersion).
 					Return(errors.New(storage.ErrDestinationAccountMsg))
 			},
 			expectedStatus: http.StatusNotFound,
 		},
+		{
+			name:    "source version matches If-Match",
+			body:    `{"source_account_id":"acc-1","destination_account_id":"acc-2","amount":"5"}`,
+			ifMatch: `"7"`,
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().ProcessTransaction(gomock.Any(), "acc-1", "acc-2", decimal.RequireFromString("5"), int64(7)).Return(nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:    "source version mismatch",
+			body:    `{"source_account_id":"acc-1","destination_account_id":"acc-2","amount":"5"}`,
+			ifMatch: `"6"`,
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().ProcessTransaction(gomock.Any(), "acc-1", "acc-2", decimal.RequireFromString("5"), int64(6)).
+					Return(errors.New(storage.ErrAccountVersionMsg))
+			},
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:           "invalid If-Match",
+			body:           `{"source_account_id":"acc-1","destination_account_id":"acc-2","amount":"5"}`,
+			ifMatch:        `"6", "7"`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "destination account closed",
+			body: `{"source_account_id":"acc-1","destination_account_id":"acc-2","amount":"5"}`,
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().ProcessTransaction(gomock.Any(), "acc-1", "acc-2", decimal.RequireFromString("5"), storage.AnyVersion).
+					Return(errors.New(storage.ErrDestinationClosedMsg))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
 		{
 			name: "internal server error",
 			body: `{"source_account_id":"acc-1","destination_account_id":"acc-2","amount":"20"}`,
 			mockSetup: func(m *mocks.MockStorage) {
-				m.EXPECT().ProcessTransaction(gomock.Any(), "acc-1", "acc-2", decimal.RequireFromString("20")).
+				m.EXPECT().ProcessTransaction(gomock.Any(), "acc-1", "acc-2", decimal.RequireFromString("20"), storage.AnyVersion).
 					Return(assert.AnError)
 			},
 			expectedStatus: http.StatusInternalServerError,
@@ -302,6 +623,9 @@ func TestProcessTransaction(t *testing.T) {
 			}
 
 			req := httptest.NewRequest(http.MethodPost, "/transactions", bytes.NewBufferString(tc.body))
+			if tc.ifMatch != "" {
+				req.Header.Set("If-Match", tc.ifMatch)
+			}
 			w := httptest.NewRecorder()
 
 			s.ProcessTransaction(w, req)
@@ -0,0 +1,107 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/utils"
+	"go.uber.org/zap"
+)
+
+// Health statuses reported by the liveness and readiness endpoints.
+const (
+	statusOK          = "ok"
+	statusUnavailable = "unavailable"
+	statusDraining    = "draining"
+)
+
+// defaultReadinessTimeout is used when no readiness timeout is configured.
+const defaultReadinessTimeout = 2 * time.Second
+
+// HealthCheck reports whether a dependency is usable. A nil error means healthy.
+type HealthCheck func(ctx context.Context) error
+
+// readinessCheck is a named HealthCheck.
+type readinessCheck struct {
+	name  string
+	check HealthCheck
+}
+
+type componentStatus struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type readinessResponse struct {
+	Status     string                     `json:"status"`
+	Components map[string]componentStatus `json:"components"`
+}
+
+// AddReadinessCheck registers a named dependency check that must pass for the server to be ready.
+// It must be called before the server starts serving requests.
+func (s *Server) AddReadinessCheck(name string, check HealthCheck) {
+	s.readinessChecks = append(s.readinessChecks, readinessCheck{name: name, check: check})
+}
+
+// StartDraining marks the server as shutting down so readiness probes fail
+// and load balancers stop routing new traffic to this instance. Open event streams are closed.
+func (s *Server) StartDraining() {
+	s.draining.Store(true)
+	if s.drained != nil {
+		s.drainOnce.Do(func() { close(s.drained) })
+	}
+}
+
+// LivenessHandler reports that the process is up. It never checks dependencies,
+// so a database outage does not cause the orchestrator to restart the instance.
+func (s *Server) LivenessHandler(w http.ResponseWriter, r *http.Request) {
+	s.HealthHandler(w, r)
+}
+
+// ReadinessHandler runs every registered dependency check with a timeout and reports
+// component-level status. It returns 503 if any check fails or the server is draining.
+func (s *Server) ReadinessHandler(w http.ResponseWriter, r *http.Request) {
+	logger := utils.ContextLogger(r.Context())
+
+	timeout := defaultReadinessTimeout
+	if s.cfg != nil && s.cfg.HealthConfig != nil && s.cfg.HealthConfig.ReadinessTimeout > 0 {
+		timeout = s.cfg.HealthConfig.ReadinessTimeout
+	}
+
+	response := readinessResponse{
+		Status:     statusOK,
+		Components: make(map[string]componentStatus, len(s.readinessChecks)),
+	}
+	if s.draining.Load() {
+		response.Status = statusDraining
+	}
+
+	for _, rc := range s.readinessChecks {
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		err := rc.check(ctx)
+		cancel()
+
+		if err != nil {
+			logger.Warn("readiness check failed", zap.String("component", rc.name), zap.Error(err))
+			response.Components[rc.name] = componentStatus{Status: statusUnavailable, Error: err.Error()}
+			if response.Status == statusOK {
+				response.Status = statusUnavailable
+			}
+			continue
+		}
+		response.Components[rc.name] = componentStatus{Status: statusOK}
+	}
+
+	statusCode := http.StatusOK
+	if response.Status != statusOK {
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Error("failed to encode response", zap.Error(err))
+	}
+}
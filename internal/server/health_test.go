@@ -0,0 +1,109 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/config"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage/mocks"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+// TestLivenessHandler tests that liveness succeeds without touching storage.
+func TestLivenessHandler(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockStorage := mocks.NewMockStorage(mockCtrl)
+	s := NewServer(&config.Config{}, mockStorage, zap.NewNop(), zap.NewAtomicLevel())
+
+	r := mux.NewRouter()
+	s.BindRoutes(r)
+
+	req := httptest.NewRequest(http.MethodGet, "/livez", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, w.Body.String())
+}
+
+// TestReadinessHandler tests the ReadinessHandler endpoint.
+// Scenarios include healthy dependencies, a failing database, a failing extra check, and draining.
+func TestReadinessHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockSetup      func(m *mocks.MockStorage)
+		extraCheck     HealthCheck
+		draining       bool
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "ready",
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().Ping(gomock.Any()).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"ok","components":{"postgres":{"status":"ok"},"migrations":{"status":"ok"}}}`,
+		},
+		{
+			name: "database down",
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().Ping(gomock.Any()).Return(assert.AnError)
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   `{"status":"unavailable","components":{"postgres":{"status":"unavailable","error":"` + assert.AnError.Error() + `"},"migrations":{"status":"ok"}}}`,
+		},
+		{
+			name: "pending migrations",
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().Ping(gomock.Any()).Return(nil)
+			},
+			extraCheck: func(ctx context.Context) error {
+				return assert.AnError
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   `{"status":"unavailable","components":{"postgres":{"status":"ok"},"migrations":{"status":"unavailable","error":"` + assert.AnError.Error() + `"}}}`,
+		},
+		{
+			name: "draining",
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().Ping(gomock.Any()).Return(nil)
+			},
+			draining:       true,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   `{"status":"draining","components":{"postgres":{"status":"ok"},"migrations":{"status":"ok"}}}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockStorage := mocks.NewMockStorage(mockCtrl)
+			if tc.mockSetup != nil {
+				tc.mockSetup(mockStorage)
+			}
+
+			s := NewServer(&config.Config{}, mockStorage, zap.NewNop(), zap.NewAtomicLevel())
+			check := tc.extraCheck
+			if check == nil {
+				check = func(ctx context.Context) error { return nil }
+			}
+			s.AddReadinessCheck("migrations", check)
+			if tc.draining {
+				s.StartDraining()
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+			w := httptest.NewRecorder()
+			s.ReadinessHandler(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.JSONEq(t, tc.expectedBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package server
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/config"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwtLeeway tolerates clock skew between the token issuer and this service.
+const jwtLeeway = 30 * time.Second
+
+// wildcardAccount in an account claim grants access to every account.
+const wildcardAccount = "*"
+
+// jwtClaims are the claims accepted in bearer tokens.
+// AccountsRead lists accounts the caller may read; AccountsDebit lists accounts it may debit, which implies read.
+type jwtClaims struct {
+	jwt.RegisteredClaims
+	Scope         string   `json:"scope"`
+	AccountsRead  []string `json:"accounts_read"`
+	AccountsDebit []string `json:"accounts_debit"`
+}
+
+// jwk is a single RSA key from a JWKS document.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// JWTVerifier validates HS256 tokens against a shared secret and RS256 tokens against a local JWKS file.
+type JWTVerifier struct {
+	hmacSecret []byte
+	rsaKeys    map[string]*rsa.PublicKey
+	parser     *jwt.Parser
+}
+
+// NewJWTVerifier creates a JWTVerifier from configuration, loading RSA keys from the JWKS file if one is set.
+func NewJWTVerifier(cfg *config.JWTConfig) (*JWTVerifier, error) {
+	v := &JWTVerifier{
+		hmacSecret: []byte(cfg.HMACSecret),
+		rsaKeys:    make(map[string]*rsa.PublicKey),
+	}
+
+	if cfg.JWKSFile != "" {
+		keys, err := loadJWKS(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		v.rsaKeys = keys
+	}
+
+	if len(v.hmacSecret) == 0 && len(v.rsaKeys) == 0 {
+		return nil, errors.New("jwt: either an HMAC secret or a JWKS file is required")
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg(), jwt.SigningMethodRS256.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(jwtLeeway),
+	}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+	v.parser = jwt.NewParser(opts...)
+
+	return v, nil
+}
+
+// Verify validates the token's signature and claims and returns the caller it represents.
+func (v *JWTVerifier) Verify(token string) (*principal, error) {
+	var claims jwtClaims
+	if _, err := v.parser.ParseWithClaims(token, &claims, v.keyFunc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCredentials, err)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: missing sub claim", ErrInvalidCredentials)
+	}
+
+	p := &principal{
+		ClientID:          claims.Subject,
+		Scopes:            make(map[string]bool),
+		AccountRestricted: true,
+		ReadAccounts:      make(map[string]bool),
+		DebitAccounts:     make(map[string]bool),
+	}
+	for _, scope := range strings.Fields(claims.Scope) {
+		p.Scopes[scope] = true
+	}
+	for _, id := range claims.AccountsRead {
+		p.ReadAccounts[id] = true
+	}
+	for _, id := range claims.AccountsDebit {
+		p.DebitAccounts[id] = true
+	}
+	return p, nil
+}
+
+// keyFunc selects the verification key for the token's signing method.
+func (v *JWTVerifier) keyFunc(token *jwt.Token) (any, error) {
+	switch token.Method.Alg() {
+	case jwt.SigningMethodHS256.Alg():
+		if len(v.hmacSecret) == 0 {
+			return nil, errors.New("HS256 tokens are not accepted")
+		}
+		return v.hmacSecret, nil
+	case jwt.SigningMethodRS256.Alg():
+		kid, _ := token.Header["kid"].(string)
+		key, ok := v.rsaKeys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown key id %q", kid)
+		}
+		return key, nil
+	}
+	return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+}
+
+// loadJWKS reads RSA public keys, indexed by key ID, from a JWKS file.
+func loadJWKS(path string) (map[string]*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("jwt: read jwks: %w", err)
+	}
+
+	var doc struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("jwt: parse jwks: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(doc.Keys))
+	for _, k := range doc.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("jwt: key %q: invalid modulus: %w", k.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("jwt: key %q: invalid exponent: %w", k.Kid, err)
+		}
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	return keys, nil
+}
@@ -0,0 +1,248 @@
+package server
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/config"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage/mocks"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/mux"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const testHMACSecret = "test-hmac-secret"
+
+// writeTestJWKS writes a JWKS file containing the public half of key and returns its path.
+func writeTestJWKS(t *testing.T, kid string, key *rsa.PrivateKey) string {
+	t.Helper()
+	doc := map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": kid,
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}},
+	}
+	data, err := json.Marshal(doc)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+// testClaims builds claims for a UI user valid for the next hour.
+func testClaims(scope string, read, debit []string) jwtClaims {
+	return jwtClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "employee-7",
+			Audience:  jwt.ClaimStrings{"internal-transfers-system"},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		Scope:         scope,
+		AccountsRead:  read,
+		AccountsDebit: debit,
+	}
+}
+
+// TestJWTVerifier validates HS256 and RS256 token verification, including rejected tokens.
+func TestJWTVerifier(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	v, err := NewJWTVerifier(&config.JWTConfig{
+		HMACSecret: testHMACSecret,
+		JWKSFile:   writeTestJWKS(t, "key-1", rsaKey),
+		Audience:   "internal-transfers-system",
+	})
+	require.NoError(t, err)
+
+	claims := testClaims("accounts:read", []string{"acc-1"}, nil)
+	expired := claims
+	expired.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Hour))
+	wrongAudience := claims
+	wrongAudience.Audience = jwt.ClaimStrings{"other-service"}
+
+	sign := func(method jwt.SigningMethod, kid string, key any, c jwtClaims) string {
+		token := jwt.NewWithClaims(method, c)
+		if kid != "" {
+			token.Header["kid"] = kid
+		}
+		signed, err := token.SignedString(key)
+		require.NoError(t, err)
+		return signed
+	}
+
+	tests := []struct {
+		name      string
+		token     string
+		expectErr bool
+	}{
+		{
+			name:  "hs256",
+			token: sign(jwt.SigningMethodHS256, "", []byte(testHMACSecret), claims),
+		},
+		{
+			name:  "rs256",
+			token: sign(jwt.SigningMethodRS256, "key-1", rsaKey, claims),
+		},
+		{
+			name:      "hs256 wrong secret",
+			token:     sign(jwt.SigningMethodHS256, "", []byte("wrong"), claims),
+			expectErr: true,
+		},
+		{
+			name:      "rs256 unknown kid",
+			token:     sign(jwt.SigningMethodRS256, "key-2", rsaKey, claims),
+			expectErr: true,
+		},
+		{
+			name:      "rs256 wrong key",
+			token:     sign(jwt.SigningMethodRS256, "key-1", otherKey, claims),
+			expectErr: true,
+		},
+		{
+			name:      "expired",
+			token:     sign(jwt.SigningMethodHS256, "", []byte(testHMACSecret), expired),
+			expectErr: true,
+		},
+		{
+			name:      "wrong audience",
+			token:     sign(jwt.SigningMethodHS256, "", []byte(testHMACSecret), wrongAudience),
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := v.Verify(tc.token)
+			if tc.expectErr {
+				assert.ErrorIs(t, err, ErrInvalidCredentials)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "employee-7", p.ClientID)
+			assert.True(t, p.hasScopes(ScopeAccountsRead))
+			assert.True(t, p.canRead("acc-1"))
+			assert.False(t, p.canRead("acc-2"))
+			assert.False(t, p.canDebit("acc-1"))
+		})
+	}
+}
+
+// TestJWTAccountAuthorization tests that handlers refuse accounts outside the caller's claims with 403.
+func TestJWTAccountAuthorization(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		claims         jwtClaims
+		mockSetup      func(m *mocks.MockStorage)
+		expectedStatus int
+	}{
+		{
+			name:   "read allowed account",
+			method: http.MethodGet,
+			path:   "/accounts/acc-1",
+			claims: testClaims("accounts:read", []string{"acc-1"}, nil),
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().GetAccountDetails(gomock.Any(), "acc-1").Return(&storage.Account{ID: "acc-1", Balance: decimal.RequireFromString("10")}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "read other account",
+			method:         http.MethodGet,
+			path:           "/accounts/acc-2",
+			claims:         testClaims("accounts:read", []string{"acc-1"}, nil),
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "read wildcard",
+			method: http.MethodGet,
+			path:   "/accounts/acc-9",
+			claims: testClaims("accounts:read", []string{"*"}, nil),
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().GetAccountDetails(gomock.Any(), "acc-9").Return(&storage.Account{ID: "acc-9", Balance: decimal.RequireFromString("10")}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "debit allowed account",
+			method: http.MethodPost,
+			path:   "/transactions",
+			body:   `{"source_account_id":"acc-1","destination_account_id":"acc-2","amount":"5"}`,
+			claims: testClaims("transactions:write", nil, []string{"acc-1"}),
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().ProcessTransaction(gomock.Any(), "acc-1", "acc-2", decimal.RequireFromString("5"), storage.AnyVersion).Return(nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "debit readable but not debitable account",
+			method:         http.MethodPost,
+			path:           "/transactions",
+			body:           `{"source_account_id":"acc-1","destination_account_id":"acc-2","amount":"5"}`,
+			claims:         testClaims("transactions:write", []string{"acc-1"}, nil),
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "missing scope",
+			method:         http.MethodPost,
+			path:           "/transactions",
+			body:           `{"source_account_id":"acc-1","destination_account_id":"acc-2","amount":"5"}`,
+			claims:         testClaims("accounts:read", nil, []string{"acc-1"}),
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockStorage := mocks.NewMockStorage(mockCtrl)
+			if tc.mockSetup != nil {
+				tc.mockSetup(mockStorage)
+			}
+
+			jwtCfg := &config.JWTConfig{Enabled: true, HMACSecret: testHMACSecret}
+			v, err := NewJWTVerifier(jwtCfg)
+			require.NoError(t, err)
+
+			s := Server{
+				cfg:   &config.Config{Auth: &config.AuthConfig{Enabled: true, JWT: jwtCfg}},
+				store: mockStorage,
+				jwt:   v,
+			}
+			r := mux.NewRouter()
+			s.BindRoutes(r)
+
+			token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, tc.claims).SignedString([]byte(testHMACSecret))
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			req.Header.Set("Authorization", "Bearer "+token)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+		})
+	}
+}
@@ -3,26 +3,75 @@ package server
 import (
 	"context"
 	"net/http"
-	"strconv"
-	"time"
+	"regexp"
+	"strings"
 
 	"github.com/cursed-ninja/internal-transfers-system/internal/utils"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
+const (
+	// requestIDHeader carries the caller-supplied or generated request ID.
+	requestIDHeader = "X-Request-ID"
+	// traceparentHeader carries the W3C trace context.
+	traceparentHeader = "traceparent"
+	// maxRequestIDLength bounds inbound request IDs so they stay safe to log and persist.
+	maxRequestIDLength = 128
+)
+
+var (
+	// requestIDPattern restricts inbound request IDs to printable, header-safe characters.
+	requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:\-]+$`)
+	// traceparentPattern matches a W3C traceparent: version-traceid-parentid-flags.
+	traceparentPattern = regexp.MustCompile(`^[0-9a-f]{2}-([0-9a-f]{32})-([0-9a-f]{16})-[0-9a-f]{2}$`)
+)
+
 // loggingMiddleware attaches a request ID and logger to each incoming HTTP request's context.
+// The inbound X-Request-ID is honored when valid, otherwise a new ID is generated.
+// The ID is echoed back in the response header.
 func (s *Server) loggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		reqID := newRequestID()
-		logger := utils.GetLogger(s.cfg.Env)
-		ctx := context.WithValue(r.Context(), utils.LoggerContextKey, logger)
-		ctx, _ = utils.LoggerWithKey(ctx, zap.String("request_id", reqID))
+		reqID := requestIDFromHeader(r.Header.Get(requestIDHeader))
+		if reqID == "" {
+			reqID = newRequestID()
+		}
+		w.Header().Set(requestIDHeader, reqID)
 
+		ctx := context.WithValue(r.Context(), utils.LoggerContextKey, s.rootLogger())
+		ctx = utils.WithRequestID(ctx, reqID)
+		ctx, _ = utils.LoggerWithKey(ctx, zap.String("request_id", reqID))
+		if traceID, spanID, ok := parseTraceparent(r.Header.Get(traceparentHeader)); ok {
+			ctx, _ = utils.LoggerWithKey(ctx, zap.String("trace_id", traceID))
+			ctx, _ = utils.LoggerWithKey(ctx, zap.String("parent_span_id", spanID))
+		}
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
-// newRequestID generates a new unique request ID based on the current timestamp.
+// requestIDFromHeader returns the trimmed inbound request ID, or an empty string if it is missing or invalid.
+func requestIDFromHeader(value string) string {
+	value = strings.TrimSpace(value)
+	if value == "" || len(value) > maxRequestIDLength || !requestIDPattern.MatchString(value) {
+		return ""
+	}
+	return value
+}
+
+// parseTraceparent extracts the trace ID and parent span ID from a W3C traceparent header.
+func parseTraceparent(value string) (traceID, spanID string, ok bool) {
+	m := traceparentPattern.FindStringSubmatch(strings.TrimSpace(value))
+	if m == nil {
+		return "", "", false
+	}
+	return m[1], m[2], true
+}
+
+// newRequestID generates a new time-ordered, collision-free request ID (UUIDv7).
 func newRequestID() string {
-	return strconv.FormatInt(time.Now().UnixNano(), 10)
+	id, err := uuid.NewV7()
+	if err != nil {
+		return uuid.NewString()
+	}
+	return id.String()
 }
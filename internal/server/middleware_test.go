@@ -0,0 +1,84 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/config"
+	"github.com/cursed-ninja/internal-transfers-system/internal/utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLoggingMiddlewareRequestID tests that inbound request IDs are honored and missing or invalid ones are generated.
+func TestLoggingMiddlewareRequestID(t *testing.T) {
+	tests := []struct {
+		name       string
+		inbound    string
+		expectedID string
+	}{
+		{
+			name:       "honors inbound id",
+			inbound:    "support-ticket-42",
+			expectedID: "support-ticket-42",
+		},
+		{
+			name:    "generates when missing",
+			inbound: "",
+		},
+		{
+			name:    "generates when invalid",
+			inbound: "bad id\r\nX-Injected: 1",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := Server{cfg: &config.Config{}}
+
+			var ctxID string
+			handler := s.loggingMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ctxID = utils.RequestID(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/health", nil)
+			if tc.inbound != "" {
+				req.Header.Set(requestIDHeader, tc.inbound)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			respID := w.Header().Get(requestIDHeader)
+			assert.Equal(t, ctxID, respID)
+			if tc.expectedID != "" {
+				assert.Equal(t, tc.expectedID, respID)
+				return
+			}
+			id, err := uuid.Parse(respID)
+			assert.NoError(t, err)
+			assert.Equal(t, uuid.Version(7), id.Version())
+		})
+	}
+}
+
+// TestNewRequestIDUnique tests that generated request IDs do not collide.
+func TestNewRequestIDUnique(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 10000; i++ {
+		id := newRequestID()
+		assert.False(t, seen[id], "duplicate request id %s", id)
+		seen[id] = true
+	}
+}
+
+// TestParseTraceparent tests extraction of trace and span IDs from W3C traceparent headers.
+func TestParseTraceparent(t *testing.T) {
+	traceID, spanID, ok := parseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	assert.True(t, ok)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", traceID)
+	assert.Equal(t, "00f067aa0ba902b7", spanID)
+
+	_, _, ok = parseTraceparent("garbage")
+	assert.False(t, ok)
+}
@@ -0,0 +1,34 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// clientCertPrincipal resolves the caller from a verified TLS client certificate.
+// The certificate's common name or full subject is looked up in the configured identities;
+// unmapped certificates are rejected.
+func (s *Server) clientCertPrincipal(r *http.Request) (*principal, bool, error) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil, false, nil
+	}
+
+	subject := r.TLS.VerifiedChains[0][0].Subject
+	if s.cfg.TLS == nil {
+		return nil, true, fmt.Errorf("%w: client certificates are not mapped", ErrInvalidCredentials)
+	}
+	for _, id := range s.cfg.TLS.ClientIdentities {
+		if id.Subject != subject.CommonName && id.Subject != subject.String() {
+			continue
+		}
+		p := &principal{ClientID: id.ClientID, Scopes: make(map[string]bool, len(id.Scopes))}
+		if p.ClientID == "" {
+			p.ClientID = subject.CommonName
+		}
+		for _, scope := range id.Scopes {
+			p.Scopes[scope] = true
+		}
+		return p, true, nil
+	}
+	return nil, true, fmt.Errorf("%w: unknown client certificate subject %q", ErrInvalidCredentials, subject.String())
+}
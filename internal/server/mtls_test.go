@@ -0,0 +1,93 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/config"
+	"github.com/cursed-ninja/internal-transfers-system/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestClientCertAuthentication tests that verified client certificate subjects map to caller identities.
+// Scenarios include a common-name match, a full-subject match, an unmapped subject and an unverified certificate.
+func TestClientCertAuthentication(t *testing.T) {
+	tests := []struct {
+		name           string
+		subject        pkix.Name
+		verified       bool
+		expectedStatus int
+		expectedClient string
+	}{
+		{
+			name:           "mapped common name",
+			subject:        pkix.Name{CommonName: "payroll.internal"},
+			verified:       true,
+			expectedStatus: http.StatusOK,
+			expectedClient: "payroll",
+		},
+		{
+			name:           "mapped full subject",
+			subject:        pkix.Name{CommonName: "treasury", Organization: []string{"Acme"}},
+			verified:       true,
+			expectedStatus: http.StatusOK,
+			expectedClient: "treasury",
+		},
+		{
+			name:           "unmapped subject",
+			subject:        pkix.Name{CommonName: "stranger"},
+			verified:       true,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "unverified certificate",
+			subject:        pkix.Name{CommonName: "payroll.internal"},
+			verified:       false,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "mapped without required scope",
+			subject:        pkix.Name{CommonName: "reporting"},
+			verified:       true,
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := Server{
+				cfg: &config.Config{
+					Auth: &config.AuthConfig{Enabled: true},
+					TLS: &config.TLSConfig{
+						ClientIdentities: []config.ClientIdentity{
+							{Subject: "payroll.internal", ClientID: "payroll", Scopes: []string{ScopeTransactionsWrite}},
+							{Subject: "CN=treasury,O=Acme", Scopes: []string{ScopeTransactionsWrite}},
+							{Subject: "reporting", ClientID: "reporting", Scopes: []string{ScopeAccountsRead}},
+						},
+					},
+				},
+			}
+
+			var clientID string
+			handler := s.requireScopes(ScopeTransactionsWrite)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				clientID = utils.ClientID(r.Context())
+			}))
+
+			cert := &x509.Certificate{Subject: tc.subject}
+			req := httptest.NewRequest(http.MethodPost, "/transactions", nil)
+			req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+			if tc.verified {
+				req.TLS.VerifiedChains = [][]*x509.Certificate{{cert}}
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Equal(t, tc.expectedClient, clientID)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/config"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openAPIDocument is the subset of the OpenAPI document the tests inspect.
+type openAPIDocument struct {
+	OpenAPI    string                                `json:"openapi"`
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Schemas   map[string]json.RawMessage `json:"schemas"`
+		Responses map[string]json.RawMessage `json:"responses"`
+	} `json:"components"`
+}
+
+// loadOpenAPIDocument parses the embedded OpenAPI document.
+func loadOpenAPIDocument(t *testing.T) openAPIDocument {
+	t.Helper()
+	var doc openAPIDocument
+	require.NoError(t, json.Unmarshal(openAPISpec, &doc))
+	return doc
+}
+
+// TestOpenAPICoversRoutes verifies every route bound in BindRoutes is documented and that the document
+// only describes routes that exist.
+func TestOpenAPICoversRoutes(t *testing.T) {
+	doc := loadOpenAPIDocument(t)
+	assert.True(t, strings.HasPrefix(doc.OpenAPI, "3."), "unexpected openapi version %q", doc.OpenAPI)
+
+	r := mux.NewRouter()
+	(&Server{cfg: &config.Config{}}).BindRoutes(r)
+
+	bound := make(map[string]bool)
+	err := r.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		tpl, err := route.GetPathTemplate()
+		if err != nil {
+			return err
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			return err
+		}
+		for _, method := range methods {
+			method = strings.ToLower(method)
+			bound[method+" "+tpl] = true
+			_, ok := doc.Paths[tpl][method]
+			assert.True(t, ok, "route %s %s is missing from openapi.json", strings.ToUpper(method), tpl)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	for path, item := range doc.Paths {
+		for method := range item {
+			if method == "parameters" {
+				continue
+			}
+			assert.True(t, bound[method+" "+path], "openapi.json documents %s %s, which is not bound", strings.ToUpper(method), path)
+		}
+	}
+}
+
+// TestOpenAPIReferencesResolve verifies every $ref in the document points at a defined component.
+func TestOpenAPIReferencesResolve(t *testing.T) {
+	doc := loadOpenAPIDocument(t)
+
+	var raw any
+	require.NoError(t, json.Unmarshal(openAPISpec, &raw))
+
+	var walk func(v any)
+	walk = func(v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			if ref, ok := v["$ref"].(string); ok {
+				name, found := strings.CutPrefix(ref, "#/components/schemas/")
+				if found {
+					assert.Contains(t, doc.Components.Schemas, name, "unresolved $ref %s", ref)
+				} else if name, found = strings.CutPrefix(ref, "#/components/responses/"); found {
+					assert.Contains(t, doc.Components.Responses, name, "unresolved $ref %s", ref)
+				} else {
+					t.Errorf("unsupported $ref %s", ref)
+				}
+			}
+			for _, child := range v {
+				walk(child)
+			}
+		case []any:
+			for _, child := range v {
+				walk(child)
+			}
+		}
+	}
+	walk(raw)
+}
+
+// TestOpenAPIHandler verifies the document is served as JSON at /openapi.json without authentication.
+func TestOpenAPIHandler(t *testing.T) {
+	s := &Server{cfg: &config.Config{Auth: &config.AuthConfig{Enabled: true}}}
+	r := mux.NewRouter()
+	s.BindRoutes(r)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, string(openAPISpec), w.Body.String())
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/payments"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+	"github.com/cursed-ninja/internal-transfers-system/internal/transfers"
+	"github.com/cursed-ninja/internal-transfers-system/internal/utils"
+	"go.uber.org/zap"
+)
+
+// maxImportTransactionsBodyBytes bounds POST /transactions/import messages, leaving room for
+// payments.MaxTransfers transfers with remittance information. Request signatures on the route are
+// checked against bodies of the same size.
+const maxImportTransactionsBodyBytes = 8 << 20
+
+// ImportTransactions handles POST /transactions/import, making the credit transfers of an uploaded ISO
+// 20022 pain.001 message and answering with a pain.002 status report of every transfer. Messages that
+// cannot be read return 400, messages over maxImportTransactionsBodyBytes return 413, and a message ID
+// the caller has already imported returns 409.
+func (s *Server) ImportTransactions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := utils.ContextLogger(ctx)
+
+	logger.Info("received ImportTransactions request")
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxImportTransactionsBodyBytes))
+	if err != nil {
+		logger.Error("failed to read request body", zap.Error(err))
+		if maxBytesErr := (*http.MaxBytesError)(nil); errors.As(err, &maxBytesErr) {
+			http.Error(w, ErrPain001TooLarge.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	msg, err := payments.ParsePain001(bytes.NewReader(body))
+	if err != nil {
+		logger.Error("failed to parse pain.001 message", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx, logger = utils.LoggerWithKey(ctx, zap.String("message_id", msg.MessageID))
+
+	var canDebit func(accountID string) bool
+	if p := principalFromContext(ctx); p != nil {
+		canDebit = p.canDebit
+	}
+
+	report, err := transfers.ExecutePain001(ctx, s.store, msg, canDebit, time.Now())
+	if err != nil {
+		logger.Error("failed to import transactions", zap.Error(err))
+		errorMsg := err.Error()
+		statusCode := http.StatusInternalServerError
+		if errorMsg == storage.ErrBatchExistsMsg {
+			statusCode = http.StatusConflict
+		}
+		http.Error(w, errorMsg, statusCode)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(http.StatusOK)
+	if err := payments.WritePain002(w, report); err != nil {
+		logger.Error("failed to write status report", zap.Error(err))
+		return
+	}
+
+	logger.Info("transactions imported", zap.String("status", report.Status()))
+}
@@ -0,0 +1,313 @@
+package server
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/config"
+	"github.com/cursed-ninja/internal-transfers-system/internal/payments"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage/mocks"
+	"github.com/gorilla/mux"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// pain001Message returns a pain.001 message paying 100 EUR and 20.5 EUR from payroll, then 7 EUR from
+// expenses, with the payroll block dated payrollDate.
+func pain001Message(payrollDate string) string {
+	transfer := func(endToEndID, amount, creditor string) string {
+		return fmt.Sprintf(`<CdtTrfTxInf><PmtId><EndToEndId>%s</EndToEndId></PmtId><Amt><InstdAmt Ccy="EUR">%s</InstdAmt></Amt>`+
+			`<CdtrAcct><Id><Othr><Id>%s</Id></Othr></Id></CdtrAcct></CdtTrfTxInf>`, endToEndID, amount, creditor)
+	}
+	block := func(id, date, debtor string, transfers ...string) string {
+		return fmt.Sprintf(`<PmtInf><PmtInfId>%s</PmtInfId><PmtMtd>TRF</PmtMtd><ReqdExctnDt>%s</ReqdExctnDt>`+
+			`<DbtrAcct><Id><Othr><Id>%s</Id></Othr></Id></DbtrAcct>%s</PmtInf>`, id, date, debtor, strings.Join(transfers, ""))
+	}
+	return `<?xml version="1.0" encoding="UTF-8"?><Document xmlns="urn:iso:std:iso:20022:tech:xsd:pain.001.001.03"><CstmrCdtTrfInitn>` +
+		`<GrpHdr><MsgId>PAYROLL-1</MsgId><NbOfTxs>3</NbOfTxs><CtrlSum>127.5</CtrlSum></GrpHdr>` +
+		block("SALARIES", payrollDate, "payroll", transfer("E2E-1", "100", "emp-1"), transfer("E2E-2", "20.5", "emp-2")) +
+		block("EXPENSES", "2025-06-30", "expenses", transfer("E2E-3", "7", "emp-1")) +
+		`</CstmrCdtTrfInitn></Document>`
+}
+
+// TestImportTransactions tests the ImportTransactions endpoint: settled and refused transfers, refusals
+// before the ledger is reached, malformed messages, duplicate messages and storage failures.
+func TestImportTransactions(t *testing.T) {
+	type transferStatus struct {
+		EndToEndID  string `xml:"OrgnlEndToEndId"`
+		Status      string `xml:"TxSts"`
+		Reason      string `xml:"StsRsnInf>Rsn>Cd"`
+		ServicerRef string `xml:"AcctSvcrRef"`
+	}
+
+	payrollTransfers := []storage.Transfer{
+		{SourceAccountID: "payroll", DestinationAccountID: "emp-1", Amount: decimal.NewFromInt(100), Currency: "EUR"},
+		{SourceAccountID: "payroll", DestinationAccountID: "emp-2", Amount: decimal.RequireFromString("20.5"), Currency: "EUR"},
+	}
+	expensesTransfer := storage.Transfer{SourceAccountID: "expenses", DestinationAccountID: "emp-1", Amount: decimal.NewFromInt(7), Currency: "EUR"}
+
+	tests := []struct {
+		name              string
+		body              string
+		principal         *principal
+		mockSetup         func(m *mocks.MockStorage)
+		expectedStatus    int
+		expectedBody      string
+		expectedGroup     string
+		expectedTransfers []transferStatus
+	}{
+		{
+			name: "partially settled",
+			body: pain001Message("2025-06-30"),
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().ProcessTransferBatch(gomock.Any(), "PAYROLL-1", append(payrollTransfers, expensesTransfer)).Return([]storage.TransferResult{
+					{TransactionID: 11},
+					{Err: errors.New(storage.ErrDestinationAccountMsg)},
+					{Err: errors.New(storage.ErrInsufficientFundsMsg)},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedGroup:  payments.StatusPartial,
+			expectedTransfers: []transferStatus{
+				{EndToEndID: "E2E-1", Status: payments.StatusSettled, ServicerRef: "11"},
+				{EndToEndID: "E2E-2", Status: payments.StatusRejected, Reason: payments.ReasonInvalidCreditorAccount},
+				{EndToEndID: "E2E-3", Status: payments.StatusRejected, Reason: payments.ReasonInsufficientFunds},
+			},
+		},
+		{
+			name: "settled",
+			body: pain001Message("2025-06-30"),
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().ProcessTransferBatch(gomock.Any(), "PAYROLL-1", gomock.Len(3)).
+					Return([]storage.TransferResult{{TransactionID: 11}, {TransactionID: 12}, {TransactionID: 13}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedGroup:  payments.StatusSettled,
+			expectedTransfers: []transferStatus{
+				{EndToEndID: "E2E-1", Status: payments.StatusSettled, ServicerRef: "11"},
+				{EndToEndID: "E2E-2", Status: payments.StatusSettled, ServicerRef: "12"},
+				{EndToEndID: "E2E-3", Status: payments.StatusSettled, ServicerRef: "13"},
+			},
+		},
+		{
+			name: "payment dated in the future",
+			body: pain001Message("2999-01-01"),
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().ProcessTransferBatch(gomock.Any(), "PAYROLL-1", []storage.Transfer{expensesTransfer}).
+					Return([]storage.TransferResult{{TransactionID: 13}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedGroup:  payments.StatusPartial,
+			expectedTransfers: []transferStatus{
+				{EndToEndID: "E2E-1", Status: payments.StatusRejected, Reason: payments.ReasonInvalidDate},
+				{EndToEndID: "E2E-2", Status: payments.StatusRejected, Reason: payments.ReasonInvalidDate},
+				{EndToEndID: "E2E-3", Status: payments.StatusSettled, ServicerRef: "13"},
+			},
+		},
+		{
+			name:      "caller restricted to other debtor accounts",
+			body:      pain001Message("2025-06-30"),
+			principal: &principal{ClientID: "payroll-app", AccountRestricted: true, DebitAccounts: map[string]bool{"payroll": true}},
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().ProcessTransferBatch(gomock.Any(), "PAYROLL-1", payrollTransfers).
+					Return([]storage.TransferResult{{TransactionID: 11}, {TransactionID: 12}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedGroup:  payments.StatusPartial,
+			expectedTransfers: []transferStatus{
+				{EndToEndID: "E2E-1", Status: payments.StatusSettled, ServicerRef: "11"},
+				{EndToEndID: "E2E-2", Status: payments.StatusSettled, ServicerRef: "12"},
+				{EndToEndID: "E2E-3", Status: payments.StatusRejected, Reason: payments.ReasonTransactionForbidden},
+			},
+		},
+		{
+			name:           "every transfer refused before the ledger",
+			body:           strings.ReplaceAll(pain001Message("2999-01-01"), "<Id>expenses</Id>", "<Id>emp-1</Id>"),
+			expectedStatus: http.StatusOK,
+			expectedGroup:  payments.StatusRejected,
+			expectedTransfers: []transferStatus{
+				{EndToEndID: "E2E-1", Status: payments.StatusRejected, Reason: payments.ReasonInvalidDate},
+				{EndToEndID: "E2E-2", Status: payments.StatusRejected, Reason: payments.ReasonInvalidDate},
+				{EndToEndID: "E2E-3", Status: payments.StatusRejected, Reason: payments.ReasonIncorrectAccount},
+			},
+		},
+		{
+			name:           "zero amount",
+			body:           strings.Replace(strings.Replace(pain001Message("2999-01-01"), ">7<", ">0<", 1), "127.5", "120.5", 1),
+			expectedStatus: http.StatusOK,
+			expectedGroup:  payments.StatusRejected,
+			expectedTransfers: []transferStatus{
+				{EndToEndID: "E2E-1", Status: payments.StatusRejected, Reason: payments.ReasonInvalidDate},
+				{EndToEndID: "E2E-2", Status: payments.StatusRejected, Reason: payments.ReasonInvalidDate},
+				{EndToEndID: "E2E-3", Status: payments.StatusRejected, Reason: payments.ReasonInvalidAmount},
+			},
+		},
+		{
+			name:           "malformed message",
+			body:           "<Document>",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   payments.ErrMalformedMessage.Error() + ": XML syntax error on line 1: unexpected EOF\n",
+		},
+		{
+			name:           "control sum mismatch",
+			body:           strings.Replace(pain001Message("2025-06-30"), "127.5", "127", 1),
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "GrpHdr: " + payments.ErrControlSum.Error() + "\n",
+		},
+		{
+			name: "message already imported",
+			body: pain001Message("2025-06-30"),
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().ProcessTransferBatch(gomock.Any(), "PAYROLL-1", gomock.Any()).Return(nil, errors.New(storage.ErrBatchExistsMsg))
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   storage.ErrBatchExistsMsg + "\n",
+		},
+		{
+			name: "internal error",
+			body: pain001Message("2025-06-30"),
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().ProcessTransferBatch(gomock.Any(), "PAYROLL-1", gomock.Any()).Return(nil, errors.New(storage.ErrProcessBatchMsg))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   storage.ErrProcessBatchMsg + "\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockStorage := mocks.NewMockStorage(mockCtrl)
+			if tc.mockSetup != nil {
+				tc.mockSetup(mockStorage)
+			}
+
+			s := Server{cfg: &config.Config{}, store: mockStorage}
+			r := mux.NewRouter()
+			s.BindRoutes(r)
+
+			req := httptest.NewRequest(http.MethodPost, "/transactions/import", strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/xml")
+			if tc.principal != nil {
+				req = req.WithContext(withPrincipal(req.Context(), tc.principal))
+			}
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedBody != "" {
+				assert.Equal(t, tc.expectedBody, w.Body.String())
+			}
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			assert.Equal(t, "application/xml", w.Header().Get("Content-Type"))
+			var report struct {
+				MessageID string `xml:"CstmrPmtStsRpt>OrgnlGrpInfAndSts>OrgnlMsgId"`
+				Status    string `xml:"CstmrPmtStsRpt>OrgnlGrpInfAndSts>GrpSts"`
+				Payments  []struct {
+					Transfers []transferStatus `xml:"TxInfAndSts"`
+				} `xml:"CstmrPmtStsRpt>OrgnlPmtInfAndSts"`
+			}
+			require.NoError(t, xml.Unmarshal(w.Body.Bytes(), &report))
+			assert.Equal(t, "PAYROLL-1", report.MessageID)
+			assert.Equal(t, tc.expectedGroup, report.Status)
+
+			var transfers []transferStatus
+			for _, p := range report.Payments {
+				transfers = append(transfers, p.Transfers...)
+			}
+			assert.Equal(t, tc.expectedTransfers, transfers)
+		})
+	}
+}
+
+// TestImportTransactionsLarge tests that pain.001 messages are read, and signed ones verified, under
+// the route's own body limit rather than the 1 MiB limit of other routes.
+func TestImportTransactionsLarge(t *testing.T) {
+	// padded returns the sample message with an XML comment making it size bytes long.
+	padded := func(size int) string {
+		msg := pain001Message("2025-06-30")
+		comment := "<!--" + strings.Repeat("x", size-len(msg)-len("<!---->")) + "-->"
+		return strings.Replace(msg, "<Document", comment+"<Document", 1)
+	}
+
+	tests := []struct {
+		name           string
+		body           string
+		signed         bool
+		mockSetup      func(m *mocks.MockStorage)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "signed message over 1 MiB",
+			body:   padded(2 << 20),
+			signed: true,
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().ProcessTransferBatch(gomock.Any(), "PAYROLL-1", gomock.Len(3)).
+					Return([]storage.TransferResult{{TransactionID: 11}, {TransactionID: 12}, {TransactionID: 13}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "message over the limit",
+			body:           padded(maxImportTransactionsBodyBytes + 1),
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedBody:   ErrPain001TooLarge.Error() + "\n",
+		},
+		{
+			name:           "signed message over the limit",
+			body:           padded(maxImportTransactionsBodyBytes + 1),
+			signed:         true,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedBody:   ErrBodyTooLarge.Error() + "\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockStorage := mocks.NewMockStorage(mockCtrl)
+			if tc.mockSetup != nil {
+				tc.mockSetup(mockStorage)
+			}
+
+			s := Server{cfg: &config.Config{}, store: mockStorage}
+			req := httptest.NewRequest(http.MethodPost, "/transactions/import", strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/xml")
+			if tc.signed {
+				v, err := NewRequestVerifier(&config.SigningConfig{
+					Clients: []config.SigningClient{{
+						ClientID: "payroll",
+						Secrets:  []config.SigningSecret{{Secret: "payroll-secret"}},
+					}},
+				})
+				require.NoError(t, err)
+				s.signer = v
+				signRequest(req, "payroll", "payroll-secret", time.Now(), tc.body)
+			}
+			r := mux.NewRouter()
+			s.BindRoutes(r)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedBody != "" {
+				assert.Equal(t, tc.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/config"
+	"github.com/cursed-ninja/internal-transfers-system/internal/ratelimit"
+	"github.com/cursed-ninja/internal-transfers-system/internal/utils"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// rateLimitCheck is one bucket consulted for a request.
+type rateLimitCheck struct {
+	key   string
+	limit ratelimit.Limit
+}
+
+// rateLimit enforces the configured token-bucket limits for route, keyed by API client,
+// client IP and source account. Every response carries RateLimit-* headers describing the
+// most restrictive bucket; rejected requests get 429 with Retry-After.
+// Limiter errors are logged and the request is allowed.
+func (s *Server) rateLimit(route string) middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if s.limiter == nil || s.cfg.RateLimit == nil || !s.cfg.RateLimit.Enabled {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := r.Context()
+			logger := utils.ContextLogger(ctx)
+
+			checks := s.rateLimitChecks(ctx, route, clientIP(r), func() string { return rateLimitAccount(r) })
+			tightest, denied, checked := s.checkRateLimits(ctx, checks)
+			if checked {
+				w.Header().Set("RateLimit-Limit", strconv.Itoa(tightest.Limit))
+				w.Header().Set("RateLimit-Remaining", strconv.Itoa(tightest.Remaining))
+				w.Header().Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(tightest.ResetAfter)))
+			}
+			if denied {
+				logger.Warn("rate limit exceeded", zap.String("route", route))
+				w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(tightest.RetryAfter)))
+				http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// checkRateLimits consumes a token from each bucket and returns the most restrictive result, whether
+// any bucket denied the request and whether any bucket was checked at all.
+func (s *Server) checkRateLimits(ctx context.Context, checks []rateLimitCheck) (tightest ratelimit.Result, denied, checked bool) {
+	logger := utils.ContextLogger(ctx)
+	for _, check := range checks {
+		res, err := s.limiter.Allow(ctx, check.key, check.limit)
+		if err != nil {
+			logger.Error("rate limiter failed", zap.String("rate_limit_key", check.key), zap.Error(err))
+			continue
+		}
+		switch {
+		case !checked:
+			tightest = res
+		case !res.Allowed && (!denied || res.RetryAfter > tightest.RetryAfter):
+			tightest = res
+		case !denied && res.Allowed && res.Remaining < tightest.Remaining:
+			tightest = res
+		}
+		checked = true
+		denied = denied || !res.Allowed
+	}
+	return tightest, denied, checked
+}
+
+// rateLimitChecks returns the buckets that apply to a request from ip under the route's rule.
+// account is only called when the rule limits requests per account.
+func (s *Server) rateLimitChecks(ctx context.Context, route, ip string, account func() string) []rateLimitCheck {
+	rule := s.rateLimitRule(route)
+	checks := make([]rateLimitCheck, 0, 3)
+
+	if limit := toLimit(rule.PerClient); limit.Enabled() {
+		if p := principalFromContext(ctx); p != nil {
+			checks = append(checks, rateLimitCheck{key: "client:" + route + ":" + p.ClientID, limit: limit})
+		}
+	}
+	if limit := toLimit(rule.PerIP); limit.Enabled() {
+		checks = append(checks, rateLimitCheck{key: "ip:" + route + ":" + ip, limit: limit})
+	}
+	if limit := toLimit(rule.PerAccount); limit.Enabled() {
+		if accountID := account(); accountID != "" {
+			checks = append(checks, rateLimitCheck{key: "account:" + route + ":" + accountID, limit: limit})
+		}
+	}
+	return checks
+}
+
+// rateLimitRule returns the rule configured for route, or the default rule.
+func (s *Server) rateLimitRule(route string) config.RateLimitRule {
+	for _, rule := range s.cfg.RateLimit.Routes {
+		if rule.Route == route {
+			return rule
+		}
+	}
+	return s.cfg.RateLimit.Default
+}
+
+// rateLimitAccount returns the account a request acts on: the accountID path variable,
+// or the source_account_id of a JSON body. Other bodies, such as CSV and XML uploads, are not read.
+func rateLimitAccount(r *http.Request) string {
+	if accountID := strings.TrimSpace(mux.Vars(r)["accountID"]); accountID != "" {
+		return accountID
+	}
+	if r.Method != http.MethodPost {
+		return ""
+	}
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != jsonMediaType {
+		return ""
+	}
+	body, err := bufferBody(r, maxBufferedBodyBytes)
+	if err != nil {
+		return ""
+	}
+	var req struct {
+		SourceAccID string `json:"source_account_id"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		return ""
+	}
+	return strings.TrimSpace(req.SourceAccID)
+}
+
+// clientIP returns the host part of the connection's remote address.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// toLimit converts a configured rate limit to a ratelimit.Limit.
+func toLimit(cfg config.RateLimit) ratelimit.Limit {
+	return ratelimit.Limit{Rate: cfg.Rate, Burst: cfg.Burst}
+}
+
+// ceilSeconds rounds a duration up to whole seconds.
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
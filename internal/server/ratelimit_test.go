@@ -0,0 +1,145 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/config"
+	"github.com/cursed-ninja/internal-transfers-system/internal/ratelimit"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRateLimit tests per-route token-bucket limiting keyed by client, IP and source account.
+func TestRateLimit(t *testing.T) {
+	cfg := &config.Config{
+		RateLimit: &config.RateLimitConfig{
+			Enabled: true,
+			Default: config.RateLimitRule{
+				PerIP: config.RateLimit{Rate: 100, Burst: 100},
+			},
+			Routes: []config.RateLimitRule{{
+				Route:      "POST /transactions",
+				PerClient:  config.RateLimit{Rate: 1, Burst: 3},
+				PerAccount: config.RateLimit{Rate: 1, Burst: 1},
+			}},
+		},
+	}
+
+	type request struct {
+		client         string
+		contentType    string
+		body           string
+		expectedStatus int
+		expectedRemain string
+	}
+
+	tests := []struct {
+		name     string
+		requests []request
+	}{
+		{
+			name: "source account limited",
+			requests: []request{
+				{client: "payroll", body: `{"source_account_id":"acc-1"}`, expectedStatus: http.StatusOK, expectedRemain: "0"},
+				{client: "payroll", body: `{"source_account_id":"acc-1"}`, expectedStatus: http.StatusTooManyRequests, expectedRemain: "0"},
+				{client: "payroll", body: `{"source_account_id":"acc-2"}`, expectedStatus: http.StatusOK, expectedRemain: "0"},
+			},
+		},
+		{
+			name: "client limited across accounts",
+			requests: []request{
+				{client: "payroll", body: `{"source_account_id":"acc-1"}`, expectedStatus: http.StatusOK},
+				{client: "payroll", body: `{"source_account_id":"acc-2"}`, expectedStatus: http.StatusOK},
+				{client: "payroll", body: `{"source_account_id":"acc-3"}`, expectedStatus: http.StatusOK},
+				{client: "payroll", body: `{"source_account_id":"acc-4"}`, expectedStatus: http.StatusTooManyRequests},
+				{client: "treasury", body: `{"source_account_id":"acc-5"}`, expectedStatus: http.StatusOK},
+			},
+		},
+		{
+			name: "non-JSON body not read for its source account",
+			requests: []request{
+				{client: "payroll", contentType: "text/csv", body: `{"source_account_id":"acc-1"}`, expectedStatus: http.StatusOK},
+				{client: "payroll", contentType: "text/csv", body: `{"source_account_id":"acc-1"}`, expectedStatus: http.StatusOK},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := Server{cfg: cfg, limiter: ratelimit.NewMemoryStore()}
+
+			var body string
+			handler := s.rateLimit("POST /transactions")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				b, _ := bufferBody(r, maxBufferedBodyBytes)
+				body = string(b)
+			}))
+
+			for i, rq := range tc.requests {
+				body = ""
+				req := httptest.NewRequest(http.MethodPost, "/transactions", strings.NewReader(rq.body))
+				contentType := rq.contentType
+				if contentType == "" {
+					contentType = "application/json; charset=utf-8"
+				}
+				req.Header.Set("Content-Type", contentType)
+				req = req.WithContext(withPrincipal(req.Context(), &principal{ClientID: rq.client}))
+				w := httptest.NewRecorder()
+				handler.ServeHTTP(w, req)
+
+				assert.Equal(t, rq.expectedStatus, w.Code, "request %d", i)
+				assert.NotEmpty(t, w.Header().Get("RateLimit-Limit"))
+				if rq.expectedRemain != "" {
+					assert.Equal(t, rq.expectedRemain, w.Header().Get("RateLimit-Remaining"), "request %d", i)
+				}
+				if rq.expectedStatus == http.StatusTooManyRequests {
+					assert.Equal(t, "1", w.Header().Get("Retry-After"))
+				} else {
+					assert.Equal(t, rq.body, body, "body must still be readable by the handler")
+				}
+			}
+		})
+	}
+}
+
+// TestRateLimitDisabled tests that requests pass through without headers when no limiter is configured.
+func TestRateLimitDisabled(t *testing.T) {
+	s := Server{cfg: &config.Config{}}
+	handler := s.rateLimit("GET /accounts/{accountID}")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := httptest.NewRequest(http.MethodGet, "/accounts/acc-1", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+}
+
+// TestRateLimitOversizedBody tests that a body too large to look for its source account still
+// reaches the rest of the chain whole and is refused with 413.
+func TestRateLimitOversizedBody(t *testing.T) {
+	cfg := &config.Config{
+		RateLimit: &config.RateLimitConfig{
+			Enabled: true,
+			Routes: []config.RateLimitRule{{
+				Route:      "POST /transactions",
+				PerAccount: config.RateLimit{Rate: 1, Burst: 1},
+			}},
+		},
+	}
+	s := Server{cfg: cfg, limiter: ratelimit.NewMemoryStore()}
+	r := mux.NewRouter()
+	s.BindRoutes(r)
+
+	body := `{"source_account_id":"acc-1","destination_account_id":"acc-2","amount":"1` + strings.Repeat("0", maxBufferedBodyBytes) + `"}`
+	req := httptest.NewRequest(http.MethodPost, "/transactions", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(withPrincipal(req.Context(), &principal{ClientID: "payroll"}))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Equal(t, ErrBodyTooLarge.Error()+"\n", w.Body.String())
+}
@@ -7,9 +7,49 @@ import (
 )
 
 // BindRoutes binds the server's HTTP handlers to the router.
+// Probe, metrics and OpenAPI routes are unauthenticated; every other route requires the listed scopes.
+// Account and transaction routes are rate limited under the "METHOD /path" key used in config.
 func (s *Server) BindRoutes(r *mux.Router) {
-	r.Handle("/health", s.loggingMiddleware(http.HandlerFunc(s.HealthHandler))).Methods(http.MethodGet)
-	r.Handle("/accounts", s.loggingMiddleware(http.HandlerFunc(s.CreateAccount))).Methods(http.MethodPost)
-	r.Handle("/accounts/{accountID}", s.loggingMiddleware(http.HandlerFunc(s.GetAccountDetails))).Methods(http.MethodGet)
-	r.Handle("/transactions", s.loggingMiddleware(http.HandlerFunc(s.ProcessTransaction))).Methods(http.MethodPost)
+	r.Handle("/health", s.chain(s.HealthHandler)).Methods(http.MethodGet)
+	r.Handle("/livez", s.chain(s.LivenessHandler)).Methods(http.MethodGet)
+	r.Handle("/readyz", s.chain(s.ReadinessHandler)).Methods(http.MethodGet)
+	r.Handle("/metrics", s.chain(s.MetricsHandler)).Methods(http.MethodGet)
+	r.Handle("/openapi.json", s.chain(s.OpenAPIHandler)).Methods(http.MethodGet)
+
+	r.Handle("/admin/log-level", s.chain(s.LogLevelHandler, s.requireScopes(ScopeAdmin))).Methods(http.MethodGet, http.MethodPut)
+	r.Handle("/admin/api-keys", s.chain(s.CreateAPIKey, s.requireScopes(ScopeAdmin))).Methods(http.MethodPost)
+	r.Handle("/admin/api-keys", s.chain(s.ListAPIKeys, s.requireScopes(ScopeAdmin))).Methods(http.MethodGet)
+	r.Handle("/admin/api-keys/{keyID}", s.chain(s.RevokeAPIKey, s.requireScopes(ScopeAdmin))).Methods(http.MethodDelete)
+	r.Handle("/admin/audit/verify", s.chain(s.VerifyAuditChain, s.requireScopes(ScopeAdmin))).Methods(http.MethodGet)
+
+	r.Handle("/accounts", s.chain(s.CreateAccount, s.requireScopes(ScopeAccountsWrite), s.rateLimit("POST /accounts"))).Methods(http.MethodPost)
+	r.Handle("/accounts", s.chain(s.ListAccounts, s.requireScopes(ScopeAccountsRead), s.rateLimit("GET /accounts"))).Methods(http.MethodGet)
+	r.Handle("/accounts/import", s.chain(s.ImportAccounts, s.requireScopes(ScopeAccountsWrite), s.rateLimit("POST /accounts/import"))).Methods(http.MethodPost)
+	r.Handle("/accounts/export", s.chain(s.ExportAccounts, s.requireScopes(ScopeAccountsRead), s.rateLimit("GET /accounts/export"))).Methods(http.MethodGet)
+	r.Handle("/accounts/{accountID}", s.chain(s.GetAccountDetails, s.requireScopes(ScopeAccountsRead), s.rateLimit("GET /accounts/{accountID}"))).Methods(http.MethodGet)
+	r.Handle("/accounts/{accountID}", s.chain(s.UpdateAccount, s.requireScopes(ScopeAccountsWrite), s.rateLimit("PATCH /accounts/{accountID}"))).Methods(http.MethodPatch)
+	r.Handle("/accounts/{accountID}/balance", s.chain(s.GetAccountBalance, s.requireScopes(ScopeAccountsRead), s.rateLimit("GET /accounts/{accountID}/balance"))).Methods(http.MethodGet)
+	r.Handle("/accounts/{accountID}/close", s.chain(s.CloseAccount, s.requireScopes(ScopeAccountsWrite), s.rateLimit("POST /accounts/{accountID}/close"))).Methods(http.MethodPost)
+	r.Handle("/accounts/{accountID}/events", s.chain(s.StreamAccountEvents, s.requireScopes(ScopeAccountsRead), s.rateLimit("GET /accounts/{accountID}/events"))).Methods(http.MethodGet)
+	r.Handle("/accounts/{accountID}/statement", s.chain(s.GetAccountStatement, s.requireScopes(ScopeAccountsRead), s.rateLimit("GET /accounts/{accountID}/statement"))).Methods(http.MethodGet)
+	r.Handle("/webhooks", s.chain(s.CreateWebhook, s.requireScopes(ScopeWebhooksManage))).Methods(http.MethodPost)
+	r.Handle("/webhooks", s.chain(s.ListWebhooks, s.requireScopes(ScopeWebhooksManage))).Methods(http.MethodGet)
+	r.Handle("/webhooks/{subscriptionID}", s.chain(s.GetWebhook, s.requireScopes(ScopeWebhooksManage))).Methods(http.MethodGet)
+	r.Handle("/webhooks/{subscriptionID}", s.chain(s.UpdateWebhook, s.requireScopes(ScopeWebhooksManage))).Methods(http.MethodPut)
+	r.Handle("/webhooks/{subscriptionID}", s.chain(s.DeleteWebhook, s.requireScopes(ScopeWebhooksManage))).Methods(http.MethodDelete)
+	r.Handle("/webhooks/{subscriptionID}/deliveries", s.chain(s.ListWebhookDeliveries, s.requireScopes(ScopeWebhooksManage))).Methods(http.MethodGet)
+	r.Handle("/webhooks/{subscriptionID}/deliveries/{deliveryID}/attempts", s.chain(s.ListWebhookAttempts, s.requireScopes(ScopeWebhooksManage))).Methods(http.MethodGet)
+
+	r.Handle("/transactions", s.chain(s.ProcessTransaction, s.requireScopes(ScopeTransactionsWrite), s.rateLimit("POST /transactions"), s.verifySignature(maxBufferedBodyBytes))).Methods(http.MethodPost)
+	r.Handle("/transactions/import", s.chain(s.ImportTransactions, s.requireScopes(ScopeTransactionsWrite), s.rateLimit("POST /transactions/import"), s.verifySignature(maxImportTransactionsBodyBytes))).Methods(http.MethodPost)
+}
+
+// chain wraps a handler with the middleware shared by every route, followed by the route-specific middleware in order.
+// Request bodies are validated against the route's schema last, just before the handler.
+func (s *Server) chain(h http.HandlerFunc, mws ...middleware) http.Handler {
+	handler := s.validateRequestBody(h)
+	for i := len(mws) - 1; i >= 0; i-- {
+		handler = mws[i](handler)
+	}
+	return s.loggingMiddleware(s.accessLogMiddleware(handler))
 }
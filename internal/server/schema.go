@@ -0,0 +1,155 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/jsonschema"
+	"github.com/cursed-ninja/internal-transfers-system/internal/utils"
+	"go.uber.org/zap"
+)
+
+// jsonMediaType is the request body media type validated against route schemas.
+const jsonMediaType = "application/json"
+
+// ErrSchemaMismatch is reported when a request body does not match its route's schema.
+var ErrSchemaMismatch = errors.New("request body does not match schema")
+
+// requestSchema is the documented request body of one route.
+type requestSchema struct {
+	schema *jsonschema.Schema
+	// otherMediaTypes are non-JSON media types the route also accepts; such bodies are not validated here.
+	otherMediaTypes map[string]bool
+}
+
+// validationErrorResponse is the 400 body returned for request bodies that fail schema validation.
+type validationErrorResponse struct {
+	Error  string                  `json:"error"`
+	Fields []jsonschema.FieldError `json:"fields"`
+}
+
+// requestSchemas maps "METHOD /route" to the request body schema documented in openapi.json.
+var requestSchemas = mustLoadRequestSchemas(openAPISpec)
+
+// mustLoadRequestSchemas is loadRequestSchemas for package initialization; the embedded document is
+// covered by tests, so failures are programming errors.
+func mustLoadRequestSchemas(spec []byte) map[string]requestSchema {
+	schemas, err := loadRequestSchemas(spec)
+	if err != nil {
+		panic(err)
+	}
+	return schemas
+}
+
+// loadRequestSchemas compiles the component schemas of an OpenAPI document and indexes the JSON
+// request body schema of every operation by "METHOD /route".
+func loadRequestSchemas(spec []byte) (map[string]requestSchema, error) {
+	var doc struct {
+		Paths      map[string]map[string]json.RawMessage `json:"paths"`
+		Components struct {
+			Schemas map[string]json.RawMessage `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		return nil, fmt.Errorf("invalid openapi document: %w", err)
+	}
+
+	components, err := jsonschema.Compile(doc.Components.Schemas)
+	if err != nil {
+		return nil, err
+	}
+
+	routes := make(map[string]requestSchema)
+	for path, item := range doc.Paths {
+		for method, raw := range item {
+			if method == "parameters" {
+				continue
+			}
+			var op struct {
+				RequestBody *struct {
+					Content map[string]struct {
+						Schema struct {
+							Ref string `json:"$ref"`
+						} `json:"schema"`
+					} `json:"content"`
+				} `json:"requestBody"`
+			}
+			if err := json.Unmarshal(raw, &op); err != nil {
+				return nil, fmt.Errorf("%s %s: %w", method, path, err)
+			}
+			if op.RequestBody == nil {
+				continue
+			}
+
+			route := strings.ToUpper(method) + " " + path
+			content, ok := op.RequestBody.Content[jsonMediaType]
+			if !ok {
+				// Bodies in other formats, such as pain.001 XML, are parsed and checked by their handler.
+				continue
+			}
+			name, _ := strings.CutPrefix(content.Schema.Ref, "#/components/schemas/")
+			schema, ok := components[name]
+			if !ok {
+				return nil, fmt.Errorf("%s: request body schema must reference a component, got %q", route, content.Schema.Ref)
+			}
+
+			rs := requestSchema{schema: schema, otherMediaTypes: make(map[string]bool)}
+			for mediaType := range op.RequestBody.Content {
+				if mediaType != jsonMediaType {
+					rs.otherMediaTypes[mediaType] = true
+				}
+			}
+			routes[route] = rs
+		}
+	}
+	return routes, nil
+}
+
+// validateRequestBody rejects request bodies that do not match the route's schema in openapi.json,
+// so every documented endpoint is validated without a hand-written Validate function.
+// Oversized bodies return 413, malformed JSON returns 400 and schema mismatches return 400 with the
+// list of field errors. Routes without a documented body, and bodies in another documented media
+// type, pass through unchanged.
+func (s *Server) validateRequestBody(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rs, ok := requestSchemas[r.Method+" "+routeTemplate(r)]
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err == nil && rs.otherMediaTypes[mediaType] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		logger := utils.ContextLogger(r.Context())
+
+		body, err := bufferBody(r, maxBufferedBodyBytes)
+		if err != nil {
+			logger.Error("failed to read request body", zap.Error(err))
+			if errors.Is(err, ErrBodyTooLarge) {
+				http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+				return
+			}
+			http.Error(w, "failed to read request body", http.StatusBadRequest)
+			return
+		}
+
+		fieldErrs, err := rs.schema.Validate(body)
+		if err != nil {
+			logger.Error("failed to parse request body", zap.Error(err))
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if len(fieldErrs) > 0 {
+			logger.Warn("request body does not match schema", zap.Any("field_errors", fieldErrs))
+			writeJSON(w, logger, http.StatusBadRequest, validationErrorResponse{Error: ErrSchemaMismatch.Error(), Fields: fieldErrs})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,139 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/config"
+	"github.com/cursed-ninja/internal-transfers-system/internal/jsonschema"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage/mocks"
+	"github.com/gorilla/mux"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+// TestRequestSchemasCoverBodies verifies every documented request body is indexed under its route.
+func TestRequestSchemasCoverBodies(t *testing.T) {
+	for _, route := range []string{
+		"POST /accounts",
+		"POST /transactions",
+		"POST /admin/api-keys",
+		"PUT /admin/log-level",
+		"POST /webhooks",
+		"PUT /webhooks/{subscriptionID}",
+	} {
+		assert.Contains(t, requestSchemas, route)
+	}
+	assert.NotContains(t, requestSchemas, "GET /accounts/{accountID}")
+}
+
+// TestValidateRequestBody tests schema validation of request bodies through the router.
+// Scenarios include valid bodies, unknown fields, wrong types, malformed JSON and oversized bodies.
+func TestValidateRequestBody(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		contentType    string
+		body           string
+		mockSetup      func(m *mocks.MockStorage)
+		expectedStatus int
+		expectedFields []jsonschema.FieldError
+	}{
+		{
+			name:   "valid body reaches the handler",
+			method: http.MethodPost,
+			path:   "/accounts",
+			body:   `{"account_id":"acc-1","initial_balance":"10"}`,
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().CreateAccount(gomock.Any(), "acc-1", decimal.RequireFromString("10"), gomock.Any()).Return(nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "unknown field",
+			method:         http.MethodPost,
+			path:           "/accounts",
+			body:           `{"account_id":"acc-1","initial_balance":"10","balance":"10"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedFields: []jsonschema.FieldError{{Field: "balance", Message: "is not a known field"}},
+		},
+		{
+			name:           "wrong type and missing field",
+			method:         http.MethodPost,
+			path:           "/transactions",
+			body:           `{"source_account_id":"acc-1","amount":10}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedFields: []jsonschema.FieldError{
+				{Field: "destination_account_id", Message: "is required"},
+				{Field: "amount", Message: "must be a string"},
+			},
+		},
+		{
+			name:           "malformed json",
+			method:         http.MethodPost,
+			path:           "/transactions",
+			body:           `{not json`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "oversized body",
+			method:         http.MethodPost,
+			path:           "/accounts",
+			body:           `{"account_id":"` + strings.Repeat("a", maxBufferedBodyBytes) + `"}`,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "other documented media type is not validated",
+			method:         http.MethodPut,
+			path:           "/admin/log-level",
+			contentType:    "application/x-www-form-urlencoded",
+			body:           "level=warn",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "json log level is validated",
+			method:         http.MethodPut,
+			path:           "/admin/log-level",
+			contentType:    "application/json",
+			body:           `{"level":"loud"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedFields: []jsonschema.FieldError{{Field: "level", Message: `must be one of "debug", "info", "warn", "error", "dpanic", "panic", "fatal"`}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockStorage := mocks.NewMockStorage(ctrl)
+			if tc.mockSetup != nil {
+				tc.mockSetup(mockStorage)
+			}
+			s := &Server{cfg: &config.Config{}, store: mockStorage, logLevel: zap.NewAtomicLevel()}
+			r := mux.NewRouter()
+			s.BindRoutes(r)
+
+			req := httptest.NewRequest(tc.method, tc.path, bytes.NewBufferString(tc.body))
+			if tc.contentType != "" {
+				req.Header.Set("Content-Type", tc.contentType)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedFields != nil {
+				var resp validationErrorResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				assert.Equal(t, ErrSchemaMismatch.Error(), resp.Error)
+				assert.Equal(t, tc.expectedFields, resp.Fields)
+			}
+		})
+	}
+}
@@ -1,7 +1,15 @@
 package server
 
 import (
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+
 	"github.com/cursed-ninja/internal-transfers-system/internal/config"
+	"github.com/cursed-ninja/internal-transfers-system/internal/events"
+	"github.com/cursed-ninja/internal-transfers-system/internal/metrics"
+	"github.com/cursed-ninja/internal-transfers-system/internal/ratelimit"
 	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
 )
 
@@ -9,12 +17,68 @@ import (
 type Server struct {
 	cfg   *config.Config
 	store storage.Storage
+
+	// logger is the root application logger; request loggers are derived from it.
+	logger *zap.Logger
+	// logLevel controls the root logger's level at runtime.
+	logLevel zap.AtomicLevel
+	// jwt verifies bearer tokens; nil when JWT authentication is disabled.
+	jwt *JWTVerifier
+	// signer verifies HMAC request signatures; nil when signing is disabled.
+	signer *RequestVerifier
+	// limiter stores rate limit buckets; nil when rate limiting is disabled.
+	limiter ratelimit.Store
+	// metrics receives per-request observations from accessLogMiddleware.
+	metrics *metrics.Registry
+	// readinessChecks are the dependency checks run by ReadinessHandler.
+	readinessChecks []readinessCheck
+	// events feeds account event streams; nil when streaming is disabled.
+	events *events.Broker
+	// draining is set once shutdown starts so readiness fails while connections drain.
+	draining atomic.Bool
+	// drained is closed once shutdown starts so long-lived streams end and clients reconnect elsewhere.
+	drained   chan struct{}
+	drainOnce sync.Once
+}
+
+// NewServer creates a new Server instance with the given configuration, storage and root logger.
+func NewServer(cfg *config.Config, store storage.Storage, logger *zap.Logger, logLevel zap.AtomicLevel) *Server {
+	s := &Server{
+		cfg:      cfg,
+		store:    store,
+		logger:   logger,
+		logLevel: logLevel,
+		metrics:  metrics.NewRegistry(),
+		drained:  make(chan struct{}),
+	}
+	s.AddReadinessCheck("postgres", store.Ping)
+	return s
+}
+
+// SetJWTVerifier enables bearer token authentication with the given verifier.
+func (s *Server) SetJWTVerifier(v *JWTVerifier) {
+	s.jwt = v
+}
+
+// SetRequestVerifier enables HMAC signature verification for transfer requests.
+func (s *Server) SetRequestVerifier(v *RequestVerifier) {
+	s.signer = v
+}
+
+// SetEventBroker enables account event streams fed by the given broker.
+func (s *Server) SetEventBroker(broker *events.Broker) {
+	s.events = broker
+}
+
+// SetRateLimiter enables rate limiting backed by the given store.
+func (s *Server) SetRateLimiter(store ratelimit.Store) {
+	s.limiter = store
 }
 
-// NewServer creates a new Server instance with the given configuration and storage.
-func NewServer(cfg *config.Config, store storage.Storage) *Server {
-	return &Server{
-		cfg:   cfg,
-		store: store,
+// rootLogger returns the injected root logger, falling back to the global logger.
+func (s *Server) rootLogger() *zap.Logger {
+	if s.logger == nil {
+		return zap.L()
 	}
+	return s.logger
 }
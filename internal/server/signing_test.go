@@ -0,0 +1,207 @@
+package server
+
+import (
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/config"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage/mocks"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// signRequest sets signature headers on req for the given secret, timestamp and body.
+func signRequest(req *http.Request, clientID, secret string, ts time.Time, body string) {
+	payload := signaturePayload(req.Method, req.URL.RequestURI(), ts.Unix(), []byte(body))
+	req.Header.Set(clientIDHeader, clientID)
+	req.Header.Set(signatureTimestampHeader, strconv.FormatInt(ts.Unix(), 10))
+	req.Header.Set(signatureHeader, hex.EncodeToString(computeSignature([]byte(secret), payload)))
+}
+
+// TestRequestVerifier validates signature verification, replay protection and secret rotation windows.
+func TestRequestVerifier(t *testing.T) {
+	now := time.Date(2025, 12, 1, 12, 0, 0, 0, time.UTC)
+	body := `{"source_account_id":"acc-1","destination_account_id":"acc-2","amount":"5"}`
+
+	cfg := &config.SigningConfig{
+		MaxSkew: time.Minute,
+		Clients: []config.SigningClient{{
+			ClientID: "payroll",
+			Secrets: []config.SigningSecret{
+				{Secret: "old-secret", NotAfter: "2025-12-01T12:30:00Z"},
+				{Secret: "new-secret", NotBefore: "2025-12-01T11:00:00Z"},
+				{Secret: "retired-secret", NotAfter: "2025-11-01T00:00:00Z"},
+			},
+		}},
+	}
+
+	tests := []struct {
+		name        string
+		required    bool
+		clientID    string
+		sign        func(req *http.Request)
+		bodySent    string
+		expectedErr error
+	}{
+		{
+			name:     "old secret within overlap",
+			clientID: "payroll",
+			sign: func(req *http.Request) {
+				signRequest(req, "payroll", "old-secret", now, body)
+			},
+		},
+		{
+			name:     "new secret within overlap",
+			clientID: "payroll",
+			sign: func(req *http.Request) {
+				signRequest(req, "payroll", "new-secret", now, body)
+			},
+		},
+		{
+			name:     "retired secret",
+			clientID: "payroll",
+			sign: func(req *http.Request) {
+				signRequest(req, "payroll", "retired-secret", now, body)
+			},
+			expectedErr: ErrInvalidSignature,
+		},
+		{
+			name:     "stale timestamp",
+			clientID: "payroll",
+			sign: func(req *http.Request) {
+				signRequest(req, "payroll", "new-secret", now.Add(-2*time.Minute), body)
+			},
+			expectedErr: ErrStaleSignature,
+		},
+		{
+			name:     "future timestamp",
+			clientID: "payroll",
+			sign: func(req *http.Request) {
+				signRequest(req, "payroll", "new-secret", now.Add(2*time.Minute), body)
+			},
+			expectedErr: ErrStaleSignature,
+		},
+		{
+			name:     "tampered body",
+			clientID: "payroll",
+			sign: func(req *http.Request) {
+				signRequest(req, "payroll", "new-secret", now, body)
+			},
+			bodySent:    strings.Replace(body, `"5"`, `"5000"`, 1),
+			expectedErr: ErrInvalidSignature,
+		},
+		{
+			name:        "configured client unsigned",
+			clientID:    "payroll",
+			expectedErr: ErrMissingSignature,
+		},
+		{
+			name:     "unconfigured client unsigned",
+			clientID: "dashboard",
+		},
+		{
+			name:        "unconfigured client unsigned when required",
+			required:    true,
+			clientID:    "dashboard",
+			expectedErr: ErrMissingSignature,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg.Required = tc.required
+			v, err := NewRequestVerifier(cfg)
+			require.NoError(t, err)
+			v.now = func() time.Time { return now }
+
+			req := httptest.NewRequest(http.MethodPost, "/transactions", nil)
+			if tc.sign != nil {
+				tc.sign(req)
+			}
+			sent := body
+			if tc.bodySent != "" {
+				sent = tc.bodySent
+			}
+
+			err = v.Verify(req, tc.clientID, []byte(sent))
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestNewRequestVerifierInvalidWindow validates that malformed rotation windows are rejected at startup.
+func TestNewRequestVerifierInvalidWindow(t *testing.T) {
+	_, err := NewRequestVerifier(&config.SigningConfig{
+		Clients: []config.SigningClient{{
+			ClientID: "payroll",
+			Secrets:  []config.SigningSecret{{Secret: "s", NotBefore: "yesterday"}},
+		}},
+	})
+	assert.Error(t, err)
+}
+
+// TestVerifySignatureMiddleware tests that a signed transfer reaches the handler with its body intact
+// and that an invalid signature is rejected before storage is touched.
+func TestVerifySignatureMiddleware(t *testing.T) {
+	body := `{"source_account_id":"acc-1","destination_account_id":"acc-2","amount":"5"}`
+
+	tests := []struct {
+		name           string
+		secret         string
+		mockSetup      func(m *mocks.MockStorage)
+		expectedStatus int
+	}{
+		{
+			name:   "valid signature",
+			secret: "payroll-secret",
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().ProcessTransaction(gomock.Any(), "acc-1", "acc-2", decimal.RequireFromString("5"), storage.AnyVersion).Return(nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "invalid signature",
+			secret:         "wrong-secret",
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockStorage := mocks.NewMockStorage(mockCtrl)
+			if tc.mockSetup != nil {
+				tc.mockSetup(mockStorage)
+			}
+
+			v, err := NewRequestVerifier(&config.SigningConfig{
+				Clients: []config.SigningClient{{
+					ClientID: "payroll",
+					Secrets:  []config.SigningSecret{{Secret: "payroll-secret"}},
+				}},
+			})
+			require.NoError(t, err)
+			s := Server{cfg: &config.Config{}, store: mockStorage, signer: v}
+
+			handler := s.verifySignature(maxBufferedBodyBytes)(http.HandlerFunc(s.ProcessTransaction))
+			req := httptest.NewRequest(http.MethodPost, "/transactions", strings.NewReader(body))
+			signRequest(req, "payroll", tc.secret, time.Now(), body)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+		})
+	}
+}
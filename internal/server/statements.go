@@ -0,0 +1,99 @@
+package server
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/statements"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+	"github.com/cursed-ninja/internal-transfers-system/internal/utils"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// attachmentResponse sends the headers of a download with its first bytes, so errors found before any
+// output can still be reported with an error status.
+type attachmentResponse struct {
+	w           http.ResponseWriter
+	contentType string
+	filename    string
+	written     bool
+}
+
+func (s *attachmentResponse) Write(p []byte) (int, error) {
+	if !s.written {
+		s.written = true
+		s.w.Header().Set("Content-Type", s.contentType)
+		s.w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": s.filename}))
+		s.w.WriteHeader(http.StatusOK)
+	}
+	return s.w.Write(p)
+}
+
+// GetAccountStatement handles GET /accounts/{accountID}/statement, streaming the account's opening
+// balance, transfers with running balances and closing balance for the requested period.
+func (s *Server) GetAccountStatement(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := utils.ContextLogger(ctx)
+
+	logger.Info("received GetAccountStatement request")
+
+	accountID := strings.TrimSpace(mux.Vars(r)["accountID"])
+	if accountID == "" {
+		logger.Error("missing account_id in URL path")
+		http.Error(w, "account_id is required in URL path", http.StatusBadRequest)
+		return
+	}
+
+	ctx, logger = utils.LoggerWithKey(ctx, zap.String("account_id", accountID))
+
+	from, to, format, err := statements.ParseQuery(r.URL.Query(), time.Now())
+	if err != nil {
+		logger.Error("invalid statement query", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !format.FitsAccountID(accountID) {
+		logger.Error("account ID does not fit the statement format", zap.String("format", format.Name))
+		http.Error(w, statements.ErrAccountID.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if p := principalFromContext(ctx); p != nil && !p.canRead(accountID) {
+		logger.Warn("caller is not allowed to read account")
+		http.Error(w, ErrAccountForbidden.Error(), http.StatusForbidden)
+		return
+	}
+
+	resp := &attachmentResponse{
+		w:           w,
+		contentType: format.ContentType,
+		filename:    statementFilename(accountID, from, to, format),
+	}
+	err = s.store.StreamStatement(ctx, accountID, from, to, format.NewWriter(resp))
+	if err != nil {
+		logger.Error("failed to stream statement", zap.Error(err))
+		if resp.written {
+			// The status has been sent; the missing closing balance marks the statement as incomplete.
+			return
+		}
+		errorMsg := err.Error()
+		statusCode := http.StatusInternalServerError
+		switch errorMsg {
+		case storage.ErrAccountNotFound, storage.ErrAccountNotOpenMsg:
+			statusCode = http.StatusNotFound
+		}
+		http.Error(w, errorMsg, statusCode)
+		return
+	}
+
+	logger.Info("statement sent", zap.String("format", format.Name))
+}
+
+// statementFilename names a downloaded statement after its account and period.
+func statementFilename(accountID string, from, to time.Time, format statements.Format) string {
+	const layout = "20060102T150405Z"
+	return "statement-" + accountID + "-" + from.UTC().Format(layout) + "-" + to.UTC().Format(layout) + "." + format.Extension
+}
@@ -0,0 +1,252 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/config"
+	"github.com/cursed-ninja/internal-transfers-system/internal/statements"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage/mocks"
+	"github.com/gorilla/mux"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestGetAccountStatement tests the GetAccountStatement endpoint: formats, date and timestamp
+// periods, invalid queries, access restrictions and failures before and after output starts.
+func TestGetAccountStatement(t *testing.T) {
+	june := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	july := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	stream := func(_ any, accountID string, from, to time.Time, w storage.StatementWriter) error {
+		stmt := &storage.Statement{
+			AccountID: accountID, Currency: "EUR", From: from, To: to,
+			OpeningBalance: decimal.NewFromInt(100), ClosingBalance: decimal.NewFromInt(60), TotalDebits: decimal.NewFromInt(40), DebitCount: 1,
+		}
+		if err := w.WriteHeader(stmt); err != nil {
+			return err
+		}
+		entry := storage.StatementEntry{TransactionID: 7, CreatedAt: from.Add(time.Hour), CounterpartyID: "acc-2", Amount: decimal.NewFromInt(-40), Balance: decimal.NewFromInt(60)}
+		if err := w.WriteEntry(entry); err != nil {
+			return err
+		}
+		return w.WriteFooter(stmt)
+	}
+
+	tests := []struct {
+		name                string
+		accountID           string
+		query               string
+		principal           *principal
+		mockSetup           func(m *mocks.MockStorage)
+		expectedStatus      int
+		expectedContentType string
+		expectedBody        string
+		expectedDisposition string
+	}{
+		{
+			name:  "csv for a month of dates",
+			query: "?from=2025-06-01&to=2025-06-30&format=csv",
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().StreamStatement(gomock.Any(), "acc-1", june, july, gomock.Any()).DoAndReturn(stream)
+			},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/csv; charset=utf-8",
+			expectedDisposition: `attachment; filename=statement-acc-1-20250601T000000Z-20250701T000000Z.csv`,
+			expectedBody: "type,time,transaction_id,counterparty_account_id,amount,balance,currency,request_id\n" +
+				"opening,2025-06-01T00:00:00Z,,,,100,EUR,\n" +
+				"entry,2025-06-01T01:00:00Z,7,acc-2,-40,60,EUR,\n" +
+				"closing,2025-07-01T00:00:00Z,,,,60,EUR,\n",
+		},
+		{
+			name:  "json by default with timestamps",
+			query: "?from=2025-06-01T02:00:00%2B02:00&to=2025-07-01T00:00:00Z",
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().StreamStatement(gomock.Any(), "acc-1", gomock.Cond(func(t time.Time) bool { return t.Equal(june) }), july, gomock.Any()).DoAndReturn(stream)
+			},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/json",
+			expectedBody: `{"account_id":"acc-1","currency":"EUR","from":"2025-06-01T00:00:00Z","to":"2025-07-01T00:00:00Z","opening_balance":"100",` +
+				`"entries":[{"transaction_id":7,"created_at":"2025-06-01T01:00:00Z","counterparty_account_id":"acc-2","amount":"-40","balance":"60"}],` +
+				`"closing_balance":"60","total_credits":"0","total_debits":"40","entry_count":1}` + "\n",
+		},
+		{
+			name:  "text",
+			query: "?from=2025-06-01&to=2025-06-30&format=txt",
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().StreamStatement(gomock.Any(), "acc-1", june, july, gomock.Any()).DoAndReturn(stream)
+			},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/plain; charset=utf-8",
+		},
+		{
+			name:  "camt053",
+			query: "?from=2025-06-01&to=2025-06-30&format=camt053",
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().StreamStatement(gomock.Any(), "acc-1", june, july, gomock.Any()).DoAndReturn(stream)
+			},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/xml",
+			expectedDisposition: `attachment; filename=statement-acc-1-20250601T000000Z-20250701T000000Z.xml`,
+		},
+		{
+			name:           "account ID too long for camt053",
+			accountID:      strings.Repeat("a", 35),
+			query:          "?from=2025-06-01&to=2025-06-30&format=camt053",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   statements.ErrAccountID.Error() + "\n",
+		},
+		{
+			name:  "mt940",
+			query: "?from=2025-06-01&to=2025-06-30&format=mt940",
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().StreamStatement(gomock.Any(), "acc-1", june, july, gomock.Any()).DoAndReturn(stream)
+			},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/plain; charset=us-ascii",
+			expectedDisposition: `attachment; filename=statement-acc-1-20250601T000000Z-20250701T000000Z.sta`,
+		},
+		{
+			name:           "account ID outside the mt940 character set",
+			accountID:      "acc_1",
+			query:          "?from=2025-06-01&to=2025-06-30&format=mt940",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   statements.ErrAccountID.Error() + "\n",
+		},
+		{
+			name:           "missing period",
+			query:          "?from=2025-06-01",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   statements.ErrMissingPeriod.Error() + "\n",
+		},
+		{
+			name:           "invalid from",
+			query:          "?from=June&to=2025-06-30",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   statements.ErrInvalidFrom.Error() + "\n",
+		},
+		{
+			name:           "invalid to",
+			query:          "?from=2025-06-01&to=2025-06-31",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   statements.ErrInvalidTo.Error() + "\n",
+		},
+		{
+			name:           "empty period",
+			query:          "?from=2025-07-01&to=2025-06-01",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   statements.ErrPeriod.Error() + "\n",
+		},
+		{
+			name:           "period in the future",
+			query:          "?from=2025-06-01&to=2999-01-01",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   statements.ErrFutureTo.Error() + "\n",
+		},
+		{
+			name:           "unknown format",
+			query:          "?from=2025-06-01&to=2025-06-30&format=pdf",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "format must be one of camt053, csv, json, mt940, txt\n",
+		},
+		{
+			name:           "caller restricted to other accounts",
+			query:          "?from=2025-06-01&to=2025-06-30",
+			principal:      &principal{ClientID: "user", AccountRestricted: true, ReadAccounts: map[string]bool{"acc-2": true}},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:  "account not found",
+			query: "?from=2025-06-01&to=2025-06-30",
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().StreamStatement(gomock.Any(), "acc-1", june, july, gomock.Any()).Return(errors.New(storage.ErrAccountNotFound))
+			},
+			expectedStatus:      http.StatusNotFound,
+			expectedContentType: "text/plain; charset=utf-8",
+			expectedBody:        storage.ErrAccountNotFound + "\n",
+		},
+		{
+			name:  "account opened after the period",
+			query: "?from=2025-06-01&to=2025-06-30",
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().StreamStatement(gomock.Any(), "acc-1", june, july, gomock.Any()).Return(errors.New(storage.ErrAccountNotOpenMsg))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:  "internal error",
+			query: "?from=2025-06-01&to=2025-06-30",
+			mockSetup: func(m *mocks.MockStorage) {
+				m.EXPECT().StreamStatement(gomock.Any(), "acc-1", june, july, gomock.Any()).Return(errors.New(storage.ErrStatementMsg))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockStorage := mocks.NewMockStorage(mockCtrl)
+			if tc.mockSetup != nil {
+				tc.mockSetup(mockStorage)
+			}
+
+			s := Server{cfg: &config.Config{}, store: mockStorage}
+			r := mux.NewRouter()
+			s.BindRoutes(r)
+
+			accountID := tc.accountID
+			if accountID == "" {
+				accountID = "acc-1"
+			}
+			req := httptest.NewRequest(http.MethodGet, "/accounts/"+accountID+"/statement"+tc.query, nil)
+			if tc.principal != nil {
+				req = req.WithContext(withPrincipal(req.Context(), tc.principal))
+			}
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedContentType != "" {
+				assert.Equal(t, tc.expectedContentType, w.Header().Get("Content-Type"))
+			}
+			if tc.expectedDisposition != "" {
+				assert.Equal(t, tc.expectedDisposition, w.Header().Get("Content-Disposition"))
+			}
+			if tc.expectedBody != "" {
+				assert.Equal(t, tc.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+// TestGetAccountStatementFailsMidStream verifies that a failure after output has started keeps the
+// status already sent instead of appending an error message to the statement.
+func TestGetAccountStatementFailsMidStream(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockStorage := mocks.NewMockStorage(mockCtrl)
+	mockStorage.EXPECT().StreamStatement(gomock.Any(), "acc-1", gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ any, accountID string, from, to time.Time, w storage.StatementWriter) error {
+			_ = w.WriteHeader(&storage.Statement{AccountID: accountID, Currency: "EUR", From: from, To: to})
+			// The footer flushes the output, as a full buffer would.
+			_ = w.WriteFooter(&storage.Statement{AccountID: accountID, Currency: "EUR", From: from, To: to})
+			return errors.New(storage.ErrStatementMsg)
+		})
+
+	s := Server{cfg: &config.Config{}, store: mockStorage}
+	r := mux.NewRouter()
+	s.BindRoutes(r)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/accounts/acc-1/statement?from=2025-06-01&to=2025-06-30&format=csv", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.NotContains(t, w.Body.String(), storage.ErrStatementMsg)
+}
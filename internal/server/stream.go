@@ -0,0 +1,225 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+	"github.com/cursed-ninja/internal-transfers-system/internal/utils"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+const (
+	// EventBalanceChanged is the stream event sent with an account's balance after a change.
+	EventBalanceChanged = "balance.changed"
+
+	defaultHeartbeatInterval = 15 * time.Second
+	// replayBatchSize is the number of events read per query when resuming a stream.
+	replayBatchSize = 500
+	// streamRetryMillis tells clients how long to wait before reconnecting.
+	streamRetryMillis = 3000
+)
+
+type balanceChangedEvent struct {
+	AccountID string `json:"account_id"`
+	Balance   string `json:"balance"`
+	// EventID is the ID of the event that caused the change; empty for the initial balance.
+	EventID int64 `json:"event_id,omitempty"`
+}
+
+// StreamAccountEvents handles GET /accounts/{accountID}/events requests as a Server-Sent Events stream.
+// Each committed event touching the account is sent as a balance.changed message followed by the event
+// itself, which carries the outbox ID as its SSE id. Clients resuming with a Last-Event-ID header (or
+// last_event_id query parameter) first receive every event they missed; new clients first receive the
+// current balance.
+func (s *Server) StreamAccountEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := utils.ContextLogger(ctx)
+	logger.Info("received StreamAccountEvents request")
+
+	if s.events == nil {
+		http.Error(w, "event streaming is not enabled", http.StatusServiceUnavailable)
+		return
+	}
+
+	accountID := strings.TrimSpace(mux.Vars(r)["accountID"])
+	if accountID == "" {
+		logger.Error("missing account_id in URL path")
+		http.Error(w, "account_id is required in URL path", http.StatusBadRequest)
+		return
+	}
+	ctx, logger = utils.LoggerWithKey(ctx, zap.String("account_id", accountID))
+
+	if p := principalFromContext(ctx); p != nil && !p.canRead(accountID) {
+		logger.Warn("caller is not allowed to read account")
+		http.Error(w, ErrAccountForbidden.Error(), http.StatusForbidden)
+		return
+	}
+
+	lastEventID, resume, err := lastEventIDFromRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Subscribe before reading the account so no event committed in between is missed.
+	sub := s.events.Subscribe(accountID)
+	defer s.events.Unsubscribe(sub)
+
+	acc, err := s.store.GetAccountDetails(ctx, accountID)
+	if err != nil {
+		logger.Error("failed to get account details", zap.Error(err))
+		errorMsg := err.Error()
+		statusCode := http.StatusInternalServerError
+		if errorMsg == storage.ErrAccountNotFound {
+			statusCode = http.StatusNotFound
+		}
+		http.Error(w, errorMsg, statusCode)
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetryMillis)
+
+	if resume {
+		for {
+			missed, err := s.store.ListEventsAfter(ctx, lastEventID, accountID, replayBatchSize)
+			if err != nil {
+				logger.Error("failed to replay account events", zap.Error(err))
+				return
+			}
+			for _, event := range missed {
+				writeAccountEvent(w, accountID, event)
+				lastEventID = event.ID
+			}
+			if len(missed) < replayBatchSize {
+				break
+			}
+		}
+	} else {
+		data, _ := json.Marshal(balanceChangedEvent{AccountID: acc.ID, Balance: acc.Balance.String()})
+		writeSSE(w, "", EventBalanceChanged, data)
+	}
+	if err := rc.Flush(); err != nil {
+		logger.Error("event streaming is not supported by the response writer", zap.Error(err))
+		return
+	}
+	logger.Info("account event stream opened", zap.Bool("resumed", resume), zap.Int64("last_event_id", lastEventID))
+
+	heartbeat := defaultHeartbeatInterval
+	if s.cfg != nil && s.cfg.Events != nil && s.cfg.Events.HeartbeatInterval > 0 {
+		heartbeat = s.cfg.Events.HeartbeatInterval
+	}
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("account event stream closed by client")
+			return
+		case <-s.drained:
+			logger.Info("account event stream closed for shutdown")
+			return
+		case <-ticker.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case event, ok := <-sub.Events():
+			if !ok {
+				logger.Info("account event stream dropped by broker")
+				return
+			}
+			// Events already sent during replay may also arrive live.
+			if event.ID <= lastEventID {
+				continue
+			}
+			writeAccountEvent(w, accountID, event)
+			lastEventID = event.ID
+		}
+		if err := rc.Flush(); err != nil {
+			logger.Info("account event stream write failed", zap.Error(err))
+			return
+		}
+	}
+}
+
+// lastEventIDFromRequest reads the resume position from the Last-Event-ID header or last_event_id query parameter.
+func lastEventIDFromRequest(r *http.Request) (int64, bool, error) {
+	raw := strings.TrimSpace(r.Header.Get("Last-Event-ID"))
+	if raw == "" {
+		raw = strings.TrimSpace(r.URL.Query().Get("last_event_id"))
+	}
+	if raw == "" {
+		return 0, false, nil
+	}
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || id < 0 {
+		return 0, false, fmt.Errorf("Last-Event-ID must be a non-negative integer")
+	}
+	return id, true, nil
+}
+
+// writeAccountEvent writes the balance change for accountID, if the event carries one, followed by the event.
+// Only the event carries an SSE id, so a client that disconnects in between replays both. The event is
+// sent without the balance and version of a transfer's other account.
+func writeAccountEvent(w io.Writer, accountID string, event storage.OutboxEvent) {
+	if balance, ok := balanceAfter(accountID, event); ok {
+		data, _ := json.Marshal(balanceChangedEvent{AccountID: accountID, Balance: balance, EventID: event.ID})
+		writeSSE(w, "", EventBalanceChanged, data)
+	}
+	payload := storage.RedactEventPayload(event.EventType, event.Payload, func(id string) bool { return id == accountID })
+	writeSSE(w, strconv.FormatInt(event.ID, 10), event.EventType, payload)
+}
+
+// balanceAfter extracts accountID's balance after the event from its payload.
+func balanceAfter(accountID string, event storage.OutboxEvent) (string, bool) {
+	var payload struct {
+		AccountID            string `json:"account_id"`
+		InitialBalance       string `json:"initial_balance"`
+		SourceAccountID      string `json:"source_account_id"`
+		SourceBalance        string `json:"source_balance"`
+		DestinationAccountID string `json:"destination_account_id"`
+		DestinationBalance   string `json:"destination_balance"`
+	}
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return "", false
+	}
+
+	var balance string
+	switch event.EventType {
+	case storage.EventAccountCreated:
+		balance = payload.InitialBalance
+	case storage.EventTransferCompleted:
+		switch accountID {
+		case payload.SourceAccountID:
+			balance = payload.SourceBalance
+		case payload.DestinationAccountID:
+			balance = payload.DestinationBalance
+		}
+	}
+	return balance, balance != ""
+}
+
+// writeSSE writes one Server-Sent Events message. Multi-line data is split across data fields.
+func writeSSE(w io.Writer, id, event string, data []byte) {
+	var b strings.Builder
+	if id != "" {
+		b.WriteString("id: " + id + "\n")
+	}
+	b.WriteString("event: " + event + "\n")
+	for _, line := range strings.Split(string(data), "\n") {
+		b.WriteString("data: " + line + "\n")
+	}
+	b.WriteString("\n")
+	_, _ = io.WriteString(w, b.String())
+}
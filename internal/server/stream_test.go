@@ -0,0 +1,208 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/config"
+	"github.com/cursed-ninja/internal-transfers-system/internal/events"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage/mocks"
+	"github.com/gorilla/mux"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+// sseMessage is one parsed Server-Sent Events message.
+type sseMessage struct {
+	id    string
+	event string
+	data  string
+}
+
+// readSSE reads the next message with an event field, skipping retry and comment blocks.
+func readSSE(t *testing.T, r *bufio.Reader) sseMessage {
+	t.Helper()
+	var msg sseMessage
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "":
+			if msg.event != "" {
+				return msg
+			}
+		case strings.HasPrefix(line, "id: "):
+			msg.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			msg.event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			msg.data += strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func transferEvent(id int64) storage.OutboxEvent {
+	payload, _ := json.Marshal(map[string]any{
+		"transaction_id":         id,
+		"source_account_id":      "acc-1",
+		"destination_account_id": "acc-2",
+		"amount":                 "10",
+		"source_balance":         "90",
+		"destination_balance":    "110",
+	})
+	return storage.OutboxEvent{ID: id, EventType: storage.EventTransferCompleted, AccountIDs: []string{"acc-1", "acc-2"}, Payload: payload}
+}
+
+// newStreamServer starts an HTTP server streaming events from broker.
+func newStreamServer(t *testing.T, store storage.Storage, broker *events.Broker) (*Server, *httptest.Server) {
+	t.Helper()
+	s := &Server{cfg: &config.Config{}, store: store, events: broker, drained: make(chan struct{})}
+	r := mux.NewRouter()
+	s.BindRoutes(r)
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+	return s, srv
+}
+
+// TestStreamAccountEvents validates the initial balance, live events and resuming with Last-Event-ID.
+func TestStreamAccountEvents(t *testing.T) {
+	t.Run("live events", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		mockStorage := mocks.NewMockStorage(mockCtrl)
+		mockStorage.EXPECT().GetAccountDetails(gomock.Any(), "acc-1").Return(&storage.Account{ID: "acc-1", Balance: decimal.RequireFromString("100")}, nil)
+		broker := events.NewBroker(mockStorage, nil, &config.EventsConfig{}, zap.NewNop())
+		_, srv := newStreamServer(t, mockStorage, broker)
+
+		resp, err := http.Get(srv.URL + "/accounts/acc-1/events")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+		body := bufio.NewReader(resp.Body)
+
+		assert.Equal(t, sseMessage{event: EventBalanceChanged, data: `{"account_id":"acc-1","balance":"100"}`}, readSSE(t, body))
+
+		broker.Publish(transferEvent(7))
+		assert.Equal(t, sseMessage{event: EventBalanceChanged, data: `{"account_id":"acc-1","balance":"90","event_id":7}`}, readSSE(t, body))
+		msg := readSSE(t, body)
+		assert.Equal(t, "7", msg.id)
+		assert.Equal(t, storage.EventTransferCompleted, msg.event)
+		// The counterparty's balance is not sent to acc-1's stream.
+		assert.JSONEq(t, `{"transaction_id":7,"source_account_id":"acc-1","destination_account_id":"acc-2","amount":"10","source_balance":"90"}`, msg.data)
+	})
+
+	t.Run("resume", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		mockStorage := mocks.NewMockStorage(mockCtrl)
+		mockStorage.EXPECT().GetAccountDetails(gomock.Any(), "acc-2").Return(&storage.Account{ID: "acc-2", Balance: decimal.RequireFromString("110")}, nil)
+		mockStorage.EXPECT().ListEventsAfter(gomock.Any(), int64(5), "acc-2", replayBatchSize).Return([]storage.OutboxEvent{transferEvent(6)}, nil)
+		broker := events.NewBroker(mockStorage, nil, &config.EventsConfig{}, zap.NewNop())
+		_, srv := newStreamServer(t, mockStorage, broker)
+
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+"/accounts/acc-2/events", nil)
+		req.Header.Set("Last-Event-ID", "5")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body := bufio.NewReader(resp.Body)
+
+		assert.Equal(t, sseMessage{event: EventBalanceChanged, data: `{"account_id":"acc-2","balance":"110","event_id":6}`}, readSSE(t, body))
+		msg := readSSE(t, body)
+		assert.Equal(t, "6", msg.id)
+		assert.NotContains(t, msg.data, "source_balance")
+
+		// Event 6 also arrives live; it must not be sent twice.
+		broker.Publish(transferEvent(6))
+		broker.Publish(transferEvent(8))
+		readSSE(t, body)
+		assert.Equal(t, "8", readSSE(t, body).id)
+	})
+
+	t.Run("drain closes stream", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		mockStorage := mocks.NewMockStorage(mockCtrl)
+		mockStorage.EXPECT().GetAccountDetails(gomock.Any(), "acc-1").Return(&storage.Account{ID: "acc-1", Balance: decimal.Zero}, nil)
+		broker := events.NewBroker(mockStorage, nil, &config.EventsConfig{}, zap.NewNop())
+		s, srv := newStreamServer(t, mockStorage, broker)
+
+		resp, err := http.Get(srv.URL + "/accounts/acc-1/events")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body := bufio.NewReader(resp.Body)
+		readSSE(t, body)
+
+		s.StartDraining()
+		done := make(chan error, 1)
+		go func() {
+			_, err := body.ReadString('\n')
+			done <- err
+		}()
+		select {
+		case err := <-done:
+			assert.Error(t, err)
+		case <-time.After(time.Second):
+			require.Fail(t, "stream was not closed")
+		}
+	})
+}
+
+// TestStreamAccountEventsErrors validates rejected stream requests.
+func TestStreamAccountEventsErrors(t *testing.T) {
+	tests := []struct {
+		name           string
+		disabled       bool
+		lastEventID    string
+		principal      *principal
+		mockErr        error
+		expectedStatus int
+	}{
+		{name: "streaming disabled", disabled: true, expectedStatus: http.StatusServiceUnavailable},
+		{name: "invalid last event id", lastEventID: "abc", expectedStatus: http.StatusBadRequest},
+		{
+			name:           "forbidden account",
+			principal:      &principal{ClientID: "user-1", AccountRestricted: true, ReadAccounts: map[string]bool{"acc-2": true}},
+			expectedStatus: http.StatusForbidden,
+		},
+		{name: "account not found", mockErr: errors.New(storage.ErrAccountNotFound), expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockStorage := mocks.NewMockStorage(mockCtrl)
+			if tc.mockErr != nil {
+				mockStorage.EXPECT().GetAccountDetails(gomock.Any(), "acc-1").Return(nil, tc.mockErr)
+			}
+			s := Server{cfg: &config.Config{}, store: mockStorage}
+			if !tc.disabled {
+				s.events = events.NewBroker(mockStorage, nil, &config.EventsConfig{}, zap.NewNop())
+			}
+
+			r := mux.NewRouter()
+			s.BindRoutes(r)
+
+			req := httptest.NewRequest(http.MethodGet, "/accounts/acc-1/events", nil)
+			if tc.lastEventID != "" {
+				req.Header.Set("Last-Event-ID", tc.lastEventID)
+			}
+			if tc.principal != nil {
+				req = req.WithContext(withPrincipal(req.Context(), tc.principal))
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+		})
+	}
+}
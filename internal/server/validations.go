@@ -1,9 +1,19 @@
 package server
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 
+	"github.com/cursed-ninja/internal-transfers-system/internal/currency"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+	"github.com/cursed-ninja/internal-transfers-system/internal/transfers"
 	"github.com/shopspring/decimal"
 )
 
@@ -13,16 +23,89 @@ var (
 	ErrMissingBalance         = errors.New("balance is required")
 	ErrInvalidBalance         = errors.New("balance must be a valid decimal number")
 	ErrNegativeBalance        = errors.New("balance must be non-negative")
-	ErrMissingSourceAccountID = errors.New("source_account_id is required")
-	ErrMissingDestAccountID   = errors.New("destination_account_id is required")
-	ErrMissingAmount          = errors.New("amount is required")
-	ErrInvalidAmount          = errors.New("amount must be a valid decimal number")
-	ErrNonPositiveAmount      = errors.New("amount must be positive")
-	ErrSameAccountTransfer    = errors.New("source_account_id and destination_account_id cannot be the same")
+	ErrBalancePrecision       = errors.New("balance has more decimal places than the currency allows")
+	ErrMissingSourceAccountID = transfers.ErrMissingSourceAccountID
+	ErrMissingDestAccountID   = transfers.ErrMissingDestAccountID
+	ErrMissingAmount          = transfers.ErrMissingAmount
+	ErrInvalidAmount          = transfers.ErrInvalidAmount
+	ErrNonPositiveAmount      = transfers.ErrNonPositiveAmount
+	ErrSameAccountTransfer    = transfers.ErrSameAccountTransfer
+	ErrMissingClientID        = errors.New("client_id is required")
+	ErrMissingScopes          = errors.New("at least one scope is required")
+	ErrUnknownScope           = errors.New("unknown scope")
+	ErrMissingWebhookURL      = errors.New("url is required")
+	ErrInvalidWebhookURL      = errors.New("url must be an absolute http or https URL")
+	ErrMissingEventTypes      = errors.New("at least one event type is required")
+	ErrUnknownEventType       = errors.New("unknown event type")
+	ErrNameTooLong            = fmt.Errorf("name must be at most %d characters", maxAccountTextLength)
+	ErrOwnerRefTooLong        = fmt.Errorf("owner_ref must be at most %d characters", maxAccountTextLength)
+	ErrUnknownAccountType     = errors.New("type must be one of operating, settlement, fee, customer")
+	ErrTooManyLabels          = fmt.Errorf("at most %d labels are allowed", maxAccountLabels)
+	ErrLabelTooLong           = fmt.Errorf("labels must be at most %d characters", maxLabelLength)
+	ErrInvalidMetadata        = errors.New("metadata must be a JSON object")
+	ErrMetadataTooLarge       = fmt.Errorf("metadata must be at most %d bytes", maxMetadataBytes)
+	ErrMissingVersion         = errors.New("version or an If-Match header is required")
+	ErrEmptyAccountUpdate     = errors.New("at least one of name, owner_ref, type, labels or metadata is required")
+	ErrInvalidCurrency        = errors.New("currency must be a three-letter ISO 4217 code")
+	ErrUnknownAccountStatus   = errors.New("status must be one of active, closed")
+	ErrInvalidMinBalance      = errors.New("min_balance must be a valid decimal number")
+	ErrInvalidMaxBalance      = errors.New("max_balance must be a valid decimal number")
+	ErrBalanceRange           = errors.New("min_balance must not be greater than max_balance")
+	ErrUnknownAccountSort     = errors.New("sort must be one of created_at, -created_at, balance, -balance")
+	ErrInvalidLimit           = errors.New("limit must be a positive integer")
+	ErrInvalidIncludeTotal    = errors.New("include_total must be true or false")
+	ErrInvalidAsOf            = errors.New("as_of must be an RFC 3339 timestamp")
+	ErrFutureAsOf             = errors.New("as_of must not be in the future")
+	ErrFutureExecutionDate    = errors.New("requested execution date is in the future")
+	ErrInvalidImportMode      = errors.New("mode must be one of atomic, partial")
+	ErrMissingImportColumns   = errors.New("CSV header must have id and initial_balance columns")
+	ErrUnknownImportColumn    = errors.New("unknown CSV column")
+	ErrDuplicateImportColumn  = errors.New("duplicate CSV column")
+	ErrImportMetadataColumns  = errors.New("metadata cannot be combined with metadata.<key> columns")
+	ErrNoImportAccounts       = errors.New("CSV has no accounts")
+	ErrTooManyImportAccounts  = fmt.Errorf("at most %d accounts can be imported at once", maxImportAccounts)
+	ErrImportTooLarge         = fmt.Errorf("CSV must be at most %d MiB", maxImportBodyBytes>>20)
+	ErrPain001TooLarge        = fmt.Errorf("pain.001 message must be at most %d MiB", maxImportTransactionsBodyBytes>>20)
+	ErrInvalidLabels          = errors.New("labels must be a JSON array of strings")
 )
 
+// Limits on an account's descriptive attributes.
+const (
+	maxAccountTextLength = 200
+	maxAccountLabels     = 20
+	maxLabelLength       = 64
+	maxMetadataBytes     = 16 << 10
+)
+
+// currencyPattern matches an ISO 4217 alphabetic currency code.
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// accountStatuses are the values accepted when filtering accounts by status.
+var accountStatuses = map[string]bool{
+	storage.AccountStatusActive: true,
+	storage.AccountStatusClosed: true,
+}
+
+// accountTypes are the values accepted for an account's type.
+var accountTypes = map[string]bool{
+	storage.AccountTypeOperating:  true,
+	storage.AccountTypeSettlement: true,
+	storage.AccountTypeFee:        true,
+	storage.AccountTypeCustomer:   true,
+}
+
+// webhookEventTypes are the event types a webhook can subscribe to.
+var webhookEventTypes = map[string]bool{
+	storage.EventAccountCreated:    true,
+	storage.EventAccountUpdated:    true,
+	storage.EventAccountClosed:     true,
+	storage.EventTransferCompleted: true,
+}
+
 // ValidateCreateAccount checks the incoming account creation request for required fields,
-// trims whitespace, parses the initial balance, and ensures it is non-negative.
+// trims whitespace, parses the initial balance, and ensures it is non-negative and no finer than the
+// currency's minor unit.
+// The optional attributes are checked as in ValidateUpdateAccount.
 func ValidateCreateAccount(req *createAccountRequest) (decimal.Decimal, error) {
 	req.AccountID = strings.TrimSpace(req.AccountID)
 	req.InitialBalance = strings.TrimSpace(req.InitialBalance)
@@ -44,40 +127,325 @@ func ValidateCreateAccount(req *createAccountRequest) (decimal.Decimal, error) {
 		return decimal.Zero, ErrNegativeBalance
 	}
 
+	if req.Currency, err = validateCurrency(req.Currency); err != nil {
+		return decimal.Zero, err
+	}
+	if req.Currency == "" {
+		req.Currency = storage.DefaultCurrency
+	}
+	if !currency.Fits(balance, req.Currency) {
+		return decimal.Zero, ErrBalancePrecision
+	}
+
+	if req.Name, req.OwnerRef, req.Type, err = validateAccountText(req.Name, req.OwnerRef, req.Type); err != nil {
+		return decimal.Zero, err
+	}
+	if req.Labels, err = validateLabels(req.Labels); err != nil {
+		return decimal.Zero, err
+	}
+	if err := validateMetadata(req.Metadata); err != nil {
+		return decimal.Zero, err
+	}
+
 	return balance, nil
 }
 
-// ValidateProcessTransaction checks the transaction request for required fields,
-// trims whitespace, parses the amount, and ensures it is positive.
+// ValidateUpdateAccount checks the account update request for a version and at least one field, and
+// converts it to a storage patch. Text fields are trimmed, labels deduplicated, and null fields are
+// cleared: empty text, no labels and empty metadata.
+// When hasIfMatch is set, ifMatch is the version required by the If-Match header: it fills in a missing
+// body version and must otherwise agree with it, except for "*" (storage.AnyVersion), which leaves the
+// body's version, if any, in charge.
+func ValidateUpdateAccount(req *updateAccountRequest, ifMatch int64, hasIfMatch bool) (storage.AccountPatch, error) {
+	var patch storage.AccountPatch
+	switch {
+	case !hasIfMatch:
+		if req.Version <= 0 {
+			return patch, ErrMissingVersion
+		}
+	case ifMatch == storage.AnyVersion:
+	case req.Version == storage.AnyVersion:
+		req.Version = ifMatch
+	case req.Version != ifMatch:
+		return patch, ErrIfMatchVersion
+	}
+	if !req.Name.Set && !req.OwnerRef.Set && !req.Type.Set && !req.Labels.Set && !req.Metadata.Set {
+		return patch, ErrEmptyAccountUpdate
+	}
+
+	name, ownerRef, accountType, err := validateAccountText(req.Name.Value, req.OwnerRef.Value, req.Type.Value)
+	if err != nil {
+		return patch, err
+	}
+	if req.Name.Set {
+		patch.Name = &name
+	}
+	if req.OwnerRef.Set {
+		patch.OwnerRef = &ownerRef
+	}
+	if req.Type.Set {
+		patch.Type = &accountType
+	}
+
+	if req.Labels.Set {
+		labels, err := validateLabels(req.Labels.Value)
+		if err != nil {
+			return patch, err
+		}
+		patch.Labels = &labels
+	}
+
+	if req.Metadata.Set {
+		patch.Metadata = json.RawMessage(`{}`)
+		if !req.Metadata.Null {
+			if err := validateMetadata(req.Metadata.Value); err != nil {
+				return patch, err
+			}
+			patch.Metadata = req.Metadata.Value
+		}
+	}
+	return patch, nil
+}
+
+// ValidateListAccounts parses the filters, sort order and page size of an account listing from its
+// query string. Labels may be repeated; accounts must carry all of them. The cursor is parsed by the
+// handler. It also reports whether the total count was requested.
+func ValidateListAccounts(values url.Values) (storage.AccountQuery, bool, error) {
+	query := storage.AccountQuery{Sort: storage.AccountSortCreatedAt, Limit: defaultAccountsPageSize}
+
+	query.Type = strings.TrimSpace(values.Get("type"))
+	if query.Type != "" && !accountTypes[query.Type] {
+		return query, false, ErrUnknownAccountType
+	}
+	query.Status = strings.TrimSpace(values.Get("status"))
+	if query.Status != "" && !accountStatuses[query.Status] {
+		return query, false, ErrUnknownAccountStatus
+	}
+	currency, err := validateCurrency(values.Get("currency"))
+	if err != nil {
+		return query, false, err
+	}
+	query.Currency = currency
+	if labels := values["label"]; len(labels) > 0 {
+		query.Labels, _ = dedupe(labels, nil)
+	}
+
+	if raw := strings.TrimSpace(values.Get("min_balance")); raw != "" {
+		minBalance, err := decimal.NewFromString(raw)
+		if err != nil {
+			return query, false, ErrInvalidMinBalance
+		}
+		query.MinBalance = &minBalance
+	}
+	if raw := strings.TrimSpace(values.Get("max_balance")); raw != "" {
+		maxBalance, err := decimal.NewFromString(raw)
+		if err != nil {
+			return query, false, ErrInvalidMaxBalance
+		}
+		query.MaxBalance = &maxBalance
+	}
+	if query.MinBalance != nil && query.MaxBalance != nil && query.MinBalance.GreaterThan(*query.MaxBalance) {
+		return query, false, ErrBalanceRange
+	}
+
+	if raw := strings.TrimSpace(values.Get("sort")); raw != "" {
+		sort, descending := strings.CutPrefix(raw, "-")
+		if sort != storage.AccountSortCreatedAt && sort != storage.AccountSortBalance {
+			return query, false, ErrUnknownAccountSort
+		}
+		query.Sort, query.Descending = sort, descending
+	}
+
+	if raw := strings.TrimSpace(values.Get("limit")); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			return query, false, ErrInvalidLimit
+		}
+		query.Limit = min(n, maxAccountsPageSize)
+	}
+
+	var includeTotal bool
+	if raw := strings.TrimSpace(values.Get("include_total")); raw != "" {
+		if includeTotal, err = strconv.ParseBool(raw); err != nil {
+			return query, false, ErrInvalidIncludeTotal
+		}
+	}
+	return query, includeTotal, nil
+}
+
+// validateCurrency trims and upper-cases a currency code and checks its format. An empty code is
+// returned as is.
+func validateCurrency(currency string) (string, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency != "" && !currencyPattern.MatchString(currency) {
+		return "", ErrInvalidCurrency
+	}
+	return currency, nil
+}
+
+// validateAccountText trims an account's name, owner reference and type and checks their lengths and
+// that the type, if set, is known.
+func validateAccountText(name, ownerRef, accountType string) (string, string, string, error) {
+	name = strings.TrimSpace(name)
+	ownerRef = strings.TrimSpace(ownerRef)
+	accountType = strings.TrimSpace(accountType)
+
+	if utf8.RuneCountInString(name) > maxAccountTextLength {
+		return "", "", "", ErrNameTooLong
+	}
+	if utf8.RuneCountInString(ownerRef) > maxAccountTextLength {
+		return "", "", "", ErrOwnerRefTooLong
+	}
+	if accountType != "" && !accountTypes[accountType] {
+		return "", "", "", ErrUnknownAccountType
+	}
+	return name, ownerRef, accountType, nil
+}
+
+// validateLabels trims and deduplicates labels and checks their number and length.
+func validateLabels(labels []string) ([]string, error) {
+	labels, err := dedupe(labels, func(label string) error {
+		if utf8.RuneCountInString(label) > maxLabelLength {
+			return ErrLabelTooLong
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(labels) > maxAccountLabels {
+		return nil, ErrTooManyLabels
+	}
+	return labels, nil
+}
+
+// validateMetadata checks that metadata, if set, is a JSON object of bounded size.
+func validateMetadata(metadata json.RawMessage) error {
+	if len(metadata) == 0 {
+		return nil
+	}
+	if len(metadata) > maxMetadataBytes {
+		return ErrMetadataTooLarge
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(metadata, &obj); err != nil || obj == nil {
+		return ErrInvalidMetadata
+	}
+	return nil
+}
+
+// ValidateBalanceQuery parses the as_of time of a balance query, defaulting to now. Balances are
+// only known up to now, so later times are rejected.
+func ValidateBalanceQuery(values url.Values, now time.Time) (time.Time, error) {
+	raw := strings.TrimSpace(values.Get("as_of"))
+	if raw == "" {
+		return now, nil
+	}
+	asOf, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, ErrInvalidAsOf
+	}
+	if asOf.After(now) {
+		return time.Time{}, ErrFutureAsOf
+	}
+	return asOf, nil
+}
+
+// ValidateImportMode parses the mode of an account import, reporting whether it is atomic. The mode
+// defaults to atomic.
+func ValidateImportMode(values url.Values) (bool, error) {
+	switch strings.TrimSpace(values.Get("mode")) {
+	case "", importModeAtomic:
+		return true, nil
+	case importModePartial:
+		return false, nil
+	}
+	return false, ErrInvalidImportMode
+}
+
+// ValidateProcessTransaction trims whitespace from the transaction request and checks it with
+// transfers.Validate, returning the parsed amount.
 func ValidateProcessTransaction(req *processTransactionRequest) (decimal.Decimal, error) {
 	req.SourceAccID = strings.TrimSpace(req.SourceAccID)
 	req.DestAccID = strings.TrimSpace(req.DestAccID)
 	req.Amount = strings.TrimSpace(req.Amount)
+	return transfers.Validate(req.SourceAccID, req.DestAccID, req.Amount)
+}
 
-	if req.SourceAccID == "" {
-		return decimal.Zero, ErrMissingSourceAccountID
+// ValidateCreateAPIKey checks the API key creation request for a client ID and at least one known scope.
+// Whitespace is trimmed and duplicate scopes are removed.
+func ValidateCreateAPIKey(req *createAPIKeyRequest) error {
+	req.ClientID = strings.TrimSpace(req.ClientID)
+	if req.ClientID == "" {
+		return ErrMissingClientID
 	}
 
-	if req.DestAccID == "" {
-		return decimal.Zero, ErrMissingDestAccountID
+	seen := make(map[string]bool, len(req.Scopes))
+	scopes := make([]string, 0, len(req.Scopes))
+	for _, scope := range req.Scopes {
+		scope = strings.TrimSpace(scope)
+		if !knownScopes[scope] {
+			return fmt.Errorf("%w: %q", ErrUnknownScope, scope)
+		}
+		if seen[scope] {
+			continue
+		}
+		seen[scope] = true
+		scopes = append(scopes, scope)
 	}
+	if len(scopes) == 0 {
+		return ErrMissingScopes
+	}
+	req.Scopes = scopes
+	return nil
+}
 
-	if req.Amount == "" {
-		return decimal.Zero, ErrMissingAmount
+// ValidateWebhookSubscription checks the webhook subscription request for an absolute http(s) URL and
+// at least one known event type. Whitespace is trimmed and duplicate event types and account IDs are removed.
+func ValidateWebhookSubscription(req *webhookSubscriptionRequest) error {
+	req.URL = strings.TrimSpace(req.URL)
+	if req.URL == "" {
+		return ErrMissingWebhookURL
+	}
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidWebhookURL
 	}
 
-	amt, err := decimal.NewFromString(req.Amount)
+	eventTypes, err := dedupe(req.EventTypes, func(eventType string) error {
+		if !webhookEventTypes[eventType] {
+			return fmt.Errorf("%w: %q", ErrUnknownEventType, eventType)
+		}
+		return nil
+	})
 	if err != nil {
-		return decimal.Zero, ErrInvalidAmount
+		return err
 	}
-
-	if !amt.IsPositive() {
-		return decimal.Zero, ErrNonPositiveAmount
+	if len(eventTypes) == 0 {
+		return ErrMissingEventTypes
 	}
+	req.EventTypes = eventTypes
 
-	if req.SourceAccID == req.DestAccID {
-		return decimal.Zero, ErrSameAccountTransfer
-	}
+	req.AccountIDs, _ = dedupe(req.AccountIDs, nil)
+	return nil
+}
 
-	return amt, nil
+// dedupe trims values, drops empty and duplicate ones, and checks the rest with check if it is set.
+func dedupe(values []string, check func(string) error) ([]string, error) {
+	seen := make(map[string]bool, len(values))
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" || seen[v] {
+			continue
+		}
+		if check != nil {
+			if err := check(v); err != nil {
+				return nil, err
+			}
+		}
+		seen[v] = true
+		out = append(out, v)
+	}
+	return out, nil
 }
@@ -0,0 +1,331 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+	"github.com/cursed-ninja/internal-transfers-system/internal/utils"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultDeliveriesLimit = 50
+	maxDeliveriesLimit     = 200
+)
+
+type webhookSubscriptionRequest struct {
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	AccountIDs []string `json:"account_ids"`
+}
+
+type webhookSubscriptionResponse struct {
+	storage.WebhookSubscription
+	// Secret signs deliveries to this subscription. It is only returned once, when the subscription is created.
+	Secret string `json:"secret,omitempty"`
+}
+
+// CreateWebhook handles POST /webhooks requests to register a webhook subscription for the caller.
+// The signing secret is returned once in the response.
+func (s *Server) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := utils.ContextLogger(ctx)
+	logger.Info("received CreateWebhook request")
+
+	var req webhookSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("failed to parse request body", zap.Error(err))
+		http.Error(w, "invalid JSON format", http.StatusBadRequest)
+		return
+	}
+
+	if err := ValidateWebhookSubscription(&req); err != nil {
+		logger.Error("failed to validate request", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := canSubscribe(principalFromContext(ctx), req.AccountIDs); err != nil {
+		logger.Warn("caller is not allowed to subscribe to accounts", zap.Strings("account_ids", req.AccountIDs), zap.Error(err))
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		logger.Error("failed to generate webhook secret", zap.Error(err))
+		http.Error(w, storage.ErrCreateWebhookMsg, http.StatusInternalServerError)
+		return
+	}
+
+	sub := &storage.WebhookSubscription{
+		ID:         uuid.NewString(),
+		ClientID:   utils.ClientID(ctx),
+		URL:        req.URL,
+		Secret:     secret,
+		EventTypes: req.EventTypes,
+		AccountIDs: req.AccountIDs,
+	}
+	ctx, logger = utils.LoggerWithKey(ctx, zap.String("subscription_id", sub.ID))
+
+	if err := s.store.CreateWebhookSubscription(ctx, sub); err != nil {
+		logger.Error("failed to create webhook subscription", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(webhookSubscriptionResponse{WebhookSubscription: *sub, Secret: secret}); err != nil {
+		logger.Error("failed to encode response", zap.Error(err))
+		return
+	}
+	logger.Info("webhook subscription created successfully")
+}
+
+// ListWebhooks handles GET /webhooks requests. Callers see their own subscriptions; admins see all.
+func (s *Server) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := utils.ContextLogger(ctx)
+	logger.Info("received ListWebhooks request")
+
+	owner := ""
+	if p := principalFromContext(ctx); p != nil && !p.Scopes[ScopeAdmin] {
+		owner = p.ClientID
+	}
+
+	subs, err := s.store.ListWebhookSubscriptions(ctx, owner)
+	if err != nil {
+		logger.Error("failed to list webhook subscriptions", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, logger, http.StatusOK, subs)
+}
+
+// GetWebhook handles GET /webhooks/{subscriptionID} requests.
+func (s *Server) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	logger := utils.ContextLogger(r.Context())
+	logger.Info("received GetWebhook request")
+
+	sub, ok := s.ownedWebhook(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, logger, http.StatusOK, sub)
+}
+
+// UpdateWebhook handles PUT /webhooks/{subscriptionID} requests, replacing the URL, event types
+// and account filter. The signing secret is unchanged.
+func (s *Server) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := utils.ContextLogger(ctx)
+	logger.Info("received UpdateWebhook request")
+
+	sub, ok := s.ownedWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	var req webhookSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("failed to parse request body", zap.Error(err))
+		http.Error(w, "invalid JSON format", http.StatusBadRequest)
+		return
+	}
+
+	if err := ValidateWebhookSubscription(&req); err != nil {
+		logger.Error("failed to validate request", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := canSubscribe(principalFromContext(ctx), req.AccountIDs); err != nil {
+		logger.Warn("caller is not allowed to subscribe to accounts", zap.Strings("account_ids", req.AccountIDs), zap.Error(err))
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	sub.URL = req.URL
+	sub.EventTypes = req.EventTypes
+	sub.AccountIDs = req.AccountIDs
+	if err := s.store.UpdateWebhookSubscription(ctx, sub); err != nil {
+		logger.Error("failed to update webhook subscription", zap.Error(err))
+		errorMsg := err.Error()
+		statusCode := http.StatusInternalServerError
+		if errorMsg == storage.ErrWebhookNotFound {
+			statusCode = http.StatusNotFound
+		}
+		http.Error(w, errorMsg, statusCode)
+		return
+	}
+
+	logger.Info("webhook subscription updated successfully")
+	writeJSON(w, logger, http.StatusOK, sub)
+}
+
+// DeleteWebhook handles DELETE /webhooks/{subscriptionID} requests. Pending deliveries are discarded.
+func (s *Server) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := utils.ContextLogger(ctx)
+	logger.Info("received DeleteWebhook request")
+
+	sub, ok := s.ownedWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.store.DeleteWebhookSubscription(ctx, sub.ID); err != nil {
+		logger.Error("failed to delete webhook subscription", zap.Error(err))
+		errorMsg := err.Error()
+		statusCode := http.StatusInternalServerError
+		if errorMsg == storage.ErrWebhookNotFound {
+			statusCode = http.StatusNotFound
+		}
+		http.Error(w, errorMsg, statusCode)
+		return
+	}
+
+	logger.Info("webhook subscription deleted successfully")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListWebhookDeliveries handles GET /webhooks/{subscriptionID}/deliveries requests, newest first.
+// The optional limit query parameter defaults to 50 and is capped at 200.
+func (s *Server) ListWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := utils.ContextLogger(ctx)
+	logger.Info("received ListWebhookDeliveries request")
+
+	sub, ok := s.ownedWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	limit := defaultDeliveriesLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		limit = min(n, maxDeliveriesLimit)
+	}
+
+	deliveries, err := s.store.ListWebhookDeliveries(ctx, sub.ID, limit)
+	if err != nil {
+		logger.Error("failed to list webhook deliveries", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, logger, http.StatusOK, deliveries)
+}
+
+// ListWebhookAttempts handles GET /webhooks/{subscriptionID}/deliveries/{deliveryID}/attempts requests,
+// returning every HTTP attempt made for the delivery.
+func (s *Server) ListWebhookAttempts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := utils.ContextLogger(ctx)
+	logger.Info("received ListWebhookAttempts request")
+
+	sub, ok := s.ownedWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	deliveryID, err := strconv.ParseInt(mux.Vars(r)["deliveryID"], 10, 64)
+	if err != nil {
+		http.Error(w, "delivery_id must be an integer", http.StatusBadRequest)
+		return
+	}
+	ctx, logger = utils.LoggerWithKey(ctx, zap.Int64("delivery_id", deliveryID))
+
+	delivery, err := s.store.GetWebhookDelivery(ctx, deliveryID)
+	if err == nil && delivery.SubscriptionID != sub.ID {
+		err = errors.New(storage.ErrDeliveryNotFound)
+	}
+	if err != nil {
+		logger.Error("failed to get webhook delivery", zap.Error(err))
+		errorMsg := err.Error()
+		statusCode := http.StatusInternalServerError
+		if errorMsg == storage.ErrDeliveryNotFound {
+			statusCode = http.StatusNotFound
+		}
+		http.Error(w, errorMsg, statusCode)
+		return
+	}
+
+	attempts, err := s.store.ListWebhookAttempts(ctx, deliveryID)
+	if err != nil {
+		logger.Error("failed to list webhook attempts", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, logger, http.StatusOK, attempts)
+}
+
+// ownedWebhook loads the subscription named in the URL and checks the caller owns it.
+// Subscriptions owned by other clients are reported as not found. Admins may access any subscription.
+// On failure the error response has been written and ok is false.
+func (s *Server) ownedWebhook(w http.ResponseWriter, r *http.Request) (*storage.WebhookSubscription, bool) {
+	ctx := r.Context()
+	logger := utils.ContextLogger(ctx)
+
+	subscriptionID := strings.TrimSpace(mux.Vars(r)["subscriptionID"])
+	if subscriptionID == "" {
+		logger.Error("missing subscription_id in URL path")
+		http.Error(w, "subscription_id is required in URL path", http.StatusBadRequest)
+		return nil, false
+	}
+
+	sub, err := s.store.GetWebhookSubscription(ctx, subscriptionID)
+	if err == nil {
+		if p := principalFromContext(ctx); p != nil && !p.Scopes[ScopeAdmin] && sub.ClientID != p.ClientID {
+			err = errors.New(storage.ErrWebhookNotFound)
+		}
+	}
+	if err != nil {
+		logger.Error("failed to get webhook subscription", zap.String("subscription_id", subscriptionID), zap.Error(err))
+		errorMsg := err.Error()
+		statusCode := http.StatusInternalServerError
+		if errorMsg == storage.ErrWebhookNotFound {
+			statusCode = http.StatusNotFound
+		}
+		http.Error(w, errorMsg, statusCode)
+		return nil, false
+	}
+	return sub, true
+}
+
+// canSubscribe checks that the principal may receive events for the given accounts. Subscribers receive
+// account data, so they need the accounts:read scope; account-restricted callers must also name accounts
+// they can read, an empty filter meaning every account.
+func canSubscribe(p *principal, accountIDs []string) error {
+	if p == nil {
+		return nil
+	}
+	if !p.hasScopes(ScopeAccountsRead) {
+		return ErrInsufficientScope
+	}
+	if !p.AccountRestricted {
+		return nil
+	}
+	if len(accountIDs) == 0 {
+		return ErrAccountForbidden
+	}
+	for _, id := range accountIDs {
+		if !p.canRead(id) {
+			return ErrAccountForbidden
+		}
+	}
+	return nil
+}
@@ -0,0 +1,327 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/config"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage/mocks"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestCreateWebhook validates subscription creation, request validation and account restrictions.
+func TestCreateWebhook(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		principal      *principal
+		prepare        func(m *mocks.MockStorage)
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name: "success",
+			body: `{"url":"https://example.com/hook","event_types":["transfer.completed","transfer.completed"],"account_ids":["acc-1"]}`,
+			prepare: func(m *mocks.MockStorage) {
+				m.EXPECT().CreateWebhookSubscription(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ any, sub *storage.WebhookSubscription) error {
+						assert.Equal(t, []string{storage.EventTransferCompleted}, sub.EventTypes)
+						assert.True(t, strings.HasPrefix(sub.Secret, webhookSecretPrefix))
+						return nil
+					})
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "invalid url",
+			body:           `{"url":"ftp://example.com","event_types":["transfer.completed"]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  ErrInvalidWebhookURL.Error(),
+		},
+		{
+			name:           "unknown event type",
+			body:           `{"url":"https://example.com/hook","event_types":["account.deleted"]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  `{"error":"request body does not match schema","fields":[{"field":"event_types[0]","message":"must be one of \"account.created\", \"account.updated\", \"account.closed\", \"transfer.completed\""}]}`,
+		},
+		{
+			name:           "missing event types",
+			body:           `{"url":"https://example.com/hook"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  `{"error":"request body does not match schema","fields":[{"field":"event_types","message":"is required"}]}`,
+		},
+		{
+			name:           "key without accounts:read",
+			body:           `{"url":"https://example.com/hook","event_types":["transfer.completed"]}`,
+			principal:      &principal{ClientID: "hooks", Scopes: map[string]bool{ScopeWebhooksManage: true}},
+			expectedStatus: http.StatusForbidden,
+			expectedError:  ErrInsufficientScope.Error(),
+		},
+		{
+			name: "restricted caller without account filter",
+			body: `{"url":"https://example.com/hook","event_types":["transfer.completed"]}`,
+			principal: &principal{
+				ClientID:          "user-1",
+				Scopes:            map[string]bool{ScopeWebhooksManage: true, ScopeAccountsRead: true},
+				AccountRestricted: true,
+				ReadAccounts:      map[string]bool{"acc-1": true},
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedError:  ErrAccountForbidden.Error(),
+		},
+		{
+			name: "restricted caller with foreign account",
+			body: `{"url":"https://example.com/hook","event_types":["transfer.completed"],"account_ids":["acc-1","acc-2"]}`,
+			principal: &principal{
+				ClientID:          "user-1",
+				Scopes:            map[string]bool{ScopeWebhooksManage: true, ScopeAccountsRead: true},
+				AccountRestricted: true,
+				ReadAccounts:      map[string]bool{"acc-1": true},
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedError:  ErrAccountForbidden.Error(),
+		},
+		{
+			name: "storage error",
+			body: `{"url":"https://example.com/hook","event_types":["account.created"]}`,
+			prepare: func(m *mocks.MockStorage) {
+				m.EXPECT().CreateWebhookSubscription(gomock.Any(), gomock.Any()).Return(errors.New(storage.ErrCreateWebhookMsg))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedError:  storage.ErrCreateWebhookMsg,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockStorage := mocks.NewMockStorage(mockCtrl)
+			if tc.prepare != nil {
+				tc.prepare(mockStorage)
+			}
+			s := Server{cfg: &config.Config{}, store: mockStorage}
+
+			r := mux.NewRouter()
+			s.BindRoutes(r)
+
+			req := httptest.NewRequest(http.MethodPost, "/webhooks", bytes.NewBufferString(tc.body))
+			if tc.principal != nil {
+				req = req.WithContext(withPrincipal(req.Context(), tc.principal))
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedError != "" {
+				assert.Equal(t, tc.expectedError, strings.TrimSpace(w.Body.String()))
+			}
+			if tc.expectedStatus == http.StatusCreated {
+				var resp map[string]any
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				assert.NotEmpty(t, resp["secret"])
+				assert.NotEmpty(t, resp["id"])
+			}
+		})
+	}
+}
+
+// TestWebhookOwnership validates that subscriptions owned by other clients are hidden from non-admins.
+func TestWebhookOwnership(t *testing.T) {
+	sub := &storage.WebhookSubscription{ID: "sub-1", ClientID: "payroll", URL: "https://example.com/hook", Secret: "whsec", EventTypes: []string{storage.EventTransferCompleted}}
+
+	tests := []struct {
+		name           string
+		principal      *principal
+		expectedStatus int
+	}{
+		{
+			name:           "owner",
+			principal:      &principal{ClientID: "payroll"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "admin",
+			principal:      &principal{ClientID: "ops", Scopes: map[string]bool{ScopeAdmin: true}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "other client",
+			principal:      &principal{ClientID: "billing"},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockStorage := mocks.NewMockStorage(mockCtrl)
+			mockStorage.EXPECT().GetWebhookSubscription(gomock.Any(), "sub-1").Return(sub, nil)
+			s := Server{cfg: &config.Config{}, store: mockStorage}
+
+			r := mux.NewRouter()
+			s.BindRoutes(r)
+
+			req := httptest.NewRequest(http.MethodGet, "/webhooks/sub-1", nil)
+			req = req.WithContext(withPrincipal(req.Context(), tc.principal))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.NotContains(t, w.Body.String(), "whsec")
+		})
+	}
+}
+
+// TestUpdateWebhook validates that replacing a subscription's account filter is subject to the same
+// checks as creating one.
+func TestUpdateWebhook(t *testing.T) {
+	body := `{"url":"https://example.com/hook","event_types":["transfer.completed"]}`
+
+	tests := []struct {
+		name           string
+		principal      *principal
+		prepare        func(m *mocks.MockStorage)
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:      "success",
+			principal: &principal{ClientID: "payroll", Scopes: map[string]bool{ScopeWebhooksManage: true, ScopeAccountsRead: true}},
+			prepare: func(m *mocks.MockStorage) {
+				m.EXPECT().UpdateWebhookSubscription(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "key without accounts:read",
+			principal:      &principal{ClientID: "payroll", Scopes: map[string]bool{ScopeWebhooksManage: true}},
+			expectedStatus: http.StatusForbidden,
+			expectedError:  ErrInsufficientScope.Error(),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockStorage := mocks.NewMockStorage(mockCtrl)
+			mockStorage.EXPECT().GetWebhookSubscription(gomock.Any(), "sub-1").
+				Return(&storage.WebhookSubscription{ID: "sub-1", ClientID: "payroll", AccountIDs: []string{"acc-1"}}, nil)
+			if tc.prepare != nil {
+				tc.prepare(mockStorage)
+			}
+			s := Server{cfg: &config.Config{}, store: mockStorage}
+
+			r := mux.NewRouter()
+			s.BindRoutes(r)
+
+			req := httptest.NewRequest(http.MethodPut, "/webhooks/sub-1", bytes.NewBufferString(body))
+			req = req.WithContext(withPrincipal(req.Context(), tc.principal))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedError != "" {
+				assert.Equal(t, tc.expectedError, strings.TrimSpace(w.Body.String()))
+			}
+		})
+	}
+}
+
+// TestListWebhookAttempts validates the delivery attempt log, including deliveries of other subscriptions.
+func TestListWebhookAttempts(t *testing.T) {
+	sub := &storage.WebhookSubscription{ID: "sub-1", EventTypes: []string{storage.EventTransferCompleted}}
+
+	tests := []struct {
+		name           string
+		path           string
+		prepare        func(m *mocks.MockStorage)
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			path: "/webhooks/sub-1/deliveries/7/attempts",
+			prepare: func(m *mocks.MockStorage) {
+				m.EXPECT().GetWebhookDelivery(gomock.Any(), int64(7)).Return(&storage.WebhookDelivery{ID: 7, SubscriptionID: "sub-1"}, nil)
+				m.EXPECT().ListWebhookAttempts(gomock.Any(), int64(7)).Return([]storage.WebhookAttempt{{ID: 1, DeliveryID: 7}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "delivery of another subscription",
+			path: "/webhooks/sub-1/deliveries/7/attempts",
+			prepare: func(m *mocks.MockStorage) {
+				m.EXPECT().GetWebhookDelivery(gomock.Any(), int64(7)).Return(&storage.WebhookDelivery{ID: 7, SubscriptionID: "sub-2"}, nil)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "invalid delivery id",
+			path:           "/webhooks/sub-1/deliveries/abc/attempts",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockStorage := mocks.NewMockStorage(mockCtrl)
+			mockStorage.EXPECT().GetWebhookSubscription(gomock.Any(), "sub-1").Return(sub, nil)
+			if tc.prepare != nil {
+				tc.prepare(mockStorage)
+			}
+			s := Server{cfg: &config.Config{}, store: mockStorage}
+
+			r := mux.NewRouter()
+			s.BindRoutes(r)
+
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+		})
+	}
+}
+
+// TestListWebhookDeliveries validates the limit parameter.
+func TestListWebhookDeliveries(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		expectedLimit  int
+		expectedStatus int
+	}{
+		{name: "default limit", expectedLimit: defaultDeliveriesLimit, expectedStatus: http.StatusOK},
+		{name: "capped limit", query: "?limit=1000", expectedLimit: maxDeliveriesLimit, expectedStatus: http.StatusOK},
+		{name: "invalid limit", query: "?limit=-1", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockStorage := mocks.NewMockStorage(mockCtrl)
+			mockStorage.EXPECT().GetWebhookSubscription(gomock.Any(), "sub-1").Return(&storage.WebhookSubscription{ID: "sub-1"}, nil)
+			if tc.expectedStatus == http.StatusOK {
+				mockStorage.EXPECT().ListWebhookDeliveries(gomock.Any(), "sub-1", tc.expectedLimit).Return([]storage.WebhookDelivery{}, nil)
+			}
+			s := Server{cfg: &config.Config{}, store: mockStorage}
+
+			r := mux.NewRouter()
+			s.BindRoutes(r)
+
+			req := httptest.NewRequest(http.MethodGet, "/webhooks/sub-1/deliveries"+tc.query, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+		})
+	}
+}
@@ -0,0 +1,103 @@
+// Package snapshots periodically records account balances so point-in-time balance queries stay fast.
+package snapshots
+
+import (
+	"context"
+	"time"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/config"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultInterval     = 24 * time.Hour
+	defaultSettleDelay  = time.Minute
+	defaultPollInterval = time.Minute
+)
+
+// Store is the subset of storage.Storage the snapshotter needs.
+type Store interface {
+	CreateBalanceSnapshots(ctx context.Context, asOf time.Time) (int64, error)
+}
+
+// Snapshotter records every account's balance at each interval boundary, so point-in-time balance
+// queries only need to add up the transfers since the latest snapshot.
+//
+// A boundary is snapshotted once the settle delay has passed after it, leaving time for transfers
+// timestamped before the boundary to commit. If a transaction that started before the boundary is
+// still open after that, the boundary is retried on the next poll, or skipped once a later boundary
+// settles. Snapshots are idempotent, so restarts and concurrent snapshotters are safe.
+type Snapshotter struct {
+	store    Store
+	interval time.Duration
+	settle   time.Duration
+	poll     time.Duration
+	logger   *zap.Logger
+	now      func() time.Time
+	// last is the most recent boundary snapshotted by this process.
+	last time.Time
+}
+
+// NewSnapshotter creates a Snapshotter writing to store.
+func NewSnapshotter(store Store, cfg *config.SnapshotsConfig, logger *zap.Logger) *Snapshotter {
+	s := &Snapshotter{
+		store:    store,
+		interval: cfg.Interval,
+		settle:   cfg.SettleDelay,
+		poll:     cfg.PollInterval,
+		logger:   logger,
+		now:      time.Now,
+	}
+	if s.interval <= 0 {
+		s.interval = defaultInterval
+	}
+	if s.settle <= 0 {
+		s.settle = defaultSettleDelay
+	}
+	if s.poll <= 0 {
+		s.poll = defaultPollInterval
+	}
+	return s
+}
+
+// Run snapshots each boundary as it settles until ctx is cancelled.
+func (s *Snapshotter) Run(ctx context.Context) {
+	s.logger.Info("balance snapshotter started", zap.Duration("interval", s.interval), zap.Duration("settle_delay", s.settle))
+	ticker := time.NewTicker(s.poll)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.RunOnce(ctx); err != nil {
+			s.logger.Error("balance snapshot failed", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			s.logger.Info("balance snapshotter stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce snapshots the latest settled boundary unless this snapshotter already has, and returns how
+// many account snapshots were recorded.
+func (s *Snapshotter) RunOnce(ctx context.Context) (int64, error) {
+	boundary := s.now().Add(-s.settle).UTC().Truncate(s.interval)
+	if boundary.Equal(s.last) {
+		return 0, nil
+	}
+	created, err := s.store.CreateBalanceSnapshots(ctx, boundary)
+	if err != nil {
+		if err.Error() == storage.ErrSnapshotUnsettledMsg {
+			s.logger.Info("balance snapshot postponed", zap.Time("as_of", boundary), zap.String("reason", err.Error()))
+			return 0, nil
+		}
+		return 0, err
+	}
+	s.last = boundary
+	if created > 0 {
+		s.logger.Info("balance snapshots recorded", zap.Time("as_of", boundary), zap.Int64("accounts", created))
+	}
+	return created, nil
+}
@@ -0,0 +1,64 @@
+package snapshots
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/config"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+// TestSnapshotterRunOnce validates boundary selection, the settle delay, skipping boundaries already
+// snapshotted and retrying after store errors or open transactions.
+func TestSnapshotterRunOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mocks.NewMockStorage(ctrl)
+
+	s := NewSnapshotter(store, &config.SnapshotsConfig{Interval: time.Hour, SettleDelay: 5 * time.Minute}, zap.NewNop())
+	now := time.Date(2025, 6, 1, 10, 3, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	// Within the settle delay of 10:00, so 09:00 is the latest settled boundary.
+	store.EXPECT().CreateBalanceSnapshots(gomock.Any(), time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)).Return(int64(4), nil)
+	created, err := s.RunOnce(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), created)
+
+	// The same boundary is not snapshotted twice.
+	now = now.Add(time.Minute)
+	created, err = s.RunOnce(context.Background())
+	assert.NoError(t, err)
+	assert.Zero(t, created)
+
+	// Failures are retried on the next run.
+	now = time.Date(2025, 6, 1, 10, 5, 0, 0, time.UTC)
+	next := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	store.EXPECT().CreateBalanceSnapshots(gomock.Any(), next).Return(int64(0), errors.New("db error"))
+	_, err = s.RunOnce(context.Background())
+	assert.EqualError(t, err, "db error")
+
+	// A boundary with an older transaction still open is postponed, not marked done.
+	store.EXPECT().CreateBalanceSnapshots(gomock.Any(), next).Return(int64(0), errors.New(storage.ErrSnapshotUnsettledMsg))
+	created, err = s.RunOnce(context.Background())
+	assert.NoError(t, err)
+	assert.Zero(t, created)
+
+	store.EXPECT().CreateBalanceSnapshots(gomock.Any(), next).Return(int64(4), nil)
+	created, err = s.RunOnce(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), created)
+}
+
+// TestNewSnapshotterDefaults validates that zero config values fall back to the defaults.
+func TestNewSnapshotterDefaults(t *testing.T) {
+	s := NewSnapshotter(nil, &config.SnapshotsConfig{}, zap.NewNop())
+	assert.Equal(t, defaultInterval, s.interval)
+	assert.Equal(t, defaultSettleDelay, s.settle)
+	assert.Equal(t, defaultPollInterval, s.poll)
+}
@@ -0,0 +1,263 @@
+package statements
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+	"github.com/shopspring/decimal"
+)
+
+// camt053Namespace is the XML namespace of ISO 20022 BankToCustomerStatementV02 documents.
+const camt053Namespace = "urn:iso:std:iso:20022:tech:xsd:camt.053.001.02"
+
+// Limits the camt.053 schema puts on the identifiers the exporter writes.
+const (
+	camtMaxAccountID = 34
+	camtMaxInfo      = 500
+)
+
+// ISO 20022 codes written by the exporter.
+const (
+	camtCredit         = "CRDT"
+	camtDebit          = "DBIT"
+	camtOpeningBooked  = "OPBD"
+	camtClosingBooked  = "CLBD"
+	camtBooked         = "BOOK"
+	camtDomainPayments = "PMNT"
+	// Received and issued credit transfers; transfers between accounts are book transfers.
+	camtReceivedCreditTransfers = "RCDT"
+	camtIssuedCreditTransfers   = "ICDT"
+	camtBookTransfer            = "BOOK"
+)
+
+// now returns the creation time written into generated documents; tests replace it.
+var now = time.Now
+
+type camtAmount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type camtGroupHeader struct {
+	MessageID string `xml:"MsgId"`
+	CreatedAt string `xml:"CreDtTm"`
+}
+
+type camtPeriod struct {
+	From string `xml:"FrDtTm"`
+	To   string `xml:"ToDtTm"`
+}
+
+type camtAccount struct {
+	ID       string `xml:"Id>Othr>Id"`
+	Currency string `xml:"Ccy"`
+}
+
+type camtBalance struct {
+	Type      string     `xml:"Tp>CdOrPrtry>Cd"`
+	Amount    camtAmount `xml:"Amt"`
+	CdtDbtInd string     `xml:"CdtDbtInd"`
+	DateTime  string     `xml:"Dt>DtTm"`
+}
+
+type camtSummary struct {
+	Count        int    `xml:"TtlNtries>NbOfNtries"`
+	Sum          string `xml:"TtlNtries>Sum"`
+	Net          string `xml:"TtlNtries>TtlNetNtryAmt"`
+	NetCdtDbtInd string `xml:"TtlNtries>CdtDbtInd"`
+	CreditCount  int    `xml:"TtlCdtNtries>NbOfNtries"`
+	CreditSum    string `xml:"TtlCdtNtries>Sum"`
+	DebitCount   int    `xml:"TtlDbtNtries>NbOfNtries"`
+	DebitSum     string `xml:"TtlDbtNtries>Sum"`
+}
+
+type camtEntry struct {
+	Reference      string      `xml:"NtryRef"`
+	Amount         camtAmount  `xml:"Amt"`
+	CdtDbtInd      string      `xml:"CdtDbtInd"`
+	Status         string      `xml:"Sts"`
+	BookingDate    string      `xml:"BookgDt>DtTm"`
+	ValueDate      string      `xml:"ValDt>DtTm"`
+	ServicerRef    string      `xml:"AcctSvcrRef"`
+	Domain         string      `xml:"BkTxCd>Domn>Cd"`
+	Family         string      `xml:"BkTxCd>Domn>Fmly>Cd"`
+	SubFamily      string      `xml:"BkTxCd>Domn>Fmly>SubFmlyCd"`
+	TxServicerRef  string      `xml:"NtryDtls>TxDtls>Refs>AcctSvcrRef"`
+	RelatedParties camtParties `xml:"NtryDtls>TxDtls>RltdPties"`
+	Info           string      `xml:"NtryDtls>TxDtls>AddtlTxInf,omitempty"`
+}
+
+// camtParties names the counterparty of an entry: the debtor of a credit or the creditor of a debit.
+type camtParties struct {
+	DebtorAccount   *camtPartyAccount `xml:"DbtrAcct"`
+	CreditorAccount *camtPartyAccount `xml:"CdtrAcct"`
+}
+
+type camtPartyAccount struct {
+	ID string `xml:"Id>Othr>Id"`
+}
+
+type camt053Writer struct {
+	w        io.Writer
+	enc      *xml.Encoder
+	currency string
+}
+
+// NewCamt053Writer returns a writer rendering a statement as an ISO 20022 camt.053.001.02 bank to
+// customer statement. The opening and closing balances are booked balances (OPBD and CLBD), each
+// transfer is a booked entry referenced by its transaction ID and amounts have the decimal places of
+// the account's currency.
+func NewCamt053Writer(w io.Writer) storage.StatementWriter {
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	return &camt053Writer{w: w, enc: enc}
+}
+
+func (c *camt053Writer) WriteHeader(s *storage.Statement) error {
+	if len(s.AccountID) > camtMaxAccountID {
+		return fmt.Errorf("account ID is longer than the %d characters camt.053 allows", camtMaxAccountID)
+	}
+	c.currency = s.Currency
+	createdAt := now()
+	stmtID := statementID(s)
+
+	if _, err := io.WriteString(c.w, xml.Header); err != nil {
+		return err
+	}
+	start := []xml.StartElement{
+		{Name: xml.Name{Local: "Document"}, Attr: []xml.Attr{{Name: xml.Name{Local: "xmlns"}, Value: camt053Namespace}}},
+		{Name: xml.Name{Local: "BkToCstmrStmt"}},
+	}
+	for _, el := range start {
+		if err := c.enc.EncodeToken(el); err != nil {
+			return err
+		}
+	}
+	header := camtGroupHeader{
+		// A statement's ID and creation time identify the message.
+		MessageID: createdAt.UTC().Format("20060102150405") + "-" + stmtID[:20],
+		CreatedAt: camtTime(createdAt),
+	}
+	if err := c.enc.EncodeElement(header, camtStart("GrpHdr")); err != nil {
+		return err
+	}
+	if err := c.enc.EncodeToken(camtStart("Stmt")); err != nil {
+		return err
+	}
+	for _, el := range []struct {
+		name  string
+		value any
+	}{
+		{name: "Id", value: stmtID},
+		{name: "CreDtTm", value: camtTime(createdAt)},
+		{name: "FrToDt", value: camtPeriod{From: camtTime(s.From), To: camtTime(s.To)}},
+		{name: "Acct", value: camtAccount{ID: s.AccountID, Currency: s.Currency}},
+	} {
+		if err := c.enc.EncodeElement(el.value, camtStart(el.name)); err != nil {
+			return err
+		}
+	}
+	for _, bal := range []camtBalance{
+		c.balance(camtOpeningBooked, s.OpeningBalance, s.From),
+		c.balance(camtClosingBooked, s.ClosingBalance, s.To),
+	} {
+		if err := c.enc.EncodeElement(bal, camtStart("Bal")); err != nil {
+			return err
+		}
+	}
+	net := s.TotalCredits.Sub(s.TotalDebits)
+	summary := camtSummary{
+		Count:        s.EntryCount(),
+		Sum:          c.amount(s.TotalCredits.Add(s.TotalDebits)),
+		Net:          c.amount(net.Abs()),
+		NetCdtDbtInd: creditDebit(net),
+		CreditCount:  s.CreditCount,
+		CreditSum:    c.amount(s.TotalCredits),
+		DebitCount:   s.DebitCount,
+		DebitSum:     c.amount(s.TotalDebits),
+	}
+	return c.enc.EncodeElement(summary, camtStart("TxsSummry"))
+}
+
+func (c *camt053Writer) WriteEntry(e storage.StatementEntry) error {
+	id := strconv.FormatInt(e.TransactionID, 10)
+	entry := camtEntry{
+		Reference:     id,
+		Amount:        camtAmount{Currency: c.currency, Value: c.amount(e.Amount.Abs())},
+		CdtDbtInd:     creditDebit(e.Amount),
+		Status:        camtBooked,
+		BookingDate:   camtTime(e.CreatedAt),
+		ValueDate:     camtTime(e.CreatedAt),
+		ServicerRef:   id,
+		Domain:        camtDomainPayments,
+		SubFamily:     camtBookTransfer,
+		TxServicerRef: id,
+	}
+	if e.Amount.IsNegative() {
+		entry.Family = camtIssuedCreditTransfers
+		entry.RelatedParties.CreditorAccount = &camtPartyAccount{ID: e.CounterpartyID}
+	} else {
+		entry.Family = camtReceivedCreditTransfers
+		entry.RelatedParties.DebtorAccount = &camtPartyAccount{ID: e.CounterpartyID}
+	}
+	if e.RequestID != "" {
+		entry.Info = truncate("Request ID "+e.RequestID, camtMaxInfo)
+	}
+	return c.enc.EncodeElement(entry, camtStart("Ntry"))
+}
+
+func (c *camt053Writer) WriteFooter(*storage.Statement) error {
+	for _, name := range []string{"Stmt", "BkToCstmrStmt", "Document"} {
+		if err := c.enc.EncodeToken(xml.EndElement{Name: xml.Name{Local: name}}); err != nil {
+			return err
+		}
+	}
+	if err := c.enc.Flush(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(c.w, "\n")
+	return err
+}
+
+// balance returns a booked balance of the given type at the given time.
+func (c *camt053Writer) balance(balanceType string, amount decimal.Decimal, at time.Time) camtBalance {
+	return camtBalance{
+		Type:      balanceType,
+		Amount:    camtAmount{Currency: c.currency, Value: c.amount(amount.Abs())},
+		CdtDbtInd: creditDebit(amount),
+		DateTime:  camtTime(at),
+	}
+}
+
+func (c *camt053Writer) amount(amount decimal.Decimal) string {
+	return currencyAmount(amount, c.currency)
+}
+
+// creditDebit returns the ISO 20022 credit or debit indicator of a signed amount; zero is a credit.
+func creditDebit(amount decimal.Decimal) string {
+	if amount.IsNegative() {
+		return camtDebit
+	}
+	return camtCredit
+}
+
+func camtStart(name string) xml.StartElement {
+	return xml.StartElement{Name: xml.Name{Local: name}}
+}
+
+func camtTime(at time.Time) string {
+	return at.UTC().Format(time.RFC3339Nano)
+}
+
+// truncate shortens s to at most limit runes.
+func truncate(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit])
+}
@@ -0,0 +1,202 @@
+package statements
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+	"github.com/cursed-ninja/internal-transfers-system/internal/xmltest"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// camt053Schema is the camt.053.001.02 schema generated documents are validated against.
+const camt053Schema = "testdata/camt.053.001.02.xsd"
+
+// camtDocument is the part of a camt.053 document the tests read back.
+type camtDocument struct {
+	XMLName   xml.Name `xml:"urn:iso:std:iso:20022:tech:xsd:camt.053.001.02 Document"`
+	MessageID string   `xml:"BkToCstmrStmt>GrpHdr>MsgId"`
+	Statement struct {
+		ID       string `xml:"Id"`
+		Account  string `xml:"Acct>Id>Othr>Id"`
+		Currency string `xml:"Acct>Ccy"`
+		Balances []struct {
+			Type      string     `xml:"Tp>CdOrPrtry>Cd"`
+			Amount    camtAmount `xml:"Amt"`
+			CdtDbtInd string     `xml:"CdtDbtInd"`
+			DateTime  string     `xml:"Dt>DtTm"`
+		} `xml:"Bal"`
+		Summary struct {
+			Count     int    `xml:"TtlNtries>NbOfNtries"`
+			Sum       string `xml:"TtlNtries>Sum"`
+			Net       string `xml:"TtlNtries>TtlNetNtryAmt"`
+			CreditSum string `xml:"TtlCdtNtries>Sum"`
+			DebitSum  string `xml:"TtlDbtNtries>Sum"`
+		} `xml:"TxsSummry"`
+		Entries []struct {
+			Reference string     `xml:"NtryRef"`
+			Amount    camtAmount `xml:"Amt"`
+			CdtDbtInd string     `xml:"CdtDbtInd"`
+			Family    string     `xml:"BkTxCd>Domn>Fmly>Cd"`
+			Debtor    string     `xml:"NtryDtls>TxDtls>RltdPties>DbtrAcct>Id>Othr>Id"`
+			Creditor  string     `xml:"NtryDtls>TxDtls>RltdPties>CdtrAcct>Id>Othr>Id"`
+			Info      string     `xml:"NtryDtls>TxDtls>AddtlTxInf"`
+		} `xml:"Ntry"`
+	} `xml:"BkToCstmrStmt>Stmt"`
+}
+
+// fixNow makes generated documents carry a fixed creation time for the duration of the test.
+func fixNow(t *testing.T) {
+	t.Helper()
+	now = func() time.Time { return time.Date(2025, 7, 2, 8, 30, 0, 0, time.UTC) }
+	t.Cleanup(func() { now = time.Now })
+}
+
+// TestCamt053Writer validates the balances, entries and amounts of a generated statement.
+func TestCamt053Writer(t *testing.T) {
+	fixNow(t)
+	f, ok := Lookup("camt053")
+	require.True(t, ok)
+	assert.Equal(t, camtMaxAccountID, f.MaxAccountIDLength)
+
+	out := render(t, f)
+	assert.True(t, strings.HasPrefix(out, xml.Header))
+
+	var doc camtDocument
+	require.NoError(t, xml.Unmarshal([]byte(out), &doc))
+	stmt := doc.Statement
+	assert.Equal(t, "20250702083000-"+stmt.ID[:20], doc.MessageID)
+	assert.Len(t, stmt.ID, 32)
+	assert.Equal(t, "acc-1", stmt.Account)
+	assert.Equal(t, "EUR", stmt.Currency)
+
+	require.Len(t, stmt.Balances, 2)
+	assert.Equal(t, "OPBD", stmt.Balances[0].Type)
+	assert.Equal(t, camtAmount{Currency: "EUR", Value: "100.00"}, stmt.Balances[0].Amount)
+	assert.Equal(t, "2025-06-01T00:00:00Z", stmt.Balances[0].DateTime)
+	assert.Equal(t, "CLBD", stmt.Balances[1].Type)
+	assert.Equal(t, camtAmount{Currency: "EUR", Value: "119.50"}, stmt.Balances[1].Amount)
+	assert.Equal(t, "2025-07-01T00:00:00Z", stmt.Balances[1].DateTime)
+
+	assert.Equal(t, 2, stmt.Summary.Count)
+	assert.Equal(t, "80.50", stmt.Summary.Sum)
+	assert.Equal(t, "19.50", stmt.Summary.Net)
+	assert.Equal(t, "50.00", stmt.Summary.CreditSum)
+	assert.Equal(t, "30.50", stmt.Summary.DebitSum)
+
+	require.Len(t, stmt.Entries, 2)
+	credit, debit := stmt.Entries[0], stmt.Entries[1]
+	assert.Equal(t, "7", credit.Reference)
+	assert.Equal(t, camtAmount{Currency: "EUR", Value: "50.00"}, credit.Amount)
+	assert.Equal(t, "CRDT", credit.CdtDbtInd)
+	assert.Equal(t, "RCDT", credit.Family)
+	assert.Equal(t, "acc-2", credit.Debtor)
+	assert.Empty(t, credit.Creditor)
+	assert.Equal(t, "Request ID req-7", credit.Info)
+
+	assert.Equal(t, "9", debit.Reference)
+	assert.Equal(t, camtAmount{Currency: "EUR", Value: "30.50"}, debit.Amount)
+	assert.Equal(t, "DBIT", debit.CdtDbtInd)
+	assert.Equal(t, "ICDT", debit.Family)
+	assert.Equal(t, "acc-3", debit.Creditor)
+	assert.Empty(t, debit.Debtor)
+	assert.Empty(t, debit.Info)
+
+	report, err := xmltest.Validate(t, camt053Schema, out)
+	assert.NoError(t, err, report)
+}
+
+// TestCamt053WriterSchema validates generated statements against the camt.053 schema.
+func TestCamt053WriterSchema(t *testing.T) {
+	fixNow(t)
+	f, _ := Lookup("camt053")
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		stmt    *storage.Statement
+		entries []storage.StatementEntry
+	}{
+		{
+			name: "no entries",
+			stmt: &storage.Statement{
+				AccountID: "acc-1", Currency: "USD", From: from, To: from.Add(24 * time.Hour),
+				OpeningBalance: decimal.Zero, ClosingBalance: decimal.Zero,
+			},
+		},
+		{
+			name: "overdrawn account in a currency without decimals",
+			stmt: &storage.Statement{
+				AccountID: strings.Repeat("a", camtMaxAccountID), Currency: "JPY", From: from, To: from.Add(time.Hour),
+				OpeningBalance: decimal.RequireFromString("-500"), ClosingBalance: decimal.RequireFromString("-1500"),
+				TotalDebits: decimal.RequireFromString("1000"), DebitCount: 1,
+			},
+			entries: []storage.StatementEntry{
+				{
+					TransactionID: 1, CreatedAt: from.Add(time.Minute), CounterpartyID: "acc-2",
+					Amount: decimal.RequireFromString("-1000"), Balance: decimal.RequireFromString("-1500"),
+					RequestID: strings.Repeat("r", 600),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := f.NewWriter(&buf)
+			require.NoError(t, w.WriteHeader(tt.stmt))
+			for _, e := range tt.entries {
+				require.NoError(t, w.WriteEntry(e))
+			}
+			require.NoError(t, w.WriteFooter(tt.stmt))
+
+			report, err := xmltest.Validate(t, camt053Schema, buf.String())
+			assert.NoError(t, err, report)
+		})
+	}
+}
+
+// TestCamt053SchemaRejects validates that the schema catches documents the exporter must not write.
+func TestCamt053SchemaRejects(t *testing.T) {
+	fixNow(t)
+	f, _ := Lookup("camt053")
+	out := render(t, f)
+
+	tests := []struct {
+		name    string
+		old     string
+		new     string
+		message string
+	}{
+		{name: "unknown balance type", old: "<Cd>OPBD</Cd>", new: "<Cd>OPEN</Cd>", message: "Cd"},
+		{name: "signed amount", old: ">30.50</Amt>", new: ">-30.50</Amt>", message: "Amt"},
+		{name: "missing currency", old: `<Amt Ccy="EUR">50.00</Amt>`, new: "<Amt>50.00</Amt>", message: "Ccy"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Contains(t, out, tt.old)
+			report, err := xmltest.Validate(t, camt053Schema, strings.Replace(out, tt.old, tt.new, 1))
+			assert.Error(t, err)
+			assert.Contains(t, report, tt.message)
+		})
+	}
+}
+
+// TestCamt053WriterAccountTooLong validates account IDs camt.053 cannot carry are refused before
+// anything is written.
+func TestCamt053WriterAccountTooLong(t *testing.T) {
+	stmt, _ := sampleStatement()
+	stmt.AccountID = strings.Repeat("a", camtMaxAccountID+1)
+
+	var buf bytes.Buffer
+	err := NewCamt053Writer(&buf).WriteHeader(stmt)
+	assert.EqualError(t, err, "account ID is longer than the 34 characters camt.053 allows")
+	assert.Zero(t, buf.Len())
+}
@@ -0,0 +1,56 @@
+package statements
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+)
+
+// csvHeader names the columns of a CSV statement. The first row after it is the opening balance and
+// the last the closing balance; the rows between are transfers.
+var csvHeader = []string{"type", "time", "transaction_id", "counterparty_account_id", "amount", "balance", "currency", "request_id"}
+
+// Row types of a CSV statement.
+const (
+	csvOpening = "opening"
+	csvEntry   = "entry"
+	csvClosing = "closing"
+)
+
+type csvWriter struct {
+	w        *csv.Writer
+	currency string
+}
+
+// NewCSVWriter returns a writer rendering a statement as CSV with one row per transfer, between rows
+// carrying the opening and closing balances. Amounts are signed: credits positive, debits negative.
+func NewCSVWriter(w io.Writer) storage.StatementWriter {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (c *csvWriter) WriteHeader(s *storage.Statement) error {
+	c.currency = s.Currency
+	if err := c.w.Write(csvHeader); err != nil {
+		return err
+	}
+	return c.write(csvOpening, s.From, "", "", "", s.OpeningBalance.String(), "")
+}
+
+func (c *csvWriter) WriteEntry(e storage.StatementEntry) error {
+	return c.write(csvEntry, e.CreatedAt, strconv.FormatInt(e.TransactionID, 10), e.CounterpartyID, e.Amount.String(), e.Balance.String(), e.RequestID)
+}
+
+func (c *csvWriter) WriteFooter(s *storage.Statement) error {
+	if err := c.write(csvClosing, s.To, "", "", "", s.ClosingBalance.String(), ""); err != nil {
+		return err
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvWriter) write(rowType string, at time.Time, transactionID, counterparty, amount, balance, requestID string) error {
+	return c.w.Write([]string{rowType, at.UTC().Format(time.RFC3339Nano), transactionID, counterparty, amount, balance, c.currency, requestID})
+}
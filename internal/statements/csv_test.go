@@ -0,0 +1,19 @@
+package statements
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCSVWriter validates the CSV columns, the opening and closing rows and signed amounts.
+func TestCSVWriter(t *testing.T) {
+	f, _ := Lookup("csv")
+
+	expected := "type,time,transaction_id,counterparty_account_id,amount,balance,currency,request_id\n" +
+		"opening,2025-06-01T00:00:00Z,,,,100,EUR,\n" +
+		"entry,2025-06-01T01:00:00Z,7,acc-2,50,150,EUR,req-7\n" +
+		"entry,2025-06-01T02:00:00Z,9,acc-3,-30.5,119.5,EUR,\n" +
+		"closing,2025-07-01T00:00:00Z,,,,119.5,EUR,\n"
+	assert.Equal(t, expected, render(t, f))
+}
@@ -0,0 +1,13 @@
+package statements
+
+import (
+	"github.com/cursed-ninja/internal-transfers-system/internal/currency"
+	"github.com/shopspring/decimal"
+)
+
+// currencyAmount formats amount with the decimal places of its currency. Accounts and transfers are
+// refused when their amounts are finer than the currency's minor unit, so nothing is rounded here
+// except amounts recorded before that check, which are rounded half away from zero.
+func currencyAmount(amount decimal.Decimal, code string) string {
+	return amount.StringFixed(currency.Decimals(code))
+}
@@ -0,0 +1,31 @@
+package statements
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCurrencyAmount validates amounts are written with each currency's decimal places.
+func TestCurrencyAmount(t *testing.T) {
+	tests := []struct {
+		amount   string
+		currency string
+		expected string
+	}{
+		{amount: "12.5", currency: "USD", expected: "12.50"},
+		{amount: "12.34567", currency: "EUR", expected: "12.35"},
+		{amount: "-0.005", currency: "EUR", expected: "-0.01"},
+		{amount: "1500", currency: "JPY", expected: "1500"},
+		{amount: "1499.5", currency: "JPY", expected: "1500"},
+		{amount: "1.2", currency: "KWD", expected: "1.200"},
+		{amount: "3", currency: "CLF", expected: "3.0000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.currency+" "+tt.amount, func(t *testing.T) {
+			assert.Equal(t, tt.expected, currencyAmount(decimal.RequireFromString(tt.amount), tt.currency))
+		})
+	}
+}
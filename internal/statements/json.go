@@ -0,0 +1,99 @@
+package statements
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+)
+
+type jsonStatementHeader struct {
+	AccountID      string    `json:"account_id"`
+	Currency       string    `json:"currency"`
+	From           time.Time `json:"from"`
+	To             time.Time `json:"to"`
+	OpeningBalance string    `json:"opening_balance"`
+}
+
+type jsonStatementEntry struct {
+	TransactionID  int64     `json:"transaction_id"`
+	CreatedAt      time.Time `json:"created_at"`
+	CounterpartyID string    `json:"counterparty_account_id"`
+	Amount         string    `json:"amount"`
+	Balance        string    `json:"balance"`
+	RequestID      string    `json:"request_id,omitempty"`
+}
+
+type jsonStatementFooter struct {
+	ClosingBalance string `json:"closing_balance"`
+	TotalCredits   string `json:"total_credits"`
+	TotalDebits    string `json:"total_debits"`
+	EntryCount     int    `json:"entry_count"`
+}
+
+type jsonWriter struct {
+	w       *bufio.Writer
+	entries int
+}
+
+// NewJSONWriter returns a writer rendering a statement as a single JSON object. The object is written
+// piece by piece: the header fields, an "entries" array and then the closing balance and totals.
+func NewJSONWriter(w io.Writer) storage.StatementWriter {
+	return &jsonWriter{w: bufio.NewWriter(w)}
+}
+
+func (j *jsonWriter) WriteHeader(s *storage.Statement) error {
+	header, err := json.Marshal(jsonStatementHeader{
+		AccountID:      s.AccountID,
+		Currency:       s.Currency,
+		From:           s.From.UTC(),
+		To:             s.To.UTC(),
+		OpeningBalance: s.OpeningBalance.String(),
+	})
+	if err != nil {
+		return err
+	}
+	// Reopen the header object to append the entries to it.
+	_, _ = j.w.Write(header[:len(header)-1])
+	_, err = j.w.WriteString(`,"entries":[`)
+	return err
+}
+
+func (j *jsonWriter) WriteEntry(e storage.StatementEntry) error {
+	entry, err := json.Marshal(jsonStatementEntry{
+		TransactionID:  e.TransactionID,
+		CreatedAt:      e.CreatedAt.UTC(),
+		CounterpartyID: e.CounterpartyID,
+		Amount:         e.Amount.String(),
+		Balance:        e.Balance.String(),
+		RequestID:      e.RequestID,
+	})
+	if err != nil {
+		return err
+	}
+	if j.entries > 0 {
+		_ = j.w.WriteByte(',')
+	}
+	j.entries++
+	_, err = j.w.Write(entry)
+	return err
+}
+
+func (j *jsonWriter) WriteFooter(s *storage.Statement) error {
+	footer, err := json.Marshal(jsonStatementFooter{
+		ClosingBalance: s.ClosingBalance.String(),
+		TotalCredits:   s.TotalCredits.String(),
+		TotalDebits:    s.TotalDebits.String(),
+		EntryCount:     s.EntryCount(),
+	})
+	if err != nil {
+		return err
+	}
+	// Close the entries array and splice the footer fields into the object.
+	_, _ = j.w.WriteString("],")
+	_, _ = j.w.Write(footer[1:])
+	_ = j.w.WriteByte('\n')
+	return j.w.Flush()
+}
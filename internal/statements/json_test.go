@@ -0,0 +1,33 @@
+package statements
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestJSONWriter validates that the streamed pieces form one JSON object with every field.
+func TestJSONWriter(t *testing.T) {
+	f, _ := Lookup("json")
+
+	out := render(t, f)
+	assert.True(t, json.Valid([]byte(out)))
+
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal([]byte(out), &doc))
+	assert.Equal(t, "acc-1", doc["account_id"])
+	assert.Equal(t, "EUR", doc["currency"])
+	assert.Equal(t, "2025-06-01T00:00:00Z", doc["from"])
+	assert.Equal(t, "2025-07-01T00:00:00Z", doc["to"])
+	assert.Equal(t, "100", doc["opening_balance"])
+	assert.Equal(t, "119.5", doc["closing_balance"])
+	assert.Equal(t, "50", doc["total_credits"])
+	assert.Equal(t, "30.5", doc["total_debits"])
+	assert.Equal(t, float64(2), doc["entry_count"])
+	assert.Equal(t, []any{
+		map[string]any{"transaction_id": float64(7), "created_at": "2025-06-01T01:00:00Z", "counterparty_account_id": "acc-2", "amount": "50", "balance": "150", "request_id": "req-7"},
+		map[string]any{"transaction_id": float64(9), "created_at": "2025-06-01T02:00:00Z", "counterparty_account_id": "acc-3", "amount": "-30.5", "balance": "119.5"},
+	}, doc["entries"])
+}
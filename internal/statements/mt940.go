@@ -0,0 +1,258 @@
+package statements
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+	"github.com/shopspring/decimal"
+)
+
+// Limits SWIFT puts on the MT940 fields the exporter writes, in characters.
+const (
+	mt940MaxReference = 16
+	mt940MaxAccountID = 35
+	// mt940MaxAmount includes the decimal comma.
+	mt940MaxAmount   = 15
+	mt940MaxDetails  = 34
+	mt940MaxInfoLine = 65
+	mt940MaxInfo     = 6
+	mt940MaxSequence = 99999
+	// mt940MaxMessage is the most characters the text of one message may hold, line breaks included.
+	// Longer statements are split into several messages with intermediate balances.
+	mt940MaxMessage = 2000
+)
+
+// mt940FooterSize is the room a message keeps for its closing balance and terminator.
+const mt940FooterSize = len(":62F:D250630EUR") + mt940MaxAmount + len("\r\n-\r\n")
+
+const (
+	// mt940TransferType identifies a transfer that is not a SWIFT message.
+	mt940TransferType = "NTRF"
+	mt940NoReference  = "NONREF"
+)
+
+// swiftChar reports whether r is in the SWIFT X character set.
+func swiftChar(r rune) bool {
+	switch {
+	case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+		return true
+	}
+	return strings.ContainsRune("/-?:().,'+ ", r)
+}
+
+type mt940Writer struct {
+	w         *bufio.Writer
+	accountID string
+	currency  string
+	reference string
+	number    string
+	// sequence is the number of the current message within the statement.
+	sequence int
+	// size is the number of characters written in the current message, and entries its entries.
+	size    int
+	entries int
+	// balance is the balance after the last entry written, at balanceDate.
+	balance     decimal.Decimal
+	balanceDate time.Time
+}
+
+// NewMT940Writer returns a writer rendering a statement as SWIFT MT940 customer statement messages, with
+// final opening and closing balances (:60F: and :62F:) and a :61: statement line and :86: information
+// per transfer. Text is limited to the SWIFT X character set, other characters being replaced by dots,
+// and statements too long for one message continue in further messages with intermediate balances.
+func NewMT940Writer(w io.Writer) storage.StatementWriter {
+	return &mt940Writer{w: bufio.NewWriter(w)}
+}
+
+func (m *mt940Writer) WriteHeader(s *storage.Statement) error {
+	if !(Format{MaxAccountIDLength: mt940MaxAccountID, AccountIDChars: swiftChar}).FitsAccountID(s.AccountID) {
+		return fmt.Errorf("account ID cannot be written in MT940, which allows %d characters of the SWIFT X set", mt940MaxAccountID)
+	}
+	m.accountID = s.AccountID
+	m.currency = s.Currency
+	m.reference = statementID(s)[:mt940MaxReference]
+	// Statements are numbered by the year and day of their closing balance.
+	closing := balanceDate(s.To)
+	m.number = closing.Format("06") + fmt.Sprintf("%03d", closing.YearDay())
+	m.balance = s.OpeningBalance
+	m.balanceDate = balanceDate(s.From)
+	return m.startMessage("60F")
+}
+
+func (m *mt940Writer) WriteEntry(e storage.StatementEntry) error {
+	lines, err := m.entryLines(e)
+	if err != nil {
+		return err
+	}
+	size := 0
+	for _, line := range lines {
+		size += len(line) + len("\r\n")
+	}
+	if m.entries > 0 && m.size+size+mt940FooterSize > mt940MaxMessage {
+		if err := m.endMessage("62M", m.balance, m.balanceDate); err != nil {
+			return err
+		}
+		if err := m.startMessage("60M"); err != nil {
+			return err
+		}
+	}
+	for _, line := range lines {
+		m.writeLine(line)
+	}
+	m.entries++
+	m.balance = e.Balance
+	m.balanceDate = e.CreatedAt.UTC()
+	return nil
+}
+
+func (m *mt940Writer) WriteFooter(s *storage.Statement) error {
+	if err := m.endMessage("62F", s.ClosingBalance, balanceDate(s.To)); err != nil {
+		return err
+	}
+	return m.w.Flush()
+}
+
+// startMessage begins the next message of the statement with its opening balance, a final balance
+// (tag 60F) for the first message and an intermediate one (60M) for the others.
+func (m *mt940Writer) startMessage(tag string) error {
+	m.sequence++
+	if m.sequence > mt940MaxSequence {
+		return fmt.Errorf("statement needs more than the %d messages MT940 allows", mt940MaxSequence)
+	}
+	m.size, m.entries = 0, 0
+
+	opening, err := m.balanceField(tag, m.balance, m.balanceDate)
+	if err != nil {
+		return err
+	}
+	m.writeLine(":20:" + m.reference)
+	m.writeLine(":25:" + m.accountID)
+	m.writeLine(":28C:" + m.number + "/" + strconv.Itoa(m.sequence))
+	m.writeLine(opening)
+	return nil
+}
+
+// endMessage closes the current message with its closing balance and the terminator.
+func (m *mt940Writer) endMessage(tag string, amount decimal.Decimal, date time.Time) error {
+	closing, err := m.balanceField(tag, amount, date)
+	if err != nil {
+		return err
+	}
+	m.writeLine(closing)
+	m.writeLine("-")
+	return nil
+}
+
+// entryLines returns the :61: statement line of a transfer, with the counterparty as supplementary
+// details, and its :86: information. The transaction ID is the bank's reference.
+func (m *mt940Writer) entryLines(e storage.StatementEntry) ([]string, error) {
+	amount, err := m.amount(e.Amount)
+	if err != nil {
+		return nil, err
+	}
+	date := e.CreatedAt.UTC()
+	id := strconv.FormatInt(e.TransactionID, 10)
+
+	statement := ":61:" + date.Format("060102") + date.Format("0102") + debitCredit(e.Amount) + amount + mt940TransferType + mt940NoReference
+	if len(id) <= mt940MaxReference {
+		statement += "//" + id
+	}
+	lines := []string{statement}
+	if e.CounterpartyID != "" {
+		lines = append(lines, swiftLine(truncate(swiftText(e.CounterpartyID), mt940MaxDetails)))
+	}
+
+	info := "Transfer from " + e.CounterpartyID
+	if e.Amount.IsNegative() {
+		info = "Transfer to " + e.CounterpartyID
+	}
+	parts := []string{info, "Transaction " + id}
+	if e.RequestID != "" {
+		parts = append(parts, "Request ID "+e.RequestID)
+	}
+	var infoLines []string
+	for _, part := range parts {
+		infoLines = append(infoLines, wrap(swiftText(part), mt940MaxInfoLine)...)
+	}
+	if len(infoLines) > mt940MaxInfo {
+		infoLines = infoLines[:mt940MaxInfo]
+	}
+	infoLines[0] = ":86:" + infoLines[0]
+	return append(lines, infoLines...), nil
+}
+
+// balanceField renders a balance field: the debit or credit mark, date, currency and amount.
+func (m *mt940Writer) balanceField(tag string, amount decimal.Decimal, date time.Time) (string, error) {
+	value, err := m.amount(amount)
+	if err != nil {
+		return "", err
+	}
+	return ":" + tag + ":" + debitCredit(amount) + date.Format("060102") + m.currency + value, nil
+}
+
+// amount renders the magnitude of amount with the decimal places of the statement's currency and a
+// decimal comma, which MT940 requires even for currencies without minor units.
+func (m *mt940Writer) amount(amount decimal.Decimal) (string, error) {
+	value := strings.Replace(currencyAmount(amount.Abs(), m.currency), ".", ",", 1)
+	if !strings.Contains(value, ",") {
+		value += ","
+	}
+	if len(value) > mt940MaxAmount {
+		return "", fmt.Errorf("amount %s is longer than the %d characters MT940 allows", value, mt940MaxAmount)
+	}
+	return value, nil
+}
+
+func (m *mt940Writer) writeLine(line string) {
+	m.w.WriteString(line)
+	m.w.WriteString("\r\n")
+	m.size += len(line) + len("\r\n")
+}
+
+// balanceDate returns the day whose end of day balance is the balance at, which is the previous day
+// when at is midnight.
+func balanceDate(at time.Time) time.Time {
+	return at.UTC().Add(-time.Nanosecond)
+}
+
+// debitCredit returns the MT940 debit or credit mark of a signed amount; zero is a credit.
+func debitCredit(amount decimal.Decimal) string {
+	if amount.IsNegative() {
+		return "D"
+	}
+	return "C"
+}
+
+// swiftText replaces the characters of s that are not in the SWIFT X character set with dots.
+func swiftText(s string) string {
+	return strings.Map(func(r rune) rune {
+		if swiftChar(r) {
+			return r
+		}
+		return '.'
+	}, s)
+}
+
+// swiftLine keeps a continuation line from starting with ':' or '-', which would read as a new field or
+// the end of the message.
+func swiftLine(line string) string {
+	if strings.HasPrefix(line, ":") || strings.HasPrefix(line, "-") {
+		return "." + line[1:]
+	}
+	return line
+}
+
+// wrap splits text of the SWIFT X character set into lines of at most width characters.
+func wrap(text string, width int) []string {
+	var lines []string
+	for len(text) > width {
+		lines = append(lines, swiftLine(text[:width]))
+		text = text[width:]
+	}
+	return append(lines, swiftLine(text))
+}
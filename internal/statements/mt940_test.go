@@ -0,0 +1,207 @@
+package statements
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mt940Messages splits MT940 output into its messages, each a list of lines without the terminator.
+func mt940Messages(t *testing.T, out string) [][]string {
+	t.Helper()
+	require.True(t, strings.HasSuffix(out, "\r\n-\r\n"))
+	var messages [][]string
+	for _, msg := range strings.Split(strings.TrimSuffix(out, "\r\n-\r\n"), "\r\n-\r\n") {
+		assert.LessOrEqual(t, len(msg)+len("\r\n-\r\n"), mt940MaxMessage)
+		messages = append(messages, strings.Split(msg, "\r\n"))
+	}
+	return messages
+}
+
+// TestMT940Writer validates the fields of a statement that fits in one message.
+func TestMT940Writer(t *testing.T) {
+	f, ok := Lookup("mt940")
+	require.True(t, ok)
+	assert.Equal(t, "sta", f.Extension)
+
+	stmt, _ := sampleStatement()
+	assert.Equal(t, strings.Join([]string{
+		":20:" + statementID(stmt)[:16],
+		":25:acc-1",
+		":28C:25181/1",
+		":60F:C250531EUR100,00",
+		":61:2506010601C50,00NTRFNONREF//7",
+		"acc-2",
+		":86:Transfer from acc-2",
+		"Transaction 7",
+		"Request ID req-7",
+		":61:2506010601D30,50NTRFNONREF//9",
+		"acc-3",
+		":86:Transfer to acc-3",
+		"Transaction 9",
+		":62F:C250630EUR119,50",
+		"-",
+		"",
+	}, "\r\n"), render(t, f))
+}
+
+// TestMT940WriterBalances validates balance marks, dates and amounts in currencies without two decimal places.
+func TestMT940WriterBalances(t *testing.T) {
+	tests := []struct {
+		name            string
+		stmt            *storage.Statement
+		expectedOpening string
+		expectedClosing string
+	}{
+		{
+			name: "overdrawn yen",
+			stmt: &storage.Statement{
+				AccountID: "acc-1", Currency: "JPY",
+				From: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+				OpeningBalance: decimal.NewFromInt(5000), ClosingBalance: decimal.NewFromInt(-250),
+			},
+			expectedOpening: ":60F:C241231JPY5000,",
+			expectedClosing: ":62F:D250101JPY250,",
+		},
+		{
+			name: "dinar",
+			stmt: &storage.Statement{
+				AccountID: "acc-1", Currency: "KWD",
+				From: time.Date(2025, 3, 1, 6, 0, 0, 0, time.FixedZone("UTC+2", 2*60*60)), To: time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC),
+				OpeningBalance: decimal.RequireFromString("0.5"), ClosingBalance: decimal.Zero,
+			},
+			expectedOpening: ":60F:C250301KWD0,500",
+			expectedClosing: ":62F:C250301KWD0,000",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := NewMT940Writer(&buf)
+			require.NoError(t, w.WriteHeader(tc.stmt))
+			require.NoError(t, w.WriteFooter(tc.stmt))
+
+			messages := mt940Messages(t, buf.String())
+			require.Len(t, messages, 1)
+			assert.Equal(t, tc.expectedOpening, messages[0][3])
+			assert.Equal(t, tc.expectedClosing, messages[0][4])
+		})
+	}
+}
+
+// TestMT940WriterSplitsMessages validates long statements continue in further messages whose
+// intermediate balances carry on from one message to the next.
+func TestMT940WriterSplitsMessages(t *testing.T) {
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	stmt := &storage.Statement{
+		AccountID: "acc-1", Currency: "EUR", From: from, To: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+		OpeningBalance: decimal.NewFromInt(1000), ClosingBalance: decimal.NewFromInt(900),
+	}
+	var buf bytes.Buffer
+	w := NewMT940Writer(&buf)
+	require.NoError(t, w.WriteHeader(stmt))
+	for i := range 100 {
+		require.NoError(t, w.WriteEntry(storage.StatementEntry{
+			TransactionID:  int64(i + 1),
+			CreatedAt:      from.Add(time.Duration(i) * time.Hour),
+			CounterpartyID: "acc-2",
+			Amount:         decimal.NewFromInt(-1),
+			Balance:        decimal.NewFromInt(int64(999 - i)),
+			RequestID:      strings.Repeat("r", 40),
+		}))
+	}
+	require.NoError(t, w.WriteFooter(stmt))
+
+	messages := mt940Messages(t, buf.String())
+	require.Greater(t, len(messages), 1)
+	entries := 0
+	for i, msg := range messages {
+		assert.Equal(t, ":25:acc-1", msg[1])
+		assert.Equal(t, ":28C:25181/"+strconv.Itoa(i+1), msg[2])
+		opening, closing := msg[3], msg[len(msg)-1]
+		if i == 0 {
+			assert.Equal(t, ":60F:C250531EUR1000,00", opening)
+		} else {
+			assert.True(t, strings.HasPrefix(opening, ":60M:"), opening)
+			previous := messages[i-1][len(messages[i-1])-1]
+			assert.Equal(t, strings.TrimPrefix(previous, ":62M:"), strings.TrimPrefix(opening, ":60M:"))
+		}
+		if i == len(messages)-1 {
+			assert.Equal(t, ":62F:C250630EUR900,00", closing)
+		} else {
+			assert.True(t, strings.HasPrefix(closing, ":62M:"), closing)
+		}
+		for _, line := range msg {
+			if strings.HasPrefix(line, ":61:") {
+				entries++
+			}
+		}
+	}
+	assert.Equal(t, 100, entries)
+	assert.Equal(t, ":62M:C250601EUR986,00", messages[0][len(messages[0])-1])
+}
+
+// TestMT940WriterText validates text outside the SWIFT X character set, long values and lines that
+// would read as a field or terminator.
+func TestMT940WriterText(t *testing.T) {
+	stmt, _ := sampleStatement()
+	var buf bytes.Buffer
+	w := NewMT940Writer(&buf)
+	require.NoError(t, w.WriteHeader(stmt))
+	require.NoError(t, w.WriteEntry(storage.StatementEntry{
+		TransactionID:  7,
+		CreatedAt:      stmt.From.Add(time.Hour),
+		CounterpartyID: "-zoë_" + strings.Repeat("x", 40),
+		Amount:         decimal.NewFromInt(50),
+		Balance:        decimal.NewFromInt(150),
+		RequestID:      strings.Repeat("é", 600),
+	}))
+	require.NoError(t, w.WriteFooter(stmt))
+	out := buf.String()
+
+	for _, r := range strings.ReplaceAll(out, "\r\n", "") {
+		assert.True(t, swiftChar(r), "%q is not in the SWIFT X character set", r)
+	}
+	lines := mt940Messages(t, out)[0]
+	assert.Equal(t, ".zo.."+strings.Repeat("x", 29), lines[5])
+
+	info := lines[6 : len(lines)-1]
+	require.Len(t, info, mt940MaxInfo)
+	assert.Equal(t, ":86:Transfer from -zo.."+strings.Repeat("x", 40), info[0])
+	assert.Equal(t, "Transaction 7", info[1])
+	assert.Equal(t, "Request ID "+strings.Repeat(".", 54), info[2])
+	for _, line := range info[1:] {
+		assert.LessOrEqual(t, len(line), mt940MaxInfoLine)
+	}
+}
+
+// TestMT940WriterRejects validates statements MT940 cannot carry are refused before anything is written.
+func TestMT940WriterRejects(t *testing.T) {
+	tests := []struct {
+		name      string
+		accountID string
+		balance   decimal.Decimal
+	}{
+		{name: "account ID too long", accountID: strings.Repeat("a", 36), balance: decimal.Zero},
+		{name: "account ID outside the character set", accountID: "acc_1", balance: decimal.Zero},
+		{name: "amount too long", accountID: "acc-1", balance: decimal.RequireFromString("1000000000000")},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := NewMT940Writer(&buf)
+			err := w.WriteHeader(&storage.Statement{AccountID: tc.accountID, Currency: "EUR", OpeningBalance: tc.balance})
+			assert.Error(t, err)
+			assert.Empty(t, buf.String())
+		})
+	}
+}
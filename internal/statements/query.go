@@ -0,0 +1,71 @@
+package statements
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Errors of statement queries.
+var (
+	ErrMissingPeriod = errors.New("from and to are required")
+	ErrInvalidFrom   = errors.New("from must be an RFC 3339 timestamp or a YYYY-MM-DD date")
+	ErrInvalidTo     = errors.New("to must be an RFC 3339 timestamp or a YYYY-MM-DD date")
+	ErrPeriod        = errors.New("from must be before to")
+	ErrFutureTo      = errors.New("to must not be in the future")
+	ErrUnknownFormat = fmt.Errorf("format must be one of %s", strings.Join(Names(), ", "))
+	ErrAccountID     = errors.New("account_id is too long for, or has characters not allowed in, the requested statement format")
+)
+
+// ParseQuery parses the period and format of a statement request. from and to are RFC 3339
+// timestamps or YYYY-MM-DD dates in UTC; a date from starts at the beginning of the day and a date to
+// ends at the end of it, so from=2025-06-01&to=2025-06-30 covers June. The format defaults to JSON.
+func ParseQuery(values url.Values, now time.Time) (time.Time, time.Time, Format, error) {
+	var format Format
+	rawFrom, rawTo := strings.TrimSpace(values.Get("from")), strings.TrimSpace(values.Get("to"))
+	if rawFrom == "" || rawTo == "" {
+		return time.Time{}, time.Time{}, format, ErrMissingPeriod
+	}
+	from, ok := parseTime(rawFrom, false)
+	if !ok {
+		return time.Time{}, time.Time{}, format, ErrInvalidFrom
+	}
+	to, ok := parseTime(rawTo, true)
+	if !ok {
+		return time.Time{}, time.Time{}, format, ErrInvalidTo
+	}
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, format, ErrPeriod
+	}
+	if to.After(now) {
+		return time.Time{}, time.Time{}, format, ErrFutureTo
+	}
+
+	name := strings.TrimSpace(values.Get("format"))
+	if name == "" {
+		name = "json"
+	}
+	format, ok = Lookup(name)
+	if !ok {
+		return time.Time{}, time.Time{}, format, ErrUnknownFormat
+	}
+	return from, to, format, nil
+}
+
+// parseTime parses an RFC 3339 timestamp or a YYYY-MM-DD date, which stands for the start of the day,
+// or its end when endOfDay is set.
+func parseTime(raw string, endOfDay bool) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, true
+	}
+	day, err := time.Parse(time.DateOnly, raw)
+	if err != nil {
+		return time.Time{}, false
+	}
+	if endOfDay {
+		day = day.AddDate(0, 0, 1)
+	}
+	return day, true
+}
@@ -0,0 +1,78 @@
+package statements
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParseQuery validates the period and format parsing of statement requests.
+func TestParseQuery(t *testing.T) {
+	now := time.Date(2025, 7, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		values         url.Values
+		expectedFrom   time.Time
+		expectedTo     time.Time
+		expectedFormat string
+		expectedErr    error
+	}{
+		{
+			name:           "dates cover whole days",
+			values:         url.Values{"from": {"2025-06-01"}, "to": {"2025-06-30"}},
+			expectedFrom:   time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+			expectedTo:     time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+			expectedFormat: "json",
+		},
+		{
+			name:           "timestamps and format",
+			values:         url.Values{"from": {"2025-06-01T08:00:00Z"}, "to": {"2025-06-01T09:00:00Z"}, "format": {"mt940"}},
+			expectedFrom:   time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC),
+			expectedTo:     time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC),
+			expectedFormat: "mt940",
+		},
+		{
+			name:        "missing to",
+			values:      url.Values{"from": {"2025-06-01"}},
+			expectedErr: ErrMissingPeriod,
+		},
+		{
+			name:        "invalid from",
+			values:      url.Values{"from": {"June"}, "to": {"2025-06-30"}},
+			expectedErr: ErrInvalidFrom,
+		},
+		{
+			name:        "invalid to",
+			values:      url.Values{"from": {"2025-06-01"}, "to": {"30/06/2025"}},
+			expectedErr: ErrInvalidTo,
+		},
+		{
+			name:        "empty period",
+			values:      url.Values{"from": {"2025-06-01T08:00:00Z"}, "to": {"2025-06-01T08:00:00Z"}},
+			expectedErr: ErrPeriod,
+		},
+		{
+			name:        "future to",
+			values:      url.Values{"from": {"2025-07-01"}, "to": {"2025-07-31"}},
+			expectedErr: ErrFutureTo,
+		},
+		{
+			name:        "unknown format",
+			values:      url.Values{"from": {"2025-06-01"}, "to": {"2025-06-30"}, "format": {"pdf"}},
+			expectedErr: ErrUnknownFormat,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			from, to, format, err := ParseQuery(tc.values, now)
+			assert.Equal(t, tc.expectedErr, err)
+			assert.Equal(t, tc.expectedFrom, from)
+			assert.Equal(t, tc.expectedTo, to)
+			assert.Equal(t, tc.expectedFormat, format.Name)
+		})
+	}
+}
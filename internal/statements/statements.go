@@ -0,0 +1,71 @@
+// Package statements renders account statements in the formats clients download them in.
+package statements
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+)
+
+// Format is a statement file format.
+type Format struct {
+	Name        string
+	ContentType string
+	// Extension is the file name extension of downloaded statements, without the dot.
+	Extension string
+	// NewWriter returns a writer rendering a statement to w as it is read.
+	NewWriter func(w io.Writer) storage.StatementWriter
+	// MaxAccountIDLength is the longest account ID the format can carry; zero means no limit.
+	MaxAccountIDLength int
+	// AccountIDChars reports whether the format can carry a character of an account ID; nil means any.
+	AccountIDChars func(r rune) bool
+}
+
+// FitsAccountID reports whether the format can carry accountID unchanged.
+func (f Format) FitsAccountID(accountID string) bool {
+	if f.MaxAccountIDLength > 0 && len(accountID) > f.MaxAccountIDLength {
+		return false
+	}
+	return f.AccountIDChars == nil || strings.IndexFunc(accountID, func(r rune) bool { return !f.AccountIDChars(r) }) < 0
+}
+
+var formats = map[string]Format{
+	"camt053": {
+		Name: "camt053", ContentType: "application/xml", Extension: "xml",
+		NewWriter: NewCamt053Writer, MaxAccountIDLength: camtMaxAccountID,
+	},
+	"csv":  {Name: "csv", ContentType: "text/csv; charset=utf-8", Extension: "csv", NewWriter: NewCSVWriter},
+	"json": {Name: "json", ContentType: "application/json", Extension: "json", NewWriter: NewJSONWriter},
+	"mt940": {
+		Name: "mt940", ContentType: "text/plain; charset=us-ascii", Extension: "sta",
+		NewWriter: NewMT940Writer, MaxAccountIDLength: mt940MaxAccountID, AccountIDChars: swiftChar,
+	},
+	"txt": {Name: "txt", ContentType: "text/plain; charset=utf-8", Extension: "txt", NewWriter: NewTextWriter},
+}
+
+// Lookup returns the format with the given name.
+func Lookup(name string) (Format, bool) {
+	f, ok := formats[name]
+	return f, ok
+}
+
+// Names returns the names of every format, sorted.
+func Names() []string {
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// statementID derives a statement's identification from its account and period, so the same statement
+// always has the same ID.
+func statementID(s *storage.Statement) string {
+	sum := sha256.Sum256([]byte(s.AccountID + "\x00" + camtTime(s.From) + "\x00" + camtTime(s.To)))
+	return hex.EncodeToString(sum[:16])
+}
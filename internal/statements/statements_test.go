@@ -0,0 +1,86 @@
+package statements
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sampleStatement returns a statement with one credit and one debit, and its entries.
+func sampleStatement() (*storage.Statement, []storage.StatementEntry) {
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	stmt := &storage.Statement{
+		AccountID:      "acc-1",
+		Currency:       "EUR",
+		From:           from,
+		To:             time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+		OpeningBalance: decimal.RequireFromString("100"),
+		ClosingBalance: decimal.RequireFromString("119.5"),
+		TotalCredits:   decimal.RequireFromString("50"),
+		TotalDebits:    decimal.RequireFromString("30.5"),
+		CreditCount:    1,
+		DebitCount:     1,
+	}
+	entries := []storage.StatementEntry{
+		{TransactionID: 7, CreatedAt: from.Add(time.Hour), CounterpartyID: "acc-2", Amount: decimal.RequireFromString("50"), Balance: decimal.RequireFromString("150"), RequestID: "req-7"},
+		{TransactionID: 9, CreatedAt: from.Add(2 * time.Hour), CounterpartyID: "acc-3", Amount: decimal.RequireFromString("-30.5"), Balance: decimal.RequireFromString("119.5")},
+	}
+	return stmt, entries
+}
+
+// render writes the sample statement through the format's writer the way storage does.
+func render(t *testing.T, format Format) string {
+	t.Helper()
+	stmt, entries := sampleStatement()
+	var buf bytes.Buffer
+	w := format.NewWriter(&buf)
+
+	require.NoError(t, w.WriteHeader(stmt))
+	for _, e := range entries {
+		require.NoError(t, w.WriteEntry(e))
+	}
+	require.NoError(t, w.WriteFooter(stmt))
+	return buf.String()
+}
+
+// TestLookup validates format lookup and listing.
+func TestLookup(t *testing.T) {
+	f, ok := Lookup("csv")
+	assert.True(t, ok)
+	assert.Equal(t, "text/csv; charset=utf-8", f.ContentType)
+
+	_, ok = Lookup("pdf")
+	assert.False(t, ok)
+
+	assert.Equal(t, []string{"camt053", "csv", "json", "mt940", "txt"}, Names())
+}
+
+// TestFitsAccountID validates the account ID length and character limits of formats.
+func TestFitsAccountID(t *testing.T) {
+	csv, _ := Lookup("csv")
+	mt940, _ := Lookup("mt940")
+
+	tests := []struct {
+		name        string
+		format      Format
+		accountID   string
+		expectedFit bool
+	}{
+		{name: "no limits", format: csv, accountID: "zoë_" + strings.Repeat("a", 100), expectedFit: true},
+		{name: "within limits", format: mt940, accountID: "ACC-1/" + strings.Repeat("a", 29), expectedFit: true},
+		{name: "too long", format: mt940, accountID: strings.Repeat("a", 36)},
+		{name: "character outside the set", format: mt940, accountID: "acc_1"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedFit, tc.format.FitsAccountID(tc.accountID))
+		})
+	}
+}
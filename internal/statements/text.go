@@ -0,0 +1,46 @@
+package statements
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+)
+
+// textRow lays out the columns of a plain-text statement: time, transaction, counterparty, amount and balance.
+const textRow = "%-30s  %-12s  %-24s  %20s  %20s\n"
+
+type textWriter struct {
+	w *bufio.Writer
+}
+
+// NewTextWriter returns a writer rendering a statement as fixed-width plain text for reading or printing.
+func NewTextWriter(w io.Writer) storage.StatementWriter {
+	return &textWriter{w: bufio.NewWriter(w)}
+}
+
+func (t *textWriter) WriteHeader(s *storage.Statement) error {
+	fmt.Fprintf(t.w, "Statement for account %s (%s)\n", s.AccountID, s.Currency)
+	fmt.Fprintf(t.w, "Period: after %s up to %s\n\n", textTime(s.From), textTime(s.To))
+	fmt.Fprintf(t.w, textRow, "Time", "Transaction", "Counterparty", "Amount", "Balance")
+	_, err := fmt.Fprintf(t.w, textRow, textTime(s.From), "", "Opening balance", "", s.OpeningBalance.String())
+	return err
+}
+
+func (t *textWriter) WriteEntry(e storage.StatementEntry) error {
+	_, err := fmt.Fprintf(t.w, textRow, textTime(e.CreatedAt), strconv.FormatInt(e.TransactionID, 10), e.CounterpartyID, e.Amount.String(), e.Balance.String())
+	return err
+}
+
+func (t *textWriter) WriteFooter(s *storage.Statement) error {
+	fmt.Fprintf(t.w, textRow, textTime(s.To), "", "Closing balance", "", s.ClosingBalance.String())
+	fmt.Fprintf(t.w, "\nCredits: %s  Debits: %s  Entries: %d\n", s.TotalCredits.String(), s.TotalDebits.String(), s.EntryCount())
+	return t.w.Flush()
+}
+
+func textTime(at time.Time) string {
+	return at.UTC().Format(time.RFC3339Nano)
+}
@@ -0,0 +1,23 @@
+package statements
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTextWriter validates the plain-text heading, balance lines, entries and totals.
+func TestTextWriter(t *testing.T) {
+	f, _ := Lookup("txt")
+
+	lines := strings.Split(render(t, f), "\n")
+	assert.Equal(t, "Statement for account acc-1 (EUR)", lines[0])
+	assert.Equal(t, "Period: after 2025-06-01T00:00:00Z up to 2025-07-01T00:00:00Z", lines[1])
+	assert.Regexp(t, `^Time\s+Transaction\s+Counterparty\s+Amount\s+Balance$`, lines[3])
+	assert.Regexp(t, `^2025-06-01T00:00:00Z\s+Opening balance\s+100$`, lines[4])
+	assert.Regexp(t, `^2025-06-01T01:00:00Z\s+7\s+acc-2\s+50\s+150$`, lines[5])
+	assert.Regexp(t, `^2025-06-01T02:00:00Z\s+9\s+acc-3\s+-30.5\s+119.5$`, lines[6])
+	assert.Regexp(t, `^2025-07-01T00:00:00Z\s+Closing balance\s+119.5$`, lines[7])
+	assert.Equal(t, "Credits: 50  Debits: 30.5  Entries: 2", lines[9])
+}
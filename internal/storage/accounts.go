@@ -0,0 +1,406 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+const accountColumns = `id, balance, status, currency, display_name, owner_ref, account_type, labels, metadata, version, created_at, updated_at`
+
+// UpdateAccount applies patch to the account if its current version is version (or version is
+// AnyVersion), increments the version, records the change in the audit log and queues an
+// account.updated event in the outbox.
+// Returns ErrAccountNotFound if the account doesn't exist, ErrAccountVersionMsg if it has been updated
+// since the caller read it, or ErrUpdateAccountMsg on internal failures.
+func (p *PostgressStorage) UpdateAccount(ctx context.Context, accountID string, version int64, patch AccountPatch) (*Account, error) {
+	const (
+		// Query to apply the patch; NULL parameters keep the current value
+		updateQuery = `
+			UPDATE accounts
+			SET display_name = COALESCE($3, display_name),
+				owner_ref = COALESCE($4, owner_ref),
+				account_type = COALESCE($5, account_type),
+				labels = COALESCE($6::TEXT[], labels),
+				metadata = COALESCE($7::JSONB, metadata),
+				version = version + 1,
+				updated_at = now()
+			WHERE id = $1 AND ($2::BIGINT = 0 OR version = $2)
+			RETURNING ` + accountColumns + `
+		`
+		// Query to tell a missing account from a stale version
+		existsQuery = `
+			SELECT 1
+			FROM accounts
+			WHERE id = $1
+		`
+	)
+
+	logger := p.contextLogger(ctx)
+
+	var labels, metadata any
+	if patch.Labels != nil {
+		labels = pq.Array(*patch.Labels)
+	}
+	if patch.Metadata != nil {
+		metadata = string(patch.Metadata)
+	}
+
+	var acc *Account
+	err := p.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		acc, err = scanAccount(tx.QueryRowContext(ctx, updateQuery, accountID, version, patch.Name, patch.OwnerRef, patch.Type, labels, metadata))
+		if errors.Is(err, sql.ErrNoRows) {
+			var tmp int
+			if err := tx.QueryRowContext(ctx, existsQuery, accountID).Scan(&tmp); err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return errors.New(ErrAccountNotFound)
+				}
+				logger.Error("failed to check account exists", zap.Error(err))
+				return errors.New(ErrUpdateAccountMsg)
+			}
+			logger.Warn("account version mismatch", zap.Int64("version", version))
+			return errors.New(ErrAccountVersionMsg)
+		}
+		if err != nil {
+			logger.Error("failed to update account", zap.Error(err))
+			return errors.New(ErrUpdateAccountMsg)
+		}
+
+		payload := accountUpdatedPayload(acc, patch)
+		if err := lockAuditChain(ctx, tx); err != nil {
+			logger.Error("failed to lock audit chain", zap.Error(err))
+			return errors.New(ErrUpdateAccountMsg)
+		}
+		if err := insertOutboxEvent(ctx, tx, EventAccountUpdated, []string{accountID}, payload); err != nil {
+			logger.Error("failed to insert outbox event", zap.Error(err))
+			return errors.New(ErrUpdateAccountMsg)
+		}
+		if err := appendAuditEvent(ctx, tx, EventAccountUpdated, accountID, payload); err != nil {
+			logger.Error("failed to append audit event", zap.Error(err))
+			return errors.New(ErrUpdateAccountMsg)
+		}
+		return nil
+	}, ErrUpdateAccountMsg)
+	if err != nil {
+		return nil, err
+	}
+	return acc, nil
+}
+
+// CloseAccount closes the account if its current version is version (or version is AnyVersion),
+// increments the version, records the change in the audit log and queues an account.closed event in
+// the outbox. Only active accounts with a zero balance can be closed. Returns ErrAccountNotFound,
+// ErrAccountVersionMsg, ErrAccountClosedMsg, ErrAccountNotEmptyMsg, or ErrCloseAccountMsg on
+// internal failures.
+func (p *PostgressStorage) CloseAccount(ctx context.Context, accountID string, version int64) (*Account, error) {
+	const (
+		// Query to lock the account while its state is checked
+		lockQuery = `
+			SELECT balance, status, version
+			FROM accounts
+			WHERE id = $1
+			FOR UPDATE
+		`
+		// Query to close the account
+		closeQuery = `
+			UPDATE accounts
+			SET status = 'closed', version = version + 1, updated_at = now()
+			WHERE id = $1
+			RETURNING ` + accountColumns + `
+		`
+	)
+
+	logger := p.contextLogger(ctx)
+
+	var acc *Account
+	err := p.withTx(ctx, func(tx *sql.Tx) error {
+		var (
+			balance        decimal.Decimal
+			status         string
+			currentVersion int64
+		)
+		if err := tx.QueryRowContext(ctx, lockQuery, accountID).Scan(&balance, &status, &currentVersion); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return errors.New(ErrAccountNotFound)
+			}
+			logger.Error("failed to get account", zap.Error(err))
+			return errors.New(ErrCloseAccountMsg)
+		}
+		switch {
+		case version != AnyVersion && currentVersion != version:
+			logger.Warn("account version mismatch", zap.Int64("version", version), zap.Int64("current_version", currentVersion))
+			return errors.New(ErrAccountVersionMsg)
+		case status == AccountStatusClosed:
+			return errors.New(ErrAccountClosedMsg)
+		case !balance.IsZero():
+			logger.Warn("account balance is not zero", zap.String("balance", balance.String()))
+			return errors.New(ErrAccountNotEmptyMsg)
+		}
+
+		var err error
+		if acc, err = scanAccount(tx.QueryRowContext(ctx, closeQuery, accountID)); err != nil {
+			logger.Error("failed to close account", zap.Error(err))
+			return errors.New(ErrCloseAccountMsg)
+		}
+
+		payload := map[string]any{"account_id": acc.ID, "version": acc.Version}
+		if err := lockAuditChain(ctx, tx); err != nil {
+			logger.Error("failed to lock audit chain", zap.Error(err))
+			return errors.New(ErrCloseAccountMsg)
+		}
+		if err := insertOutboxEvent(ctx, tx, EventAccountClosed, []string{accountID}, payload); err != nil {
+			logger.Error("failed to insert outbox event", zap.Error(err))
+			return errors.New(ErrCloseAccountMsg)
+		}
+		if err := appendAuditEvent(ctx, tx, EventAccountClosed, accountID, payload); err != nil {
+			logger.Error("failed to append audit event", zap.Error(err))
+			return errors.New(ErrCloseAccountMsg)
+		}
+		return nil
+	}, ErrCloseAccountMsg)
+	if err != nil {
+		return nil, err
+	}
+	return acc, nil
+}
+
+// errImportRefused rolls back an atomic import with a refused account.
+var errImportRefused = errors.New("an account of the import was refused")
+
+// importChunkSize is the most accounts a partial import creates per DB transaction. Each transaction
+// holds the audit log lock until it commits, so chunks bound how long other writes wait for an import.
+const importChunkSize = 500
+
+// CreateAccounts creates accounts in order, as CreateAccount does, returning the error each account was
+// refused with, or nil. A refused account is rolled back on its own and the rest are still attempted, so
+// every refusal is reported.
+// When atomic is set, the accounts are created in one DB transaction and one refusal rolls back every
+// account. That transaction holds the audit log lock, and so delays every other account change and
+// transfer, until the whole import commits. Otherwise the accounts are committed in chunks of
+// importChunkSize and refusals do not affect the others.
+// Returns ErrImportAccountsMsg on internal failures, together with the results of the accounts already
+// committed, which are the leading chunks of a partial import and none of an atomic one.
+func (p *PostgressStorage) CreateAccounts(ctx context.Context, accounts []NewAccount, atomic bool) ([]error, error) {
+	logger := p.contextLogger(ctx)
+	results := make([]error, len(accounts))
+
+	chunkSize := len(accounts)
+	if !atomic {
+		chunkSize = importChunkSize
+	}
+	for start := 0; start < len(accounts); start += chunkSize {
+		end := min(start+chunkSize, len(accounts))
+		committed, err := p.createAccountChunk(ctx, accounts[start:end], results[start:end], atomic)
+		if err != nil {
+			return results[:start], err
+		}
+		logger.Info("accounts imported", zap.Int("accounts", end-start), zap.Bool("atomic", atomic), zap.Bool("committed", committed))
+	}
+	return results, nil
+}
+
+// createAccountChunk creates accounts within one DB transaction, storing the error each account was
+// refused with in results, and reports whether the transaction was committed. When atomic is set, one
+// refusal rolls back every account.
+func (p *PostgressStorage) createAccountChunk(ctx context.Context, accounts []NewAccount, results []error, atomic bool) (bool, error) {
+	const (
+		savepointQuery         = `SAVEPOINT import_account`
+		rollbackSavepointQuery = `ROLLBACK TO SAVEPOINT import_account`
+		releaseSavepointQuery  = `RELEASE SAVEPOINT import_account`
+	)
+
+	logger := p.contextLogger(ctx)
+
+	err := p.withTx(ctx, func(tx *sql.Tx) error {
+		refused := 0
+		for i, acc := range accounts {
+			if _, err := tx.ExecContext(ctx, savepointQuery); err != nil {
+				logger.Error("failed to create savepoint", zap.Error(err))
+				return errors.New(ErrImportAccountsMsg)
+			}
+
+			if err := p.createAccount(ctx, tx, acc.ID, acc.Balance, acc.AccountAttributes); err != nil {
+				if err.Error() == ErrCreateAccountMsg {
+					return errors.New(ErrImportAccountsMsg)
+				}
+				if _, err := tx.ExecContext(ctx, rollbackSavepointQuery); err != nil {
+					logger.Error("failed to roll back to savepoint", zap.Error(err))
+					return errors.New(ErrImportAccountsMsg)
+				}
+				results[i] = err
+				refused++
+				continue
+			}
+
+			if _, err := tx.ExecContext(ctx, releaseSavepointQuery); err != nil {
+				logger.Error("failed to release savepoint", zap.Error(err))
+				return errors.New(ErrImportAccountsMsg)
+			}
+		}
+
+		if atomic && refused > 0 {
+			return errImportRefused
+		}
+		return nil
+	}, ErrImportAccountsMsg)
+	if errors.Is(err, errImportRefused) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// ListAccounts returns up to query.Limit accounts matching the query's filters, in the query's order.
+// When query.After is set, listing resumes after that account, so the last account of one page is
+// the cursor for the next. Returns ErrListAccountsMsg on internal failures.
+func (p *PostgressStorage) ListAccounts(ctx context.Context, query AccountQuery) ([]Account, error) {
+	logger := p.contextLogger(ctx)
+
+	conds, args := accountConditions(query)
+
+	column := "created_at"
+	if query.Sort == AccountSortBalance {
+		column = "balance"
+	}
+	dir, cmp := "ASC", ">"
+	if query.Descending {
+		dir, cmp = "DESC", "<"
+	}
+	if query.After != nil {
+		var key any = query.After.CreatedAt
+		cast := "TIMESTAMPTZ"
+		if query.Sort == AccountSortBalance {
+			key, cast = query.After.Balance, "NUMERIC"
+		}
+		args = append(args, key, query.After.ID)
+		conds = append(conds, fmt.Sprintf("(%s, id) %s ($%d::%s, $%d::TEXT)", column, cmp, len(args)-1, cast, len(args)))
+	}
+	args = append(args, query.Limit)
+
+	sqlQuery := `SELECT ` + accountColumns + ` FROM accounts` + whereClause(conds) +
+		fmt.Sprintf(` ORDER BY %s %s, id %s LIMIT $%d`, column, dir, dir, len(args))
+
+	rows, err := p.db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		logger.Error("failed to list accounts", zap.Error(err))
+		return nil, errors.New(ErrListAccountsMsg)
+	}
+	defer rows.Close()
+
+	accounts := make([]Account, 0)
+	for rows.Next() {
+		acc, err := scanAccount(rows)
+		if err != nil {
+			logger.Error("failed to scan account", zap.Error(err))
+			return nil, errors.New(ErrListAccountsMsg)
+		}
+		accounts = append(accounts, *acc)
+	}
+	if err := rows.Err(); err != nil {
+		logger.Error("failed to list accounts", zap.Error(err))
+		return nil, errors.New(ErrListAccountsMsg)
+	}
+	return accounts, nil
+}
+
+// CountAccounts returns the number of accounts matching the query's filters.
+// Returns ErrCountAccountsMsg on internal failures.
+func (p *PostgressStorage) CountAccounts(ctx context.Context, query AccountQuery) (int64, error) {
+	conds, args := accountConditions(query)
+
+	var count int64
+	if err := p.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM accounts`+whereClause(conds), args...).Scan(&count); err != nil {
+		p.contextLogger(ctx).Error("failed to count accounts", zap.Error(err))
+		return 0, errors.New(ErrCountAccountsMsg)
+	}
+	return count, nil
+}
+
+// accountConditions returns the SQL conditions for the query's filters and their parameters,
+// numbered from $1.
+func accountConditions(query AccountQuery) ([]string, []any) {
+	var (
+		conds []string
+		args  []any
+	)
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if query.IDs != nil {
+		add("id = ANY($%d::TEXT[])", pq.Array(query.IDs))
+	}
+	if query.Type != "" {
+		add("account_type = $%d", query.Type)
+	}
+	if query.Status != "" {
+		add("status = $%d", query.Status)
+	}
+	if query.Currency != "" {
+		add("currency = $%d", query.Currency)
+	}
+	if len(query.Labels) > 0 {
+		add("labels @> $%d::TEXT[]", pq.Array(query.Labels))
+	}
+	if query.MinBalance != nil {
+		add("balance >= $%d", *query.MinBalance)
+	}
+	if query.MaxBalance != nil {
+		add("balance <= $%d", *query.MaxBalance)
+	}
+	return conds, args
+}
+
+// whereClause joins conditions into a WHERE clause, or returns an empty string if there are none.
+func whereClause(conds []string) string {
+	if len(conds) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(conds, " AND ")
+}
+
+// accountUpdatedPayload describes an update: the account's new version and the new values of the changed fields.
+func accountUpdatedPayload(acc *Account, patch AccountPatch) map[string]any {
+	payload := map[string]any{"account_id": acc.ID, "version": acc.Version}
+	if patch.Name != nil {
+		payload["name"] = acc.Name
+	}
+	if patch.OwnerRef != nil {
+		payload["owner_ref"] = acc.OwnerRef
+	}
+	if patch.Type != nil {
+		payload["type"] = acc.Type
+	}
+	if patch.Labels != nil {
+		payload["labels"] = acc.Labels
+	}
+	if patch.Metadata != nil {
+		payload["metadata"] = acc.Metadata
+	}
+	return payload
+}
+
+// scanAccount scans a single row selected with accountColumns.
+func scanAccount(row rowScanner) (*Account, error) {
+	var (
+		acc      Account
+		metadata []byte
+	)
+	if err := row.Scan(&acc.ID, &acc.Balance, &acc.Status, &acc.Currency, &acc.Name, &acc.OwnerRef, &acc.Type, pq.Array(&acc.Labels),
+		&metadata, &acc.Version, &acc.CreatedAt, &acc.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if acc.Labels == nil {
+		acc.Labels = []string{}
+	}
+	acc.Metadata = json.RawMessage(metadata)
+	return &acc, nil
+}
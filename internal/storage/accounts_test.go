@@ -0,0 +1,516 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// testAccount returns a fully populated account as scanned from the database.
+func testAccount() *Account {
+	createdAt := time.Date(2025, 12, 1, 12, 0, 0, 0, time.UTC)
+	return &Account{
+		ID:      "acc-1",
+		Balance: decimal.RequireFromString("250.5"),
+		Status:  AccountStatusActive,
+		AccountAttributes: AccountAttributes{
+			Currency: "EUR",
+			Name:     "Payroll",
+			OwnerRef: "team-payments",
+			Type:     AccountTypeOperating,
+			Labels:   []string{"eu", "payroll"},
+			Metadata: json.RawMessage(`{"cost_center":"42"}`),
+		},
+		Version:   3,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt.Add(time.Hour),
+	}
+}
+
+// accountRows returns the rows a query selecting accountColumns yields for the accounts.
+func accountRows(accounts ...*Account) *sqlmock.Rows {
+	rows := sqlmock.NewRows([]string{"id", "balance", "status", "currency", "display_name", "owner_ref", "account_type", "labels", "metadata", "version", "created_at", "updated_at"})
+	for _, a := range accounts {
+		labels, _ := json.Marshal(a.Labels)
+		labels[0], labels[len(labels)-1] = '{', '}'
+		rows.AddRow(a.ID, a.Balance.String(), a.Status, a.Currency, a.Name, a.OwnerRef, a.Type, string(labels), []byte(a.Metadata), a.Version, a.CreatedAt, a.UpdatedAt)
+	}
+	return rows
+}
+
+// TestUpdateAccount validates applying a patch, including version conflicts, missing accounts and audit failures.
+func TestUpdateAccount(t *testing.T) {
+	name := "Payroll"
+	labels := []string{"eu", "payroll"}
+
+	tests := []struct {
+		name        string
+		patch       AccountPatch
+		prepare     func(sqlmock.Sqlmock)
+		expected    *Account
+		expectedErr string
+	}{
+		{
+			name:  "success",
+			patch: AccountPatch{Name: &name, Labels: &labels},
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(`UPDATE accounts SET display_name = COALESCE`).
+					WithArgs("acc-1", int64(2), "Payroll", nil, nil, "{\"eu\",\"payroll\"}", nil).
+					WillReturnRows(accountRows(testAccount()))
+				expectAuditLock(m)
+				expectOutboxInsert(m, EventAccountUpdated)
+				expectAuditAppend(m, EventAccountUpdated, "acc-1")
+				m.ExpectCommit()
+			},
+			expected: testAccount(),
+		},
+		{
+			name:  "version mismatch",
+			patch: AccountPatch{Name: &name},
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(`UPDATE accounts`).WillReturnError(sql.ErrNoRows)
+				m.ExpectQuery(`SELECT 1 FROM accounts`).WithArgs("acc-1").WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+				m.ExpectRollback()
+			},
+			expectedErr: ErrAccountVersionMsg,
+		},
+		{
+			name:  "not found",
+			patch: AccountPatch{Metadata: json.RawMessage(`{}`)},
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(`UPDATE accounts`).WithArgs("acc-1", int64(2), nil, nil, nil, nil, "{}").WillReturnError(sql.ErrNoRows)
+				m.ExpectQuery(`SELECT 1 FROM accounts`).WithArgs("acc-1").WillReturnError(sql.ErrNoRows)
+				m.ExpectRollback()
+			},
+			expectedErr: ErrAccountNotFound,
+		},
+		{
+			name:  "audit lock error",
+			patch: AccountPatch{Name: &name},
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(`UPDATE accounts`).WillReturnRows(accountRows(testAccount()))
+				m.ExpectExec(`SELECT pg_advisory_xact_lock`).WillReturnError(errors.New("lock error"))
+				m.ExpectRollback()
+			},
+			expectedErr: ErrUpdateAccountMsg,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			store, mock, cleanup := newTestStorage(t)
+			defer cleanup()
+
+			tc.prepare(mock)
+
+			acc, err := store.UpdateAccount(context.Background(), "acc-1", 2, tc.patch)
+			if tc.expectedErr != "" {
+				assert.Nil(t, acc)
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, acc)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestCloseAccount validates closing accounts, including version conflicts, closed and non-empty
+// accounts, and missing accounts.
+func TestCloseAccount(t *testing.T) {
+	closed := testAccount()
+	closed.Status = AccountStatusClosed
+	closed.Version = 4
+
+	lockRows := func(balance, status string, version int64) *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"balance", "status", "version"}).AddRow(balance, status, version)
+	}
+
+	tests := []struct {
+		name        string
+		version     int64
+		prepare     func(sqlmock.Sqlmock)
+		expected    *Account
+		expectedErr string
+	}{
+		{
+			name:    "success",
+			version: 3,
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(`SELECT balance, status, version FROM accounts WHERE id = \$1 FOR UPDATE`).WithArgs("acc-1").WillReturnRows(lockRows("0", AccountStatusActive, 3))
+				m.ExpectQuery(`UPDATE accounts SET status = 'closed', version = version \+ 1`).WithArgs("acc-1").WillReturnRows(accountRows(closed))
+				expectAuditLock(m)
+				expectOutboxInsert(m, EventAccountClosed)
+				expectAuditAppend(m, EventAccountClosed, "acc-1")
+				m.ExpectCommit()
+			},
+			expected: closed,
+		},
+		{
+			name:    "any version",
+			version: AnyVersion,
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(`SELECT balance, status, version FROM accounts`).WillReturnRows(lockRows("0.00", AccountStatusActive, 7))
+				m.ExpectQuery(`UPDATE accounts`).WillReturnRows(accountRows(closed))
+				expectAuditLock(m)
+				expectOutboxInsert(m, EventAccountClosed)
+				expectAuditAppend(m, EventAccountClosed, "acc-1")
+				m.ExpectCommit()
+			},
+			expected: closed,
+		},
+		{
+			name:    "version mismatch",
+			version: 2,
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(`SELECT balance, status, version FROM accounts`).WillReturnRows(lockRows("0", AccountStatusActive, 3))
+				m.ExpectRollback()
+			},
+			expectedErr: ErrAccountVersionMsg,
+		},
+		{
+			name:    "already closed",
+			version: 3,
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(`SELECT balance, status, version FROM accounts`).WillReturnRows(lockRows("0", AccountStatusClosed, 3))
+				m.ExpectRollback()
+			},
+			expectedErr: ErrAccountClosedMsg,
+		},
+		{
+			name:    "non-zero balance",
+			version: 3,
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(`SELECT balance, status, version FROM accounts`).WillReturnRows(lockRows("0.01", AccountStatusActive, 3))
+				m.ExpectRollback()
+			},
+			expectedErr: ErrAccountNotEmptyMsg,
+		},
+		{
+			name:    "not found",
+			version: 3,
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(`SELECT balance, status, version FROM accounts`).WillReturnError(sql.ErrNoRows)
+				m.ExpectRollback()
+			},
+			expectedErr: ErrAccountNotFound,
+		},
+		{
+			name:    "update error",
+			version: 3,
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(`SELECT balance, status, version FROM accounts`).WillReturnRows(lockRows("0", AccountStatusActive, 3))
+				m.ExpectQuery(`UPDATE accounts`).WillReturnError(errors.New("db error"))
+				m.ExpectRollback()
+			},
+			expectedErr: ErrCloseAccountMsg,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			store, mock, cleanup := newTestStorage(t)
+			defer cleanup()
+
+			tc.prepare(mock)
+
+			acc, err := store.CloseAccount(context.Background(), "acc-1", tc.version)
+			if tc.expectedErr != "" {
+				assert.Nil(t, acc)
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, acc)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestListAccounts validates the filters, ordering and cursor of account listings.
+func TestListAccounts(t *testing.T) {
+	minBalance := decimal.RequireFromString("10")
+	after := testAccount()
+
+	tests := []struct {
+		name        string
+		query       AccountQuery
+		prepare     func(sqlmock.Sqlmock)
+		expected    []Account
+		expectedErr string
+	}{
+		{
+			name:  "default order",
+			query: AccountQuery{Limit: 2},
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(`SELECT id, balance, .* FROM accounts ORDER BY created_at ASC, id ASC LIMIT \$1`).
+					WithArgs(2).
+					WillReturnRows(accountRows(testAccount()))
+			},
+			expected: []Account{*testAccount()},
+		},
+		{
+			name: "filters and cursor by balance descending",
+			query: AccountQuery{
+				IDs:        []string{"acc-1", "acc-2"},
+				Type:       AccountTypeOperating,
+				Status:     AccountStatusActive,
+				Currency:   "EUR",
+				Labels:     []string{"eu"},
+				MinBalance: &minBalance,
+				Sort:       AccountSortBalance,
+				Descending: true,
+				After:      after,
+				Limit:      10,
+			},
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(`FROM accounts WHERE id = ANY\(\$1::TEXT\[\]\) AND account_type = \$2 AND status = \$3 AND currency = \$4 `+
+					`AND labels @> \$5::TEXT\[\] AND balance >= \$6 AND \(balance, id\) < \(\$7::NUMERIC, \$8::TEXT\) `+
+					`ORDER BY balance DESC, id DESC LIMIT \$9`).
+					WithArgs(`{"acc-1","acc-2"}`, AccountTypeOperating, AccountStatusActive, "EUR", `{"eu"}`, "10", "250.5", "acc-1", 10).
+					WillReturnRows(accountRows())
+			},
+			expected: []Account{},
+		},
+		{
+			name:  "query error",
+			query: AccountQuery{Limit: 2},
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(`FROM accounts`).WillReturnError(errors.New("db error"))
+			},
+			expectedErr: ErrListAccountsMsg,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			store, mock, cleanup := newTestStorage(t)
+			defer cleanup()
+
+			tc.prepare(mock)
+
+			accounts, err := store.ListAccounts(context.Background(), tc.query)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, accounts)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestCountAccounts validates counting accounts with and without filters.
+func TestCountAccounts(t *testing.T) {
+	store, mock, cleanup := newTestStorage(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM accounts WHERE status = \$1`).
+		WithArgs(AccountStatusActive).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(42))
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM accounts$`).WillReturnError(errors.New("db error"))
+
+	count, err := store.CountAccounts(context.Background(), AccountQuery{Status: AccountStatusActive, After: testAccount()})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), count)
+
+	_, err = store.CountAccounts(context.Background(), AccountQuery{})
+	assert.EqualError(t, err, ErrCountAccountsMsg)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestCreateAccounts validates imports where every account is created, duplicates are skipped or roll
+// back the whole import, and internal failures.
+func TestCreateAccounts(t *testing.T) {
+	accounts := []NewAccount{
+		{ID: "acc-1", Balance: decimal.RequireFromString("100"), AccountAttributes: AccountAttributes{Currency: "EUR"}},
+		{ID: "acc-2", Balance: decimal.Zero, AccountAttributes: AccountAttributes{Name: "Savings", Metadata: json.RawMessage(`{"branch":"7"}`)}},
+	}
+	insert := func(m sqlmock.Sqlmock, id string) *sqlmock.ExpectedExec {
+		m.ExpectExec(`SAVEPOINT import_account`).WillReturnResult(sqlmock.NewResult(0, 0))
+		return m.ExpectExec(`INSERT INTO accounts`).WithArgs(id, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg())
+	}
+	created := func(m sqlmock.Sqlmock, id string) {
+		insert(m, id).WillReturnResult(sqlmock.NewResult(1, 1))
+		expectAuditLock(m)
+		expectOutboxInsert(m, EventAccountCreated)
+		expectAuditAppend(m, EventAccountCreated, id)
+		m.ExpectExec(`RELEASE SAVEPOINT import_account`).WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+	duplicate := func(m sqlmock.Sqlmock, id string) {
+		insert(m, id).WillReturnError(&pq.Error{Code: "23505"})
+		m.ExpectExec(`ROLLBACK TO SAVEPOINT import_account`).WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+
+	tests := []struct {
+		name            string
+		atomic          bool
+		prepare         func(sqlmock.Sqlmock)
+		expectedResults []error
+		expectedErr     string
+	}{
+		{
+			name:   "every account created",
+			atomic: true,
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				created(m, "acc-1")
+				created(m, "acc-2")
+				m.ExpectCommit()
+			},
+			expectedResults: []error{nil, nil},
+		},
+		{
+			name: "duplicate skipped",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				duplicate(m, "acc-1")
+				created(m, "acc-2")
+				m.ExpectCommit()
+			},
+			expectedResults: []error{errors.New(ErrAccountExists), nil},
+		},
+		{
+			name:   "duplicate rolls back an atomic import",
+			atomic: true,
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				created(m, "acc-1")
+				duplicate(m, "acc-2")
+				m.ExpectRollback()
+			},
+			expectedResults: []error{nil, errors.New(ErrAccountExists)},
+		},
+		{
+			name: "internal failure",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				created(m, "acc-1")
+				insert(m, "acc-2").WillReturnError(errors.New("db error"))
+				m.ExpectRollback()
+			},
+			expectedErr: ErrImportAccountsMsg,
+		},
+		{
+			name: "savepoint failure",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectExec(`SAVEPOINT import_account`).WillReturnError(errors.New("db error"))
+				m.ExpectRollback()
+			},
+			expectedErr: ErrImportAccountsMsg,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			store, mock, cleanup := newTestStorage(t)
+			defer cleanup()
+
+			tc.prepare(mock)
+
+			results, err := store.CreateAccounts(context.Background(), accounts, tc.atomic)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				assert.Empty(t, results)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedResults, results)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestCreateAccountsChunks validates partial imports commit every importChunkSize accounts, and report
+// the accounts of committed chunks when a later chunk fails.
+func TestCreateAccountsChunks(t *testing.T) {
+	accounts := make([]NewAccount, importChunkSize+1)
+	for i := range accounts {
+		accounts[i] = NewAccount{ID: fmt.Sprintf("acc-%d", i), Balance: decimal.Zero}
+	}
+
+	tests := []struct {
+		name            string
+		failLastChunk   bool
+		expectedResults int
+		expectedErr     string
+	}{
+		{name: "every chunk committed", expectedResults: importChunkSize + 1},
+		{name: "last chunk failed", failLastChunk: true, expectedResults: importChunkSize, expectedErr: ErrImportAccountsMsg},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			store, mock, cleanup := newTestStorage(t)
+			defer cleanup()
+
+			mock.ExpectBegin()
+			for _, acc := range accounts[:importChunkSize] {
+				mock.ExpectExec(`SAVEPOINT import_account`).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`INSERT INTO accounts`).WithArgs(acc.ID, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				expectAuditLock(mock)
+				expectOutboxInsert(mock, EventAccountCreated)
+				expectAuditAppend(mock, EventAccountCreated, acc.ID)
+				mock.ExpectExec(`RELEASE SAVEPOINT import_account`).WillReturnResult(sqlmock.NewResult(0, 0))
+			}
+			mock.ExpectCommit()
+
+			last := accounts[importChunkSize]
+			mock.ExpectBegin()
+			mock.ExpectExec(`SAVEPOINT import_account`).WillReturnResult(sqlmock.NewResult(0, 0))
+			if tc.failLastChunk {
+				mock.ExpectExec(`INSERT INTO accounts`).WithArgs(last.ID, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnError(errors.New("db error"))
+				mock.ExpectRollback()
+			} else {
+				mock.ExpectExec(`INSERT INTO accounts`).WithArgs(last.ID, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnError(&pq.Error{Code: "23505"})
+				mock.ExpectExec(`ROLLBACK TO SAVEPOINT import_account`).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			}
+
+			results, err := store.CreateAccounts(context.Background(), accounts, false)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.EqualError(t, results[importChunkSize], ErrAccountExists)
+			}
+			assert.Len(t, results, tc.expectedResults)
+			for _, res := range results[:importChunkSize] {
+				assert.NoError(t, res)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// CreateAPIKey stores a new hashed API key. CreatedAt is populated from the database.
+// Returns ErrCreateAPIKeyMsg on internal failures.
+func (p *PostgressStorage) CreateAPIKey(ctx context.Context, key *APIKey) error {
+	const query = `
+		INSERT INTO api_keys (id, client_id, key_hash, scopes)
+		VALUES ($1, $2, $3, $4)
+		RETURNING created_at
+	`
+
+	logger := p.contextLogger(ctx)
+
+	err := p.db.QueryRowContext(ctx, query, key.ID, key.ClientID, key.KeyHash, pq.Array(key.Scopes)).Scan(&key.CreatedAt)
+	if err != nil {
+		logger.Error("failed to create api key", zap.Error(err))
+		return errors.New(ErrCreateAPIKeyMsg)
+	}
+	return nil
+}
+
+// GetAPIKeyByHash fetches an API key by the hash of its plaintext value.
+// Returns ErrAPIKeyNotFound if no key matches or ErrGetAPIKeyMsg on internal failures.
+func (p *PostgressStorage) GetAPIKeyByHash(ctx context.Context, keyHash string) (*APIKey, error) {
+	const query = `
+		SELECT id, client_id, key_hash, scopes, created_at, revoked_at
+		FROM api_keys
+		WHERE key_hash = $1
+	`
+
+	logger := p.contextLogger(ctx)
+
+	key, err := scanAPIKey(p.db.QueryRowContext(ctx, query, keyHash))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New(ErrAPIKeyNotFound)
+		}
+		logger.Error("failed to get api key", zap.Error(err))
+		return nil, errors.New(ErrGetAPIKeyMsg)
+	}
+	return key, nil
+}
+
+// ListAPIKeys returns all API keys, including revoked ones, newest first.
+// Returns ErrListAPIKeysMsg on internal failures.
+func (p *PostgressStorage) ListAPIKeys(ctx context.Context) ([]APIKey, error) {
+	const query = `
+		SELECT id, client_id, key_hash, scopes, created_at, revoked_at
+		FROM api_keys
+		ORDER BY created_at DESC
+	`
+
+	logger := p.contextLogger(ctx)
+
+	rows, err := p.db.QueryContext(ctx, query)
+	if err != nil {
+		logger.Error("failed to list api keys", zap.Error(err))
+		return nil, errors.New(ErrListAPIKeysMsg)
+	}
+	defer rows.Close()
+
+	keys := make([]APIKey, 0)
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			logger.Error("failed to scan api key", zap.Error(err))
+			return nil, errors.New(ErrListAPIKeysMsg)
+		}
+		keys = append(keys, *key)
+	}
+	if err := rows.Err(); err != nil {
+		logger.Error("failed to list api keys", zap.Error(err))
+		return nil, errors.New(ErrListAPIKeysMsg)
+	}
+	return keys, nil
+}
+
+// RevokeAPIKey marks an API key as revoked. Revoking an already revoked key is a no-op.
+// Returns ErrAPIKeyNotFound if the key doesn't exist or ErrRevokeAPIKeyMsg on internal failures.
+func (p *PostgressStorage) RevokeAPIKey(ctx context.Context, keyID string) error {
+	const query = `
+		UPDATE api_keys
+		SET revoked_at = COALESCE(revoked_at, CURRENT_TIMESTAMP)
+		WHERE id = $1
+	`
+
+	logger := p.contextLogger(ctx)
+
+	res, err := p.db.ExecContext(ctx, query, keyID)
+	if err != nil {
+		logger.Error("failed to revoke api key", zap.Error(err))
+		return errors.New(ErrRevokeAPIKeyMsg)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		logger.Error("failed to revoke api key", zap.Error(err))
+		return errors.New(ErrRevokeAPIKeyMsg)
+	}
+	if n == 0 {
+		return errors.New(ErrAPIKeyNotFound)
+	}
+	return nil
+}
+
+// rowScanner is implemented by *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanAPIKey scans a single api_keys row.
+func scanAPIKey(row rowScanner) (*APIKey, error) {
+	var (
+		key       APIKey
+		revokedAt sql.NullTime
+	)
+	if err := row.Scan(&key.ID, &key.ClientID, &key.KeyHash, pq.Array(&key.Scopes), &key.CreatedAt, &revokedAt); err != nil {
+		return nil, err
+	}
+	if revokedAt.Valid {
+		t := revokedAt.Time
+		key.RevokedAt = &t
+	}
+	return &key, nil
+}
@@ -0,0 +1,153 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCreateAPIKey validates API key creation, including internal errors.
+func TestCreateAPIKey(t *testing.T) {
+	createdAt := time.Date(2025, 11, 20, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		err         error
+		expectedErr string
+	}{
+		{
+			name: "success",
+		},
+		{
+			name:        "insert error",
+			err:         errors.New("insert error"),
+			expectedErr: ErrCreateAPIKeyMsg,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			store, mock, cleanup := newTestStorage(t)
+			defer cleanup()
+
+			expect := mock.ExpectQuery(`INSERT INTO api_keys`).WithArgs("key-1", "payroll", "hash", pq.Array([]string{"accounts:read"}))
+			if tc.err != nil {
+				expect.WillReturnError(tc.err)
+			} else {
+				expect.WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(createdAt))
+			}
+
+			key := &APIKey{ID: "key-1", ClientID: "payroll", KeyHash: "hash", Scopes: []string{"accounts:read"}}
+			err := store.CreateAPIKey(context.Background(), key)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, createdAt, key.CreatedAt)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestGetAPIKeyByHash validates lookup of API keys by hash for existing, revoked and missing keys.
+func TestGetAPIKeyByHash(t *testing.T) {
+	createdAt := time.Date(2025, 11, 20, 10, 0, 0, 0, time.UTC)
+	revokedAt := createdAt.Add(time.Hour)
+	columns := []string{"id", "client_id", "key_hash", "scopes", "created_at", "revoked_at"}
+
+	tests := []struct {
+		name        string
+		prepare     func(sqlmock.Sqlmock)
+		expectedKey *APIKey
+		expectedErr string
+	}{
+		{
+			name: "success",
+			prepare: func(m sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(columns).AddRow("key-1", "payroll", "hash", "{accounts:read,transactions:write}", createdAt, nil)
+				m.ExpectQuery(`SELECT id, client_id, key_hash, scopes, created_at, revoked_at FROM api_keys`).WithArgs("hash").WillReturnRows(rows)
+			},
+			expectedKey: &APIKey{ID: "key-1", ClientID: "payroll", KeyHash: "hash", Scopes: []string{"accounts:read", "transactions:write"}, CreatedAt: createdAt},
+		},
+		{
+			name: "revoked",
+			prepare: func(m sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(columns).AddRow("key-1", "payroll", "hash", "{accounts:read}", createdAt, revokedAt)
+				m.ExpectQuery(`SELECT id, client_id, key_hash, scopes, created_at, revoked_at FROM api_keys`).WithArgs("hash").WillReturnRows(rows)
+			},
+			expectedKey: &APIKey{ID: "key-1", ClientID: "payroll", KeyHash: "hash", Scopes: []string{"accounts:read"}, CreatedAt: createdAt, RevokedAt: &revokedAt},
+		},
+		{
+			name: "not found",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(`SELECT id, client_id, key_hash, scopes, created_at, revoked_at FROM api_keys`).WithArgs("hash").WillReturnError(sql.ErrNoRows)
+			},
+			expectedErr: ErrAPIKeyNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			store, mock, cleanup := newTestStorage(t)
+			defer cleanup()
+
+			tc.prepare(mock)
+
+			key, err := store.GetAPIKeyByHash(context.Background(), "hash")
+			if tc.expectedErr != "" {
+				assert.Nil(t, key)
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedKey, key)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestRevokeAPIKey validates revocation of existing and missing API keys.
+func TestRevokeAPIKey(t *testing.T) {
+	tests := []struct {
+		name        string
+		rows        int64
+		expectedErr string
+	}{
+		{
+			name: "success",
+			rows: 1,
+		},
+		{
+			name:        "not found",
+			rows:        0,
+			expectedErr: ErrAPIKeyNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			store, mock, cleanup := newTestStorage(t)
+			defer cleanup()
+
+			mock.ExpectExec(`UPDATE api_keys SET revoked_at`).WithArgs("key-1").WillReturnResult(sqlmock.NewResult(0, tc.rows))
+
+			err := store.RevokeAPIKey(context.Background(), "key-1")
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"strconv"
+	"time"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/utils"
+	"go.uber.org/zap"
+)
+
+// auditChainLockID is the advisory lock key serializing appends to the audit chain.
+const auditChainLockID = 7_302_035
+
+// auditEmptyChainNote qualifies the verification of an empty chain, which verifies trivially.
+const auditEmptyChainNote = "the chain is empty; an empty chain cannot prove that no events were removed"
+
+// lockAuditChain takes the transaction-scoped advisory lock serializing audited writes, so every
+// audit row links to the row committed before it and outbox IDs are drawn in commit order.
+// The lock is global and held until tx ends, so every audited write in the system waits for it:
+// callers take it after their row changes, just before recording their events, to keep the time it
+// is held short. A transaction recording several events, such as a transfer batch, holds it from
+// the first event on; taking it again in the same transaction does not block.
+func lockAuditChain(ctx context.Context, tx *sql.Tx) error {
+	const query = `
+		SELECT pg_advisory_xact_lock($1)
+	`
+
+	if _, err := tx.ExecContext(ctx, query, auditChainLockID); err != nil {
+		return fmt.Errorf("lock audit chain: %w", err)
+	}
+	return nil
+}
+
+// appendAuditEvent appends an event to the hash chain within tx, which must hold the audit chain
+// lock (see lockAuditChain). The actor and request ID are taken from the context.
+func appendAuditEvent(ctx context.Context, tx *sql.Tx, eventType, entityID string, payload any) error {
+	const (
+		lastHashQuery = `
+			SELECT hash
+			FROM audit_events
+			ORDER BY id DESC
+			LIMIT 1
+		`
+		insertQuery = `
+			INSERT INTO audit_events (event_type, entity_id, actor, request_id, payload, created_at, prev_hash, hash)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		`
+	)
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal audit payload: %w", err)
+	}
+
+	var prevHash string
+	if err := tx.QueryRowContext(ctx, lastHashQuery).Scan(&prevHash); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("read audit chain head: %w", err)
+	}
+
+	event := AuditEvent{
+		EventType: eventType,
+		EntityID:  entityID,
+		Actor:     utils.ClientID(ctx),
+		RequestID: utils.RequestID(ctx),
+		Payload:   string(body),
+		// Postgres stores microseconds; truncate so the hashed value round-trips.
+		CreatedAt: time.Now().UTC().Truncate(time.Microsecond),
+		PrevHash:  prevHash,
+	}
+	event.Hash = auditHash(&event)
+
+	if _, err := tx.ExecContext(ctx, insertQuery, event.EventType, event.EntityID, event.Actor, event.RequestID,
+		event.Payload, event.CreatedAt, event.PrevHash, event.Hash); err != nil {
+		return fmt.Errorf("insert audit event: %w", err)
+	}
+	return nil
+}
+
+// VerifyAuditChain walks the audit chain in order, recomputing each row's hash and checking
+// it links to the previous row. It stops at the first broken link. An empty chain is valid, with a
+// note that it cannot show whether events were removed.
+// Returns ErrVerifyAuditChainMsg on internal failures.
+func (p *PostgressStorage) VerifyAuditChain(ctx context.Context) (*AuditVerification, error) {
+	const query = `
+		SELECT id, event_type, entity_id, actor, request_id, payload, created_at, prev_hash, hash
+		FROM audit_events
+		ORDER BY id
+	`
+
+	logger := p.contextLogger(ctx)
+
+	rows, err := p.db.QueryContext(ctx, query)
+	if err != nil {
+		logger.Error("failed to read audit events", zap.Error(err))
+		return nil, errors.New(ErrVerifyAuditChainMsg)
+	}
+	defer rows.Close()
+
+	result := &AuditVerification{Valid: true}
+	prevHash := ""
+	for rows.Next() {
+		var e AuditEvent
+		if err := rows.Scan(&e.ID, &e.EventType, &e.EntityID, &e.Actor, &e.RequestID, &e.Payload, &e.CreatedAt, &e.PrevHash, &e.Hash); err != nil {
+			logger.Error("failed to scan audit event", zap.Error(err))
+			return nil, errors.New(ErrVerifyAuditChainMsg)
+		}
+
+		switch {
+		case e.PrevHash != prevHash:
+			result.markBroken(e.ID, "prev_hash does not match the previous event's hash")
+		case auditHash(&e) != e.Hash:
+			result.markBroken(e.ID, "hash does not match the event content")
+		}
+		if !result.Valid {
+			break
+		}
+
+		result.EventsChecked++
+		result.HeadHash = e.Hash
+		prevHash = e.Hash
+	}
+	if err := rows.Err(); err != nil {
+		logger.Error("failed to read audit events", zap.Error(err))
+		return nil, errors.New(ErrVerifyAuditChainMsg)
+	}
+	if result.EventsChecked == 0 && result.Valid {
+		result.Note = auditEmptyChainNote
+	}
+	return result, nil
+}
+
+// auditHash computes the SHA-256 over the previous hash and the event content.
+// Each field is length-prefixed so field boundaries cannot be shifted.
+func auditHash(e *AuditEvent) string {
+	h := sha256.New()
+	for _, field := range []string{
+		e.PrevHash,
+		e.EventType,
+		e.EntityID,
+		e.Actor,
+		e.RequestID,
+		e.CreatedAt.UTC().Format(time.RFC3339Nano),
+		e.Payload,
+	} {
+		writeField(h, field)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// writeField writes a length-prefixed field to h.
+func writeField(h hash.Hash, field string) {
+	h.Write([]byte(strconv.Itoa(len(field))))
+	h.Write([]byte{':'})
+	h.Write([]byte(field))
+}
@@ -0,0 +1,125 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// expectAuditLock registers the query lockAuditChain runs.
+func expectAuditLock(m sqlmock.Sqlmock) {
+	m.ExpectExec(`SELECT pg_advisory_xact_lock`).WithArgs(auditChainLockID).WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+// expectAuditAppend registers the queries appendAuditEvent runs against a chain whose head hash is "prev".
+func expectAuditAppend(m sqlmock.Sqlmock, eventType, entityID string) {
+	m.ExpectQuery(`SELECT hash FROM audit_events`).WillReturnRows(sqlmock.NewRows([]string{"hash"}).AddRow("prev"))
+	m.ExpectExec(`INSERT INTO audit_events`).
+		WithArgs(eventType, entityID, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "prev", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
+// buildChain returns a valid chain of n events.
+func buildChain(n int) []AuditEvent {
+	base := time.Date(2025, 12, 1, 12, 0, 0, 0, time.UTC)
+	events := make([]AuditEvent, 0, n)
+	prev := ""
+	for i := 0; i < n; i++ {
+		e := AuditEvent{
+			ID:        int64(i + 1),
+			EventType: EventTransferCompleted,
+			EntityID:  "acc-1",
+			Actor:     "payroll",
+			RequestID: "req",
+			Payload:   `{"amount":"10"}`,
+			CreatedAt: base.Add(time.Duration(i) * time.Second),
+			PrevHash:  prev,
+		}
+		e.Hash = auditHash(&e)
+		prev = e.Hash
+		events = append(events, e)
+	}
+	return events
+}
+
+// TestVerifyAuditChain validates chain verification for intact chains, altered content and broken links.
+func TestVerifyAuditChain(t *testing.T) {
+	columns := []string{"id", "event_type", "entity_id", "actor", "request_id", "payload", "created_at", "prev_hash", "hash"}
+
+	tests := []struct {
+		name     string
+		mutate   func(events []AuditEvent)
+		expected AuditVerification
+	}{
+		{
+			name: "intact chain",
+			expected: AuditVerification{
+				Valid:         true,
+				EventsChecked: 3,
+			},
+		},
+		{
+			name: "altered payload",
+			mutate: func(events []AuditEvent) {
+				events[1].Payload = `{"amount":"10000"}`
+			},
+			expected: AuditVerification{
+				EventsChecked: 1,
+				BrokenAtID:    2,
+				Reason:        "hash does not match the event content",
+			},
+		},
+		{
+			name: "deleted row",
+			mutate: func(events []AuditEvent) {
+				events[1] = events[2]
+			},
+			expected: AuditVerification{
+				EventsChecked: 1,
+				BrokenAtID:    3,
+				Reason:        "prev_hash does not match the previous event's hash",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			store, mock, cleanup := newTestStorage(t)
+			defer cleanup()
+
+			events := buildChain(3)
+			if tc.mutate != nil {
+				tc.mutate(events)
+			}
+			rows := sqlmock.NewRows(columns)
+			for _, e := range events {
+				rows.AddRow(e.ID, e.EventType, e.EntityID, e.Actor, e.RequestID, e.Payload, e.CreatedAt, e.PrevHash, e.Hash)
+			}
+			mock.ExpectQuery(`SELECT id, event_type, entity_id, actor, request_id, payload, created_at, prev_hash, hash FROM audit_events`).WillReturnRows(rows)
+
+			result, err := store.VerifyAuditChain(context.Background())
+			assert.NoError(t, err)
+			tc.expected.HeadHash = events[tc.expected.EventsChecked-1].Hash
+			assert.Equal(t, &tc.expected, result)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestVerifyAuditChainEmpty validates an empty chain verifies with a note that it cannot show removals.
+func TestVerifyAuditChainEmpty(t *testing.T) {
+	store, mock, cleanup := newTestStorage(t)
+	defer cleanup()
+
+	columns := []string{"id", "event_type", "entity_id", "actor", "request_id", "payload", "created_at", "prev_hash", "hash"}
+	mock.ExpectQuery(`SELECT id, event_type, entity_id, actor, request_id, payload, created_at, prev_hash, hash FROM audit_events`).
+		WillReturnRows(sqlmock.NewRows(columns))
+
+	result, err := store.VerifyAuditChain(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, &AuditVerification{Valid: true, Note: auditEmptyChainNote}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// balanceAtExpr is the balance of account a at time $1: its latest snapshot s at or before then,
+	// or its opening balance if there is none, plus the transfers made since.
+	balanceAtExpr = `
+		COALESCE(s.balance, a.opening_balance)
+		+ COALESCE((
+			SELECT SUM(amount) FROM transactions
+			WHERE destination_account_id = a.id
+			  AND created_at > COALESCE(s.as_of, '-infinity') AND created_at <= $1::TIMESTAMPTZ
+		), 0)
+		- COALESCE((
+			SELECT SUM(amount) FROM transactions
+			WHERE source_account_id = a.id
+			  AND created_at > COALESCE(s.as_of, '-infinity') AND created_at <= $1::TIMESTAMPTZ
+		), 0)
+	`
+	// latestSnapshotJoin joins account a to its latest snapshot s at or before $1, if any.
+	latestSnapshotJoin = `
+		LEFT JOIN LATERAL (
+			SELECT as_of, balance FROM account_balance_snapshots
+			WHERE account_id = a.id AND as_of <= $1::TIMESTAMPTZ
+			ORDER BY as_of DESC
+			LIMIT 1
+		) s ON true
+	`
+	// balanceAtQuery selects the currency, creation time and balance at time $1 of account $2.
+	balanceAtQuery = `
+		SELECT a.currency, a.created_at, ` + balanceAtExpr + `
+		FROM accounts a
+		` + latestSnapshotJoin + `
+		WHERE a.id = $2
+	`
+)
+
+// GetBalanceAt returns the account's balance at asOf, computed from its transfer history.
+// Returns ErrAccountNotFound if the account doesn't exist, ErrAccountNotOpenMsg if it was created
+// after asOf, or ErrGetBalanceMsg on internal failures.
+func (p *PostgressStorage) GetBalanceAt(ctx context.Context, accountID string, asOf time.Time) (*AccountBalance, error) {
+	balance := &AccountBalance{AccountID: accountID, AsOf: asOf}
+	var createdAt time.Time
+	err := p.db.QueryRowContext(ctx, balanceAtQuery, asOf, accountID).Scan(&balance.Currency, &createdAt, &balance.Balance)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New(ErrAccountNotFound)
+		}
+		p.contextLogger(ctx).Error("failed to get balance", zap.Error(err))
+		return nil, errors.New(ErrGetBalanceMsg)
+	}
+	if asOf.Before(createdAt) {
+		return nil, errors.New(ErrAccountNotOpenMsg)
+	}
+	return balance, nil
+}
+
+// CreateBalanceSnapshots records the balance at asOf of every account that existed then, building on
+// each account's previous snapshot, and returns how many were recorded. Accounts already snapshotted
+// at asOf are skipped, so it is safe to repeat. Transfers are timestamped when their transaction
+// starts, so while a transaction that started at or before asOf is still open a transfer dated before
+// asOf may yet commit; ErrSnapshotUnsettledMsg is returned then and nothing is recorded. Returns
+// ErrCreateSnapshotsMsg on internal failures.
+func (p *PostgressStorage) CreateBalanceSnapshots(ctx context.Context, asOf time.Time) (int64, error) {
+	const (
+		// Transactions starting after this check start after asOf, so their transfers fall after it too.
+		openTransactionsQuery = `
+			SELECT EXISTS (
+				SELECT 1
+				FROM pg_stat_activity
+				WHERE datname = current_database()
+				  AND pid <> pg_backend_pid()
+				  AND xact_start <= $1::TIMESTAMPTZ
+			)
+		`
+		query = `
+			INSERT INTO account_balance_snapshots (account_id, as_of, balance)
+			SELECT a.id, $1::TIMESTAMPTZ, ` + balanceAtExpr + `
+			FROM accounts a
+			` + latestSnapshotJoin + `
+			WHERE a.created_at <= $1::TIMESTAMPTZ
+			ON CONFLICT (account_id, as_of) DO NOTHING
+		`
+	)
+
+	var open bool
+	if err := p.db.QueryRowContext(ctx, openTransactionsQuery, asOf).Scan(&open); err != nil {
+		p.contextLogger(ctx).Error("failed to check for open transactions", zap.Error(err))
+		return 0, errors.New(ErrCreateSnapshotsMsg)
+	}
+	if open {
+		return 0, errors.New(ErrSnapshotUnsettledMsg)
+	}
+
+	result, err := p.db.ExecContext(ctx, query, asOf)
+	if err != nil {
+		p.contextLogger(ctx).Error("failed to create balance snapshots", zap.Error(err))
+		return 0, errors.New(ErrCreateSnapshotsMsg)
+	}
+	created, err := result.RowsAffected()
+	if err != nil {
+		p.contextLogger(ctx).Error("failed to count balance snapshots", zap.Error(err))
+		return 0, errors.New(ErrCreateSnapshotsMsg)
+	}
+	return created, nil
+}
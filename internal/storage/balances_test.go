@@ -0,0 +1,146 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGetBalanceAt validates point-in-time balances, accounts created after the requested time,
+// missing accounts and database errors.
+func TestGetBalanceAt(t *testing.T) {
+	asOf := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	columns := []string{"currency", "created_at", "balance"}
+
+	tests := []struct {
+		name        string
+		prepare     func(sqlmock.Sqlmock)
+		expected    *AccountBalance
+		expectedErr string
+	}{
+		{
+			name: "success",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(`SELECT a.currency, a.created_at,.*FROM accounts a.*account_balance_snapshots.*WHERE a.id = \$2`).
+					WithArgs(asOf, "acc-1").
+					WillReturnRows(sqlmock.NewRows(columns).AddRow("EUR", asOf.Add(-time.Hour), "42.5"))
+			},
+			expected: &AccountBalance{AccountID: "acc-1", Balance: decimal.RequireFromString("42.5"), Currency: "EUR", AsOf: asOf},
+		},
+		{
+			name: "account created later",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(`SELECT a.currency`).
+					WithArgs(asOf, "acc-1").
+					WillReturnRows(sqlmock.NewRows(columns).AddRow("EUR", asOf.Add(time.Second), "0"))
+			},
+			expectedErr: ErrAccountNotOpenMsg,
+		},
+		{
+			name: "not found",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(`SELECT a.currency`).WithArgs(asOf, "acc-1").WillReturnError(sql.ErrNoRows)
+			},
+			expectedErr: ErrAccountNotFound,
+		},
+		{
+			name: "db error",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(`SELECT a.currency`).WithArgs(asOf, "acc-1").WillReturnError(errors.New("db error"))
+			},
+			expectedErr: ErrGetBalanceMsg,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, mock, cleanup := newTestStorage(t)
+			defer cleanup()
+			tt.prepare(mock)
+
+			balance, err := store.GetBalanceAt(context.Background(), "acc-1", asOf)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				assert.Nil(t, balance)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, balance)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestCreateBalanceSnapshots validates that snapshots are inserted idempotently for accounts open at
+// the snapshot time, that none are recorded while an older transaction is open, and that database
+// errors are reported.
+func TestCreateBalanceSnapshots(t *testing.T) {
+	asOf := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	expectOpenCheck := func(m sqlmock.Sqlmock) *sqlmock.ExpectedQuery {
+		return m.ExpectQuery(`SELECT EXISTS \(\s*SELECT 1\s+FROM pg_stat_activity.*xact_start <= \$1::TIMESTAMPTZ`).WithArgs(asOf)
+	}
+
+	tests := []struct {
+		name            string
+		prepare         func(m sqlmock.Sqlmock)
+		expectedCreated int64
+		expectedErr     string
+	}{
+		{
+			name: "success",
+			prepare: func(m sqlmock.Sqlmock) {
+				expectOpenCheck(m).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+				m.ExpectExec(`INSERT INTO account_balance_snapshots.*WHERE a.created_at <= \$1::TIMESTAMPTZ\s+ON CONFLICT \(account_id, as_of\) DO NOTHING`).
+					WithArgs(asOf).
+					WillReturnResult(sqlmock.NewResult(0, 3))
+			},
+			expectedCreated: 3,
+		},
+		{
+			name: "transaction older than the boundary still open",
+			prepare: func(m sqlmock.Sqlmock) {
+				expectOpenCheck(m).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+			},
+			expectedErr: ErrSnapshotUnsettledMsg,
+		},
+		{
+			name: "open transaction check error",
+			prepare: func(m sqlmock.Sqlmock) {
+				expectOpenCheck(m).WillReturnError(errors.New("db error"))
+			},
+			expectedErr: ErrCreateSnapshotsMsg,
+		},
+		{
+			name: "insert error",
+			prepare: func(m sqlmock.Sqlmock) {
+				expectOpenCheck(m).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+				m.ExpectExec(`INSERT INTO account_balance_snapshots`).WithArgs(asOf).WillReturnError(errors.New("db error"))
+			},
+			expectedErr: ErrCreateSnapshotsMsg,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			store, mock, cleanup := newTestStorage(t)
+			defer cleanup()
+			tc.prepare(mock)
+
+			created, err := store.CreateBalanceSnapshots(context.Background(), asOf)
+			if tc.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedErr)
+			}
+			assert.Equal(t, tc.expectedCreated, created)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/utils"
+	"go.uber.org/zap"
+)
+
+// errBatchRefused rolls back a batch whose every transfer was refused.
+var errBatchRefused = errors.New("every transfer of the batch was refused")
+
+// ProcessTransferBatch makes the transfers of a batch in order within one DB transaction and records
+// the batch under batchID for the calling client. Each transfer is checked like ProcessTransaction; a
+// refused transfer is rolled back on its own, with its error in its result, and the others go ahead.
+// If every transfer is refused nothing is recorded, so a corrected batch can be resubmitted under the
+// same ID. Returns ErrBatchExistsMsg if the client has already processed a batch with this ID, and
+// ErrProcessBatchMsg on internal failures, in which case no transfer is made.
+func (p *PostgressStorage) ProcessTransferBatch(ctx context.Context, batchID string, transfers []Transfer) ([]TransferResult, error) {
+	const (
+		insertBatchQuery = `
+			INSERT INTO transfer_batches (client_id, batch_id, transfer_count)
+			VALUES ($1, $2, $3)
+			ON CONFLICT DO NOTHING
+		`
+		savepointQuery         = `SAVEPOINT batch_transfer`
+		rollbackSavepointQuery = `ROLLBACK TO SAVEPOINT batch_transfer`
+		releaseSavepointQuery  = `RELEASE SAVEPOINT batch_transfer`
+	)
+
+	logger := p.contextLogger(ctx)
+	results := make([]TransferResult, len(transfers))
+
+	err := p.withTx(ctx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, insertBatchQuery, utils.ClientID(ctx), batchID, len(transfers))
+		if err != nil {
+			logger.Error("failed to record transfer batch", zap.Error(err))
+			return errors.New(ErrProcessBatchMsg)
+		}
+		if n, err := res.RowsAffected(); err != nil || n == 0 {
+			logger.Warn("transfer batch has already been processed", zap.String("batch_id", batchID))
+			return errors.New(ErrBatchExistsMsg)
+		}
+
+		// Every account of the batch is locked up front: transfers later in the batch run while this
+		// transaction holds the audit chain lock, so locking their rows then could deadlock against a
+		// single transfer that holds one of those rows and waits for the audit chain.
+		accountIDs := make([]string, 0, 2*len(transfers))
+		for _, t := range transfers {
+			accountIDs = append(accountIDs, t.SourceAccountID, t.DestinationAccountID)
+		}
+		if err := lockAccounts(ctx, tx, accountIDs...); err != nil {
+			logger.Error("failed to lock batch accounts", zap.Error(err))
+			return errors.New(ErrProcessBatchMsg)
+		}
+
+		accepted := 0
+		for i, t := range transfers {
+			if _, err := tx.ExecContext(ctx, savepointQuery); err != nil {
+				logger.Error("failed to create savepoint", zap.Error(err))
+				return errors.New(ErrProcessBatchMsg)
+			}
+
+			transactionID, err := p.transfer(ctx, tx, t, AnyVersion)
+			if err != nil {
+				if err.Error() == ErrProcessTransactionMsg {
+					return errors.New(ErrProcessBatchMsg)
+				}
+				if _, err := tx.ExecContext(ctx, rollbackSavepointQuery); err != nil {
+					logger.Error("failed to roll back to savepoint", zap.Error(err))
+					return errors.New(ErrProcessBatchMsg)
+				}
+				results[i].Err = err
+				continue
+			}
+
+			if _, err := tx.ExecContext(ctx, releaseSavepointQuery); err != nil {
+				logger.Error("failed to release savepoint", zap.Error(err))
+				return errors.New(ErrProcessBatchMsg)
+			}
+			results[i].TransactionID = transactionID
+			accepted++
+		}
+
+		if accepted == 0 {
+			return errBatchRefused
+		}
+		return nil
+	}, ErrProcessBatchMsg)
+	if err != nil && !errors.Is(err, errBatchRefused) {
+		return nil, err
+	}
+
+	logger.Info("transfer batch processed", zap.String("batch_id", batchID), zap.Int("transfers", len(transfers)))
+	return results, nil
+}
@@ -0,0 +1,197 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/cursed-ninja/internal-transfers-system/internal/utils"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// expectBatchTransfer expects one successful transfer of amount from source to dest in EUR.
+func expectBatchTransfer(m sqlmock.Sqlmock, source, dest, amount string, transactionID int64) {
+	amt := decimal.RequireFromString(amount)
+	m.ExpectExec(`SAVEPOINT batch_transfer`).WillReturnResult(sqlmock.NewResult(0, 0))
+	expectAccountLock(m, min(source, dest), max(source, dest)).WillReturnResult(sqlmock.NewResult(0, 2))
+	m.ExpectQuery(`SELECT currency, status FROM accounts`).WithArgs(dest).WillReturnRows(sqlmock.NewRows([]string{"currency", "status"}).AddRow("EUR", AccountStatusActive))
+	m.ExpectQuery(`SELECT balance, currency, status, version FROM accounts`).WithArgs(source).WillReturnRows(sqlmock.NewRows([]string{"balance", "currency", "status", "version"}).AddRow("500", "EUR", AccountStatusActive, 3))
+	m.ExpectQuery(`UPDATE accounts SET balance = balance -`).WithArgs(amt, source).WillReturnRows(sqlmock.NewRows([]string{"balance", "version"}).AddRow("400", 4))
+	m.ExpectQuery(`UPDATE accounts SET balance = balance +`).WithArgs(amt, dest).WillReturnRows(sqlmock.NewRows([]string{"balance", "version"}).AddRow("100", 2))
+	m.ExpectQuery(`INSERT INTO transactions`).WithArgs(source, dest, amt, "req-1", "client-1").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(transactionID))
+	expectAuditLock(m)
+	expectOutboxInsert(m, EventTransferCompleted)
+	expectAuditAppend(m, EventTransferCompleted, decimal.NewFromInt(transactionID).String())
+	m.ExpectExec(`RELEASE SAVEPOINT batch_transfer`).WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+// expectRefusedTransfer expects a transfer from payroll refused because its destination account does not exist.
+func expectRefusedTransfer(m sqlmock.Sqlmock, dest string) {
+	m.ExpectExec(`SAVEPOINT batch_transfer`).WillReturnResult(sqlmock.NewResult(0, 0))
+	expectAccountLock(m, dest, "payroll").WillReturnResult(sqlmock.NewResult(0, 1))
+	m.ExpectQuery(`SELECT currency, status FROM accounts`).WithArgs(dest).WillReturnError(sql.ErrNoRows)
+	m.ExpectExec(`ROLLBACK TO SAVEPOINT batch_transfer`).WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+// TestProcessTransferBatch validates batches where every transfer is made, some are refused, every
+// one is refused, the batch was already processed, and internal failures.
+func TestProcessTransferBatch(t *testing.T) {
+	transfers := []Transfer{
+		{SourceAccountID: "payroll", DestinationAccountID: "emp-1", Amount: decimal.RequireFromString("100"), Currency: "EUR"},
+		{SourceAccountID: "payroll", DestinationAccountID: "emp-2", Amount: decimal.RequireFromString("50")},
+	}
+	insertBatch := func(m sqlmock.Sqlmock) *sqlmock.ExpectedExec {
+		return m.ExpectExec(`INSERT INTO transfer_batches`).WithArgs("client-1", "msg-1", 2)
+	}
+
+	tests := []struct {
+		name            string
+		transfers       []Transfer
+		prepare         func(sqlmock.Sqlmock)
+		expectedResults []TransferResult
+		expectedErr     string
+	}{
+		{
+			name:      "every transfer made",
+			transfers: transfers,
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				insertBatch(m).WillReturnResult(sqlmock.NewResult(0, 1))
+				expectAccountLock(m, "emp-1", "emp-2", "payroll").WillReturnResult(sqlmock.NewResult(0, 3))
+				expectBatchTransfer(m, "payroll", "emp-1", "100", 7)
+				expectBatchTransfer(m, "payroll", "emp-2", "50", 8)
+				m.ExpectCommit()
+			},
+			expectedResults: []TransferResult{{TransactionID: 7}, {TransactionID: 8}},
+		},
+		{
+			name:      "refused transfer rolled back on its own",
+			transfers: transfers,
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				insertBatch(m).WillReturnResult(sqlmock.NewResult(0, 1))
+				expectAccountLock(m, "emp-1", "emp-2", "payroll").WillReturnResult(sqlmock.NewResult(0, 3))
+				expectRefusedTransfer(m, "emp-1")
+				expectBatchTransfer(m, "payroll", "emp-2", "50", 8)
+				m.ExpectCommit()
+			},
+			expectedResults: []TransferResult{{Err: errors.New(ErrDestinationAccountMsg)}, {TransactionID: 8}},
+		},
+		{
+			name: "instructed currency differs",
+			transfers: []Transfer{
+				{SourceAccountID: "payroll", DestinationAccountID: "emp-1", Amount: decimal.RequireFromString("100"), Currency: "USD"},
+				transfers[1],
+			},
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				insertBatch(m).WillReturnResult(sqlmock.NewResult(0, 1))
+				expectAccountLock(m, "emp-1", "emp-2", "payroll").WillReturnResult(sqlmock.NewResult(0, 3))
+				m.ExpectExec(`SAVEPOINT batch_transfer`).WillReturnResult(sqlmock.NewResult(0, 0))
+				expectAccountLock(m, "emp-1", "payroll").WillReturnResult(sqlmock.NewResult(0, 2))
+				m.ExpectQuery(`SELECT currency, status FROM accounts`).WithArgs("emp-1").WillReturnRows(sqlmock.NewRows([]string{"currency", "status"}).AddRow("EUR", AccountStatusActive))
+				m.ExpectQuery(`SELECT balance, currency, status, version FROM accounts`).WithArgs("payroll").WillReturnRows(sqlmock.NewRows([]string{"balance", "currency", "status", "version"}).AddRow("500", "EUR", AccountStatusActive, 3))
+				m.ExpectExec(`ROLLBACK TO SAVEPOINT batch_transfer`).WillReturnResult(sqlmock.NewResult(0, 0))
+				expectBatchTransfer(m, "payroll", "emp-2", "50", 8)
+				m.ExpectCommit()
+			},
+			expectedResults: []TransferResult{{Err: errors.New(ErrTransferCurrencyMsg)}, {TransactionID: 8}},
+		},
+		{
+			name:      "every transfer refused",
+			transfers: transfers,
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				insertBatch(m).WillReturnResult(sqlmock.NewResult(0, 1))
+				expectAccountLock(m, "emp-1", "emp-2", "payroll").WillReturnResult(sqlmock.NewResult(0, 3))
+				expectRefusedTransfer(m, "emp-1")
+				expectRefusedTransfer(m, "emp-2")
+				m.ExpectRollback()
+			},
+			expectedResults: []TransferResult{{Err: errors.New(ErrDestinationAccountMsg)}, {Err: errors.New(ErrDestinationAccountMsg)}},
+		},
+		{
+			name:      "batch already processed",
+			transfers: transfers,
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				insertBatch(m).WillReturnResult(sqlmock.NewResult(0, 0))
+				m.ExpectRollback()
+			},
+			expectedErr: ErrBatchExistsMsg,
+		},
+		{
+			name:      "record batch error",
+			transfers: transfers,
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				insertBatch(m).WillReturnError(errors.New("insert error"))
+				m.ExpectRollback()
+			},
+			expectedErr: ErrProcessBatchMsg,
+		},
+		{
+			name:      "internal transfer error aborts the batch",
+			transfers: transfers,
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				insertBatch(m).WillReturnResult(sqlmock.NewResult(0, 1))
+				expectAccountLock(m, "emp-1", "emp-2", "payroll").WillReturnResult(sqlmock.NewResult(0, 3))
+				expectBatchTransfer(m, "payroll", "emp-1", "100", 7)
+				m.ExpectExec(`SAVEPOINT batch_transfer`).WillReturnResult(sqlmock.NewResult(0, 0))
+				expectAccountLock(m, "emp-2", "payroll").WillReturnResult(sqlmock.NewResult(0, 2))
+				m.ExpectQuery(`SELECT currency, status FROM accounts`).WithArgs("emp-2").WillReturnError(errors.New("connection reset"))
+				m.ExpectRollback()
+			},
+			expectedErr: ErrProcessBatchMsg,
+		},
+		{
+			name:      "lock accounts error",
+			transfers: transfers,
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				insertBatch(m).WillReturnResult(sqlmock.NewResult(0, 1))
+				expectAccountLock(m, "emp-1", "emp-2", "payroll").WillReturnError(errors.New("lock timeout"))
+				m.ExpectRollback()
+			},
+			expectedErr: ErrProcessBatchMsg,
+		},
+		{
+			name:      "savepoint error",
+			transfers: transfers,
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				insertBatch(m).WillReturnResult(sqlmock.NewResult(0, 1))
+				expectAccountLock(m, "emp-1", "emp-2", "payroll").WillReturnResult(sqlmock.NewResult(0, 3))
+				m.ExpectExec(`SAVEPOINT batch_transfer`).WillReturnError(errors.New("savepoint error"))
+				m.ExpectRollback()
+			},
+			expectedErr: ErrProcessBatchMsg,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := utils.WithRequestID(context.Background(), "req-1")
+			ctx = utils.WithClientID(ctx, "client-1")
+			store, mock, cleanup := newTestStorage(t)
+			defer cleanup()
+
+			tc.prepare(mock)
+
+			results, err := store.ProcessTransferBatch(ctx, "msg-1", tc.transfers)
+			if tc.expectedErr == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedResults, results)
+			} else {
+				assert.EqualError(t, err, tc.expectedErr)
+				assert.Nil(t, results)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// outboxChannel is the notification channel the outbox_notify trigger publishes to.
+const outboxChannel = "outbox_events"
+
+// OutboxListener receives Postgres notifications for committed outbox events on a dedicated connection.
+type OutboxListener struct {
+	listener *pq.Listener
+	wake     chan struct{}
+	done     chan struct{}
+}
+
+// NewOutboxListener opens a LISTEN connection to the outbox_events channel. The connection is
+// re-established automatically if it drops.
+func NewOutboxListener(connStr string, logger *zap.Logger) (*OutboxListener, error) {
+	report := func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			logger.Warn("outbox listener connection event", zap.Int("event", int(ev)), zap.Error(err))
+		}
+	}
+	listener := pq.NewListener(connStr, time.Second, time.Minute, report)
+	if err := listener.Listen(outboxChannel); err != nil {
+		_ = listener.Close()
+		return nil, err
+	}
+
+	l := &OutboxListener{
+		listener: listener,
+		wake:     make(chan struct{}, 1),
+		done:     make(chan struct{}),
+	}
+	go l.forward()
+	return l, nil
+}
+
+// Wake returns a channel that receives a value whenever new outbox events may have been committed.
+// Signals are coalesced; after a reconnect a signal is sent so missed notifications are caught up.
+func (l *OutboxListener) Wake() <-chan struct{} {
+	return l.wake
+}
+
+// Close stops listening and closes the connection.
+func (l *OutboxListener) Close() error {
+	close(l.done)
+	return l.listener.Close()
+}
+
+// forward turns notifications into coalesced wake-ups. pq sends a nil notification after reconnecting.
+func (l *OutboxListener) forward() {
+	for {
+		select {
+		case <-l.done:
+			return
+		case _, ok := <-l.listener.Notify:
+			if !ok {
+				return
+			}
+			select {
+			case l.wake <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
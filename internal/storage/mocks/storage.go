@@ -12,6 +12,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	storage "github.com/cursed-ninja/internal-transfers-system/internal/storage"
 	decimal "github.com/shopspring/decimal"
@@ -42,18 +43,165 @@ func (m *MockStorage) EXPECT() *MockStorageMockRecorder {
 	return m.recorder
 }
 
+// ClaimWebhookDeliveries mocks base method.
+func (m *MockStorage) ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]storage.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimWebhookDeliveries", ctx, limit, lease)
+	ret0, _ := ret[0].([]storage.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimWebhookDeliveries indicates an expected call of ClaimWebhookDeliveries.
+func (mr *MockStorageMockRecorder) ClaimWebhookDeliveries(ctx, limit, lease any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimWebhookDeliveries", reflect.TypeOf((*MockStorage)(nil).ClaimWebhookDeliveries), ctx, limit, lease)
+}
+
+// CloseAccount mocks base method.
+func (m *MockStorage) CloseAccount(ctx context.Context, accountID string, version int64) (*storage.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseAccount", ctx, accountID, version)
+	ret0, _ := ret[0].(*storage.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseAccount indicates an expected call of CloseAccount.
+func (mr *MockStorageMockRecorder) CloseAccount(ctx, accountID, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseAccount", reflect.TypeOf((*MockStorage)(nil).CloseAccount), ctx, accountID, version)
+}
+
+// CountAccounts mocks base method.
+func (m *MockStorage) CountAccounts(ctx context.Context, query storage.AccountQuery) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountAccounts", ctx, query)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountAccounts indicates an expected call of CountAccounts.
+func (mr *MockStorageMockRecorder) CountAccounts(ctx, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAccounts", reflect.TypeOf((*MockStorage)(nil).CountAccounts), ctx, query)
+}
+
+// CreateAPIKey mocks base method.
+func (m *MockStorage) CreateAPIKey(ctx context.Context, key *storage.APIKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *MockStorageMockRecorder) CreateAPIKey(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockStorage)(nil).CreateAPIKey), ctx, key)
+}
+
 // CreateAccount mocks base method.
-func (m *MockStorage) CreateAccount(ctx context.Context, accountID string, balance decimal.Decimal) error {
+func (m *MockStorage) CreateAccount(ctx context.Context, accountID string, balance decimal.Decimal, attrs storage.AccountAttributes) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateAccount", ctx, accountID, balance)
+	ret := m.ctrl.Call(m, "CreateAccount", ctx, accountID, balance, attrs)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CreateAccount indicates an expected call of CreateAccount.
-func (mr *MockStorageMockRecorder) CreateAccount(ctx, accountID, balance any) *gomock.Call {
+func (mr *MockStorageMockRecorder) CreateAccount(ctx, accountID, balance, attrs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockStorage)(nil).CreateAccount), ctx, accountID, balance, attrs)
+}
+
+// CreateAccounts mocks base method.
+func (m *MockStorage) CreateAccounts(ctx context.Context, accounts []storage.NewAccount, atomic bool) ([]error, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAccounts", ctx, accounts, atomic)
+	ret0, _ := ret[0].([]error)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAccounts indicates an expected call of CreateAccounts.
+func (mr *MockStorageMockRecorder) CreateAccounts(ctx, accounts, atomic any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccounts", reflect.TypeOf((*MockStorage)(nil).CreateAccounts), ctx, accounts, atomic)
+}
+
+// CreateBalanceSnapshots mocks base method.
+func (m *MockStorage) CreateBalanceSnapshots(ctx context.Context, asOf time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBalanceSnapshots", ctx, asOf)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBalanceSnapshots indicates an expected call of CreateBalanceSnapshots.
+func (mr *MockStorageMockRecorder) CreateBalanceSnapshots(ctx, asOf any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBalanceSnapshots", reflect.TypeOf((*MockStorage)(nil).CreateBalanceSnapshots), ctx, asOf)
+}
+
+// CreateWebhookSubscription mocks base method.
+func (m *MockStorage) CreateWebhookSubscription(ctx context.Context, sub *storage.WebhookSubscription) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookSubscription", ctx, sub)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateWebhookSubscription indicates an expected call of CreateWebhookSubscription.
+func (mr *MockStorageMockRecorder) CreateWebhookSubscription(ctx, sub any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookSubscription", reflect.TypeOf((*MockStorage)(nil).CreateWebhookSubscription), ctx, sub)
+}
+
+// DeleteWebhookSubscription mocks base method.
+func (m *MockStorage) DeleteWebhookSubscription(ctx context.Context, subscriptionID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhookSubscription", ctx, subscriptionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhookSubscription indicates an expected call of DeleteWebhookSubscription.
+func (mr *MockStorageMockRecorder) DeleteWebhookSubscription(ctx, subscriptionID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockStorage)(nil).CreateAccount), ctx, accountID, balance)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhookSubscription", reflect.TypeOf((*MockStorage)(nil).DeleteWebhookSubscription), ctx, subscriptionID)
+}
+
+// EnqueueWebhookDeliveries mocks base method.
+func (m *MockStorage) EnqueueWebhookDeliveries(ctx context.Context, event storage.OutboxEvent) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueWebhookDeliveries", ctx, event)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnqueueWebhookDeliveries indicates an expected call of EnqueueWebhookDeliveries.
+func (mr *MockStorageMockRecorder) EnqueueWebhookDeliveries(ctx, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueWebhookDeliveries", reflect.TypeOf((*MockStorage)(nil).EnqueueWebhookDeliveries), ctx, event)
+}
+
+// GetAPIKeyByHash mocks base method.
+func (m *MockStorage) GetAPIKeyByHash(ctx context.Context, keyHash string) (*storage.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKeyByHash", ctx, keyHash)
+	ret0, _ := ret[0].(*storage.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKeyByHash indicates an expected call of GetAPIKeyByHash.
+func (mr *MockStorageMockRecorder) GetAPIKeyByHash(ctx, keyHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeyByHash", reflect.TypeOf((*MockStorage)(nil).GetAPIKeyByHash), ctx, keyHash)
 }
 
 // GetAccountDetails mocks base method.
@@ -71,16 +219,339 @@ func (mr *MockStorageMockRecorder) GetAccountDetails(ctx, accountID any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountDetails", reflect.TypeOf((*MockStorage)(nil).GetAccountDetails), ctx, accountID)
 }
 
+// GetBalanceAt mocks base method.
+func (m *MockStorage) GetBalanceAt(ctx context.Context, accountID string, asOf time.Time) (*storage.AccountBalance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalanceAt", ctx, accountID, asOf)
+	ret0, _ := ret[0].(*storage.AccountBalance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalanceAt indicates an expected call of GetBalanceAt.
+func (mr *MockStorageMockRecorder) GetBalanceAt(ctx, accountID, asOf any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalanceAt", reflect.TypeOf((*MockStorage)(nil).GetBalanceAt), ctx, accountID, asOf)
+}
+
+// GetWebhookDelivery mocks base method.
+func (m *MockStorage) GetWebhookDelivery(ctx context.Context, deliveryID int64) (*storage.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDelivery", ctx, deliveryID)
+	ret0, _ := ret[0].(*storage.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDelivery indicates an expected call of GetWebhookDelivery.
+func (mr *MockStorageMockRecorder) GetWebhookDelivery(ctx, deliveryID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDelivery", reflect.TypeOf((*MockStorage)(nil).GetWebhookDelivery), ctx, deliveryID)
+}
+
+// GetWebhookSubscription mocks base method.
+func (m *MockStorage) GetWebhookSubscription(ctx context.Context, subscriptionID string) (*storage.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookSubscription", ctx, subscriptionID)
+	ret0, _ := ret[0].(*storage.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookSubscription indicates an expected call of GetWebhookSubscription.
+func (mr *MockStorageMockRecorder) GetWebhookSubscription(ctx, subscriptionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookSubscription", reflect.TypeOf((*MockStorage)(nil).GetWebhookSubscription), ctx, subscriptionID)
+}
+
+// LatestEventID mocks base method.
+func (m *MockStorage) LatestEventID(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LatestEventID", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LatestEventID indicates an expected call of LatestEventID.
+func (mr *MockStorageMockRecorder) LatestEventID(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LatestEventID", reflect.TypeOf((*MockStorage)(nil).LatestEventID), ctx)
+}
+
+// ListAPIKeys mocks base method.
+func (m *MockStorage) ListAPIKeys(ctx context.Context) ([]storage.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAPIKeys", ctx)
+	ret0, _ := ret[0].([]storage.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAPIKeys indicates an expected call of ListAPIKeys.
+func (mr *MockStorageMockRecorder) ListAPIKeys(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAPIKeys", reflect.TypeOf((*MockStorage)(nil).ListAPIKeys), ctx)
+}
+
+// ListAccounts mocks base method.
+func (m *MockStorage) ListAccounts(ctx context.Context, query storage.AccountQuery) ([]storage.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccounts", ctx, query)
+	ret0, _ := ret[0].([]storage.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccounts indicates an expected call of ListAccounts.
+func (mr *MockStorageMockRecorder) ListAccounts(ctx, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccounts", reflect.TypeOf((*MockStorage)(nil).ListAccounts), ctx, query)
+}
+
+// ListEventsAfter mocks base method.
+func (m *MockStorage) ListEventsAfter(ctx context.Context, afterID int64, accountID string, limit int) ([]storage.OutboxEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEventsAfter", ctx, afterID, accountID, limit)
+	ret0, _ := ret[0].([]storage.OutboxEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEventsAfter indicates an expected call of ListEventsAfter.
+func (mr *MockStorageMockRecorder) ListEventsAfter(ctx, afterID, accountID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEventsAfter", reflect.TypeOf((*MockStorage)(nil).ListEventsAfter), ctx, afterID, accountID, limit)
+}
+
+// ListTransactions mocks base method.
+func (m *MockStorage) ListTransactions(ctx context.Context, accountID string, beforeID int64, limit int) ([]storage.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransactions", ctx, accountID, beforeID, limit)
+	ret0, _ := ret[0].([]storage.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransactions indicates an expected call of ListTransactions.
+func (mr *MockStorageMockRecorder) ListTransactions(ctx, accountID, beforeID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransactions", reflect.TypeOf((*MockStorage)(nil).ListTransactions), ctx, accountID, beforeID, limit)
+}
+
+// ListUnpublishedEvents mocks base method.
+func (m *MockStorage) ListUnpublishedEvents(ctx context.Context, limit int) ([]storage.OutboxEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUnpublishedEvents", ctx, limit)
+	ret0, _ := ret[0].([]storage.OutboxEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUnpublishedEvents indicates an expected call of ListUnpublishedEvents.
+func (mr *MockStorageMockRecorder) ListUnpublishedEvents(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnpublishedEvents", reflect.TypeOf((*MockStorage)(nil).ListUnpublishedEvents), ctx, limit)
+}
+
+// ListWebhookAttempts mocks base method.
+func (m *MockStorage) ListWebhookAttempts(ctx context.Context, deliveryID int64) ([]storage.WebhookAttempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookAttempts", ctx, deliveryID)
+	ret0, _ := ret[0].([]storage.WebhookAttempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookAttempts indicates an expected call of ListWebhookAttempts.
+func (mr *MockStorageMockRecorder) ListWebhookAttempts(ctx, deliveryID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookAttempts", reflect.TypeOf((*MockStorage)(nil).ListWebhookAttempts), ctx, deliveryID)
+}
+
+// ListWebhookDeliveries mocks base method.
+func (m *MockStorage) ListWebhookDeliveries(ctx context.Context, subscriptionID string, limit int) ([]storage.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookDeliveries", ctx, subscriptionID, limit)
+	ret0, _ := ret[0].([]storage.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookDeliveries indicates an expected call of ListWebhookDeliveries.
+func (mr *MockStorageMockRecorder) ListWebhookDeliveries(ctx, subscriptionID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookDeliveries", reflect.TypeOf((*MockStorage)(nil).ListWebhookDeliveries), ctx, subscriptionID, limit)
+}
+
+// ListWebhookSubscriptions mocks base method.
+func (m *MockStorage) ListWebhookSubscriptions(ctx context.Context, clientID string) ([]storage.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookSubscriptions", ctx, clientID)
+	ret0, _ := ret[0].([]storage.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookSubscriptions indicates an expected call of ListWebhookSubscriptions.
+func (mr *MockStorageMockRecorder) ListWebhookSubscriptions(ctx, clientID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookSubscriptions", reflect.TypeOf((*MockStorage)(nil).ListWebhookSubscriptions), ctx, clientID)
+}
+
+// MarkEventFailed mocks base method.
+func (m *MockStorage) MarkEventFailed(ctx context.Context, eventID int64, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkEventFailed", ctx, eventID, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkEventFailed indicates an expected call of MarkEventFailed.
+func (mr *MockStorageMockRecorder) MarkEventFailed(ctx, eventID, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkEventFailed", reflect.TypeOf((*MockStorage)(nil).MarkEventFailed), ctx, eventID, reason)
+}
+
+// MarkEventPublished mocks base method.
+func (m *MockStorage) MarkEventPublished(ctx context.Context, eventID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkEventPublished", ctx, eventID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkEventPublished indicates an expected call of MarkEventPublished.
+func (mr *MockStorageMockRecorder) MarkEventPublished(ctx, eventID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkEventPublished", reflect.TypeOf((*MockStorage)(nil).MarkEventPublished), ctx, eventID)
+}
+
+// Ping mocks base method.
+func (m *MockStorage) Ping(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockStorageMockRecorder) Ping(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockStorage)(nil).Ping), ctx)
+}
+
 // ProcessTransaction mocks base method.
-func (m *MockStorage) ProcessTransaction(ctx context.Context, sourceAccID, destAccID string, amount decimal.Decimal) error {
+func (m *MockStorage) ProcessTransaction(ctx context.Context, sourceAccID, destAccID string, amount decimal.Decimal, sourceVersion int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ProcessTransaction", ctx, sourceAccID, destAccID, amount)
+	ret := m.ctrl.Call(m, "ProcessTransaction", ctx, sourceAccID, destAccID, amount, sourceVersion)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // ProcessTransaction indicates an expected call of ProcessTransaction.
-func (mr *MockStorageMockRecorder) ProcessTransaction(ctx, sourceAccID, destAccID, amount any) *gomock.Call {
+func (mr *MockStorageMockRecorder) ProcessTransaction(ctx, sourceAccID, destAccID, amount, sourceVersion any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessTransaction", reflect.TypeOf((*MockStorage)(nil).ProcessTransaction), ctx, sourceAccID, destAccID, amount, sourceVersion)
+}
+
+// ProcessTransferBatch mocks base method.
+func (m *MockStorage) ProcessTransferBatch(ctx context.Context, batchID string, transfers []storage.Transfer) ([]storage.TransferResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessTransferBatch", ctx, batchID, transfers)
+	ret0, _ := ret[0].([]storage.TransferResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProcessTransferBatch indicates an expected call of ProcessTransferBatch.
+func (mr *MockStorageMockRecorder) ProcessTransferBatch(ctx, batchID, transfers any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessTransferBatch", reflect.TypeOf((*MockStorage)(nil).ProcessTransferBatch), ctx, batchID, transfers)
+}
+
+// RecordWebhookAttempt mocks base method.
+func (m *MockStorage) RecordWebhookAttempt(ctx context.Context, deliveryID int64, result storage.WebhookAttemptResult) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordWebhookAttempt", ctx, deliveryID, result)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordWebhookAttempt indicates an expected call of RecordWebhookAttempt.
+func (mr *MockStorageMockRecorder) RecordWebhookAttempt(ctx, deliveryID, result any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordWebhookAttempt", reflect.TypeOf((*MockStorage)(nil).RecordWebhookAttempt), ctx, deliveryID, result)
+}
+
+// RevokeAPIKey mocks base method.
+func (m *MockStorage) RevokeAPIKey(ctx context.Context, keyID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIKey", ctx, keyID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAPIKey indicates an expected call of RevokeAPIKey.
+func (mr *MockStorageMockRecorder) RevokeAPIKey(ctx, keyID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKey", reflect.TypeOf((*MockStorage)(nil).RevokeAPIKey), ctx, keyID)
+}
+
+// StreamStatement mocks base method.
+func (m *MockStorage) StreamStatement(ctx context.Context, accountID string, from, to time.Time, w storage.StatementWriter) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamStatement", ctx, accountID, from, to, w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamStatement indicates an expected call of StreamStatement.
+func (mr *MockStorageMockRecorder) StreamStatement(ctx, accountID, from, to, w any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamStatement", reflect.TypeOf((*MockStorage)(nil).StreamStatement), ctx, accountID, from, to, w)
+}
+
+// UpdateAccount mocks base method.
+func (m *MockStorage) UpdateAccount(ctx context.Context, accountID string, version int64, patch storage.AccountPatch) (*storage.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccount", ctx, accountID, version, patch)
+	ret0, _ := ret[0].(*storage.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAccount indicates an expected call of UpdateAccount.
+func (mr *MockStorageMockRecorder) UpdateAccount(ctx, accountID, version, patch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccount", reflect.TypeOf((*MockStorage)(nil).UpdateAccount), ctx, accountID, version, patch)
+}
+
+// UpdateWebhookSubscription mocks base method.
+func (m *MockStorage) UpdateWebhookSubscription(ctx context.Context, sub *storage.WebhookSubscription) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhookSubscription", ctx, sub)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWebhookSubscription indicates an expected call of UpdateWebhookSubscription.
+func (mr *MockStorageMockRecorder) UpdateWebhookSubscription(ctx, sub any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhookSubscription", reflect.TypeOf((*MockStorage)(nil).UpdateWebhookSubscription), ctx, sub)
+}
+
+// VerifyAuditChain mocks base method.
+func (m *MockStorage) VerifyAuditChain(ctx context.Context) (*storage.AuditVerification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyAuditChain", ctx)
+	ret0, _ := ret[0].(*storage.AuditVerification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyAuditChain indicates an expected call of VerifyAuditChain.
+func (mr *MockStorageMockRecorder) VerifyAuditChain(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessTransaction", reflect.TypeOf((*MockStorage)(nil).ProcessTransaction), ctx, sourceAccID, destAccID, amount)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyAuditChain", reflect.TypeOf((*MockStorage)(nil).VerifyAuditChain), ctx)
 }
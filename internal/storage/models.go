@@ -1,6 +1,11 @@
 package storage
 
-import "github.com/shopspring/decimal"
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
 
 // Error definitions for storage operations
 const (
@@ -12,10 +17,336 @@ const (
 	ErrDestinationAccountMsg = "destination account not found"
 	ErrSourceAccountMsg      = "source account not found"
 	ErrInsufficientFundsMsg  = "insufficient funds in source account"
+	ErrAPIKeyNotFound        = "api key not found"
+	ErrCreateAPIKeyMsg       = "internal Server Error: failed to create api key"
+	ErrGetAPIKeyMsg          = "internal Server Error: failed to get api key"
+	ErrListAPIKeysMsg        = "internal Server Error: failed to list api keys"
+	ErrRevokeAPIKeyMsg       = "internal Server Error: failed to revoke api key"
+	ErrVerifyAuditChainMsg   = "internal Server Error: failed to verify audit chain"
+	ErrListOutboxEventsMsg   = "internal Server Error: failed to list outbox events"
+	ErrUpdateOutboxEventMsg  = "internal Server Error: failed to update outbox event"
+	ErrWebhookNotFound       = "webhook subscription not found"
+	ErrCreateWebhookMsg      = "internal Server Error: failed to create webhook subscription"
+	ErrGetWebhookMsg         = "internal Server Error: failed to get webhook subscription"
+	ErrListWebhooksMsg       = "internal Server Error: failed to list webhook subscriptions"
+	ErrUpdateWebhookMsg      = "internal Server Error: failed to update webhook subscription"
+	ErrDeleteWebhookMsg      = "internal Server Error: failed to delete webhook subscription"
+	ErrEnqueueDeliveriesMsg  = "internal Server Error: failed to enqueue webhook deliveries"
+	ErrClaimDeliveriesMsg    = "internal Server Error: failed to claim webhook deliveries"
+	ErrRecordAttemptMsg      = "internal Server Error: failed to record webhook attempt"
+	ErrDeliveryNotFound      = "webhook delivery not found"
+	ErrListDeliveriesMsg     = "internal Server Error: failed to list webhook deliveries"
+	ErrListAttemptsMsg       = "internal Server Error: failed to list webhook attempts"
+	ErrListTransactionsMsg   = "internal Server Error: failed to list transactions"
+	ErrUpdateAccountMsg      = "internal Server Error: failed to update account"
+	ErrAccountVersionMsg     = "account version mismatch: it was modified by another request"
+	ErrListAccountsMsg       = "internal Server Error: failed to list accounts"
+	ErrCountAccountsMsg      = "internal Server Error: failed to count accounts"
+	ErrCurrencyMismatchMsg   = "source and destination accounts have different currencies"
+	ErrSourceClosedMsg       = "source account is closed"
+	ErrDestinationClosedMsg  = "destination account is closed"
+	ErrCloseAccountMsg       = "internal Server Error: failed to close account"
+	ErrAccountClosedMsg      = "account is already closed"
+	ErrAccountNotEmptyMsg    = "account balance must be zero to close it"
+	ErrAccountNotOpenMsg     = "account did not exist at the requested time"
+	ErrGetBalanceMsg         = "internal Server Error: failed to get balance"
+	ErrCreateSnapshotsMsg    = "internal Server Error: failed to create balance snapshots"
+	ErrSnapshotUnsettledMsg  = "a transaction that started before the snapshot boundary is still open"
+	ErrStatementMsg          = "internal Server Error: failed to read statement"
+	ErrTransferCurrencyMsg   = "transfer currency differs from the source account's currency"
+	ErrAmountPrecisionMsg    = "amount has more decimal places than the accounts' currency allows"
+	ErrBatchExistsMsg        = "a batch with this ID has already been processed"
+	ErrProcessBatchMsg       = "internal Server Error: failed to process transfer batch"
+	ErrImportAccountsMsg     = "internal Server Error: failed to import accounts"
+)
+
+// AnyVersion is passed as an expected account version to skip the version check.
+const AnyVersion int64 = 0
+
+// Webhook delivery states.
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryDead      = "dead"
+)
+
+// Event types recorded in the audit log and published through the outbox.
+const (
+	EventAccountCreated    = "account.created"
+	EventAccountUpdated    = "account.updated"
+	EventAccountClosed     = "account.closed"
+	EventTransferCompleted = "transfer.completed"
+)
+
+// Account types.
+const (
+	AccountTypeOperating  = "operating"
+	AccountTypeSettlement = "settlement"
+	AccountTypeFee        = "fee"
+	AccountTypeCustomer   = "customer"
+)
+
+// Account statuses.
+const (
+	AccountStatusActive = "active"
+	AccountStatusClosed = "closed"
+)
+
+// DefaultCurrency is the currency of accounts created without one, and of accounts created before
+// accounts had a currency.
+const DefaultCurrency = "USD"
+
+// Account sort keys for ListAccounts.
+const (
+	AccountSortCreatedAt = "created_at"
+	AccountSortBalance   = "balance"
 )
 
-// Account represents an account in storage, with a unique ID and balance.
+// Account represents an account in storage, with a unique ID, balance and descriptive attributes.
 type Account struct {
 	ID      string          `json:"id"`
 	Balance decimal.Decimal `json:"balance"`
+	Status  string          `json:"status"`
+	AccountAttributes
+	// Version starts at 1 and is incremented by every change to the balance, status or attributes,
+	// for optimistic concurrency.
+	Version   int64     `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// AccountAttributes are the fields of an account set when it is created.
+type AccountAttributes struct {
+	// Currency is the ISO 4217 code of the balance. It cannot be changed.
+	Currency string `json:"currency"`
+	Name     string `json:"name"`
+	OwnerRef string `json:"owner_ref"`
+	// Type is one of the AccountType constants, or empty if unset.
+	Type   string   `json:"type"`
+	Labels []string `json:"labels"`
+	// Metadata is a free-form JSON object; nil is stored as {}.
+	Metadata json.RawMessage `json:"metadata"`
+}
+
+// AccountQuery selects and orders accounts for ListAccounts and CountAccounts. Empty fields do not filter.
+type AccountQuery struct {
+	// IDs restricts the results to these accounts when non-nil.
+	IDs      []string
+	Type     string
+	Status   string
+	Currency string
+	// Labels lists labels an account must all carry.
+	Labels     []string
+	MinBalance *decimal.Decimal
+	MaxBalance *decimal.Decimal
+
+	// Sort is AccountSortCreatedAt or AccountSortBalance; ties are broken by ID.
+	Sort       string
+	Descending bool
+	// After continues a listing after the last account of the previous page; it is ignored by CountAccounts.
+	After *Account
+	Limit int
+}
+
+// AccountPatch lists the attributes an update changes; nil fields are left as they are.
+type AccountPatch struct {
+	Name     *string
+	OwnerRef *string
+	Type     *string
+	Labels   *[]string
+	Metadata json.RawMessage
+}
+
+// Transaction is a completed transfer between two accounts.
+type Transaction struct {
+	ID                   int64           `json:"id"`
+	SourceAccountID      string          `json:"source_account_id"`
+	DestinationAccountID string          `json:"destination_account_id"`
+	Amount               decimal.Decimal `json:"amount"`
+	// RequestID is the X-Request-ID of the call that created the transfer; empty for legacy rows.
+	RequestID string    `json:"request_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewAccount is an account to create with CreateAccounts.
+type NewAccount struct {
+	ID      string
+	Balance decimal.Decimal
+	AccountAttributes
+}
+
+// Transfer is one transfer of a batch.
+type Transfer struct {
+	SourceAccountID      string
+	DestinationAccountID string
+	Amount               decimal.Decimal
+	// Currency, when set, must be the source account's currency.
+	Currency string
+}
+
+// TransferResult is the outcome of one transfer of a batch: the ID of the transaction it created, or
+// the error it was refused with.
+type TransferResult struct {
+	TransactionID int64
+	Err           error
+}
+
+// AccountBalance is an account's balance at a point in time.
+type AccountBalance struct {
+	AccountID string          `json:"account_id"`
+	Balance   decimal.Decimal `json:"balance"`
+	Currency  string          `json:"currency"`
+	AsOf      time.Time       `json:"as_of"`
+}
+
+// Statement summarises an account's transfers over the period after From up to and including To.
+// Its opening and closing balances are the account's balances at From and To.
+type Statement struct {
+	AccountID      string
+	Currency       string
+	From           time.Time
+	To             time.Time
+	OpeningBalance decimal.Decimal
+	ClosingBalance decimal.Decimal
+	TotalCredits   decimal.Decimal
+	TotalDebits    decimal.Decimal
+	CreditCount    int
+	DebitCount     int
+}
+
+// EntryCount returns the number of transfers on the statement.
+func (s *Statement) EntryCount() int {
+	return s.CreditCount + s.DebitCount
+}
+
+// StatementEntry is one transfer on a statement, seen from the statement's account.
+type StatementEntry struct {
+	TransactionID  int64
+	CreatedAt      time.Time
+	CounterpartyID string
+	// Amount is positive for credits and negative for debits.
+	Amount decimal.Decimal
+	// Balance is the account's balance after the transfer.
+	Balance   decimal.Decimal
+	RequestID string
+}
+
+// StatementWriter receives a statement as it is read, so long periods need not be held in memory.
+// WriteHeader is called once with the statement's balances and totals, WriteEntry once per transfer
+// in order and WriteFooter once at the end with the same statement.
+type StatementWriter interface {
+	WriteHeader(s *Statement) error
+	WriteEntry(e StatementEntry) error
+	WriteFooter(s *Statement) error
+}
+
+// APIKey represents a hashed API key issued to a client, with the scopes it grants.
+type APIKey struct {
+	ID        string     `json:"id"`
+	ClientID  string     `json:"client_id"`
+	KeyHash   string     `json:"-"`
+	Scopes    []string   `json:"scopes"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// Revoked reports whether the key has been revoked.
+func (k *APIKey) Revoked() bool {
+	return k.RevokedAt != nil && !k.RevokedAt.After(time.Now())
+}
+
+// AuditEvent is one link in the hash-chained audit log.
+type AuditEvent struct {
+	ID        int64     `json:"id"`
+	EventType string    `json:"event_type"`
+	EntityID  string    `json:"entity_id"`
+	Actor     string    `json:"actor"`
+	RequestID string    `json:"request_id"`
+	Payload   string    `json:"payload"`
+	CreatedAt time.Time `json:"created_at"`
+	PrevHash  string    `json:"prev_hash"`
+	Hash      string    `json:"hash"`
+}
+
+// AuditVerification is the result of verifying the audit chain.
+type AuditVerification struct {
+	Valid         bool   `json:"valid"`
+	EventsChecked int    `json:"events_checked"`
+	HeadHash      string `json:"head_hash,omitempty"`
+	// BrokenAtID is the ID of the first event whose link or content does not verify.
+	BrokenAtID int64  `json:"broken_at_id,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+	// Note qualifies a valid result, e.g. that an empty chain cannot show events were not removed.
+	Note string `json:"note,omitempty"`
+}
+
+// markBroken records the first broken link.
+func (v *AuditVerification) markBroken(id int64, reason string) {
+	v.Valid = false
+	v.BrokenAtID = id
+	v.Reason = reason
+}
+
+// OutboxEvent is a domain event written in the same DB transaction as the change it describes,
+// waiting to be published by the outbox relay.
+type OutboxEvent struct {
+	ID        int64  `json:"id"`
+	EventType string `json:"event_type"`
+	// AccountIDs lists the accounts the event affects; events are published in order per account.
+	AccountIDs []string        `json:"account_ids"`
+	Payload    json.RawMessage `json:"payload"`
+	CreatedAt  time.Time       `json:"created_at"`
+	Attempts   int             `json:"-"`
+}
+
+// WebhookSubscription is a client's callback URL for account events.
+type WebhookSubscription struct {
+	ID       string `json:"id"`
+	ClientID string `json:"client_id"`
+	URL      string `json:"url"`
+	// Secret signs delivered payloads. It is only returned when the subscription is created.
+	Secret     string   `json:"-"`
+	EventTypes []string `json:"event_types"`
+	// AccountIDs restricts deliveries to events touching these accounts; empty means every account.
+	AccountIDs []string  `json:"account_ids"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// WebhookDelivery is one outbox event queued for one subscription.
+type WebhookDelivery struct {
+	ID             int64           `json:"id"`
+	SubscriptionID string          `json:"subscription_id"`
+	EventID        int64           `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	LastStatusCode *int            `json:"last_status_code,omitempty"`
+	LastError      *string         `json:"last_error,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+	// URL and Secret are loaded from the subscription when a delivery is claimed for sending.
+	URL    string `json:"-"`
+	Secret string `json:"-"`
+}
+
+// WebhookAttempt is one HTTP attempt to deliver a webhook.
+type WebhookAttempt struct {
+	ID          int64     `json:"id"`
+	DeliveryID  int64     `json:"delivery_id"`
+	AttemptedAt time.Time `json:"attempted_at"`
+	StatusCode  *int      `json:"status_code,omitempty"`
+	Error       *string   `json:"error,omitempty"`
+	DurationMS  int64     `json:"duration_ms"`
+}
+
+// WebhookAttemptResult records the outcome of an attempt and the delivery state that follows it.
+type WebhookAttemptResult struct {
+	Attempt WebhookAttempt
+	// Status is the delivery's new status.
+	Status string
+	// NextAttemptAt is when a pending delivery is retried.
+	NextAttemptAt time.Time
 }
@@ -0,0 +1,191 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// insertOutboxEvent records an event in the outbox within tx, so it is published if and only if tx commits.
+// tx must hold the audit chain lock (see lockAuditChain), which keeps outbox IDs in commit order.
+func insertOutboxEvent(ctx context.Context, tx *sql.Tx, eventType string, accountIDs []string, payload any) error {
+	const query = `
+		INSERT INTO outbox (event_type, account_ids, payload)
+		VALUES ($1, $2, $3)
+	`
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal outbox payload: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, eventType, pq.Array(accountIDs), string(body)); err != nil {
+		return fmt.Errorf("insert outbox event: %w", err)
+	}
+	return nil
+}
+
+// transferSides maps the account ID field of each side of a transfer.completed payload to the fields
+// describing that account after the transfer.
+var transferSides = map[string][]string{
+	"source_account_id":      {"source_balance", "source_version"},
+	"destination_account_id": {"destination_balance", "destination_version"},
+}
+
+// RedactEventPayload returns payload without the balance and version of each transfer side whose
+// account visible refuses, for recipients entitled to some of an event's accounts only. Other event
+// types carry nothing about accounts besides their own and are returned unchanged, as are payloads
+// that cannot be read.
+func RedactEventPayload(eventType string, payload json.RawMessage, visible func(accountID string) bool) json.RawMessage {
+	if eventType != EventTransferCompleted {
+		return payload
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return payload
+	}
+
+	redacted := false
+	for idField, sideFields := range transferSides {
+		var accountID string
+		if err := json.Unmarshal(fields[idField], &accountID); err != nil || visible(accountID) {
+			continue
+		}
+		for _, field := range sideFields {
+			delete(fields, field)
+		}
+		redacted = true
+	}
+	if !redacted {
+		return payload
+	}
+	body, err := json.Marshal(fields)
+	if err != nil {
+		return payload
+	}
+	return body
+}
+
+// ListUnpublishedEvents returns up to limit unpublished outbox events, oldest first.
+// Returns ErrListOutboxEventsMsg on internal failures.
+func (p *PostgressStorage) ListUnpublishedEvents(ctx context.Context, limit int) ([]OutboxEvent, error) {
+	const query = `
+		SELECT id, event_type, account_ids, payload, created_at, attempts
+		FROM outbox
+		WHERE published_at IS NULL
+		ORDER BY id
+		LIMIT $1
+	`
+
+	logger := p.contextLogger(ctx)
+
+	rows, err := p.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		logger.Error("failed to list outbox events", zap.Error(err))
+		return nil, errors.New(ErrListOutboxEventsMsg)
+	}
+	defer rows.Close()
+
+	return scanOutboxEvents(rows, logger)
+}
+
+// ListEventsAfter returns up to limit outbox events with an ID greater than afterID, oldest first,
+// whether or not they have been published. A non-empty accountID restricts the result to events touching
+// that account. Outbox inserts are serialized by the audit chain lock, so IDs are assigned in commit order
+// and reading past the last seen ID never skips a committed event.
+// Returns ErrListOutboxEventsMsg on internal failures.
+func (p *PostgressStorage) ListEventsAfter(ctx context.Context, afterID int64, accountID string, limit int) ([]OutboxEvent, error) {
+	const query = `
+		SELECT id, event_type, account_ids, payload, created_at, attempts
+		FROM outbox
+		WHERE id > $1 AND ($2 = '' OR $2 = ANY(account_ids))
+		ORDER BY id
+		LIMIT $3
+	`
+
+	logger := p.contextLogger(ctx)
+
+	rows, err := p.db.QueryContext(ctx, query, afterID, accountID, limit)
+	if err != nil {
+		logger.Error("failed to list outbox events", zap.Error(err))
+		return nil, errors.New(ErrListOutboxEventsMsg)
+	}
+	defer rows.Close()
+
+	return scanOutboxEvents(rows, logger)
+}
+
+// LatestEventID returns the ID of the newest outbox event, or 0 if there are none.
+// Returns ErrListOutboxEventsMsg on internal failures.
+func (p *PostgressStorage) LatestEventID(ctx context.Context) (int64, error) {
+	const query = `
+		SELECT COALESCE(MAX(id), 0)
+		FROM outbox
+	`
+
+	var id int64
+	if err := p.db.QueryRowContext(ctx, query).Scan(&id); err != nil {
+		p.contextLogger(ctx).Error("failed to read latest outbox event id", zap.Error(err))
+		return 0, errors.New(ErrListOutboxEventsMsg)
+	}
+	return id, nil
+}
+
+// MarkEventPublished records that an outbox event has been delivered.
+// Returns ErrUpdateOutboxEventMsg on internal failures.
+func (p *PostgressStorage) MarkEventPublished(ctx context.Context, eventID int64) error {
+	const query = `
+		UPDATE outbox
+		SET published_at = CURRENT_TIMESTAMP, attempts = attempts + 1, last_error = NULL
+		WHERE id = $1
+	`
+
+	if _, err := p.db.ExecContext(ctx, query, eventID); err != nil {
+		p.contextLogger(ctx).Error("failed to mark outbox event published", zap.Int64("event_id", eventID), zap.Error(err))
+		return errors.New(ErrUpdateOutboxEventMsg)
+	}
+	return nil
+}
+
+// MarkEventFailed records a failed delivery attempt; the event stays unpublished and is retried.
+// Returns ErrUpdateOutboxEventMsg on internal failures.
+func (p *PostgressStorage) MarkEventFailed(ctx context.Context, eventID int64, reason string) error {
+	const query = `
+		UPDATE outbox
+		SET attempts = attempts + 1, last_error = $2
+		WHERE id = $1
+	`
+
+	if _, err := p.db.ExecContext(ctx, query, eventID, reason); err != nil {
+		p.contextLogger(ctx).Error("failed to mark outbox event failed", zap.Int64("event_id", eventID), zap.Error(err))
+		return errors.New(ErrUpdateOutboxEventMsg)
+	}
+	return nil
+}
+
+// scanOutboxEvents reads all outbox rows from rows.
+func scanOutboxEvents(rows *sql.Rows, logger *zap.Logger) ([]OutboxEvent, error) {
+	events := make([]OutboxEvent, 0)
+	for rows.Next() {
+		var (
+			e       OutboxEvent
+			payload string
+		)
+		if err := rows.Scan(&e.ID, &e.EventType, pq.Array(&e.AccountIDs), &payload, &e.CreatedAt, &e.Attempts); err != nil {
+			logger.Error("failed to scan outbox event", zap.Error(err))
+			return nil, errors.New(ErrListOutboxEventsMsg)
+		}
+		e.Payload = json.RawMessage(payload)
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		logger.Error("failed to list outbox events", zap.Error(err))
+		return nil, errors.New(ErrListOutboxEventsMsg)
+	}
+	return events, nil
+}
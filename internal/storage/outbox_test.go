@@ -0,0 +1,201 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// expectOutboxInsert registers the query insertOutboxEvent runs.
+func expectOutboxInsert(m sqlmock.Sqlmock, eventType string) {
+	m.ExpectExec(`INSERT INTO outbox`).
+		WithArgs(eventType, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
+// TestListUnpublishedEvents validates reading pending outbox events and query failures.
+func TestListUnpublishedEvents(t *testing.T) {
+	createdAt := time.Date(2025, 12, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		prepare     func(sqlmock.Sqlmock)
+		expected    []OutboxEvent
+		expectedErr string
+	}{
+		{
+			name: "success",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(`SELECT id, event_type, account_ids, payload, created_at, attempts FROM outbox`).
+					WithArgs(10).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "event_type", "account_ids", "payload", "created_at", "attempts"}).
+						AddRow(1, EventAccountCreated, "{acc-1}", `{"account_id":"acc-1"}`, createdAt, 0).
+						AddRow(2, EventTransferCompleted, "{acc-1,acc-2}", `{"amount":"5"}`, createdAt, 2))
+			},
+			expected: []OutboxEvent{
+				{ID: 1, EventType: EventAccountCreated, AccountIDs: []string{"acc-1"}, Payload: json.RawMessage(`{"account_id":"acc-1"}`), CreatedAt: createdAt},
+				{ID: 2, EventType: EventTransferCompleted, AccountIDs: []string{"acc-1", "acc-2"}, Payload: json.RawMessage(`{"amount":"5"}`), CreatedAt: createdAt, Attempts: 2},
+			},
+		},
+		{
+			name: "query error",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(`SELECT id, event_type, account_ids, payload, created_at, attempts FROM outbox`).
+					WithArgs(10).
+					WillReturnError(errors.New("db down"))
+			},
+			expectedErr: ErrListOutboxEventsMsg,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			store, mock, cleanup := newTestStorage(t)
+			defer cleanup()
+
+			tc.prepare(mock)
+
+			events, err := store.ListUnpublishedEvents(context.Background(), 10)
+
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, events)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestMarkOutboxEvent validates recording successful and failed deliveries.
+func TestMarkOutboxEvent(t *testing.T) {
+	tests := []struct {
+		name        string
+		prepare     func(sqlmock.Sqlmock)
+		call        func(*PostgressStorage) error
+		expectedErr string
+	}{
+		{
+			name: "published",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectExec(`UPDATE outbox SET published_at`).WithArgs(int64(3)).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			call: func(s *PostgressStorage) error { return s.MarkEventPublished(context.Background(), 3) },
+		},
+		{
+			name: "failed",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectExec(`UPDATE outbox SET attempts`).WithArgs(int64(3), "timeout").WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			call: func(s *PostgressStorage) error { return s.MarkEventFailed(context.Background(), 3, "timeout") },
+		},
+		{
+			name: "update error",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectExec(`UPDATE outbox SET published_at`).WithArgs(int64(3)).WillReturnError(errors.New("db down"))
+			},
+			call:        func(s *PostgressStorage) error { return s.MarkEventPublished(context.Background(), 3) },
+			expectedErr: ErrUpdateOutboxEventMsg,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			store, mock, cleanup := newTestStorage(t)
+			defer cleanup()
+
+			tc.prepare(mock)
+
+			err := tc.call(store)
+
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestListEventsAfter validates reading events after a given ID for one account and the latest event ID.
+func TestListEventsAfter(t *testing.T) {
+	store, mock, cleanup := newTestStorage(t)
+	defer cleanup()
+
+	createdAt := time.Date(2025, 12, 1, 12, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`SELECT id, event_type, account_ids, payload, created_at, attempts FROM outbox WHERE id > \$1`).
+		WithArgs(int64(5), "acc-1", 100).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "event_type", "account_ids", "payload", "created_at", "attempts"}).
+			AddRow(6, EventTransferCompleted, "{acc-1,acc-2}", `{"amount":"5"}`, createdAt, 1))
+	mock.ExpectQuery(`SELECT COALESCE\(MAX\(id\), 0\) FROM outbox`).
+		WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(6))
+
+	events, err := store.ListEventsAfter(context.Background(), 5, "acc-1", 100)
+	assert.NoError(t, err)
+	assert.Equal(t, []OutboxEvent{
+		{ID: 6, EventType: EventTransferCompleted, AccountIDs: []string{"acc-1", "acc-2"}, Payload: json.RawMessage(`{"amount":"5"}`), CreatedAt: createdAt, Attempts: 1},
+	}, events)
+
+	latest, err := store.LatestEventID(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), latest)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestRedactEventPayload validates that only the balances and versions of accounts the recipient may see
+// are kept.
+func TestRedactEventPayload(t *testing.T) {
+	transfer := json.RawMessage(`{"amount":"1","destination_account_id":"b","destination_balance":"11","destination_version":4,"source_account_id":"a","source_balance":"9","source_version":3}`)
+
+	tests := []struct {
+		name      string
+		eventType string
+		payload   json.RawMessage
+		visible   []string
+		expected  string
+	}{
+		{
+			name:      "both accounts visible",
+			eventType: EventTransferCompleted,
+			payload:   transfer,
+			visible:   []string{"a", "b"},
+			expected:  string(transfer),
+		},
+		{
+			name:      "source only",
+			eventType: EventTransferCompleted,
+			payload:   transfer,
+			visible:   []string{"a"},
+			expected:  `{"amount":"1","destination_account_id":"b","source_account_id":"a","source_balance":"9","source_version":3}`,
+		},
+		{
+			name:      "destination only",
+			eventType: EventTransferCompleted,
+			payload:   transfer,
+			visible:   []string{"b"},
+			expected:  `{"amount":"1","destination_account_id":"b","destination_balance":"11","destination_version":4,"source_account_id":"a"}`,
+		},
+		{
+			name:      "other event types",
+			eventType: EventAccountCreated,
+			payload:   json.RawMessage(`{"account_id":"c","initial_balance":"5"}`),
+			expected:  `{"account_id":"c","initial_balance":"5"}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			visible := func(accountID string) bool { return slices.Contains(tc.visible, accountID) }
+			assert.JSONEq(t, tc.expected, string(RedactEventPayload(tc.eventType, tc.payload, visible)))
+		})
+	}
+}
@@ -3,9 +3,14 @@ package storage
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"slices"
+	"strconv"
 
 	"github.com/cursed-ninja/internal-transfers-system/internal/config"
+	"github.com/cursed-ninja/internal-transfers-system/internal/currency"
 	"github.com/cursed-ninja/internal-transfers-system/internal/utils"
 	"github.com/lib/pq"
 	"github.com/shopspring/decimal"
@@ -14,18 +19,20 @@ import (
 
 // PostgressStorage implements the Storage interface using a PostgreSQL database.
 type PostgressStorage struct {
-	db *sql.DB
+	db     *sql.DB
+	logger *zap.Logger
 }
 
-// NewPostgressManager creates a new PostgressStorage instance with the given configuration.
-func NewPostgressManager(ctx context.Context, cfg *config.PostgresConfig) (*PostgressStorage, error) {
+// NewPostgressManager creates a new PostgressStorage instance with the given configuration and root logger.
+func NewPostgressManager(ctx context.Context, cfg *config.PostgresConfig, logger *zap.Logger) (*PostgressStorage, error) {
 	db, err := sql.Open("postgres", cfg.ConnStr)
 	if err != nil {
 		return nil, err
 	}
 
 	return &PostgressStorage{
-		db: db,
+		db:     db,
+		logger: logger,
 	}, nil
 }
 
@@ -34,17 +41,44 @@ func (p *PostgressStorage) DB() *sql.DB {
 	return p.db
 }
 
-// CreateAccount inserts a new account with the given ID and balance.
+// Ping verifies the database connection is alive.
+func (p *PostgressStorage) Ping(ctx context.Context) error {
+	return p.db.PingContext(ctx)
+}
+
+// CreateAccount inserts a new account with the given ID, balance and attributes, records it in the
+// audit log and queues an account.created event in the outbox.
 // Returns ErrAccountExists if the account already exists or ErrCreateAccountMsg on internal failures.
-func (p *PostgressStorage) CreateAccount(ctx context.Context, accountID string, balance decimal.Decimal) error {
+func (p *PostgressStorage) CreateAccount(ctx context.Context, accountID string, balance decimal.Decimal, attrs AccountAttributes) error {
+	return p.withTx(ctx, func(tx *sql.Tx) error {
+		return p.createAccount(ctx, tx, accountID, balance, attrs)
+	}, ErrCreateAccountMsg)
+}
+
+// createAccount creates an account within tx, with its audit record and account.created outbox event.
+// Returns ErrAccountExists if an account with the ID exists, or ErrCreateAccountMsg on internal failures.
+func (p *PostgressStorage) createAccount(ctx context.Context, tx *sql.Tx, accountID string, balance decimal.Decimal, attrs AccountAttributes) error {
 	const query = `
-		INSERT INTO accounts (id, balance)
-		VALUES ($1, $2)
+		INSERT INTO accounts (id, balance, opening_balance, currency, display_name, owner_ref, account_type, labels, metadata)
+		VALUES ($1, $2, $2, $3, $4, $5, $6, $7, $8)
 	`
 
-	logger := utils.ContextLogger(ctx)
+	logger := p.contextLogger(ctx)
+
+	labels := attrs.Labels
+	if labels == nil {
+		labels = []string{}
+	}
+	metadata := attrs.Metadata
+	if len(metadata) == 0 {
+		metadata = json.RawMessage(`{}`)
+	}
+	currency := attrs.Currency
+	if currency == "" {
+		currency = DefaultCurrency
+	}
 
-	_, err := p.db.ExecContext(ctx, query, accountID, balance)
+	_, err := tx.ExecContext(ctx, query, accountID, balance, currency, attrs.Name, attrs.OwnerRef, attrs.Type, pq.Array(labels), string(metadata))
 	if err != nil {
 		logger.Error("failed to create account", zap.Error(err))
 		if pqErr, ok := err.(*pq.Error); ok {
@@ -54,6 +88,29 @@ func (p *PostgressStorage) CreateAccount(ctx context.Context, accountID string,
 		}
 		return errors.New(ErrCreateAccountMsg)
 	}
+
+	payload := map[string]any{
+		"account_id":      accountID,
+		"initial_balance": balance.String(),
+		"currency":        currency,
+		"name":            attrs.Name,
+		"owner_ref":       attrs.OwnerRef,
+		"type":            attrs.Type,
+		"labels":          labels,
+		"metadata":        metadata,
+	}
+	if err := lockAuditChain(ctx, tx); err != nil {
+		logger.Error("failed to lock audit chain", zap.Error(err))
+		return errors.New(ErrCreateAccountMsg)
+	}
+	if err := insertOutboxEvent(ctx, tx, EventAccountCreated, []string{accountID}, payload); err != nil {
+		logger.Error("failed to insert outbox event", zap.Error(err))
+		return errors.New(ErrCreateAccountMsg)
+	}
+	if err := appendAuditEvent(ctx, tx, EventAccountCreated, accountID, payload); err != nil {
+		logger.Error("failed to append audit event", zap.Error(err))
+		return errors.New(ErrCreateAccountMsg)
+	}
 	return nil
 }
 
@@ -61,15 +118,14 @@ func (p *PostgressStorage) CreateAccount(ctx context.Context, accountID string,
 // Returns ErrAccountNotFound if the account doesn't exist or ErrGetAccountDetailsMsg on internal failures.
 func (p *PostgressStorage) GetAccountDetails(ctx context.Context, accountID string) (*Account, error) {
 	const query = `
-		SELECT id, balance
+		SELECT ` + accountColumns + `
 		FROM accounts
 		WHERE id = $1
 	`
 
-	logger := utils.ContextLogger(ctx)
+	logger := p.contextLogger(ctx)
 
-	var acc Account
-	err := p.db.QueryRowContext(ctx, query, accountID).Scan(&acc.ID, &acc.Balance)
+	acc, err := scanAccount(p.db.QueryRowContext(ctx, query, accountID))
 	if err != nil {
 		logger.Error("failed to get account details", zap.Error(err))
 		if errors.Is(err, sql.ErrNoRows) {
@@ -78,111 +134,265 @@ func (p *PostgressStorage) GetAccountDetails(ctx context.Context, accountID stri
 		return nil, errors.New(ErrGetAccountDetailsMsg)
 	}
 
-	return &acc, nil
+	return acc, nil
 }
 
 // ProcessTransaction moves a specified amount from sourceAccID to destAccID.
-// Validates existence, sufficient funds, and performs updates within a DB transaction.
-// Returns relevant errors on failure.
-func (p *PostgressStorage) ProcessTransaction(ctx context.Context, sourceAccID, destAccID string, amount decimal.Decimal) (err error) {
+// Validates existence, open accounts, matching currencies, the source account's version (unless it is
+// AnyVersion) and sufficient funds, and performs updates within a DB transaction, incrementing both
+// accounts' versions, together with the audit record and the transfer.completed outbox event.
+// Returns relevant errors on failure, and ErrAccountVersionMsg if the source account's version differs.
+func (p *PostgressStorage) ProcessTransaction(ctx context.Context, sourceAccID, destAccID string, amount decimal.Decimal, sourceVersion int64) (err error) {
+	var tx *sql.Tx
+
+	logger := p.contextLogger(ctx)
+
+	tx, err = p.db.BeginTx(ctx, nil)
+	if err != nil {
+		logger.Error("failed to create transaction", zap.Error(err))
+		return errors.New(ErrProcessTransactionMsg)
+	}
+
+	defer func() {
+		if tx == nil {
+			return
+		}
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	_, err = p.transfer(ctx, tx, Transfer{SourceAccountID: sourceAccID, DestinationAccountID: destAccID, Amount: amount}, sourceVersion)
+	return err
+}
+
+// transfer performs one transfer inside tx: it checks both accounts, the source account's version and
+// funds, moves the amount and records the transaction, audit record and outbox event. It returns the
+// new transaction's ID, or the ProcessTransaction error describing why the transfer was refused.
+func (p *PostgressStorage) transfer(ctx context.Context, tx *sql.Tx, t Transfer, sourceVersion int64) (int64, error) {
 	const (
-		// Query to check destination Acc exists
+		// Query to check destination Acc exists; its row is locked, so it cannot be closed before commit
 		destExistsQuery = `
-			SELECT 1
+			SELECT currency, status
 			FROM accounts
 			WHERE id = $1
 		`
-		// Query to check Source Acc exists
+		// Query to check Source Acc exists; its row is locked, so its balance and version hold until commit
 		sourceBalanceQuery = `
-			SELECT balance
+			SELECT balance, currency, status, version
 			FROM accounts
 			WHERE id = $1
 		`
 		// Query to update source Acc balance
 		withdrawQuery = `
 			UPDATE accounts
-			SET balance = balance - $1
+			SET balance = balance - $1, version = version + 1, updated_at = now()
 			WHERE id = $2
+			RETURNING balance, version
 		`
 		// Query to update destination Acc balance
 		depositQuery = `
 			UPDATE accounts
-			SET balance = balance + $1
+			SET balance = balance + $1, version = version + 1, updated_at = now()
 			WHERE id = $2
+			RETURNING balance, version
 		`
 		// Query to insert transaction log
 		insertTransactionQuery = `
-			INSERT INTO transactions (source_account_id, destination_account_id, amount)
-			VALUES ($1, $2, $3)
+			INSERT INTO transactions (source_account_id, destination_account_id, amount, request_id, client_id)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id
 		`
 	)
-	var tx *sql.Tx
 
-	logger := utils.ContextLogger(ctx)
+	var (
+		sourceAccID = t.SourceAccountID
+		destAccID   = t.DestinationAccountID
+		amount      = t.Amount
+	)
 
-	tx, err = p.db.BeginTx(ctx, nil)
-	if err != nil {
-		logger.Error("failed to create transaction", zap.Error(err))
-		return errors.New(ErrProcessTransactionMsg)
-	}
+	logger := p.contextLogger(ctx)
 
-	defer func() {
-		if tx == nil {
-			return
-		}
-		if p := recover(); p != nil {
-			_ = tx.Rollback()
-			panic(p)
-		}
-		if err != nil {
-			_ = tx.Rollback()
-			return
-		}
-		err = tx.Commit()
-	}()
+	if err := lockAccounts(ctx, tx, sourceAccID, destAccID); err != nil {
+		logger.Error("failed to lock transfer accounts", zap.Error(err))
+		return 0, errors.New(ErrProcessTransactionMsg)
+	}
 
-	var tmp int
-	if err = tx.QueryRowContext(ctx, destExistsQuery, destAccID).Scan(&tmp); err != nil {
+	var destCurrency, destStatus string
+	if err := tx.QueryRowContext(ctx, destExistsQuery, destAccID).Scan(&destCurrency, &destStatus); err != nil {
 		logger.Error("failed to get destination account details", zap.Error(err))
 		if errors.Is(err, sql.ErrNoRows) {
-			return errors.New(ErrDestinationAccountMsg)
+			return 0, errors.New(ErrDestinationAccountMsg)
 		}
-		return errors.New(ErrProcessTransactionMsg)
+		return 0, errors.New(ErrProcessTransactionMsg)
 	}
 
-	var balanceStr string
-	if err = tx.QueryRowContext(ctx, sourceBalanceQuery, sourceAccID).Scan(&balanceStr); err != nil {
+	var (
+		balanceStr, sourceCurrency, sourceStatus string
+		currentVersion                           int64
+	)
+	if err := tx.QueryRowContext(ctx, sourceBalanceQuery, sourceAccID).Scan(&balanceStr, &sourceCurrency, &sourceStatus, &currentVersion); err != nil {
 		logger.Error("failed to get source account details", zap.Error(err))
 		if errors.Is(err, sql.ErrNoRows) {
-			return errors.New(ErrSourceAccountMsg)
+			return 0, errors.New(ErrSourceAccountMsg)
 		}
-		return errors.New(ErrProcessTransactionMsg)
+		return 0, errors.New(ErrProcessTransactionMsg)
+	}
+
+	if sourceVersion != AnyVersion && currentVersion != sourceVersion {
+		logger.Warn("source account version mismatch", zap.Int64("version", sourceVersion), zap.Int64("current_version", currentVersion))
+		return 0, errors.New(ErrAccountVersionMsg)
+	}
+
+	if sourceStatus == AccountStatusClosed {
+		logger.Error("source account is closed")
+		return 0, errors.New(ErrSourceClosedMsg)
+	}
+	if destStatus == AccountStatusClosed {
+		logger.Error("destination account is closed")
+		return 0, errors.New(ErrDestinationClosedMsg)
+	}
+
+	if sourceCurrency != destCurrency {
+		logger.Error("source and destination currencies differ", zap.String("source_currency", sourceCurrency), zap.String("destination_currency", destCurrency))
+		return 0, errors.New(ErrCurrencyMismatchMsg)
+	}
+	if t.Currency != "" && t.Currency != sourceCurrency {
+		logger.Error("transfer currency differs from the source account's currency", zap.String("currency", t.Currency), zap.String("source_currency", sourceCurrency))
+		return 0, errors.New(ErrTransferCurrencyMsg)
+	}
+
+	if !currency.Fits(amount, sourceCurrency) {
+		logger.Error("amount is finer than the currency's minor unit", zap.String("currency", sourceCurrency))
+		return 0, errors.New(ErrAmountPrecisionMsg)
 	}
 
 	sourceBalance, err := decimal.NewFromString(balanceStr)
 	if err != nil {
-		return errors.New(ErrProcessTransactionMsg)
+		return 0, errors.New(ErrProcessTransactionMsg)
 	}
 
 	if sourceBalance.LessThan(amount) {
 		logger.Error("insufficient funds in source account", zap.String("source_balance", sourceBalance.String()))
-		return errors.New(ErrInsufficientFundsMsg)
+		return 0, errors.New(ErrInsufficientFundsMsg)
 	}
 
-	if _, err = tx.ExecContext(ctx, withdrawQuery, amount, sourceAccID); err != nil {
+	var (
+		sourceBalanceAfter, destBalanceAfter decimal.Decimal
+		sourceVersionAfter, destVersionAfter int64
+	)
+	if err := tx.QueryRowContext(ctx, withdrawQuery, amount, sourceAccID).Scan(&sourceBalanceAfter, &sourceVersionAfter); err != nil {
 		logger.Error("failed to update source account details", zap.Error(err))
-		return errors.New(ErrProcessTransactionMsg)
+		return 0, errors.New(ErrProcessTransactionMsg)
 	}
 
-	if _, err = tx.ExecContext(ctx, depositQuery, amount, destAccID); err != nil {
+	if err := tx.QueryRowContext(ctx, depositQuery, amount, destAccID).Scan(&destBalanceAfter, &destVersionAfter); err != nil {
 		logger.Error("failed to update destination account details", zap.Error(err))
-		return errors.New(ErrProcessTransactionMsg)
+		return 0, errors.New(ErrProcessTransactionMsg)
 	}
 
-	if _, err = tx.ExecContext(ctx, insertTransactionQuery, sourceAccID, destAccID, amount); err != nil {
+	requestID := nullString(utils.RequestID(ctx))
+	clientID := nullString(utils.ClientID(ctx))
+	var transactionID int64
+	if err := tx.QueryRowContext(ctx, insertTransactionQuery, sourceAccID, destAccID, amount, requestID, clientID).Scan(&transactionID); err != nil {
 		logger.Error("failed to insert transaction record", zap.Error(err))
-		return errors.New(ErrProcessTransactionMsg)
+		return 0, errors.New(ErrProcessTransactionMsg)
 	}
 
+	payload := map[string]any{
+		"transaction_id":         transactionID,
+		"source_account_id":      sourceAccID,
+		"destination_account_id": destAccID,
+		"amount":                 amount.String(),
+		"source_balance":         sourceBalanceAfter.String(),
+		"destination_balance":    destBalanceAfter.String(),
+		"source_version":         sourceVersionAfter,
+		"destination_version":    destVersionAfter,
+	}
+	if err := lockAuditChain(ctx, tx); err != nil {
+		logger.Error("failed to lock audit chain", zap.Error(err))
+		return 0, errors.New(ErrProcessTransactionMsg)
+	}
+	if err := insertOutboxEvent(ctx, tx, EventTransferCompleted, []string{sourceAccID, destAccID}, payload); err != nil {
+		logger.Error("failed to insert outbox event", zap.Error(err))
+		return 0, errors.New(ErrProcessTransactionMsg)
+	}
+
+	if err := appendAuditEvent(ctx, tx, EventTransferCompleted, strconv.FormatInt(transactionID, 10), payload); err != nil {
+		logger.Error("failed to append audit event", zap.Error(err))
+		return 0, errors.New(ErrProcessTransactionMsg)
+	}
+
+	return transactionID, nil
+}
+
+// lockAccounts locks the rows of the given accounts FOR UPDATE in ascending id order. Transfers that
+// touch several accounts take their row locks through it, before the audit chain lock, so that two
+// transactions never wait on each other's accounts in opposite orders. Unknown ids are ignored.
+func lockAccounts(ctx context.Context, tx *sql.Tx, accountIDs ...string) error {
+	const query = `
+		SELECT id
+		FROM accounts
+		WHERE id = ANY($1::TEXT[])
+		ORDER BY id COLLATE "C"
+		FOR UPDATE
+	`
+
+	ids := slices.Clone(accountIDs)
+	slices.Sort(ids)
+	if _, err := tx.ExecContext(ctx, query, pq.Array(slices.Compact(ids))); err != nil {
+		return fmt.Errorf("lock accounts: %w", err)
+	}
 	return nil
 }
+
+// withTx runs fn inside a DB transaction, committing if it returns nil and rolling back otherwise.
+// Errors starting or committing the transaction are reported as internalErrMsg.
+func (p *PostgressStorage) withTx(ctx context.Context, fn func(tx *sql.Tx) error, internalErrMsg string) (err error) {
+	logger := p.contextLogger(ctx)
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		logger.Error("failed to create transaction", zap.Error(err))
+		return errors.New(internalErrMsg)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			_ = tx.Rollback()
+			panic(r)
+		}
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			logger.Error("failed to commit transaction", zap.Error(err))
+			err = errors.New(internalErrMsg)
+		}
+	}()
+
+	return fn(tx)
+}
+
+// contextLogger returns the request logger from context, falling back to the storage's root logger.
+func (p *PostgressStorage) contextLogger(ctx context.Context) *zap.Logger {
+	if logger, ok := utils.LoggerFromContext(ctx); ok {
+		return logger
+	}
+	if p.logger != nil {
+		return p.logger
+	}
+	return zap.L()
+}
+
+// nullString converts an empty string to a SQL NULL.
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
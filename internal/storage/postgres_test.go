@@ -3,11 +3,11 @@ package storage
 import (
 	"context"
 	"database/sql"
-	"database/sql/driver"
 	"errors"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/cursed-ninja/internal-transfers-system/internal/utils"
 	"github.com/lib/pq"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
@@ -23,27 +23,44 @@ func newTestStorage(t *testing.T) (*PostgressStorage, sqlmock.Sqlmock, func()) {
 	return &PostgressStorage{db: db}, mock, func() { db.Close() }
 }
 
-// TestCreateAccountSuccess validates account creation scenarios, including success and duplicate account errors.
+// TestCreateAccountSuccess validates account creation scenarios, including success, duplicate account errors,
+// and audit log failures.
 func TestCreateAccountSuccess(t *testing.T) {
 	tests := []struct {
 		name        string
-		args        []driver.Value
-		result      driver.Result
-		err         error
+		prepare     func(sqlmock.Sqlmock)
 		expectedErr string
 	}{
 		{
-			name:   "success",
-			args:   []driver.Value{"acc-1", "100"},
-			result: sqlmock.NewResult(1, 1),
-			err:    nil,
+			name: "success",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectExec(`INSERT INTO accounts`).WithArgs("acc-1", "100", DefaultCurrency, "Payroll", "", AccountTypeOperating, "{}", "{}").WillReturnResult(sqlmock.NewResult(1, 1))
+				expectAuditLock(m)
+				expectOutboxInsert(m, EventAccountCreated)
+				expectAuditAppend(m, EventAccountCreated, "acc-1")
+				m.ExpectCommit()
+			},
 		},
 		{
-			name:        "duplicate account",
-			args:        []driver.Value{"acc-1", "100"},
-			err:         &pq.Error{Code: "23505"},
+			name: "duplicate account",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectExec(`INSERT INTO accounts`).WithArgs("acc-1", "100", DefaultCurrency, "Payroll", "", AccountTypeOperating, "{}", "{}").WillReturnError(&pq.Error{Code: "23505"})
+				m.ExpectRollback()
+			},
 			expectedErr: ErrAccountExists,
 		},
+		{
+			name: "audit lock error",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectExec(`INSERT INTO accounts`).WithArgs("acc-1", "100", DefaultCurrency, "Payroll", "", AccountTypeOperating, "{}", "{}").WillReturnResult(sqlmock.NewResult(1, 1))
+				m.ExpectExec(`SELECT pg_advisory_xact_lock`).WillReturnError(errors.New("lock error"))
+				m.ExpectRollback()
+			},
+			expectedErr: ErrCreateAccountMsg,
+		},
 	}
 
 	for _, tc := range tests {
@@ -52,14 +69,9 @@ func TestCreateAccountSuccess(t *testing.T) {
 			store, mock, cleanup := newTestStorage(t)
 			defer cleanup()
 
-			expect := mock.ExpectExec(`INSERT INTO accounts`).WithArgs(tc.args...)
-			if tc.err != nil {
-				expect.WillReturnError(tc.err)
-			} else {
-				expect.WillReturnResult(tc.result)
-			}
+			tc.prepare(mock)
 
-			err := store.CreateAccount(ctx, "acc-1", decimal.RequireFromString("100.0"))
+			err := store.CreateAccount(ctx, "acc-1", decimal.RequireFromString("100.0"), AccountAttributes{Name: "Payroll", Type: AccountTypeOperating})
 
 			if tc.expectedErr != "" {
 				assert.EqualError(t, err, tc.expectedErr)
@@ -85,16 +97,15 @@ func TestGetAccountDetails(t *testing.T) {
 			name:      "success",
 			accountID: "acc-1",
 			prepare: func(m sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{"id", "balance"}).AddRow("acc-1", decimal.RequireFromString("250.5"))
-				m.ExpectQuery(`SELECT id, balance FROM accounts`).WithArgs("acc-1").WillReturnRows(rows)
+				m.ExpectQuery(`SELECT id, balance, .* FROM accounts WHERE id = \$1`).WithArgs("acc-1").WillReturnRows(accountRows(testAccount()))
 			},
-			expectedAcc: &Account{ID: "acc-1", Balance: decimal.RequireFromString("250.5")},
+			expectedAcc: testAccount(),
 		},
 		{
 			name:      "not found",
 			accountID: "missing",
 			prepare: func(m sqlmock.Sqlmock) {
-				m.ExpectQuery(`SELECT id, balance FROM accounts`).WithArgs("missing").WillReturnError(sql.ErrNoRows)
+				m.ExpectQuery(`SELECT id, balance, .* FROM accounts WHERE id = \$1`).WithArgs("missing").WillReturnError(sql.ErrNoRows)
 			},
 			expectedErr: ErrAccountNotFound,
 		},
@@ -122,33 +133,55 @@ func TestGetAccountDetails(t *testing.T) {
 	}
 }
 
+// expectAccountLock expects the given accounts, listed in id order, to be locked.
+func expectAccountLock(m sqlmock.Sqlmock, accountIDs ...string) *sqlmock.ExpectedExec {
+	return m.ExpectExec(`SELECT id FROM accounts WHERE id = ANY\(\$1::TEXT\[\]\) ORDER BY id COLLATE "C" FOR UPDATE`).
+		WithArgs(pq.Array(accountIDs))
+}
+
 // TestProcessTransaction validates transaction processing scenarios, including successful transfers,
-// insufficient funds, missing accounts, and update errors.
+// insufficient funds, missing or closed accounts, mismatched currencies and versions, and update errors.
 func TestProcessTransaction(t *testing.T) {
 	tests := []struct {
-		name        string
-		prepare     func(sqlmock.Sqlmock)
-		amount      string
-		expectedErr string
+		name          string
+		prepare       func(sqlmock.Sqlmock)
+		amount        string
+		sourceVersion int64
+		expectedErr   string
 	}{
 		{
 			name: "success",
 			prepare: func(m sqlmock.Sqlmock) {
 				m.ExpectBegin()
-				m.ExpectQuery(`SELECT 1 FROM accounts`).WithArgs("dest").WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
-				m.ExpectQuery(`SELECT balance FROM accounts`).WithArgs("source").WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow(decimal.RequireFromString("500.0")))
-				m.ExpectExec(`UPDATE accounts SET balance = balance -`).WithArgs(decimal.RequireFromString("200.0"), "source").WillReturnResult(sqlmock.NewResult(0, 1))
-				m.ExpectExec(`UPDATE accounts SET balance = balance +`).WithArgs(decimal.RequireFromString("200.0"), "dest").WillReturnResult(sqlmock.NewResult(0, 1))
-				m.ExpectExec(`INSERT INTO transactions`).WithArgs("source", "dest", decimal.RequireFromString("200.0")).WillReturnResult(sqlmock.NewResult(1, 1))
+				expectAccountLock(m, "dest", "source").WillReturnResult(sqlmock.NewResult(0, 2))
+				m.ExpectQuery(`SELECT currency, status FROM accounts`).WithArgs("dest").WillReturnRows(sqlmock.NewRows([]string{"currency", "status"}).AddRow("EUR", AccountStatusActive))
+				m.ExpectQuery(`SELECT balance, currency, status, version FROM accounts`).WithArgs("source").WillReturnRows(sqlmock.NewRows([]string{"balance", "currency", "status", "version"}).AddRow(decimal.RequireFromString("500.0"), "EUR", AccountStatusActive, 3))
+				m.ExpectQuery(`UPDATE accounts SET balance = balance -`).WithArgs(decimal.RequireFromString("200.0"), "source").WillReturnRows(sqlmock.NewRows([]string{"balance", "version"}).AddRow("100", 4))
+				m.ExpectQuery(`UPDATE accounts SET balance = balance +`).WithArgs(decimal.RequireFromString("200.0"), "dest").WillReturnRows(sqlmock.NewRows([]string{"balance", "version"}).AddRow("300", 4))
+				m.ExpectQuery(`INSERT INTO transactions`).WithArgs("source", "dest", decimal.RequireFromString("200.0"), "req-1", "client-1").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+				expectAuditLock(m)
+				expectOutboxInsert(m, EventTransferCompleted)
+				expectAuditAppend(m, EventTransferCompleted, "7")
 				m.ExpectCommit()
 			},
 			amount: "200.0",
 		},
+		{
+			name: "lock accounts error",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				expectAccountLock(m, "dest", "source").WillReturnError(errors.New("lock timeout"))
+				m.ExpectRollback()
+			},
+			amount:      "200.0",
+			expectedErr: ErrProcessTransactionMsg,
+		},
 		{
 			name: "destination missing",
 			prepare: func(m sqlmock.Sqlmock) {
 				m.ExpectBegin()
-				m.ExpectQuery(`SELECT 1 FROM accounts`).WithArgs("dest").WillReturnError(sql.ErrNoRows)
+				expectAccountLock(m, "dest", "source").WillReturnResult(sqlmock.NewResult(0, 2))
+				m.ExpectQuery(`SELECT currency, status FROM accounts`).WithArgs("dest").WillReturnError(sql.ErrNoRows)
 				m.ExpectRollback()
 			},
 			amount:      "200.0",
@@ -158,19 +191,100 @@ func TestProcessTransaction(t *testing.T) {
 			name: "source missing",
 			prepare: func(m sqlmock.Sqlmock) {
 				m.ExpectBegin()
-				m.ExpectQuery(`SELECT 1 FROM accounts`).WithArgs("dest").WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
-				m.ExpectQuery(`SELECT balance FROM accounts`).WithArgs("source").WillReturnError(sql.ErrNoRows)
+				expectAccountLock(m, "dest", "source").WillReturnResult(sqlmock.NewResult(0, 2))
+				m.ExpectQuery(`SELECT currency, status FROM accounts`).WithArgs("dest").WillReturnRows(sqlmock.NewRows([]string{"currency", "status"}).AddRow("EUR", AccountStatusActive))
+				m.ExpectQuery(`SELECT balance, currency, status, version FROM accounts`).WithArgs("source").WillReturnError(sql.ErrNoRows)
 				m.ExpectRollback()
 			},
 			amount:      "200.0",
 			expectedErr: ErrSourceAccountMsg,
 		},
+		{
+			name: "currency mismatch",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				expectAccountLock(m, "dest", "source").WillReturnResult(sqlmock.NewResult(0, 2))
+				m.ExpectQuery(`SELECT currency, status FROM accounts`).WithArgs("dest").WillReturnRows(sqlmock.NewRows([]string{"currency", "status"}).AddRow("USD", AccountStatusActive))
+				m.ExpectQuery(`SELECT balance, currency, status, version FROM accounts`).WithArgs("source").WillReturnRows(sqlmock.NewRows([]string{"balance", "currency", "status", "version"}).AddRow("500", "EUR", AccountStatusActive, 3))
+				m.ExpectRollback()
+			},
+			amount:      "100.0",
+			expectedErr: ErrCurrencyMismatchMsg,
+		},
+		{
+			name: "amount finer than the currency",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				expectAccountLock(m, "dest", "source").WillReturnResult(sqlmock.NewResult(0, 2))
+				m.ExpectQuery(`SELECT currency, status FROM accounts`).WithArgs("dest").WillReturnRows(sqlmock.NewRows([]string{"currency", "status"}).AddRow("EUR", AccountStatusActive))
+				m.ExpectQuery(`SELECT balance, currency, status, version FROM accounts`).WithArgs("source").WillReturnRows(sqlmock.NewRows([]string{"balance", "currency", "status", "version"}).AddRow("500", "EUR", AccountStatusActive, 3))
+				m.ExpectRollback()
+			},
+			amount:      "100.005",
+			expectedErr: ErrAmountPrecisionMsg,
+		},
+		{
+			name: "matching source version",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				expectAccountLock(m, "dest", "source").WillReturnResult(sqlmock.NewResult(0, 2))
+				m.ExpectQuery(`SELECT currency, status FROM accounts`).WithArgs("dest").WillReturnRows(sqlmock.NewRows([]string{"currency", "status"}).AddRow("EUR", AccountStatusActive))
+				m.ExpectQuery(`SELECT balance, currency, status, version FROM accounts WHERE id = \$1`).WithArgs("source").WillReturnRows(sqlmock.NewRows([]string{"balance", "currency", "status", "version"}).AddRow("500", "EUR", AccountStatusActive, 3))
+				m.ExpectQuery(`UPDATE accounts SET balance = balance - \$1, version = version \+ 1`).WithArgs(decimal.RequireFromString("100.0"), "source").WillReturnRows(sqlmock.NewRows([]string{"balance", "version"}).AddRow("400", 4))
+				m.ExpectQuery(`UPDATE accounts SET balance = balance \+ \$1, version = version \+ 1`).WithArgs(decimal.RequireFromString("100.0"), "dest").WillReturnRows(sqlmock.NewRows([]string{"balance", "version"}).AddRow("100", 2))
+				m.ExpectQuery(`INSERT INTO transactions`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(8))
+				expectAuditLock(m)
+				expectOutboxInsert(m, EventTransferCompleted)
+				expectAuditAppend(m, EventTransferCompleted, "8")
+				m.ExpectCommit()
+			},
+			amount:        "100.0",
+			sourceVersion: 3,
+		},
+		{
+			name: "source version mismatch",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				expectAccountLock(m, "dest", "source").WillReturnResult(sqlmock.NewResult(0, 2))
+				m.ExpectQuery(`SELECT currency, status FROM accounts`).WithArgs("dest").WillReturnRows(sqlmock.NewRows([]string{"currency", "status"}).AddRow("EUR", AccountStatusActive))
+				m.ExpectQuery(`SELECT balance, currency, status, version FROM accounts`).WithArgs("source").WillReturnRows(sqlmock.NewRows([]string{"balance", "currency", "status", "version"}).AddRow("500", "EUR", AccountStatusActive, 3))
+				m.ExpectRollback()
+			},
+			amount:        "100.0",
+			sourceVersion: 2,
+			expectedErr:   ErrAccountVersionMsg,
+		},
+		{
+			name: "source closed",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				expectAccountLock(m, "dest", "source").WillReturnResult(sqlmock.NewResult(0, 2))
+				m.ExpectQuery(`SELECT currency, status FROM accounts`).WithArgs("dest").WillReturnRows(sqlmock.NewRows([]string{"currency", "status"}).AddRow("EUR", AccountStatusActive))
+				m.ExpectQuery(`SELECT balance, currency, status, version FROM accounts`).WithArgs("source").WillReturnRows(sqlmock.NewRows([]string{"balance", "currency", "status", "version"}).AddRow("500", "EUR", AccountStatusClosed, 3))
+				m.ExpectRollback()
+			},
+			amount:      "100.0",
+			expectedErr: ErrSourceClosedMsg,
+		},
+		{
+			name: "destination closed",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				expectAccountLock(m, "dest", "source").WillReturnResult(sqlmock.NewResult(0, 2))
+				m.ExpectQuery(`SELECT currency, status FROM accounts`).WithArgs("dest").WillReturnRows(sqlmock.NewRows([]string{"currency", "status"}).AddRow("EUR", AccountStatusClosed))
+				m.ExpectQuery(`SELECT balance, currency, status, version FROM accounts`).WithArgs("source").WillReturnRows(sqlmock.NewRows([]string{"balance", "currency", "status", "version"}).AddRow("500", "EUR", AccountStatusActive, 3))
+				m.ExpectRollback()
+			},
+			amount:      "100.0",
+			expectedErr: ErrDestinationClosedMsg,
+		},
 		{
 			name: "insufficient funds",
 			prepare: func(m sqlmock.Sqlmock) {
 				m.ExpectBegin()
-				m.ExpectQuery(`SELECT 1 FROM accounts`).WithArgs("dest").WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
-				m.ExpectQuery(`SELECT balance FROM accounts`).WithArgs("source").WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow(decimal.RequireFromString("50.0")))
+				expectAccountLock(m, "dest", "source").WillReturnResult(sqlmock.NewResult(0, 2))
+				m.ExpectQuery(`SELECT currency, status FROM accounts`).WithArgs("dest").WillReturnRows(sqlmock.NewRows([]string{"currency", "status"}).AddRow("EUR", AccountStatusActive))
+				m.ExpectQuery(`SELECT balance, currency, status, version FROM accounts`).WithArgs("source").WillReturnRows(sqlmock.NewRows([]string{"balance", "currency", "status", "version"}).AddRow(decimal.RequireFromString("50.0"), "EUR", AccountStatusActive, 3))
 				m.ExpectRollback()
 			},
 			amount:      "100.0",
@@ -180,9 +294,10 @@ func TestProcessTransaction(t *testing.T) {
 			name: "withdraw update error",
 			prepare: func(m sqlmock.Sqlmock) {
 				m.ExpectBegin()
-				m.ExpectQuery(`SELECT 1 FROM accounts`).WithArgs("dest").WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
-				m.ExpectQuery(`SELECT balance FROM accounts`).WithArgs("source").WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow(decimal.RequireFromString("500.0")))
-				m.ExpectExec(`UPDATE accounts SET balance = balance -`).WithArgs(decimal.RequireFromString("100.0"), "source").WillReturnError(errors.New("update source error"))
+				expectAccountLock(m, "dest", "source").WillReturnResult(sqlmock.NewResult(0, 2))
+				m.ExpectQuery(`SELECT currency, status FROM accounts`).WithArgs("dest").WillReturnRows(sqlmock.NewRows([]string{"currency", "status"}).AddRow("EUR", AccountStatusActive))
+				m.ExpectQuery(`SELECT balance, currency, status, version FROM accounts`).WithArgs("source").WillReturnRows(sqlmock.NewRows([]string{"balance", "currency", "status", "version"}).AddRow(decimal.RequireFromString("500.0"), "EUR", AccountStatusActive, 3))
+				m.ExpectQuery(`UPDATE accounts SET balance = balance -`).WithArgs(decimal.RequireFromString("100.0"), "source").WillReturnError(errors.New("update source error"))
 				m.ExpectRollback()
 			},
 			amount:      "100.0",
@@ -192,10 +307,11 @@ func TestProcessTransaction(t *testing.T) {
 			name: "deposit update error",
 			prepare: func(m sqlmock.Sqlmock) {
 				m.ExpectBegin()
-				m.ExpectQuery(`SELECT 1 FROM accounts`).WithArgs("dest").WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
-				m.ExpectQuery(`SELECT balance FROM accounts`).WithArgs("source").WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow(decimal.RequireFromString("500.0")))
-				m.ExpectExec(`UPDATE accounts SET balance = balance -`).WithArgs(decimal.RequireFromString("100.0"), "source").WillReturnResult(sqlmock.NewResult(0, 1))
-				m.ExpectExec(`UPDATE accounts SET balance = balance +`).WithArgs(decimal.RequireFromString("100.0"), "dest").WillReturnError(errors.New("update dest error"))
+				expectAccountLock(m, "dest", "source").WillReturnResult(sqlmock.NewResult(0, 2))
+				m.ExpectQuery(`SELECT currency, status FROM accounts`).WithArgs("dest").WillReturnRows(sqlmock.NewRows([]string{"currency", "status"}).AddRow("EUR", AccountStatusActive))
+				m.ExpectQuery(`SELECT balance, currency, status, version FROM accounts`).WithArgs("source").WillReturnRows(sqlmock.NewRows([]string{"balance", "currency", "status", "version"}).AddRow(decimal.RequireFromString("500.0"), "EUR", AccountStatusActive, 3))
+				m.ExpectQuery(`UPDATE accounts SET balance = balance -`).WithArgs(decimal.RequireFromString("100.0"), "source").WillReturnRows(sqlmock.NewRows([]string{"balance", "version"}).AddRow("100", 4))
+				m.ExpectQuery(`UPDATE accounts SET balance = balance +`).WithArgs(decimal.RequireFromString("100.0"), "dest").WillReturnError(errors.New("update dest error"))
 				m.ExpectRollback()
 			},
 			amount:      "100.0",
@@ -205,11 +321,12 @@ func TestProcessTransaction(t *testing.T) {
 			name: "insert transaction error",
 			prepare: func(m sqlmock.Sqlmock) {
 				m.ExpectBegin()
-				m.ExpectQuery(`SELECT 1 FROM accounts`).WithArgs("dest").WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
-				m.ExpectQuery(`SELECT balance FROM accounts`).WithArgs("source").WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow(decimal.RequireFromString("500.0")))
-				m.ExpectExec(`UPDATE accounts SET balance = balance -`).WithArgs(decimal.RequireFromString("100.0"), "source").WillReturnResult(sqlmock.NewResult(0, 1))
-				m.ExpectExec(`UPDATE accounts SET balance = balance +`).WithArgs(decimal.RequireFromString("100.0"), "dest").WillReturnResult(sqlmock.NewResult(0, 1))
-				m.ExpectExec(`INSERT INTO transactions`).WithArgs("source", "dest", decimal.RequireFromString("100.0")).WillReturnError(errors.New("insert transaction error"))
+				expectAccountLock(m, "dest", "source").WillReturnResult(sqlmock.NewResult(0, 2))
+				m.ExpectQuery(`SELECT currency, status FROM accounts`).WithArgs("dest").WillReturnRows(sqlmock.NewRows([]string{"currency", "status"}).AddRow("EUR", AccountStatusActive))
+				m.ExpectQuery(`SELECT balance, currency, status, version FROM accounts`).WithArgs("source").WillReturnRows(sqlmock.NewRows([]string{"balance", "currency", "status", "version"}).AddRow(decimal.RequireFromString("500.0"), "EUR", AccountStatusActive, 3))
+				m.ExpectQuery(`UPDATE accounts SET balance = balance -`).WithArgs(decimal.RequireFromString("100.0"), "source").WillReturnRows(sqlmock.NewRows([]string{"balance", "version"}).AddRow("100", 4))
+				m.ExpectQuery(`UPDATE accounts SET balance = balance +`).WithArgs(decimal.RequireFromString("100.0"), "dest").WillReturnRows(sqlmock.NewRows([]string{"balance", "version"}).AddRow("300", 4))
+				m.ExpectQuery(`INSERT INTO transactions`).WithArgs("source", "dest", decimal.RequireFromString("100.0"), "req-1", "client-1").WillReturnError(errors.New("insert transaction error"))
 				m.ExpectRollback()
 			},
 			amount:      "100.0",
@@ -219,13 +336,14 @@ func TestProcessTransaction(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			ctx := context.Background()
+			ctx := utils.WithRequestID(context.Background(), "req-1")
+			ctx = utils.WithClientID(ctx, "client-1")
 			store, mock, cleanup := newTestStorage(t)
 			defer cleanup()
 
 			tc.prepare(mock)
 
-			err := store.ProcessTransaction(ctx, "source", "dest", decimal.RequireFromString((tc.amount)))
+			err := store.ProcessTransaction(ctx, "source", "dest", decimal.RequireFromString((tc.amount)), tc.sourceVersion)
 			if tc.expectedErr == "" {
 				assert.NoError(t, err)
 			} else {
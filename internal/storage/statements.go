@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// StreamStatement reads the account's statement for the period after from up to and including to and
+// passes it to w as it goes. The balances and totals are summed up before the entries are read, and
+// everything is read from one snapshot of the database, so they agree with the entries even while
+// transfers are being made. Periods that start before the account was created open with its initial
+// balance.
+// Returns ErrAccountNotFound if the account doesn't exist, ErrAccountNotOpenMsg if it was created
+// after to, the error of w if writing fails, or ErrStatementMsg on internal failures.
+func (p *PostgressStorage) StreamStatement(ctx context.Context, accountID string, from, to time.Time, w StatementWriter) error {
+	const (
+		summaryQuery = `
+			SELECT COALESCE(SUM(amount) FILTER (WHERE destination_account_id = $1), 0),
+				COUNT(*) FILTER (WHERE destination_account_id = $1),
+				COALESCE(SUM(amount) FILTER (WHERE source_account_id = $1), 0),
+				COUNT(*) FILTER (WHERE source_account_id = $1)
+			FROM transactions
+			WHERE (source_account_id = $1 OR destination_account_id = $1)
+			  AND created_at > $2 AND created_at <= $3
+		`
+		entriesQuery = `
+			SELECT id, source_account_id, destination_account_id, amount, request_id, created_at
+			FROM transactions
+			WHERE (source_account_id = $1 OR destination_account_id = $1)
+			  AND created_at > $2 AND created_at <= $3
+			ORDER BY created_at, id
+		`
+	)
+
+	logger := p.contextLogger(ctx)
+
+	tx, err := p.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		logger.Error("failed to create transaction", zap.Error(err))
+		return errors.New(ErrStatementMsg)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stmt := &Statement{AccountID: accountID, From: from, To: to}
+	var createdAt time.Time
+	err = tx.QueryRowContext(ctx, balanceAtQuery, from, accountID).Scan(&stmt.Currency, &createdAt, &stmt.OpeningBalance)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New(ErrAccountNotFound)
+		}
+		logger.Error("failed to get opening balance", zap.Error(err))
+		return errors.New(ErrStatementMsg)
+	}
+	if to.Before(createdAt) {
+		return errors.New(ErrAccountNotOpenMsg)
+	}
+
+	err = tx.QueryRowContext(ctx, summaryQuery, accountID, from, to).Scan(&stmt.TotalCredits, &stmt.CreditCount, &stmt.TotalDebits, &stmt.DebitCount)
+	if err != nil {
+		logger.Error("failed to sum statement entries", zap.Error(err))
+		return errors.New(ErrStatementMsg)
+	}
+	stmt.ClosingBalance = stmt.OpeningBalance.Add(stmt.TotalCredits).Sub(stmt.TotalDebits)
+
+	rows, err := tx.QueryContext(ctx, entriesQuery, accountID, from, to)
+	if err != nil {
+		logger.Error("failed to list statement entries", zap.Error(err))
+		return errors.New(ErrStatementMsg)
+	}
+	defer rows.Close()
+
+	if err := w.WriteHeader(stmt); err != nil {
+		return err
+	}
+
+	balance := stmt.OpeningBalance
+	for rows.Next() {
+		var (
+			t         Transaction
+			requestID sql.NullString
+		)
+		if err := rows.Scan(&t.ID, &t.SourceAccountID, &t.DestinationAccountID, &t.Amount, &requestID, &t.CreatedAt); err != nil {
+			logger.Error("failed to scan statement entry", zap.Error(err))
+			return errors.New(ErrStatementMsg)
+		}
+		entry := StatementEntry{TransactionID: t.ID, CreatedAt: t.CreatedAt, RequestID: requestID.String}
+		if t.DestinationAccountID == accountID {
+			entry.CounterpartyID = t.SourceAccountID
+			entry.Amount = t.Amount
+		} else {
+			entry.CounterpartyID = t.DestinationAccountID
+			entry.Amount = t.Amount.Neg()
+		}
+		balance = balance.Add(entry.Amount)
+		entry.Balance = balance
+		if err := w.WriteEntry(entry); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		logger.Error("failed to list statement entries", zap.Error(err))
+		return errors.New(ErrStatementMsg)
+	}
+
+	return w.WriteFooter(stmt)
+}
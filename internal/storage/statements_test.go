@@ -0,0 +1,173 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingWriter keeps everything written to it and fails entries once failAfter have been written.
+type recordingWriter struct {
+	header    *Statement
+	entries   []StatementEntry
+	footer    *Statement
+	failAfter int
+}
+
+func (r *recordingWriter) WriteHeader(s *Statement) error {
+	copied := *s
+	r.header = &copied
+	return nil
+}
+
+func (r *recordingWriter) WriteEntry(e StatementEntry) error {
+	if r.failAfter > 0 && len(r.entries) == r.failAfter {
+		return errors.New("client gone")
+	}
+	r.entries = append(r.entries, e)
+	return nil
+}
+
+func (r *recordingWriter) WriteFooter(s *Statement) error {
+	r.footer = s
+	return nil
+}
+
+// TestStreamStatement validates running balances, totals known before the entries, accounts created
+// after the period, missing accounts, writer failures and database errors.
+func TestStreamStatement(t *testing.T) {
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	balanceColumns := []string{"currency", "created_at", "balance"}
+	entryColumns := []string{"id", "source_account_id", "destination_account_id", "amount", "request_id", "created_at"}
+	summaryColumns := []string{"credits", "credit_count", "debits", "debit_count"}
+	expectSummary := func(m sqlmock.Sqlmock) {
+		m.ExpectQuery(`SELECT COALESCE\(SUM\(amount\) FILTER`).WithArgs("acc-1", from, to).
+			WillReturnRows(sqlmock.NewRows(summaryColumns).AddRow("50", 1, "30.5", 1))
+	}
+	entryRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows(entryColumns).
+			AddRow(7, "acc-2", "acc-1", "50", "req-7", from.Add(time.Hour)).
+			AddRow(9, "acc-1", "acc-3", "30.5", nil, from.Add(2*time.Hour))
+	}
+
+	tests := []struct {
+		name              string
+		prepare           func(sqlmock.Sqlmock)
+		failAfter         int
+		expectedErr       string
+		expectedEntries   []StatementEntry
+		expectedStatement *Statement
+	}{
+		{
+			name: "success",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(`SELECT a.currency, a.created_at`).WithArgs(from, "acc-1").
+					WillReturnRows(sqlmock.NewRows(balanceColumns).AddRow("EUR", from.Add(-time.Hour), "100"))
+				expectSummary(m)
+				m.ExpectQuery(`SELECT id, source_account_id.*created_at > \$2 AND created_at <= \$3\s+ORDER BY created_at, id`).
+					WithArgs("acc-1", from, to).WillReturnRows(entryRows())
+				m.ExpectRollback()
+			},
+			expectedEntries: []StatementEntry{
+				{TransactionID: 7, CreatedAt: from.Add(time.Hour), CounterpartyID: "acc-2", Amount: decimal.RequireFromString("50"), Balance: decimal.RequireFromString("150"), RequestID: "req-7"},
+				{TransactionID: 9, CreatedAt: from.Add(2 * time.Hour), CounterpartyID: "acc-3", Amount: decimal.RequireFromString("-30.5"), Balance: decimal.RequireFromString("119.5")},
+			},
+			expectedStatement: &Statement{
+				AccountID: "acc-1", Currency: "EUR", From: from, To: to,
+				OpeningBalance: decimal.RequireFromString("100"), ClosingBalance: decimal.RequireFromString("119.5"),
+				TotalCredits: decimal.RequireFromString("50"), TotalDebits: decimal.RequireFromString("30.5"), CreditCount: 1, DebitCount: 1,
+			},
+		},
+		{
+			name: "account created after the period",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(`SELECT a.currency`).WithArgs(from, "acc-1").
+					WillReturnRows(sqlmock.NewRows(balanceColumns).AddRow("EUR", to.Add(time.Second), "100"))
+				m.ExpectRollback()
+			},
+			expectedErr: ErrAccountNotOpenMsg,
+		},
+		{
+			name: "not found",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(`SELECT a.currency`).WithArgs(from, "acc-1").WillReturnError(sql.ErrNoRows)
+				m.ExpectRollback()
+			},
+			expectedErr: ErrAccountNotFound,
+		},
+		{
+			name: "writer fails",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(`SELECT a.currency`).WithArgs(from, "acc-1").
+					WillReturnRows(sqlmock.NewRows(balanceColumns).AddRow("EUR", from, "100"))
+				expectSummary(m)
+				m.ExpectQuery(`SELECT id, source_account_id`).WithArgs("acc-1", from, to).WillReturnRows(entryRows())
+				m.ExpectRollback()
+			},
+			failAfter:   1,
+			expectedErr: "client gone",
+		},
+		{
+			name: "entries query fails",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(`SELECT a.currency`).WithArgs(from, "acc-1").
+					WillReturnRows(sqlmock.NewRows(balanceColumns).AddRow("EUR", from, "100"))
+				expectSummary(m)
+				m.ExpectQuery(`SELECT id, source_account_id`).WithArgs("acc-1", from, to).WillReturnError(errors.New("db error"))
+				m.ExpectRollback()
+			},
+			expectedErr: ErrStatementMsg,
+		},
+		{
+			name: "summary query fails",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(`SELECT a.currency`).WithArgs(from, "acc-1").
+					WillReturnRows(sqlmock.NewRows(balanceColumns).AddRow("EUR", from, "100"))
+				m.ExpectQuery(`SELECT COALESCE`).WithArgs("acc-1", from, to).WillReturnError(errors.New("db error"))
+				m.ExpectRollback()
+			},
+			expectedErr: ErrStatementMsg,
+		},
+		{
+			name: "begin fails",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin().WillReturnError(errors.New("db error"))
+			},
+			expectedErr: ErrStatementMsg,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, mock, cleanup := newTestStorage(t)
+			defer cleanup()
+			tt.prepare(mock)
+
+			w := &recordingWriter{failAfter: tt.failAfter}
+			err := store.StreamStatement(context.Background(), "acc-1", from, to, w)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				assert.Nil(t, w.footer)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedStatement, w.header)
+				assert.Equal(t, tt.expectedEntries, w.entries)
+				assert.Equal(t, tt.expectedStatement, w.footer)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
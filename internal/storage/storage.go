@@ -2,13 +2,50 @@ package storage
 
 import (
 	"context"
+	"time"
 
 	"github.com/shopspring/decimal"
 )
 
 // Storage defines the interface for account and transaction operations.
 type Storage interface {
-	CreateAccount(ctx context.Context, accountID string, balance decimal.Decimal) error
+	CreateAccount(ctx context.Context, accountID string, balance decimal.Decimal, attrs AccountAttributes) error
+	CreateAccounts(ctx context.Context, accounts []NewAccount, atomic bool) ([]error, error)
 	GetAccountDetails(ctx context.Context, accountID string) (*Account, error)
-	ProcessTransaction(ctx context.Context, sourceAccID string, destAccID string, amount decimal.Decimal) error
+	UpdateAccount(ctx context.Context, accountID string, version int64, patch AccountPatch) (*Account, error)
+	ListAccounts(ctx context.Context, query AccountQuery) ([]Account, error)
+	CountAccounts(ctx context.Context, query AccountQuery) (int64, error)
+	CloseAccount(ctx context.Context, accountID string, version int64) (*Account, error)
+	ProcessTransaction(ctx context.Context, sourceAccID string, destAccID string, amount decimal.Decimal, sourceVersion int64) error
+	ProcessTransferBatch(ctx context.Context, batchID string, transfers []Transfer) ([]TransferResult, error)
+	ListTransactions(ctx context.Context, accountID string, beforeID int64, limit int) ([]Transaction, error)
+	GetBalanceAt(ctx context.Context, accountID string, asOf time.Time) (*AccountBalance, error)
+	StreamStatement(ctx context.Context, accountID string, from, to time.Time, w StatementWriter) error
+	CreateBalanceSnapshots(ctx context.Context, asOf time.Time) (int64, error)
+	Ping(ctx context.Context) error
+
+	CreateAPIKey(ctx context.Context, key *APIKey) error
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (*APIKey, error)
+	ListAPIKeys(ctx context.Context) ([]APIKey, error)
+	RevokeAPIKey(ctx context.Context, keyID string) error
+
+	VerifyAuditChain(ctx context.Context) (*AuditVerification, error)
+
+	ListUnpublishedEvents(ctx context.Context, limit int) ([]OutboxEvent, error)
+	ListEventsAfter(ctx context.Context, afterID int64, accountID string, limit int) ([]OutboxEvent, error)
+	LatestEventID(ctx context.Context) (int64, error)
+	MarkEventPublished(ctx context.Context, eventID int64) error
+	MarkEventFailed(ctx context.Context, eventID int64, reason string) error
+
+	CreateWebhookSubscription(ctx context.Context, sub *WebhookSubscription) error
+	GetWebhookSubscription(ctx context.Context, subscriptionID string) (*WebhookSubscription, error)
+	ListWebhookSubscriptions(ctx context.Context, clientID string) ([]WebhookSubscription, error)
+	UpdateWebhookSubscription(ctx context.Context, sub *WebhookSubscription) error
+	DeleteWebhookSubscription(ctx context.Context, subscriptionID string) error
+	EnqueueWebhookDeliveries(ctx context.Context, event OutboxEvent) (int, error)
+	ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]WebhookDelivery, error)
+	RecordWebhookAttempt(ctx context.Context, deliveryID int64, result WebhookAttemptResult) error
+	ListWebhookDeliveries(ctx context.Context, subscriptionID string, limit int) ([]WebhookDelivery, error)
+	GetWebhookDelivery(ctx context.Context, deliveryID int64) (*WebhookDelivery, error)
+	ListWebhookAttempts(ctx context.Context, deliveryID int64) ([]WebhookAttempt, error)
 }
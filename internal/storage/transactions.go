@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go.uber.org/zap"
+)
+
+// ListTransactions returns up to limit transactions that debit or credit the account, newest first.
+// When beforeID is positive only transactions with a smaller ID are returned, so the last ID of one page
+// is the cursor for the next. Returns ErrListTransactionsMsg on internal failures.
+func (p *PostgressStorage) ListTransactions(ctx context.Context, accountID string, beforeID int64, limit int) ([]Transaction, error) {
+	const query = `
+		SELECT id, source_account_id, destination_account_id, amount, request_id, created_at
+		FROM transactions
+		WHERE (source_account_id = $1 OR destination_account_id = $1)
+		  AND ($2 <= 0 OR id < $2)
+		ORDER BY id DESC
+		LIMIT $3
+	`
+
+	logger := p.contextLogger(ctx)
+
+	rows, err := p.db.QueryContext(ctx, query, accountID, beforeID, limit)
+	if err != nil {
+		logger.Error("failed to list transactions", zap.Error(err))
+		return nil, errors.New(ErrListTransactionsMsg)
+	}
+	defer rows.Close()
+
+	transactions := make([]Transaction, 0)
+	for rows.Next() {
+		var (
+			t         Transaction
+			requestID sql.NullString
+		)
+		if err := rows.Scan(&t.ID, &t.SourceAccountID, &t.DestinationAccountID, &t.Amount, &requestID, &t.CreatedAt); err != nil {
+			logger.Error("failed to scan transaction", zap.Error(err))
+			return nil, errors.New(ErrListTransactionsMsg)
+		}
+		t.RequestID = requestID.String
+		transactions = append(transactions, t)
+	}
+	if err := rows.Err(); err != nil {
+		logger.Error("failed to list transactions", zap.Error(err))
+		return nil, errors.New(ErrListTransactionsMsg)
+	}
+	return transactions, nil
+}
@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestListTransactions validates listing an account's transactions, including legacy rows without a request ID.
+func TestListTransactions(t *testing.T) {
+	createdAt := time.Date(2025, 12, 1, 12, 0, 0, 0, time.UTC)
+	columns := []string{"id", "source_account_id", "destination_account_id", "amount", "request_id", "created_at"}
+
+	tests := []struct {
+		name        string
+		prepare     func(sqlmock.Sqlmock)
+		expected    []Transaction
+		expectedErr string
+	}{
+		{
+			name: "success",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(`SELECT id, source_account_id, destination_account_id, amount, request_id, created_at FROM transactions`).
+					WithArgs("acc-1", int64(10), 2).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(9, "acc-1", "acc-2", "25.5", "req-9", createdAt).
+						AddRow(4, "acc-3", "acc-1", "10", nil, createdAt))
+			},
+			expected: []Transaction{
+				{ID: 9, SourceAccountID: "acc-1", DestinationAccountID: "acc-2", Amount: decimal.RequireFromString("25.5"), RequestID: "req-9", CreatedAt: createdAt},
+				{ID: 4, SourceAccountID: "acc-3", DestinationAccountID: "acc-1", Amount: decimal.RequireFromString("10"), CreatedAt: createdAt},
+			},
+		},
+		{
+			name: "query error",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(`SELECT id, source_account_id`).WillReturnError(errors.New("db error"))
+			},
+			expectedErr: ErrListTransactionsMsg,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			store, mock, cleanup := newTestStorage(t)
+			defer cleanup()
+
+			tc.prepare(mock)
+
+			transactions, err := store.ListTransactions(context.Background(), "acc-1", 10, 2)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, transactions)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
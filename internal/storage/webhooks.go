@@ -0,0 +1,436 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+const (
+	webhookSubscriptionColumns = `id, client_id, url, secret, event_types, account_ids, created_at, updated_at`
+	webhookDeliveryColumns     = `id, subscription_id, event_id, event_type, payload, status, attempts, next_attempt_at, last_status_code, last_error, created_at, updated_at`
+)
+
+// CreateWebhookSubscription stores a new subscription and fills in its timestamps.
+// Returns ErrCreateWebhookMsg on internal failures.
+func (p *PostgressStorage) CreateWebhookSubscription(ctx context.Context, sub *WebhookSubscription) error {
+	const query = `
+		INSERT INTO webhook_subscriptions (id, client_id, url, secret, event_types, account_ids)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at, updated_at
+	`
+
+	err := p.db.QueryRowContext(ctx, query, sub.ID, sub.ClientID, sub.URL, sub.Secret, pq.Array(sub.EventTypes), pq.Array(sub.AccountIDs)).
+		Scan(&sub.CreatedAt, &sub.UpdatedAt)
+	if err != nil {
+		p.contextLogger(ctx).Error("failed to create webhook subscription", zap.Error(err))
+		return errors.New(ErrCreateWebhookMsg)
+	}
+	return nil
+}
+
+// GetWebhookSubscription fetches a subscription by ID.
+// Returns ErrWebhookNotFound if it doesn't exist or ErrGetWebhookMsg on internal failures.
+func (p *PostgressStorage) GetWebhookSubscription(ctx context.Context, subscriptionID string) (*WebhookSubscription, error) {
+	const query = `
+		SELECT ` + webhookSubscriptionColumns + `
+		FROM webhook_subscriptions
+		WHERE id = $1
+	`
+
+	sub, err := scanWebhookSubscription(p.db.QueryRowContext(ctx, query, subscriptionID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New(ErrWebhookNotFound)
+		}
+		p.contextLogger(ctx).Error("failed to get webhook subscription", zap.Error(err))
+		return nil, errors.New(ErrGetWebhookMsg)
+	}
+	return sub, nil
+}
+
+// ListWebhookSubscriptions returns the subscriptions owned by clientID, newest first.
+// An empty clientID lists every subscription. Returns ErrListWebhooksMsg on internal failures.
+func (p *PostgressStorage) ListWebhookSubscriptions(ctx context.Context, clientID string) ([]WebhookSubscription, error) {
+	const query = `
+		SELECT ` + webhookSubscriptionColumns + `
+		FROM webhook_subscriptions
+		WHERE $1 = '' OR client_id = $1
+		ORDER BY created_at DESC
+	`
+
+	logger := p.contextLogger(ctx)
+
+	rows, err := p.db.QueryContext(ctx, query, clientID)
+	if err != nil {
+		logger.Error("failed to list webhook subscriptions", zap.Error(err))
+		return nil, errors.New(ErrListWebhooksMsg)
+	}
+	defer rows.Close()
+
+	subs := make([]WebhookSubscription, 0)
+	for rows.Next() {
+		sub, err := scanWebhookSubscription(rows)
+		if err != nil {
+			logger.Error("failed to scan webhook subscription", zap.Error(err))
+			return nil, errors.New(ErrListWebhooksMsg)
+		}
+		subs = append(subs, *sub)
+	}
+	if err := rows.Err(); err != nil {
+		logger.Error("failed to list webhook subscriptions", zap.Error(err))
+		return nil, errors.New(ErrListWebhooksMsg)
+	}
+	return subs, nil
+}
+
+// UpdateWebhookSubscription replaces a subscription's URL, event types and account filter.
+// Returns ErrWebhookNotFound if it doesn't exist or ErrUpdateWebhookMsg on internal failures.
+func (p *PostgressStorage) UpdateWebhookSubscription(ctx context.Context, sub *WebhookSubscription) error {
+	const query = `
+		UPDATE webhook_subscriptions
+		SET url = $2, event_types = $3, account_ids = $4, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING updated_at
+	`
+
+	err := p.db.QueryRowContext(ctx, query, sub.ID, sub.URL, pq.Array(sub.EventTypes), pq.Array(sub.AccountIDs)).Scan(&sub.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New(ErrWebhookNotFound)
+		}
+		p.contextLogger(ctx).Error("failed to update webhook subscription", zap.Error(err))
+		return errors.New(ErrUpdateWebhookMsg)
+	}
+	return nil
+}
+
+// DeleteWebhookSubscription deletes a subscription together with its deliveries.
+// Returns ErrWebhookNotFound if it doesn't exist or ErrDeleteWebhookMsg on internal failures.
+func (p *PostgressStorage) DeleteWebhookSubscription(ctx context.Context, subscriptionID string) error {
+	const query = `
+		DELETE FROM webhook_subscriptions
+		WHERE id = $1
+	`
+
+	logger := p.contextLogger(ctx)
+
+	res, err := p.db.ExecContext(ctx, query, subscriptionID)
+	if err != nil {
+		logger.Error("failed to delete webhook subscription", zap.Error(err))
+		return errors.New(ErrDeleteWebhookMsg)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		logger.Error("failed to delete webhook subscription", zap.Error(err))
+		return errors.New(ErrDeleteWebhookMsg)
+	}
+	if n == 0 {
+		return errors.New(ErrWebhookNotFound)
+	}
+	return nil
+}
+
+// EnqueueWebhookDeliveries queues event for every subscription whose event types and account filter match it,
+// and returns the number of deliveries queued. Subscriptions filtered to some accounts receive the payload
+// without the balances of the event's other accounts (see RedactEventPayload). Enqueuing the same event
+// again is a no-op.
+// Returns ErrEnqueueDeliveriesMsg on internal failures.
+func (p *PostgressStorage) EnqueueWebhookDeliveries(ctx context.Context, event OutboxEvent) (int, error) {
+	const query = `
+		INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (subscription_id, event_id) DO NOTHING
+	`
+
+	logger := p.contextLogger(ctx)
+
+	subs, err := p.matchWebhookSubscriptions(ctx, event)
+	if err != nil {
+		logger.Error("failed to match webhook subscriptions", zap.Error(err))
+		return 0, errors.New(ErrEnqueueDeliveriesMsg)
+	}
+
+	// Each insert is idempotent, so a failure part way is completed when the relay retries the event.
+	queued := 0
+	for _, sub := range subs {
+		payload := event.Payload
+		if len(sub.AccountIDs) > 0 {
+			payload = RedactEventPayload(event.EventType, payload, func(accountID string) bool { return slices.Contains(sub.AccountIDs, accountID) })
+		}
+		res, err := p.db.ExecContext(ctx, query, sub.ID, event.ID, event.EventType, string(payload))
+		if err != nil {
+			logger.Error("failed to enqueue webhook delivery", zap.String("subscription_id", sub.ID), zap.Error(err))
+			return 0, errors.New(ErrEnqueueDeliveriesMsg)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			logger.Error("failed to enqueue webhook delivery", zap.String("subscription_id", sub.ID), zap.Error(err))
+			return 0, errors.New(ErrEnqueueDeliveriesMsg)
+		}
+		queued += int(n)
+	}
+	return queued, nil
+}
+
+// matchWebhookSubscriptions returns the ID and account filter of every subscription event is queued for.
+func (p *PostgressStorage) matchWebhookSubscriptions(ctx context.Context, event OutboxEvent) ([]WebhookSubscription, error) {
+	const query = `
+		SELECT id, account_ids
+		FROM webhook_subscriptions
+		WHERE $1 = ANY(event_types)
+			AND (cardinality(account_ids) = 0 OR account_ids && $2)
+		ORDER BY id
+	`
+
+	rows, err := p.db.QueryContext(ctx, query, event.EventType, pq.Array(event.AccountIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subs []WebhookSubscription
+	for rows.Next() {
+		var sub WebhookSubscription
+		if err := rows.Scan(&sub.ID, pq.Array(&sub.AccountIDs)); err != nil {
+			return nil, err
+		}
+		subs = append(subs, sub)
+	}
+	return subs, rows.Err()
+}
+
+// ClaimWebhookDeliveries returns up to limit pending deliveries that are due, with their subscription's
+// URL and secret. Claimed deliveries are pushed back by lease so concurrent workers skip them; the lease
+// also retries deliveries whose worker died before recording the attempt.
+// Returns ErrClaimDeliveriesMsg on internal failures.
+func (p *PostgressStorage) ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]WebhookDelivery, error) {
+	const query = `
+		WITH due AS (
+			SELECT id
+			FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= CURRENT_TIMESTAMP
+			ORDER BY next_attempt_at, id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE webhook_deliveries d
+		SET next_attempt_at = CURRENT_TIMESTAMP + $2 * INTERVAL '1 millisecond', updated_at = CURRENT_TIMESTAMP
+		FROM due, webhook_subscriptions s
+		WHERE d.id = due.id AND s.id = d.subscription_id
+		RETURNING d.id, d.subscription_id, d.event_id, d.event_type, d.payload, d.status, d.attempts, d.next_attempt_at,
+			d.last_status_code, d.last_error, d.created_at, d.updated_at, s.url, s.secret
+	`
+
+	logger := p.contextLogger(ctx)
+
+	rows, err := p.db.QueryContext(ctx, query, limit, lease.Milliseconds())
+	if err != nil {
+		logger.Error("failed to claim webhook deliveries", zap.Error(err))
+		return nil, errors.New(ErrClaimDeliveriesMsg)
+	}
+	defer rows.Close()
+
+	deliveries := make([]WebhookDelivery, 0)
+	for rows.Next() {
+		d, err := scanWebhookDelivery(rows, true)
+		if err != nil {
+			logger.Error("failed to scan webhook delivery", zap.Error(err))
+			return nil, errors.New(ErrClaimDeliveriesMsg)
+		}
+		deliveries = append(deliveries, *d)
+	}
+	if err := rows.Err(); err != nil {
+		logger.Error("failed to claim webhook deliveries", zap.Error(err))
+		return nil, errors.New(ErrClaimDeliveriesMsg)
+	}
+	return deliveries, nil
+}
+
+// RecordWebhookAttempt logs an attempt and moves the delivery to the resulting state in one DB transaction.
+// Returns ErrRecordAttemptMsg on internal failures.
+func (p *PostgressStorage) RecordWebhookAttempt(ctx context.Context, deliveryID int64, result WebhookAttemptResult) error {
+	const (
+		insertAttemptQuery = `
+			INSERT INTO webhook_delivery_attempts (delivery_id, attempted_at, status_code, error, duration_ms)
+			VALUES ($1, $2, $3, $4, $5)
+		`
+		updateDeliveryQuery = `
+			UPDATE webhook_deliveries
+			SET status = $2, attempts = attempts + 1, next_attempt_at = $3, last_status_code = $4, last_error = $5,
+				updated_at = CURRENT_TIMESTAMP
+			WHERE id = $1
+		`
+	)
+
+	logger := p.contextLogger(ctx)
+	a := result.Attempt
+
+	return p.withTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, insertAttemptQuery, deliveryID, a.AttemptedAt, a.StatusCode, a.Error, a.DurationMS); err != nil {
+			logger.Error("failed to insert webhook attempt", zap.Error(err))
+			return errors.New(ErrRecordAttemptMsg)
+		}
+		if _, err := tx.ExecContext(ctx, updateDeliveryQuery, deliveryID, result.Status, result.NextAttemptAt, a.StatusCode, a.Error); err != nil {
+			logger.Error("failed to update webhook delivery", zap.Error(err))
+			return errors.New(ErrRecordAttemptMsg)
+		}
+		return nil
+	}, ErrRecordAttemptMsg)
+}
+
+// ListWebhookDeliveries returns up to limit deliveries for a subscription, newest first.
+// Returns ErrListDeliveriesMsg on internal failures.
+func (p *PostgressStorage) ListWebhookDeliveries(ctx context.Context, subscriptionID string, limit int) ([]WebhookDelivery, error) {
+	const query = `
+		SELECT ` + webhookDeliveryColumns + `
+		FROM webhook_deliveries
+		WHERE subscription_id = $1
+		ORDER BY id DESC
+		LIMIT $2
+	`
+
+	logger := p.contextLogger(ctx)
+
+	rows, err := p.db.QueryContext(ctx, query, subscriptionID, limit)
+	if err != nil {
+		logger.Error("failed to list webhook deliveries", zap.Error(err))
+		return nil, errors.New(ErrListDeliveriesMsg)
+	}
+	defer rows.Close()
+
+	deliveries := make([]WebhookDelivery, 0)
+	for rows.Next() {
+		d, err := scanWebhookDelivery(rows, false)
+		if err != nil {
+			logger.Error("failed to scan webhook delivery", zap.Error(err))
+			return nil, errors.New(ErrListDeliveriesMsg)
+		}
+		deliveries = append(deliveries, *d)
+	}
+	if err := rows.Err(); err != nil {
+		logger.Error("failed to list webhook deliveries", zap.Error(err))
+		return nil, errors.New(ErrListDeliveriesMsg)
+	}
+	return deliveries, nil
+}
+
+// GetWebhookDelivery fetches a delivery by ID.
+// Returns ErrDeliveryNotFound if it doesn't exist or ErrListDeliveriesMsg on internal failures.
+func (p *PostgressStorage) GetWebhookDelivery(ctx context.Context, deliveryID int64) (*WebhookDelivery, error) {
+	const query = `
+		SELECT ` + webhookDeliveryColumns + `
+		FROM webhook_deliveries
+		WHERE id = $1
+	`
+
+	d, err := scanWebhookDelivery(p.db.QueryRowContext(ctx, query, deliveryID), false)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New(ErrDeliveryNotFound)
+		}
+		p.contextLogger(ctx).Error("failed to get webhook delivery", zap.Error(err))
+		return nil, errors.New(ErrListDeliveriesMsg)
+	}
+	return d, nil
+}
+
+// ListWebhookAttempts returns every attempt for a delivery, oldest first.
+// Returns ErrListAttemptsMsg on internal failures.
+func (p *PostgressStorage) ListWebhookAttempts(ctx context.Context, deliveryID int64) ([]WebhookAttempt, error) {
+	const query = `
+		SELECT id, delivery_id, attempted_at, status_code, error, duration_ms
+		FROM webhook_delivery_attempts
+		WHERE delivery_id = $1
+		ORDER BY id
+	`
+
+	logger := p.contextLogger(ctx)
+
+	rows, err := p.db.QueryContext(ctx, query, deliveryID)
+	if err != nil {
+		logger.Error("failed to list webhook attempts", zap.Error(err))
+		return nil, errors.New(ErrListAttemptsMsg)
+	}
+	defer rows.Close()
+
+	attempts := make([]WebhookAttempt, 0)
+	for rows.Next() {
+		var (
+			a          WebhookAttempt
+			statusCode sql.NullInt64
+			errMsg     sql.NullString
+		)
+		if err := rows.Scan(&a.ID, &a.DeliveryID, &a.AttemptedAt, &statusCode, &errMsg, &a.DurationMS); err != nil {
+			logger.Error("failed to scan webhook attempt", zap.Error(err))
+			return nil, errors.New(ErrListAttemptsMsg)
+		}
+		a.StatusCode = nullIntPtr(statusCode)
+		a.Error = nullStringPtr(errMsg)
+		attempts = append(attempts, a)
+	}
+	if err := rows.Err(); err != nil {
+		logger.Error("failed to list webhook attempts", zap.Error(err))
+		return nil, errors.New(ErrListAttemptsMsg)
+	}
+	return attempts, nil
+}
+
+// scanWebhookSubscription scans a single webhook_subscriptions row.
+func scanWebhookSubscription(row rowScanner) (*WebhookSubscription, error) {
+	var sub WebhookSubscription
+	if err := row.Scan(&sub.ID, &sub.ClientID, &sub.URL, &sub.Secret, pq.Array(&sub.EventTypes), pq.Array(&sub.AccountIDs),
+		&sub.CreatedAt, &sub.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if sub.AccountIDs == nil {
+		sub.AccountIDs = []string{}
+	}
+	return &sub, nil
+}
+
+// scanWebhookDelivery scans a single webhook_deliveries row, followed by the subscription URL and secret
+// when withTarget is set.
+func scanWebhookDelivery(row rowScanner, withTarget bool) (*WebhookDelivery, error) {
+	var (
+		d          WebhookDelivery
+		payload    string
+		statusCode sql.NullInt64
+		lastError  sql.NullString
+	)
+	dest := []any{&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &payload, &d.Status, &d.Attempts, &d.NextAttemptAt,
+		&statusCode, &lastError, &d.CreatedAt, &d.UpdatedAt}
+	if withTarget {
+		dest = append(dest, &d.URL, &d.Secret)
+	}
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	d.Payload = json.RawMessage(payload)
+	d.LastStatusCode = nullIntPtr(statusCode)
+	d.LastError = nullStringPtr(lastError)
+	return &d, nil
+}
+
+// nullIntPtr converts a SQL NULL to nil.
+func nullIntPtr(v sql.NullInt64) *int {
+	if !v.Valid {
+		return nil
+	}
+	n := int(v.Int64)
+	return &n
+}
+
+// nullStringPtr converts a SQL NULL to nil.
+func nullStringPtr(v sql.NullString) *string {
+	if !v.Valid {
+		return nil
+	}
+	return &v.String
+}
@@ -0,0 +1,359 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	webhookTime           = time.Date(2025, 12, 1, 12, 0, 0, 0, time.UTC)
+	subscriptionColumns   = []string{"id", "client_id", "url", "secret", "event_types", "account_ids", "created_at", "updated_at"}
+	deliveryColumns       = []string{"id", "subscription_id", "event_id", "event_type", "payload", "status", "attempts", "next_attempt_at", "last_status_code", "last_error", "created_at", "updated_at"}
+	claimedDeliveryColumn = append(append([]string{}, deliveryColumns...), "url", "secret")
+)
+
+// TestGetWebhookSubscription validates fetching a subscription, missing subscriptions and query failures.
+func TestGetWebhookSubscription(t *testing.T) {
+	tests := []struct {
+		name        string
+		prepare     func(sqlmock.Sqlmock)
+		expected    *WebhookSubscription
+		expectedErr string
+	}{
+		{
+			name: "success",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(`SELECT id, client_id, url, secret, event_types, account_ids, created_at, updated_at FROM webhook_subscriptions`).
+					WithArgs("sub-1").
+					WillReturnRows(sqlmock.NewRows(subscriptionColumns).
+						AddRow("sub-1", "payroll", "https://example.com/hook", "whsec", "{transfer.completed}", "{}", webhookTime, webhookTime))
+			},
+			expected: &WebhookSubscription{
+				ID:         "sub-1",
+				ClientID:   "payroll",
+				URL:        "https://example.com/hook",
+				Secret:     "whsec",
+				EventTypes: []string{EventTransferCompleted},
+				AccountIDs: []string{},
+				CreatedAt:  webhookTime,
+				UpdatedAt:  webhookTime,
+			},
+		},
+		{
+			name: "not found",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(`SELECT .* FROM webhook_subscriptions`).WithArgs("sub-1").WillReturnRows(sqlmock.NewRows(subscriptionColumns))
+			},
+			expectedErr: ErrWebhookNotFound,
+		},
+		{
+			name: "query error",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(`SELECT .* FROM webhook_subscriptions`).WithArgs("sub-1").WillReturnError(errors.New("db down"))
+			},
+			expectedErr: ErrGetWebhookMsg,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			store, mock, cleanup := newTestStorage(t)
+			defer cleanup()
+
+			tc.prepare(mock)
+
+			sub, err := store.GetWebhookSubscription(context.Background(), "sub-1")
+
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, sub)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestModifyWebhookSubscription validates create, update and delete, including missing subscriptions.
+func TestModifyWebhookSubscription(t *testing.T) {
+	sub := func() *WebhookSubscription {
+		return &WebhookSubscription{
+			ID:         "sub-1",
+			ClientID:   "payroll",
+			URL:        "https://example.com/hook",
+			Secret:     "whsec",
+			EventTypes: []string{EventTransferCompleted},
+			AccountIDs: []string{"acc-1"},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		prepare     func(sqlmock.Sqlmock)
+		call        func(*PostgressStorage) error
+		expectedErr string
+	}{
+		{
+			name: "create",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(`INSERT INTO webhook_subscriptions`).
+					WithArgs("sub-1", "payroll", "https://example.com/hook", "whsec", sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at"}).AddRow(webhookTime, webhookTime))
+			},
+			call: func(s *PostgressStorage) error { return s.CreateWebhookSubscription(context.Background(), sub()) },
+		},
+		{
+			name: "create error",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(`INSERT INTO webhook_subscriptions`).WillReturnError(errors.New("db down"))
+			},
+			call:        func(s *PostgressStorage) error { return s.CreateWebhookSubscription(context.Background(), sub()) },
+			expectedErr: ErrCreateWebhookMsg,
+		},
+		{
+			name: "update",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(`UPDATE webhook_subscriptions`).
+					WithArgs("sub-1", "https://example.com/hook", sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"updated_at"}).AddRow(webhookTime))
+			},
+			call: func(s *PostgressStorage) error { return s.UpdateWebhookSubscription(context.Background(), sub()) },
+		},
+		{
+			name: "update not found",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(`UPDATE webhook_subscriptions`).WillReturnRows(sqlmock.NewRows([]string{"updated_at"}))
+			},
+			call:        func(s *PostgressStorage) error { return s.UpdateWebhookSubscription(context.Background(), sub()) },
+			expectedErr: ErrWebhookNotFound,
+		},
+		{
+			name: "delete",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectExec(`DELETE FROM webhook_subscriptions`).WithArgs("sub-1").WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			call: func(s *PostgressStorage) error { return s.DeleteWebhookSubscription(context.Background(), "sub-1") },
+		},
+		{
+			name: "delete not found",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectExec(`DELETE FROM webhook_subscriptions`).WithArgs("sub-1").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			call:        func(s *PostgressStorage) error { return s.DeleteWebhookSubscription(context.Background(), "sub-1") },
+			expectedErr: ErrWebhookNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			store, mock, cleanup := newTestStorage(t)
+			defer cleanup()
+
+			tc.prepare(mock)
+
+			err := tc.call(store)
+
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestEnqueueWebhookDeliveries validates that matching subscriptions are queued, that subscriptions filtered
+// to some accounts do not receive the others' balances, and that failures are reported.
+func TestEnqueueWebhookDeliveries(t *testing.T) {
+	payload := `{"amount":"1","destination_account_id":"b","destination_balance":"11","destination_version":4,"source_account_id":"a","source_balance":"9","source_version":3}`
+	event := OutboxEvent{ID: 9, EventType: EventTransferCompleted, AccountIDs: []string{"a", "b"}, Payload: json.RawMessage(payload)}
+	matchColumns := []string{"id", "account_ids"}
+
+	tests := []struct {
+		name          string
+		prepare       func(sqlmock.Sqlmock)
+		expectedCount int
+		expectedErr   string
+	}{
+		{
+			name: "success",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(`SELECT id, account_ids FROM webhook_subscriptions`).
+					WithArgs(EventTransferCompleted, sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows(matchColumns).AddRow("sub-all", "{}").AddRow("sub-b", "{b,c}"))
+				m.ExpectExec(`INSERT INTO webhook_deliveries`).
+					WithArgs("sub-all", int64(9), EventTransferCompleted, payload).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				m.ExpectExec(`INSERT INTO webhook_deliveries`).
+					WithArgs("sub-b", int64(9), EventTransferCompleted, `{"amount":"1","destination_account_id":"b","destination_balance":"11","destination_version":4,"source_account_id":"a"}`).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			expectedCount: 2,
+		},
+		{
+			name: "already queued",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(`SELECT id, account_ids FROM webhook_subscriptions`).
+					WillReturnRows(sqlmock.NewRows(matchColumns).AddRow("sub-all", "{}"))
+				m.ExpectExec(`INSERT INTO webhook_deliveries`).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedCount: 0,
+		},
+		{
+			name: "match error",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(`SELECT id, account_ids FROM webhook_subscriptions`).WillReturnError(errors.New("db down"))
+			},
+			expectedErr: ErrEnqueueDeliveriesMsg,
+		},
+		{
+			name: "insert error",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(`SELECT id, account_ids FROM webhook_subscriptions`).
+					WillReturnRows(sqlmock.NewRows(matchColumns).AddRow("sub-all", "{}"))
+				m.ExpectExec(`INSERT INTO webhook_deliveries`).WillReturnError(errors.New("db down"))
+			},
+			expectedErr: ErrEnqueueDeliveriesMsg,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			store, mock, cleanup := newTestStorage(t)
+			defer cleanup()
+
+			tc.prepare(mock)
+
+			n, err := store.EnqueueWebhookDeliveries(context.Background(), event)
+
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedCount, n)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestClaimWebhookDeliveries validates that claimed deliveries carry their subscription's URL and secret.
+func TestClaimWebhookDeliveries(t *testing.T) {
+	store, mock, cleanup := newTestStorage(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`WITH due AS`).
+		WithArgs(10, int64(30000)).
+		WillReturnRows(sqlmock.NewRows(claimedDeliveryColumn).
+			AddRow(1, "sub-1", 9, EventTransferCompleted, `{"amount":"1"}`, DeliveryPending, 2, webhookTime, 503, "webhook responded with status 503", webhookTime, webhookTime, "https://example.com/hook", "whsec"))
+
+	deliveries, err := store.ClaimWebhookDeliveries(context.Background(), 10, 30*time.Second)
+
+	assert.NoError(t, err)
+	statusCode, lastError := 503, "webhook responded with status 503"
+	assert.Equal(t, []WebhookDelivery{{
+		ID:             1,
+		SubscriptionID: "sub-1",
+		EventID:        9,
+		EventType:      EventTransferCompleted,
+		Payload:        json.RawMessage(`{"amount":"1"}`),
+		Status:         DeliveryPending,
+		Attempts:       2,
+		NextAttemptAt:  webhookTime,
+		LastStatusCode: &statusCode,
+		LastError:      &lastError,
+		CreatedAt:      webhookTime,
+		UpdatedAt:      webhookTime,
+		URL:            "https://example.com/hook",
+		Secret:         "whsec",
+	}}, deliveries)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestRecordWebhookAttempt validates that the attempt log and delivery state are written in one transaction.
+func TestRecordWebhookAttempt(t *testing.T) {
+	statusCode := 500
+	result := WebhookAttemptResult{
+		Attempt:       WebhookAttempt{AttemptedAt: webhookTime, StatusCode: &statusCode, DurationMS: 12},
+		Status:        DeliveryPending,
+		NextAttemptAt: webhookTime.Add(time.Minute),
+	}
+
+	tests := []struct {
+		name        string
+		prepare     func(sqlmock.Sqlmock)
+		expectedErr string
+	}{
+		{
+			name: "success",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectExec(`INSERT INTO webhook_delivery_attempts`).
+					WithArgs(int64(1), webhookTime, &statusCode, (*string)(nil), int64(12)).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				m.ExpectExec(`UPDATE webhook_deliveries`).
+					WithArgs(int64(1), DeliveryPending, webhookTime.Add(time.Minute), &statusCode, (*string)(nil)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				m.ExpectCommit()
+			},
+		},
+		{
+			name: "update error",
+			prepare: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectExec(`INSERT INTO webhook_delivery_attempts`).WillReturnResult(sqlmock.NewResult(1, 1))
+				m.ExpectExec(`UPDATE webhook_deliveries`).WillReturnError(errors.New("db down"))
+				m.ExpectRollback()
+			},
+			expectedErr: ErrRecordAttemptMsg,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			store, mock, cleanup := newTestStorage(t)
+			defer cleanup()
+
+			tc.prepare(mock)
+
+			err := store.RecordWebhookAttempt(context.Background(), 1, result)
+
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestListWebhookAttempts validates reading the attempt log, including NULL status codes and errors.
+func TestListWebhookAttempts(t *testing.T) {
+	store, mock, cleanup := newTestStorage(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`SELECT id, delivery_id, attempted_at, status_code, error, duration_ms FROM webhook_delivery_attempts`).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "delivery_id", "attempted_at", "status_code", "error", "duration_ms"}).
+			AddRow(1, 1, webhookTime, nil, "connection refused", 3).
+			AddRow(2, 1, webhookTime, 200, nil, 15))
+
+	attempts, err := store.ListWebhookAttempts(context.Background(), 1)
+
+	assert.NoError(t, err)
+	errMsg, statusCode := "connection refused", 200
+	assert.Equal(t, []WebhookAttempt{
+		{ID: 1, DeliveryID: 1, AttemptedAt: webhookTime, Error: &errMsg, DurationMS: 3},
+		{ID: 2, DeliveryID: 1, AttemptedAt: webhookTime, StatusCode: &statusCode, DurationMS: 15},
+	}, attempts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
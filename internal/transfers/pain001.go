@@ -0,0 +1,119 @@
+package transfers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/payments"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+	"github.com/cursed-ninja/internal-transfers-system/internal/utils"
+	"go.uber.org/zap"
+)
+
+// ErrFutureExecutionDate refuses the transfers of a payment dated after the day it is executed.
+var ErrFutureExecutionDate = errors.New("requested execution date is in the future")
+
+// ExecutePain001 makes the credit transfers of a pain.001 message as one batch identified by the
+// message ID. Each transfer is checked with Validate and refused if its payment is dated in the future
+// or, when canDebit is not nil, if canDebit refuses its debtor account; the others are made in order,
+// and transfers the ledger refuses are reported without affecting the rest.
+// The returned report gives the outcome of every transfer. Errors are those of ProcessTransferBatch.
+func ExecutePain001(ctx context.Context, store storage.Storage, msg *payments.CreditTransferInitiation, canDebit func(accountID string) bool, now time.Time) (*payments.StatusReport, error) {
+	logger := utils.ContextLogger(ctx)
+	today := now.UTC().Truncate(24 * time.Hour)
+
+	report := &payments.StatusReport{Original: msg, CreatedAt: now}
+	var (
+		transfers []storage.Transfer
+		// pending are the statuses of transfers, in the order they are sent to storage.
+		pending []*payments.TransferStatus
+	)
+	for i := range msg.Payments {
+		pmt := &msg.Payments[i]
+		status := payments.PaymentStatus{Payment: pmt, Transfers: make([]payments.TransferStatus, len(pmt.Transfers))}
+		for j := range pmt.Transfers {
+			ct := &pmt.Transfers[j]
+			ts := &status.Transfers[j]
+			ts.Transfer = ct
+
+			if pmt.RequestedExecutionDate.After(today) {
+				rejectTransfer(ts, ErrFutureExecutionDate)
+				continue
+			}
+			amount, err := Validate(pmt.DebtorAccountID, ct.CreditorAccountID, ct.Amount.String())
+			if err != nil {
+				rejectTransfer(ts, err)
+				continue
+			}
+			if canDebit != nil && !canDebit(pmt.DebtorAccountID) {
+				rejectTransfer(ts, ErrAccountForbidden)
+				continue
+			}
+
+			transfers = append(transfers, storage.Transfer{
+				SourceAccountID:      pmt.DebtorAccountID,
+				DestinationAccountID: ct.CreditorAccountID,
+				Amount:               amount,
+				Currency:             ct.Currency,
+			})
+			pending = append(pending, ts)
+		}
+		report.Payments = append(report.Payments, status)
+	}
+
+	if len(transfers) > 0 {
+		results, err := store.ProcessTransferBatch(ctx, msg.MessageID, transfers)
+		if err != nil {
+			return nil, err
+		}
+		for k, res := range results {
+			if res.Err != nil {
+				rejectTransfer(pending[k], res.Err)
+				continue
+			}
+			pending[k].Status = payments.StatusSettled
+			pending[k].TransactionID = res.TransactionID
+		}
+	}
+
+	logger.Info("executed pain.001 message", zap.Int("transfers", msg.NumberOfTransactions), zap.String("status", report.Status()))
+	return report, nil
+}
+
+// rejectTransfer marks a transfer as refused with the reason code matching err.
+func rejectTransfer(ts *payments.TransferStatus, err error) {
+	ts.Status = payments.StatusRejected
+	ts.Reason = transferReason(err)
+	ts.Info = err.Error()
+}
+
+// transferReason maps the error a transfer was refused with to an ISO 20022 status reason code.
+func transferReason(err error) string {
+	switch {
+	case errors.Is(err, ErrMissingSourceAccountID), errors.Is(err, ErrMissingDestAccountID), errors.Is(err, ErrSameAccountTransfer):
+		return payments.ReasonIncorrectAccount
+	case errors.Is(err, ErrMissingAmount), errors.Is(err, ErrInvalidAmount), errors.Is(err, ErrNonPositiveAmount):
+		return payments.ReasonInvalidAmount
+	case errors.Is(err, ErrAccountForbidden):
+		return payments.ReasonTransactionForbidden
+	case errors.Is(err, ErrFutureExecutionDate):
+		return payments.ReasonInvalidDate
+	}
+
+	switch err.Error() {
+	case storage.ErrSourceAccountMsg:
+		return payments.ReasonIncorrectAccount
+	case storage.ErrDestinationAccountMsg:
+		return payments.ReasonInvalidCreditorAccount
+	case storage.ErrSourceClosedMsg, storage.ErrDestinationClosedMsg:
+		return payments.ReasonClosedAccount
+	case storage.ErrInsufficientFundsMsg:
+		return payments.ReasonInsufficientFunds
+	case storage.ErrAmountPrecisionMsg:
+		return payments.ReasonInvalidAmount
+	case storage.ErrCurrencyMismatchMsg, storage.ErrTransferCurrencyMsg:
+		return payments.ReasonCurrencyNotAllowed
+	}
+	return payments.ReasonNarrative
+}
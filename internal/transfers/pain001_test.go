@@ -0,0 +1,37 @@
+package transfers
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/payments"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTransferReason validates the reason codes transfers are refused with.
+func TestTransferReason(t *testing.T) {
+	tests := []struct {
+		err            error
+		expectedReason string
+	}{
+		{err: ErrMissingDestAccountID, expectedReason: payments.ReasonIncorrectAccount},
+		{err: ErrNonPositiveAmount, expectedReason: payments.ReasonInvalidAmount},
+		{err: ErrAccountForbidden, expectedReason: payments.ReasonTransactionForbidden},
+		{err: ErrFutureExecutionDate, expectedReason: payments.ReasonInvalidDate},
+		{err: errors.New(storage.ErrSourceAccountMsg), expectedReason: payments.ReasonIncorrectAccount},
+		{err: errors.New(storage.ErrDestinationAccountMsg), expectedReason: payments.ReasonInvalidCreditorAccount},
+		{err: errors.New(storage.ErrDestinationClosedMsg), expectedReason: payments.ReasonClosedAccount},
+		{err: errors.New(storage.ErrInsufficientFundsMsg), expectedReason: payments.ReasonInsufficientFunds},
+		{err: errors.New(storage.ErrAmountPrecisionMsg), expectedReason: payments.ReasonInvalidAmount},
+		{err: errors.New(storage.ErrTransferCurrencyMsg), expectedReason: payments.ReasonCurrencyNotAllowed},
+		{err: ErrSameAccountTransfer, expectedReason: payments.ReasonIncorrectAccount},
+		{err: errors.New(storage.ErrProcessBatchMsg), expectedReason: payments.ReasonNarrative},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.err.Error(), func(t *testing.T) {
+			assert.Equal(t, tc.expectedReason, transferReason(tc.err))
+		})
+	}
+}
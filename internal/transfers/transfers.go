@@ -0,0 +1,56 @@
+// Package transfers validates transfer requests and executes the transfers of payment messages
+// against storage, for the HTTP and gRPC servers and for transfersctl alike.
+package transfers
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Validation errors of transfers.
+var (
+	ErrMissingSourceAccountID = errors.New("source_account_id is required")
+	ErrMissingDestAccountID   = errors.New("destination_account_id is required")
+	ErrMissingAmount          = errors.New("amount is required")
+	ErrInvalidAmount          = errors.New("amount must be a valid decimal number")
+	ErrNonPositiveAmount      = errors.New("amount must be positive")
+	ErrSameAccountTransfer    = errors.New("source_account_id and destination_account_id cannot be the same")
+	ErrAccountForbidden       = errors.New("access to account is forbidden")
+)
+
+// Validate checks a transfer of amount from sourceAccountID to destAccountID for required fields,
+// parses the amount and ensures it is positive. Whitespace around each field is ignored.
+func Validate(sourceAccountID, destAccountID, amount string) (decimal.Decimal, error) {
+	sourceAccountID = strings.TrimSpace(sourceAccountID)
+	destAccountID = strings.TrimSpace(destAccountID)
+	amount = strings.TrimSpace(amount)
+
+	if sourceAccountID == "" {
+		return decimal.Zero, ErrMissingSourceAccountID
+	}
+
+	if destAccountID == "" {
+		return decimal.Zero, ErrMissingDestAccountID
+	}
+
+	if amount == "" {
+		return decimal.Zero, ErrMissingAmount
+	}
+
+	amt, err := decimal.NewFromString(amount)
+	if err != nil {
+		return decimal.Zero, ErrInvalidAmount
+	}
+
+	if !amt.IsPositive() {
+		return decimal.Zero, ErrNonPositiveAmount
+	}
+
+	if sourceAccountID == destAccountID {
+		return decimal.Zero, ErrSameAccountTransfer
+	}
+
+	return amt, nil
+}
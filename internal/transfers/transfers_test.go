@@ -0,0 +1,36 @@
+package transfers
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestValidate validates the required fields and amount checks of transfers.
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name           string
+		source         string
+		dest           string
+		amount         string
+		expectedAmount decimal.Decimal
+		expectedErr    error
+	}{
+		{name: "valid", source: " acc-1 ", dest: "acc-2", amount: " 12.50 ", expectedAmount: decimal.RequireFromString("12.5")},
+		{name: "missing source", source: " ", dest: "acc-2", amount: "1", expectedAmount: decimal.Zero, expectedErr: ErrMissingSourceAccountID},
+		{name: "missing destination", source: "acc-1", amount: "1", expectedAmount: decimal.Zero, expectedErr: ErrMissingDestAccountID},
+		{name: "missing amount", source: "acc-1", dest: "acc-2", expectedAmount: decimal.Zero, expectedErr: ErrMissingAmount},
+		{name: "invalid amount", source: "acc-1", dest: "acc-2", amount: "ten", expectedAmount: decimal.Zero, expectedErr: ErrInvalidAmount},
+		{name: "zero amount", source: "acc-1", dest: "acc-2", amount: "0", expectedAmount: decimal.Zero, expectedErr: ErrNonPositiveAmount},
+		{name: "same account", source: "acc-1", dest: " acc-1", amount: "1", expectedAmount: decimal.Zero, expectedErr: ErrSameAccountTransfer},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			amount, err := Validate(tc.source, tc.dest, tc.amount)
+			assert.Equal(t, tc.expectedErr, err)
+			assert.True(t, tc.expectedAmount.Equal(amount), "amount %s", amount)
+		})
+	}
+}
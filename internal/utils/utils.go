@@ -2,26 +2,39 @@ package utils
 
 import (
 	"context"
+	"fmt"
 	"log"
 
 	"github.com/cursed-ninja/internal-transfers-system/internal/config"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 type contextKey string
 
-const LoggerContextKey contextKey = "requestLogger"
+const (
+	LoggerContextKey    contextKey = "requestLogger"
+	RequestIDContextKey contextKey = "requestID"
+	ClientIDContextKey  contextKey = "clientID"
+)
 
-// ContextLogger returns the logger stored in context, or a default logger if none exists.
+// ContextLogger returns the logger stored in context, or the global root logger if none exists.
 func ContextLogger(ctx context.Context) *zap.Logger {
-	logger, ok := ctx.Value(LoggerContextKey).(*zap.Logger)
-	if !ok || logger == nil {
-		return GetLogger(config.AppEnvLocal)
+	logger, ok := LoggerFromContext(ctx)
+	if !ok {
+		return zap.L()
 	}
 	return logger
 }
 
+// LoggerFromContext returns the logger stored in context and whether one was present.
+func LoggerFromContext(ctx context.Context) (*zap.Logger, bool) {
+	logger, ok := ctx.Value(LoggerContextKey).(*zap.Logger)
+	return logger, ok && logger != nil
+}
+
 // GetLogger initializes and returns a zap.Logger based on the application environment.
+// It is intended for bootstrapping before configuration is loaded; use NewLogger afterwards.
 func GetLogger(appEnv config.AppEnv) *zap.Logger {
 	logger, err := zap.NewProduction()
 
@@ -36,6 +49,48 @@ func GetLogger(appEnv config.AppEnv) *zap.Logger {
 	return logger
 }
 
+// NewLogger builds the root application logger from configuration.
+// The returned AtomicLevel controls the logger's level and can be changed at runtime.
+func NewLogger(cfg *config.LoggerConfig) (*zap.Logger, zap.AtomicLevel, error) {
+	level := zap.NewAtomicLevel()
+	if cfg.Level != "" {
+		if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+			return nil, level, fmt.Errorf("invalid logger level %q: %w", cfg.Level, err)
+		}
+	}
+
+	zapCfg := zap.NewProductionConfig()
+	zapCfg.Level = level
+	zapCfg.Sampling = nil
+	if cfg.SamplingInitial > 0 {
+		zapCfg.Sampling = &zap.SamplingConfig{
+			Initial:    cfg.SamplingInitial,
+			Thereafter: cfg.SamplingThereafter,
+		}
+	}
+
+	switch cfg.Encoding {
+	case "", "json":
+		zapCfg.Encoding = "json"
+	case "console":
+		zapCfg.Encoding = "console"
+		zapCfg.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+		zapCfg.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	default:
+		return nil, level, fmt.Errorf("invalid logger encoding %q", cfg.Encoding)
+	}
+
+	if len(cfg.OutputPaths) > 0 {
+		zapCfg.OutputPaths = cfg.OutputPaths
+	}
+
+	logger, err := zapCfg.Build()
+	if err != nil {
+		return nil, level, err
+	}
+	return logger, level, nil
+}
+
 // LoggerWithKey adds a field to the logger in the context and returns the updated context and logger.
 func LoggerWithKey(ctx context.Context, field zap.Field) (context.Context, *zap.Logger) {
 	logger := ContextLogger(ctx)
@@ -43,3 +98,25 @@ func LoggerWithKey(ctx context.Context, field zap.Field) (context.Context, *zap.
 	ctx = context.WithValue(ctx, LoggerContextKey, logger)
 	return ctx, logger
 }
+
+// WithRequestID stores the request ID in the context.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, RequestIDContextKey, requestID)
+}
+
+// RequestID returns the request ID stored in context, or an empty string if none exists.
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(RequestIDContextKey).(string)
+	return requestID
+}
+
+// WithClientID stores the authenticated client ID in the context.
+func WithClientID(ctx context.Context, clientID string) context.Context {
+	return context.WithValue(ctx, ClientIDContextKey, clientID)
+}
+
+// ClientID returns the authenticated client ID stored in context, or an empty string if none exists.
+func ClientID(ctx context.Context) string {
+	clientID, _ := ctx.Value(ClientIDContextKey).(string)
+	return clientID
+}
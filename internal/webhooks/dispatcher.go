@@ -0,0 +1,24 @@
+package webhooks
+
+import (
+	"context"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+)
+
+// Dispatcher fans outbox events out to matching webhook subscriptions by queuing a delivery per
+// subscription. It implements outbox.Publisher, so events reach subscribers at least once.
+type Dispatcher struct {
+	store Store
+}
+
+// NewDispatcher creates a Dispatcher queuing deliveries in store.
+func NewDispatcher(store Store) *Dispatcher {
+	return &Dispatcher{store: store}
+}
+
+// Publish queues event for every matching subscription. Queuing the same event twice is a no-op.
+func (d *Dispatcher) Publish(ctx context.Context, event storage.OutboxEvent) error {
+	_, err := d.store.EnqueueWebhookDeliveries(ctx, event)
+	return err
+}
@@ -0,0 +1,37 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestDispatcherPublish validates that events are queued for subscribers and queue failures are returned.
+func TestDispatcherPublish(t *testing.T) {
+	event := storage.OutboxEvent{ID: 1, EventType: storage.EventTransferCompleted, AccountIDs: []string{"a", "b"}}
+
+	tests := []struct {
+		name    string
+		mockErr error
+	}{
+		{name: "queued"},
+		{name: "store error", mockErr: errors.New(storage.ErrEnqueueDeliveriesMsg)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockStorage := mocks.NewMockStorage(mockCtrl)
+			mockStorage.EXPECT().EnqueueWebhookDeliveries(gomock.Any(), event).Return(1, tc.mockErr)
+
+			err := NewDispatcher(mockStorage).Publish(context.Background(), event)
+
+			assert.Equal(t, tc.mockErr, err)
+		})
+	}
+}
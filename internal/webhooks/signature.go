@@ -0,0 +1,72 @@
+// Package webhooks delivers account events to client-registered webhook subscriptions.
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignatureHeader carries the delivery signature as "t=<unix seconds>,v1=<hex HMAC-SHA256>".
+// The HMAC is computed with the subscription secret over "<t>.<raw body>".
+const SignatureHeader = "X-Webhook-Signature"
+
+// Signature verification errors.
+var (
+	ErrMalformedSignature = errors.New("malformed webhook signature")
+	ErrSignatureMismatch  = errors.New("webhook signature does not match")
+	ErrSignatureExpired   = errors.New("webhook signature timestamp outside tolerance")
+)
+
+// Sign returns the SignatureHeader value for body sent at ts.
+func Sign(secret string, ts time.Time, body []byte) string {
+	t := strconv.FormatInt(ts.Unix(), 10)
+	return "t=" + t + ",v1=" + computeSignature(secret, t, body)
+}
+
+// Verify checks a SignatureHeader value against body. Receivers should reject deliveries whose
+// timestamp is more than tolerance away from now to limit replays.
+func Verify(secret, header string, body []byte, now time.Time, tolerance time.Duration) error {
+	var t, sig string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return ErrMalformedSignature
+		}
+		switch key {
+		case "t":
+			t = value
+		case "v1":
+			sig = value
+		}
+	}
+	if t == "" || sig == "" {
+		return ErrMalformedSignature
+	}
+
+	unix, err := strconv.ParseInt(t, 10, 64)
+	if err != nil {
+		return ErrMalformedSignature
+	}
+	if skew := now.Sub(time.Unix(unix, 0)); skew > tolerance || skew < -tolerance {
+		return ErrSignatureExpired
+	}
+
+	if !hmac.Equal([]byte(sig), []byte(computeSignature(secret, t, body))) {
+		return ErrSignatureMismatch
+	}
+	return nil
+}
+
+// computeSignature returns the hex HMAC-SHA256 of "<t>.<body>".
+func computeSignature(secret, t string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(t))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
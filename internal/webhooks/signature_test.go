@@ -0,0 +1,38 @@
+package webhooks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestVerify validates signature verification for valid, tampered, stale and malformed signatures.
+func TestVerify(t *testing.T) {
+	now := time.Unix(1764600000, 0)
+	body := []byte(`{"event_id":1}`)
+	valid := Sign("whsec", now, body)
+
+	tests := []struct {
+		name        string
+		secret      string
+		header      string
+		body        []byte
+		now         time.Time
+		expectedErr error
+	}{
+		{name: "valid", secret: "whsec", header: valid, body: body, now: now},
+		{name: "wrong secret", secret: "other", header: valid, body: body, now: now, expectedErr: ErrSignatureMismatch},
+		{name: "tampered body", secret: "whsec", header: valid, body: []byte(`{"event_id":2}`), now: now, expectedErr: ErrSignatureMismatch},
+		{name: "stale", secret: "whsec", header: valid, body: body, now: now.Add(10 * time.Minute), expectedErr: ErrSignatureExpired},
+		{name: "missing v1", secret: "whsec", header: "t=1764600000", body: body, now: now, expectedErr: ErrMalformedSignature},
+		{name: "garbage", secret: "whsec", header: "nonsense", body: body, now: now, expectedErr: ErrMalformedSignature},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Verify(tc.secret, tc.header, tc.body, tc.now, 5*time.Minute)
+			assert.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/config"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultPollInterval = time.Second
+	defaultBatchSize    = 50
+	defaultTimeout      = 10 * time.Second
+	defaultMaxAttempts  = 8
+	defaultBackoffBase  = 30 * time.Second
+	defaultBackoffMax   = time.Hour
+)
+
+// Store is the subset of storage.Storage used for webhook delivery.
+type Store interface {
+	EnqueueWebhookDeliveries(ctx context.Context, event storage.OutboxEvent) (int, error)
+	ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]storage.WebhookDelivery, error)
+	RecordWebhookAttempt(ctx context.Context, deliveryID int64, result storage.WebhookAttemptResult) error
+}
+
+// payload is the JSON body POSTed to subscribers.
+type payload struct {
+	EventID   int64           `json:"event_id"`
+	EventType string          `json:"event_type"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Worker sends due webhook deliveries. Failed deliveries are retried with exponential backoff and
+// dead-lettered after the configured number of attempts. Every attempt is recorded.
+type Worker struct {
+	store       Store
+	client      *http.Client
+	interval    time.Duration
+	batchSize   int
+	maxAttempts int
+	backoffBase time.Duration
+	backoffMax  time.Duration
+	lease       time.Duration
+	logger      *zap.Logger
+	now         func() time.Time
+}
+
+// NewWorker creates a Worker delivering deliveries claimed from store.
+func NewWorker(store Store, cfg *config.WebhooksConfig, logger *zap.Logger) *Worker {
+	w := &Worker{
+		store:       store,
+		interval:    cfg.PollInterval,
+		batchSize:   cfg.BatchSize,
+		maxAttempts: cfg.MaxAttempts,
+		backoffBase: cfg.BackoffBase,
+		backoffMax:  cfg.BackoffMax,
+		logger:      logger,
+		now:         time.Now,
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	if w.interval <= 0 {
+		w.interval = defaultPollInterval
+	}
+	if w.batchSize <= 0 {
+		w.batchSize = defaultBatchSize
+	}
+	if w.maxAttempts <= 0 {
+		w.maxAttempts = defaultMaxAttempts
+	}
+	if w.backoffBase <= 0 {
+		w.backoffBase = defaultBackoffBase
+	}
+	if w.backoffMax <= 0 {
+		w.backoffMax = defaultBackoffMax
+	}
+	w.client = newClient(timeout, cfg.AllowPrivateNetworks)
+	// Claimed deliveries are hidden from other workers until the request has had time to finish.
+	w.lease = 2 * timeout
+	return w
+}
+
+// Run sends due deliveries every poll interval until ctx is cancelled.
+func (w *Worker) Run(ctx context.Context) {
+	w.logger.Info("webhook worker started", zap.Duration("poll_interval", w.interval), zap.Int("batch_size", w.batchSize))
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := w.RunOnce(ctx); err != nil {
+			w.logger.Error("webhook worker poll failed", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			w.logger.Info("webhook worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce claims one batch of due deliveries, sends them concurrently and returns how many were claimed.
+func (w *Worker) RunOnce(ctx context.Context) (int, error) {
+	deliveries, err := w.store.ClaimWebhookDeliveries(ctx, w.batchSize, w.lease)
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	for _, d := range deliveries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.deliver(ctx, d)
+		}()
+	}
+	wg.Wait()
+	return len(deliveries), nil
+}
+
+// deliver sends one delivery and records the attempt and resulting state.
+func (w *Worker) deliver(ctx context.Context, d storage.WebhookDelivery) {
+	logger := w.logger.With(
+		zap.Int64("delivery_id", d.ID),
+		zap.String("subscription_id", d.SubscriptionID),
+		zap.Int64("event_id", d.EventID),
+	)
+
+	start := w.now()
+	statusCode, err := w.send(ctx, d, start)
+	attempt := storage.WebhookAttempt{
+		AttemptedAt: start,
+		DurationMS:  w.now().Sub(start).Milliseconds(),
+	}
+	if statusCode != 0 {
+		attempt.StatusCode = &statusCode
+	}
+
+	result := storage.WebhookAttemptResult{Attempt: attempt, Status: storage.DeliverySucceeded, NextAttemptAt: start}
+	if err != nil {
+		msg := err.Error()
+		result.Attempt.Error = &msg
+		attempts := d.Attempts + 1
+		if attempts >= w.maxAttempts {
+			result.Status = storage.DeliveryDead
+			logger.Warn("webhook delivery dead-lettered", zap.Int("attempts", attempts), zap.Error(err))
+		} else {
+			result.Status = storage.DeliveryPending
+			result.NextAttemptAt = start.Add(w.backoff(attempts))
+			logger.Info("webhook delivery failed, will retry", zap.Int("attempts", attempts), zap.Time("next_attempt_at", result.NextAttemptAt), zap.Error(err))
+		}
+	}
+
+	if err := w.store.RecordWebhookAttempt(ctx, d.ID, result); err != nil {
+		// The claim lease expires and the delivery is attempted again.
+		logger.Error("failed to record webhook attempt", zap.Error(err))
+	}
+}
+
+// send POSTs the signed delivery and returns the response status code, if any.
+func (w *Worker) send(ctx context.Context, d storage.WebhookDelivery, now time.Time) (int, error) {
+	body, err := json.Marshal(payload{EventID: d.EventID, EventType: d.EventType, Data: d.Payload})
+	if err != nil {
+		return 0, fmt.Errorf("marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-ID", strconv.FormatInt(d.ID, 10))
+	req.Header.Set("X-Event-ID", strconv.FormatInt(d.EventID, 10))
+	req.Header.Set("X-Event-Type", d.EventType)
+	req.Header.Set(SignatureHeader, Sign(d.Secret, now, body))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("deliver webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff returns the delay before retrying after the given number of failed attempts.
+func (w *Worker) backoff(attempts int) time.Duration {
+	delay := w.backoffBase
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= w.backoffMax {
+			return w.backoffMax
+		}
+	}
+	return min(delay, w.backoffMax)
+}
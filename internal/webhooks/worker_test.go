@@ -0,0 +1,213 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/cursed-ninja/internal-transfers-system/internal/config"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage"
+	"github.com/cursed-ninja/internal-transfers-system/internal/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+// TestWorkerRunOnce validates delivery against a local receiver: signed success, retry with backoff
+// and dead-lettering after the last attempt.
+func TestWorkerRunOnce(t *testing.T) {
+	now := time.Date(2025, 12, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name             string
+		status           int
+		attempts         int
+		expectedStatus   string
+		expectedNextTime time.Time
+	}{
+		{
+			name:             "delivered",
+			status:           http.StatusOK,
+			expectedStatus:   storage.DeliverySucceeded,
+			expectedNextTime: now,
+		},
+		{
+			name:             "first failure retries after base backoff",
+			status:           http.StatusInternalServerError,
+			expectedStatus:   storage.DeliveryPending,
+			expectedNextTime: now.Add(30 * time.Second),
+		},
+		{
+			name:             "third failure doubles backoff",
+			status:           http.StatusInternalServerError,
+			attempts:         2,
+			expectedStatus:   storage.DeliveryPending,
+			expectedNextTime: now.Add(2 * time.Minute),
+		},
+		{
+			name:             "last attempt is dead-lettered",
+			status:           http.StatusBadGateway,
+			attempts:         4,
+			expectedStatus:   storage.DeliveryDead,
+			expectedNextTime: now,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var received payload
+			receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				assert.NoError(t, Verify("whsec", r.Header.Get(SignatureHeader), body, now, time.Minute))
+				assert.Equal(t, "7", r.Header.Get("X-Webhook-ID"))
+				assert.NoError(t, json.Unmarshal(body, &received))
+				w.WriteHeader(tc.status)
+			}))
+			defer receiver.Close()
+
+			delivery := storage.WebhookDelivery{
+				ID:             7,
+				SubscriptionID: "sub-1",
+				EventID:        3,
+				EventType:      storage.EventTransferCompleted,
+				Payload:        json.RawMessage(`{"amount":"10"}`),
+				Attempts:       tc.attempts,
+				URL:            receiver.URL,
+				Secret:         "whsec",
+			}
+
+			mockCtrl := gomock.NewController(t)
+			mockStorage := mocks.NewMockStorage(mockCtrl)
+			mockStorage.EXPECT().ClaimWebhookDeliveries(gomock.Any(), 10, 2*time.Second).Return([]storage.WebhookDelivery{delivery}, nil)
+			mockStorage.EXPECT().RecordWebhookAttempt(gomock.Any(), int64(7), gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ int64, result storage.WebhookAttemptResult) error {
+					assert.Equal(t, tc.expectedStatus, result.Status)
+					assert.Equal(t, tc.expectedNextTime, result.NextAttemptAt)
+					assert.Equal(t, tc.status, *result.Attempt.StatusCode)
+					assert.Equal(t, tc.expectedStatus != storage.DeliverySucceeded, result.Attempt.Error != nil)
+					return nil
+				})
+
+			worker := NewWorker(mockStorage, &config.WebhooksConfig{
+				BatchSize:            10,
+				Timeout:              time.Second,
+				MaxAttempts:          5,
+				BackoffBase:          30 * time.Second,
+				BackoffMax:           time.Hour,
+				AllowPrivateNetworks: true,
+			}, zap.NewNop())
+			worker.now = func() time.Time { return now }
+
+			n, err := worker.RunOnce(context.Background())
+
+			assert.NoError(t, err)
+			assert.Equal(t, 1, n)
+			assert.Equal(t, payload{EventID: 3, EventType: storage.EventTransferCompleted, Data: json.RawMessage(`{"amount":"10"}`)}, received)
+		})
+	}
+}
+
+// TestWorkerRefusesInternalTargets validates that deliveries never reach private addresses unless
+// allowed, and that redirects are not followed.
+func TestWorkerRefusesInternalTargets(t *testing.T) {
+	tests := []struct {
+		name           string
+		allowPrivate   bool
+		redirect       bool
+		expectedStatus *int
+		expectedError  string
+	}{
+		{
+			name:          "loopback address",
+			expectedError: ErrForbiddenAddress.Error(),
+		},
+		{
+			name:           "redirect",
+			allowPrivate:   true,
+			redirect:       true,
+			expectedStatus: func() *int { s := http.StatusFound; return &s }(),
+			expectedError:  "webhook responded with status 302",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				t.Error("internal target was reached")
+			}))
+			defer target.Close()
+			receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tc.redirect {
+					http.Redirect(w, r, target.URL, http.StatusFound)
+					return
+				}
+				t.Error("receiver was reached")
+			}))
+			defer receiver.Close()
+
+			mockCtrl := gomock.NewController(t)
+			mockStorage := mocks.NewMockStorage(mockCtrl)
+			mockStorage.EXPECT().ClaimWebhookDeliveries(gomock.Any(), gomock.Any(), gomock.Any()).
+				Return([]storage.WebhookDelivery{{ID: 7, URL: receiver.URL, Secret: "whsec"}}, nil)
+			mockStorage.EXPECT().RecordWebhookAttempt(gomock.Any(), int64(7), gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ int64, result storage.WebhookAttemptResult) error {
+					assert.Equal(t, storage.DeliveryPending, result.Status)
+					assert.Equal(t, tc.expectedStatus, result.Attempt.StatusCode)
+					if assert.NotNil(t, result.Attempt.Error) {
+						assert.Contains(t, *result.Attempt.Error, tc.expectedError)
+					}
+					return nil
+				})
+
+			worker := NewWorker(mockStorage, &config.WebhooksConfig{Timeout: time.Second, AllowPrivateNetworks: tc.allowPrivate}, zap.NewNop())
+
+			_, err := worker.RunOnce(context.Background())
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+// TestIsPublic validates which resolved addresses deliveries may connect to.
+func TestIsPublic(t *testing.T) {
+	tests := []struct {
+		addr     string
+		expected bool
+	}{
+		{addr: "93.184.216.34", expected: true},
+		{addr: "2606:2800:220:1::", expected: true},
+		{addr: "127.0.0.1"},
+		{addr: "::1"},
+		{addr: "169.254.169.254"},
+		{addr: "fe80::1"},
+		{addr: "10.0.0.1"},
+		{addr: "172.16.0.1"},
+		{addr: "192.168.1.1"},
+		{addr: "100.64.0.1"},
+		{addr: "fd00::1"},
+		{addr: "0.0.0.0"},
+		{addr: "224.0.0.1"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.addr, func(t *testing.T) {
+			assert.Equal(t, tc.expected, isPublic(netip.MustParseAddr(tc.addr)))
+		})
+	}
+}
+
+// TestWorkerBackoff validates exponential backoff capped at the configured maximum.
+func TestWorkerBackoff(t *testing.T) {
+	worker := NewWorker(nil, &config.WebhooksConfig{BackoffBase: time.Second, BackoffMax: 10 * time.Second}, zap.NewNop())
+
+	assert.Equal(t, time.Second, worker.backoff(1))
+	assert.Equal(t, 2*time.Second, worker.backoff(2))
+	assert.Equal(t, 8*time.Second, worker.backoff(4))
+	assert.Equal(t, 10*time.Second, worker.backoff(5))
+	assert.Equal(t, 10*time.Second, worker.backoff(40))
+}
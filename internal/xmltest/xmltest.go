@@ -0,0 +1,27 @@
+// Package xmltest validates XML documents generated in tests against their XSD schemas.
+package xmltest
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// Validate validates doc against the XSD schema at schemaPath with xmllint, returning xmllint's report
+// and an error when the document is invalid. The test fails if xmllint is not installed, so schema
+// checks cannot pass silently on machines without it.
+func Validate(t testing.TB, schemaPath, doc string) (string, error) {
+	t.Helper()
+	xmllint, err := exec.LookPath("xmllint")
+	if err != nil {
+		t.Fatal("xmllint is required to validate XML schemas; install libxml2-utils (Debian/Ubuntu) or libxml2")
+	}
+	path := filepath.Join(t.TempDir(), "document.xml")
+	if err := os.WriteFile(path, []byte(doc), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := exec.Command(xmllint, "--noout", "--schema", schemaPath, path).CombinedOutput()
+	return string(out), err
+}